@@ -14,6 +14,7 @@ import (
 	"github.com/tranvuongduy2003/go-mvc/internal/domain"
 	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure"
 	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/config"
+	"github.com/tranvuongduy2003/go-mvc/pkg/oauth"
 )
 
 var rootCmd = &cobra.Command{
@@ -33,6 +34,7 @@ func init() {
 	rootCmd.AddCommand(migrateCommand())
 	rootCmd.AddCommand(seedCommand())
 	rootCmd.AddCommand(resetDBCommand())
+	rootCmd.AddCommand(oauthClientSecretCommand())
 
 	rootCmd.AddCommand(healthCheckCommand())
 	rootCmd.AddCommand(versionCommand())
@@ -153,6 +155,24 @@ func resetDBCommand() *cobra.Command {
 	return cmd
 }
 
+func oauthClientSecretCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "oauth-client-secret",
+		Short: "Generate an OAuth client secret",
+		Long:  `Generate a secret for an OAuth client together with the hash to put in auth.oauth.clients.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			secret, err := oauth.GenerateClientSecret()
+			if err != nil {
+				log.Fatalf("Failed to generate client secret: %v", err)
+			}
+
+			fmt.Printf("Client secret: %s\n", secret)
+			fmt.Printf("Secret hash:   %s\n", oauth.HashClientSecret(secret))
+			fmt.Println("Give the secret to the client and store only the hash in the configuration.")
+		},
+	}
+}
+
 func healthCheckCommand() *cobra.Command {
 	return &cobra.Command{
 		Use:   "health",
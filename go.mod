@@ -21,6 +21,7 @@ require (
 	go.uber.org/zap v1.26.0
 	golang.org/x/crypto v0.42.0
 	golang.org/x/time v0.13.0
+	gopkg.in/yaml.v3 v3.0.1
 	gorm.io/driver/postgres v1.5.4
 	gorm.io/gorm v1.30.0
 )
@@ -104,5 +105,4 @@ require (
 	golang.org/x/text v0.29.0 // indirect
 	google.golang.org/protobuf v1.36.8 // indirect
 	gopkg.in/ini.v1 v1.67.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
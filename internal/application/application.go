@@ -14,6 +14,8 @@ import (
 var ApplicationModule = fx.Module("application",
 	modules.UserModule,
 	modules.AuthModule,
+	modules.RBACModule,
+	modules.PolicyModule,
 	modules.JobModule,
 	modules.MessagingModule,
 
@@ -41,7 +43,7 @@ type ValidatorParams struct {
 	Logger *logger.Logger
 }
 
-func NewJWTService(params JWTParams) jwt.JWTService {
+func NewJWTService(params JWTParams) (jwt.JWTService, error) {
 	return jwt.NewService(params.Config.JWT)
 }
 
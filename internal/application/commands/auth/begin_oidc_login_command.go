@@ -0,0 +1,25 @@
+package commands
+
+import (
+	"context"
+
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
+)
+
+type BeginOIDCLoginCommand struct {
+	Provider string `validate:"required"`
+}
+
+type BeginOIDCLoginCommandHandler struct {
+	externalLoginService contracts.ExternalLoginService
+}
+
+func NewBeginOIDCLoginCommandHandler(externalLoginService contracts.ExternalLoginService) *BeginOIDCLoginCommandHandler {
+	return &BeginOIDCLoginCommandHandler{
+		externalLoginService: externalLoginService,
+	}
+}
+
+func (h *BeginOIDCLoginCommandHandler) Handle(ctx context.Context, cmd BeginOIDCLoginCommand) (*contracts.OIDCAuthorization, error) {
+	return h.externalLoginService.BeginOIDCLogin(ctx, cmd.Provider)
+}
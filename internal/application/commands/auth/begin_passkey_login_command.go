@@ -0,0 +1,33 @@
+package commands
+
+import (
+	"context"
+
+	dto "github.com/tranvuongduy2003/go-mvc/internal/application/dto/auth"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
+)
+
+type BeginPasskeyLoginCommand struct {
+	Email string `validate:"omitempty,email"`
+}
+
+type BeginPasskeyLoginCommandHandler struct {
+	passkeyService contracts.PasskeyService
+}
+
+func NewBeginPasskeyLoginCommandHandler(passkeyService contracts.PasskeyService) *BeginPasskeyLoginCommandHandler {
+	return &BeginPasskeyLoginCommandHandler{
+		passkeyService: passkeyService,
+	}
+}
+
+func (h *BeginPasskeyLoginCommandHandler) Handle(ctx context.Context, cmd BeginPasskeyLoginCommand) (*dto.PasskeyRequestOptionsResponse, error) {
+	options, err := h.passkeyService.BeginPasskeyLogin(ctx, cmd.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.PasskeyRequestOptionsResponse{
+		PublicKey: options,
+	}, nil
+}
@@ -0,0 +1,33 @@
+package commands
+
+import (
+	"context"
+
+	dto "github.com/tranvuongduy2003/go-mvc/internal/application/dto/auth"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
+)
+
+type BeginPasskeyRegistrationCommand struct {
+	UserID string `validate:"required"`
+}
+
+type BeginPasskeyRegistrationCommandHandler struct {
+	passkeyService contracts.PasskeyService
+}
+
+func NewBeginPasskeyRegistrationCommandHandler(passkeyService contracts.PasskeyService) *BeginPasskeyRegistrationCommandHandler {
+	return &BeginPasskeyRegistrationCommandHandler{
+		passkeyService: passkeyService,
+	}
+}
+
+func (h *BeginPasskeyRegistrationCommandHandler) Handle(ctx context.Context, cmd BeginPasskeyRegistrationCommand) (*dto.PasskeyCreationOptionsResponse, error) {
+	options, err := h.passkeyService.BeginPasskeyRegistration(ctx, cmd.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.PasskeyCreationOptionsResponse{
+		PublicKey: options,
+	}, nil
+}
@@ -9,8 +9,9 @@ import (
 
 type ChangePasswordCommand struct {
 	UserID      string `validate:"required"`
+	SessionID   string
 	OldPassword string `validate:"required"`
-	NewPassword string `validate:"required,min=8"`
+	NewPassword string `validate:"required"`
 }
 
 type ChangePasswordCommandHandler struct {
@@ -23,14 +24,21 @@ func NewChangePasswordCommandHandler(passwordService contracts.PasswordManagemen
 	}
 }
 
-func (h *ChangePasswordCommandHandler) Handle(ctx context.Context, cmd ChangePasswordCommand) (*dto.StatusResponse, error) {
-	err := h.passwordService.ChangePassword(ctx, cmd.UserID, cmd.OldPassword, cmd.NewPassword)
+func (h *ChangePasswordCommandHandler) Handle(ctx context.Context, cmd ChangePasswordCommand) (*dto.ChangePasswordResponse, error) {
+	tokens, err := h.passwordService.ChangePassword(ctx, cmd.UserID, cmd.SessionID, cmd.OldPassword, cmd.NewPassword)
 	if err != nil {
 		return nil, err
 	}
 
-	return &dto.StatusResponse{
-		Status:  "success",
-		Message: "Password changed successfully",
-	}, nil
+	response := &dto.ChangePasswordResponse{}
+	if tokens != nil {
+		response.Tokens = &dto.TokensDTO{
+			AccessToken:           tokens.AccessToken,
+			RefreshToken:          tokens.RefreshToken,
+			AccessTokenExpiresAt:  tokens.AccessTokenExpiresAt,
+			RefreshTokenExpiresAt: tokens.RefreshTokenExpiresAt,
+			TokenType:             tokens.TokenType,
+		}
+	}
+	return response, nil
 }
@@ -0,0 +1,34 @@
+package commands
+
+import (
+	"context"
+
+	dto "github.com/tranvuongduy2003/go-mvc/internal/application/dto/auth"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
+)
+
+type ConfirmEmailChangeCommand struct {
+	Token string `validate:"required"`
+}
+
+type ConfirmEmailChangeCommandHandler struct {
+	emailChangeService contracts.EmailChangeService
+}
+
+func NewConfirmEmailChangeCommandHandler(emailChangeService contracts.EmailChangeService) *ConfirmEmailChangeCommandHandler {
+	return &ConfirmEmailChangeCommandHandler{
+		emailChangeService: emailChangeService,
+	}
+}
+
+func (h *ConfirmEmailChangeCommandHandler) Handle(ctx context.Context, cmd ConfirmEmailChangeCommand) (*dto.StatusResponse, error) {
+	err := h.emailChangeService.ConfirmEmailChange(ctx, cmd.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.StatusResponse{
+		Status:  "success",
+		Message: "Email changed successfully",
+	}, nil
+}
@@ -0,0 +1,34 @@
+package commands
+
+import (
+	"context"
+
+	dto "github.com/tranvuongduy2003/go-mvc/internal/application/dto/auth"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
+)
+
+type ConfirmMFACommand struct {
+	UserID string `validate:"required"`
+	Code   string `validate:"required"`
+}
+
+type ConfirmMFACommandHandler struct {
+	mfaService contracts.MFAService
+}
+
+func NewConfirmMFACommandHandler(mfaService contracts.MFAService) *ConfirmMFACommandHandler {
+	return &ConfirmMFACommandHandler{
+		mfaService: mfaService,
+	}
+}
+
+func (h *ConfirmMFACommandHandler) Handle(ctx context.Context, cmd ConfirmMFACommand) (*dto.RecoveryCodesResponse, error) {
+	recoveryCodes, err := h.mfaService.ConfirmMFAEnrollment(ctx, cmd.UserID, cmd.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.RecoveryCodesResponse{
+		RecoveryCodes: recoveryCodes,
+	}, nil
+}
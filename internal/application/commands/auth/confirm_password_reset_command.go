@@ -9,7 +9,7 @@ import (
 
 type ConfirmPasswordResetCommand struct {
 	Token       string `validate:"required"`
-	NewPassword string `validate:"required,min=8"`
+	NewPassword string `validate:"required"`
 }
 
 type ConfirmPasswordResetCommandHandler struct {
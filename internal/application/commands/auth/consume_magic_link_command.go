@@ -0,0 +1,37 @@
+package commands
+
+import (
+	"context"
+
+	dto "github.com/tranvuongduy2003/go-mvc/internal/application/dto/auth"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
+)
+
+type ConsumeMagicLinkCommand struct {
+	Token     string `validate:"required"`
+	Nonce     string `validate:"required"`
+	UserAgent string
+	IPAddress string
+}
+
+type ConsumeMagicLinkCommandHandler struct {
+	magicLinkService contracts.MagicLinkService
+}
+
+func NewConsumeMagicLinkCommandHandler(magicLinkService contracts.MagicLinkService) *ConsumeMagicLinkCommandHandler {
+	return &ConsumeMagicLinkCommandHandler{
+		magicLinkService: magicLinkService,
+	}
+}
+
+func (h *ConsumeMagicLinkCommandHandler) Handle(ctx context.Context, cmd ConsumeMagicLinkCommand) (*dto.LoginResponse, error) {
+	authenticatedUser, err := h.magicLinkService.ConsumeMagicLink(ctx, cmd.Token, cmd.Nonce, contracts.ClientInfo{
+		UserAgent: cmd.UserAgent,
+		IPAddress: cmd.IPAddress,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.ToLoginResponse(authenticatedUser), nil
+}
@@ -0,0 +1,42 @@
+package commands
+
+import (
+	"context"
+	"time"
+
+	dto "github.com/tranvuongduy2003/go-mvc/internal/application/dto/auth"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
+)
+
+type CreateAPIKeyCommand struct {
+	UserID    string   `validate:"required"`
+	Name      string   `validate:"required,max=100"`
+	Scopes    []string `validate:"required,min=1"`
+	ExpiresAt *time.Time
+}
+
+type CreateAPIKeyCommandHandler struct {
+	apiKeyService contracts.APIKeyService
+}
+
+func NewCreateAPIKeyCommandHandler(apiKeyService contracts.APIKeyService) *CreateAPIKeyCommandHandler {
+	return &CreateAPIKeyCommandHandler{
+		apiKeyService: apiKeyService,
+	}
+}
+
+func (h *CreateAPIKeyCommandHandler) Handle(ctx context.Context, cmd CreateAPIKeyCommand) (*dto.CreatedAPIKeyResponse, error) {
+	issued, err := h.apiKeyService.CreateAPIKey(ctx, cmd.UserID, contracts.NewAPIKey{
+		Name:      cmd.Name,
+		Scopes:    cmd.Scopes,
+		ExpiresAt: cmd.ExpiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.CreatedAPIKeyResponse{
+		APIKeyDTO: dto.ToAPIKeyDTO(issued.APIKey),
+		Key:       issued.Key,
+	}, nil
+}
@@ -0,0 +1,35 @@
+package commands
+
+import (
+	"context"
+
+	dto "github.com/tranvuongduy2003/go-mvc/internal/application/dto/auth"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
+)
+
+type DeleteAPIKeyCommand struct {
+	UserID   string `validate:"required"`
+	APIKeyID string `validate:"required,uuid"`
+}
+
+type DeleteAPIKeyCommandHandler struct {
+	apiKeyService contracts.APIKeyService
+}
+
+func NewDeleteAPIKeyCommandHandler(apiKeyService contracts.APIKeyService) *DeleteAPIKeyCommandHandler {
+	return &DeleteAPIKeyCommandHandler{
+		apiKeyService: apiKeyService,
+	}
+}
+
+func (h *DeleteAPIKeyCommandHandler) Handle(ctx context.Context, cmd DeleteAPIKeyCommand) (*dto.StatusResponse, error) {
+	err := h.apiKeyService.DeleteAPIKey(ctx, cmd.UserID, cmd.APIKeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.StatusResponse{
+		Status:  "success",
+		Message: "API key deleted successfully",
+	}, nil
+}
@@ -0,0 +1,35 @@
+package commands
+
+import (
+	"context"
+
+	dto "github.com/tranvuongduy2003/go-mvc/internal/application/dto/auth"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
+)
+
+type DeletePasskeyCommand struct {
+	UserID    string `validate:"required"`
+	PasskeyID string `validate:"required,uuid"`
+}
+
+type DeletePasskeyCommandHandler struct {
+	passkeyService contracts.PasskeyService
+}
+
+func NewDeletePasskeyCommandHandler(passkeyService contracts.PasskeyService) *DeletePasskeyCommandHandler {
+	return &DeletePasskeyCommandHandler{
+		passkeyService: passkeyService,
+	}
+}
+
+func (h *DeletePasskeyCommandHandler) Handle(ctx context.Context, cmd DeletePasskeyCommand) (*dto.StatusResponse, error) {
+	err := h.passkeyService.DeletePasskey(ctx, cmd.UserID, cmd.PasskeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.StatusResponse{
+		Status:  "success",
+		Message: "Passkey deleted successfully",
+	}, nil
+}
@@ -0,0 +1,34 @@
+package commands
+
+import (
+	"context"
+
+	dto "github.com/tranvuongduy2003/go-mvc/internal/application/dto/auth"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
+)
+
+type EnrollMFACommand struct {
+	UserID string `validate:"required"`
+}
+
+type EnrollMFACommandHandler struct {
+	mfaService contracts.MFAService
+}
+
+func NewEnrollMFACommandHandler(mfaService contracts.MFAService) *EnrollMFACommandHandler {
+	return &EnrollMFACommandHandler{
+		mfaService: mfaService,
+	}
+}
+
+func (h *EnrollMFACommandHandler) Handle(ctx context.Context, cmd EnrollMFACommand) (*dto.MFAEnrollmentResponse, error) {
+	enrollment, err := h.mfaService.BeginMFAEnrollment(ctx, cmd.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.MFAEnrollmentResponse{
+		Secret:     enrollment.Secret,
+		OTPAuthURI: enrollment.URI,
+	}, nil
+}
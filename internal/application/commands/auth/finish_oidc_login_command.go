@@ -0,0 +1,38 @@
+package commands
+
+import (
+	"context"
+
+	dto "github.com/tranvuongduy2003/go-mvc/internal/application/dto/auth"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
+)
+
+type FinishOIDCLoginCommand struct {
+	Provider  string `validate:"required"`
+	State     string `validate:"required"`
+	Code      string `validate:"required"`
+	UserAgent string
+	IPAddress string
+}
+
+type FinishOIDCLoginCommandHandler struct {
+	externalLoginService contracts.ExternalLoginService
+}
+
+func NewFinishOIDCLoginCommandHandler(externalLoginService contracts.ExternalLoginService) *FinishOIDCLoginCommandHandler {
+	return &FinishOIDCLoginCommandHandler{
+		externalLoginService: externalLoginService,
+	}
+}
+
+func (h *FinishOIDCLoginCommandHandler) Handle(ctx context.Context, cmd FinishOIDCLoginCommand) (*dto.LoginResponse, error) {
+	authenticatedUser, err := h.externalLoginService.FinishOIDCLogin(ctx, cmd.Provider, cmd.State, cmd.Code, contracts.ClientInfo{
+		UserAgent: cmd.UserAgent,
+		IPAddress: cmd.IPAddress,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.ToLoginResponse(authenticatedUser), nil
+}
@@ -0,0 +1,39 @@
+package commands
+
+import (
+	"context"
+
+	dto "github.com/tranvuongduy2003/go-mvc/internal/application/dto/auth"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
+	"github.com/tranvuongduy2003/go-mvc/pkg/webauthn"
+)
+
+type FinishPasskeyLoginCommand struct {
+	Credential webauthn.AssertionResponse
+	DeviceName string `validate:"omitempty,max=255"`
+	UserAgent  string
+	IPAddress  string
+}
+
+type FinishPasskeyLoginCommandHandler struct {
+	passkeyService contracts.PasskeyService
+}
+
+func NewFinishPasskeyLoginCommandHandler(passkeyService contracts.PasskeyService) *FinishPasskeyLoginCommandHandler {
+	return &FinishPasskeyLoginCommandHandler{
+		passkeyService: passkeyService,
+	}
+}
+
+func (h *FinishPasskeyLoginCommandHandler) Handle(ctx context.Context, cmd FinishPasskeyLoginCommand) (*dto.LoginResponse, error) {
+	authenticatedUser, err := h.passkeyService.FinishPasskeyLogin(ctx, &cmd.Credential, contracts.ClientInfo{
+		DeviceName: cmd.DeviceName,
+		UserAgent:  cmd.UserAgent,
+		IPAddress:  cmd.IPAddress,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.ToLoginResponse(authenticatedUser), nil
+}
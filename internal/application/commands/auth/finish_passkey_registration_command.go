@@ -0,0 +1,35 @@
+package commands
+
+import (
+	"context"
+
+	dto "github.com/tranvuongduy2003/go-mvc/internal/application/dto/auth"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
+	"github.com/tranvuongduy2003/go-mvc/pkg/webauthn"
+)
+
+type FinishPasskeyRegistrationCommand struct {
+	UserID     string `validate:"required"`
+	Name       string `validate:"omitempty,max=100"`
+	Credential webauthn.AttestationResponse
+}
+
+type FinishPasskeyRegistrationCommandHandler struct {
+	passkeyService contracts.PasskeyService
+}
+
+func NewFinishPasskeyRegistrationCommandHandler(passkeyService contracts.PasskeyService) *FinishPasskeyRegistrationCommandHandler {
+	return &FinishPasskeyRegistrationCommandHandler{
+		passkeyService: passkeyService,
+	}
+}
+
+func (h *FinishPasskeyRegistrationCommandHandler) Handle(ctx context.Context, cmd FinishPasskeyRegistrationCommand) (*dto.PasskeyDTO, error) {
+	credential, err := h.passkeyService.FinishPasskeyRegistration(ctx, cmd.UserID, cmd.Name, &cmd.Credential)
+	if err != nil {
+		return nil, err
+	}
+
+	passkey := dto.ToPasskeyDTO(credential)
+	return &passkey, nil
+}
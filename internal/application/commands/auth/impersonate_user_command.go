@@ -0,0 +1,53 @@
+package commands
+
+import (
+	"context"
+
+	dto "github.com/tranvuongduy2003/go-mvc/internal/application/dto/auth"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
+	apperrors "github.com/tranvuongduy2003/go-mvc/pkg/errors"
+)
+
+type ImpersonateUserCommand struct {
+	AdminID   string `validate:"required,uuid"`
+	UserID    string `validate:"required,uuid"`
+	UserAgent string
+	IPAddress string
+}
+
+type ImpersonateUserCommandHandler struct {
+	impersonationService contracts.ImpersonationService
+	authorizationService contracts.AuthorizationService
+}
+
+func NewImpersonateUserCommandHandler(
+	impersonationService contracts.ImpersonationService,
+	authorizationService contracts.AuthorizationService,
+) *ImpersonateUserCommandHandler {
+	return &ImpersonateUserCommandHandler{
+		impersonationService: impersonationService,
+		authorizationService: authorizationService,
+	}
+}
+
+// Handle refuses to impersonate other admins, which would let one admin
+// act with another's authority under a different name.
+func (h *ImpersonateUserCommandHandler) Handle(ctx context.Context, cmd ImpersonateUserCommand) (*dto.ImpersonationResponse, error) {
+	isAdmin, err := h.authorizationService.IsAdmin(ctx, cmd.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if isAdmin {
+		return nil, apperrors.NewForbiddenError("admins cannot be impersonated")
+	}
+
+	impersonation, err := h.impersonationService.ImpersonateUser(ctx, cmd.AdminID, cmd.UserID, contracts.ClientInfo{
+		UserAgent: cmd.UserAgent,
+		IPAddress: cmd.IPAddress,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.ToImpersonationResponse(impersonation), nil
+}
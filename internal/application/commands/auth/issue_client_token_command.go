@@ -0,0 +1,36 @@
+package commands
+
+import (
+	"context"
+
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
+	"github.com/tranvuongduy2003/go-mvc/pkg/oauth"
+)
+
+type IssueClientTokenCommand struct {
+	GrantType    string
+	ClientID     string
+	ClientSecret string
+	Scope        string
+}
+
+type IssueClientTokenCommandHandler struct {
+	clientCredentialsService contracts.ClientCredentialsService
+}
+
+func NewIssueClientTokenCommandHandler(clientCredentialsService contracts.ClientCredentialsService) *IssueClientTokenCommandHandler {
+	return &IssueClientTokenCommandHandler{
+		clientCredentialsService: clientCredentialsService,
+	}
+}
+
+func (h *IssueClientTokenCommandHandler) Handle(ctx context.Context, cmd IssueClientTokenCommand) (*contracts.ClientToken, error) {
+	if cmd.GrantType == "" {
+		return nil, oauth.NewError(oauth.ErrorInvalidRequest, "grant_type is required")
+	}
+	if cmd.GrantType != oauth.GrantTypeClientCredentials {
+		return nil, oauth.NewError(oauth.ErrorUnsupportedGrantType, "only the client_credentials grant is supported")
+	}
+
+	return h.clientCredentialsService.IssueClientToken(ctx, cmd.ClientID, cmd.ClientSecret, cmd.Scope)
+}
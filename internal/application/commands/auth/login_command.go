@@ -8,8 +8,11 @@ import (
 )
 
 type LoginCommand struct {
-	Email    string `validate:"required,email"`
-	Password string `validate:"required"`
+	Email      string `validate:"required,email"`
+	Password   string `validate:"required"`
+	DeviceName string `validate:"omitempty,max=255"`
+	UserAgent  string
+	IPAddress  string
 }
 
 type LoginCommandHandler struct {
@@ -26,6 +29,11 @@ func (h *LoginCommandHandler) Handle(ctx context.Context, cmd LoginCommand) (*dt
 	credentials := &contracts.LoginCredentials{
 		Email:    cmd.Email,
 		Password: cmd.Password,
+		Client: contracts.ClientInfo{
+			DeviceName: cmd.DeviceName,
+			UserAgent:  cmd.UserAgent,
+			IPAddress:  cmd.IPAddress,
+		},
 	}
 
 	authenticatedUser, err := h.authService.Login(ctx, credentials)
@@ -33,14 +41,5 @@ func (h *LoginCommandHandler) Handle(ctx context.Context, cmd LoginCommand) (*dt
 		return nil, err
 	}
 
-	return &dto.LoginResponse{
-		User: dto.ToAuthUserDTO(authenticatedUser.User),
-		Tokens: dto.TokensDTO{
-			AccessToken:           authenticatedUser.Tokens.AccessToken,
-			RefreshToken:          authenticatedUser.Tokens.RefreshToken,
-			AccessTokenExpiresAt:  authenticatedUser.Tokens.AccessTokenExpiresAt,
-			RefreshTokenExpiresAt: authenticatedUser.Tokens.RefreshTokenExpiresAt,
-			TokenType:             authenticatedUser.Tokens.TokenType,
-		},
-	}, nil
+	return dto.ToLoginResponse(authenticatedUser), nil
 }
@@ -8,7 +8,8 @@ import (
 )
 
 type LogoutCommand struct {
-	UserID string `validate:"required"`
+	UserID      string `validate:"required"`
+	AccessToken string `validate:"required"`
 }
 
 type LogoutCommandHandler struct {
@@ -22,7 +23,7 @@ func NewLogoutCommandHandler(tokenService contracts.TokenManagementService) *Log
 }
 
 func (h *LogoutCommandHandler) Handle(ctx context.Context, cmd LogoutCommand) (*dto.StatusResponse, error) {
-	err := h.tokenService.Logout(ctx, cmd.UserID)
+	err := h.tokenService.Logout(ctx, cmd.UserID, cmd.AccessToken)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,42 @@
+package commands
+
+import (
+	"context"
+
+	dto "github.com/tranvuongduy2003/go-mvc/internal/application/dto/auth"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
+)
+
+type ReauthenticateCommand struct {
+	UserID    string `validate:"required,uuid"`
+	SessionID string
+	Password  string
+	Code      string
+	UserAgent string
+	IPAddress string
+}
+
+type ReauthenticateCommandHandler struct {
+	reauthenticationService contracts.ReauthenticationService
+}
+
+func NewReauthenticateCommandHandler(reauthenticationService contracts.ReauthenticationService) *ReauthenticateCommandHandler {
+	return &ReauthenticateCommandHandler{
+		reauthenticationService: reauthenticationService,
+	}
+}
+
+func (h *ReauthenticateCommandHandler) Handle(ctx context.Context, cmd ReauthenticateCommand) (*dto.ReauthenticationResponse, error) {
+	token, err := h.reauthenticationService.Reauthenticate(ctx, cmd.UserID, cmd.SessionID, contracts.Reauthentication{
+		Password: cmd.Password,
+		Code:     cmd.Code,
+	}, contracts.ClientInfo{
+		UserAgent: cmd.UserAgent,
+		IPAddress: cmd.IPAddress,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.ToReauthenticationResponse(token), nil
+}
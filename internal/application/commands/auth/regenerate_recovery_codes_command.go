@@ -0,0 +1,34 @@
+package commands
+
+import (
+	"context"
+
+	dto "github.com/tranvuongduy2003/go-mvc/internal/application/dto/auth"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
+)
+
+type RegenerateRecoveryCodesCommand struct {
+	UserID string `validate:"required"`
+	Code   string `validate:"required"`
+}
+
+type RegenerateRecoveryCodesCommandHandler struct {
+	mfaService contracts.MFAService
+}
+
+func NewRegenerateRecoveryCodesCommandHandler(mfaService contracts.MFAService) *RegenerateRecoveryCodesCommandHandler {
+	return &RegenerateRecoveryCodesCommandHandler{
+		mfaService: mfaService,
+	}
+}
+
+func (h *RegenerateRecoveryCodesCommandHandler) Handle(ctx context.Context, cmd RegenerateRecoveryCodesCommand) (*dto.RecoveryCodesResponse, error) {
+	recoveryCodes, err := h.mfaService.RegenerateRecoveryCodes(ctx, cmd.UserID, cmd.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.RecoveryCodesResponse{
+		RecoveryCodes: recoveryCodes,
+	}, nil
+}
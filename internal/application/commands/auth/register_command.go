@@ -8,10 +8,13 @@ import (
 )
 
 type RegisterCommand struct {
-	Email    string `validate:"required,email"`
-	Name     string `validate:"required,min=2,max=100"`
-	Phone    string `validate:"omitempty"`
-	Password string `validate:"required,min=8"`
+	Email      string `validate:"required,email"`
+	Name       string `validate:"required,min=2,max=100"`
+	Phone      string `validate:"omitempty"`
+	Password   string `validate:"required"`
+	DeviceName string `validate:"omitempty,max=255"`
+	UserAgent  string
+	IPAddress  string
 }
 
 type RegisterCommandHandler struct {
@@ -30,6 +33,11 @@ func (h *RegisterCommandHandler) Handle(ctx context.Context, cmd RegisterCommand
 		Name:     cmd.Name,
 		Phone:    cmd.Phone,
 		Password: cmd.Password,
+		Client: contracts.ClientInfo{
+			DeviceName: cmd.DeviceName,
+			UserAgent:  cmd.UserAgent,
+			IPAddress:  cmd.IPAddress,
+		},
 	}
 
 	authenticatedUser, err := h.authService.Register(ctx, registerReq)
@@ -37,14 +45,21 @@ func (h *RegisterCommandHandler) Handle(ctx context.Context, cmd RegisterCommand
 		return nil, err
 	}
 
-	return &dto.RegisterResponse{
+	response := &dto.RegisterResponse{
 		User: dto.ToAuthUserDTO(authenticatedUser.User),
-		Tokens: dto.TokensDTO{
-			AccessToken:           authenticatedUser.Tokens.AccessToken,
-			RefreshToken:          authenticatedUser.Tokens.RefreshToken,
-			AccessTokenExpiresAt:  authenticatedUser.Tokens.AccessTokenExpiresAt,
-			RefreshTokenExpiresAt: authenticatedUser.Tokens.RefreshTokenExpiresAt,
-			TokenType:             authenticatedUser.Tokens.TokenType,
-		},
-	}, nil
+	}
+
+	if authenticatedUser.Tokens == nil {
+		response.VerificationRequired = true
+		return response, nil
+	}
+
+	response.Tokens = &dto.TokensDTO{
+		AccessToken:           authenticatedUser.Tokens.AccessToken,
+		RefreshToken:          authenticatedUser.Tokens.RefreshToken,
+		AccessTokenExpiresAt:  authenticatedUser.Tokens.AccessTokenExpiresAt,
+		RefreshTokenExpiresAt: authenticatedUser.Tokens.RefreshTokenExpiresAt,
+		TokenType:             authenticatedUser.Tokens.TokenType,
+	}
+	return response, nil
 }
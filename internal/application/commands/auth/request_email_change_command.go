@@ -0,0 +1,36 @@
+package commands
+
+import (
+	"context"
+
+	dto "github.com/tranvuongduy2003/go-mvc/internal/application/dto/auth"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
+)
+
+type RequestEmailChangeCommand struct {
+	UserID   string `validate:"required"`
+	NewEmail string `validate:"required,email"`
+	Password string `validate:"required"`
+}
+
+type RequestEmailChangeCommandHandler struct {
+	emailChangeService contracts.EmailChangeService
+}
+
+func NewRequestEmailChangeCommandHandler(emailChangeService contracts.EmailChangeService) *RequestEmailChangeCommandHandler {
+	return &RequestEmailChangeCommandHandler{
+		emailChangeService: emailChangeService,
+	}
+}
+
+func (h *RequestEmailChangeCommandHandler) Handle(ctx context.Context, cmd RequestEmailChangeCommand) (*dto.StatusResponse, error) {
+	err := h.emailChangeService.RequestEmailChange(ctx, cmd.UserID, cmd.NewEmail, cmd.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.StatusResponse{
+		Status:  "success",
+		Message: "A confirmation link has been sent to the new email address",
+	}, nil
+}
@@ -0,0 +1,25 @@
+package commands
+
+import (
+	"context"
+
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
+)
+
+type RequestMagicLinkCommand struct {
+	Email string `validate:"required,email"`
+}
+
+type RequestMagicLinkCommandHandler struct {
+	magicLinkService contracts.MagicLinkService
+}
+
+func NewRequestMagicLinkCommandHandler(magicLinkService contracts.MagicLinkService) *RequestMagicLinkCommandHandler {
+	return &RequestMagicLinkCommandHandler{
+		magicLinkService: magicLinkService,
+	}
+}
+
+func (h *RequestMagicLinkCommandHandler) Handle(ctx context.Context, cmd RequestMagicLinkCommand) (*contracts.MagicLinkRequest, error) {
+	return h.magicLinkService.RequestMagicLink(ctx, cmd.Email)
+}
@@ -0,0 +1,34 @@
+package commands
+
+import (
+	"context"
+
+	dto "github.com/tranvuongduy2003/go-mvc/internal/application/dto/auth"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
+)
+
+type ResetMFACommand struct {
+	UserID string `validate:"required,uuid"`
+}
+
+type ResetMFACommandHandler struct {
+	mfaService contracts.MFAService
+}
+
+func NewResetMFACommandHandler(mfaService contracts.MFAService) *ResetMFACommandHandler {
+	return &ResetMFACommandHandler{
+		mfaService: mfaService,
+	}
+}
+
+func (h *ResetMFACommandHandler) Handle(ctx context.Context, cmd ResetMFACommand) (*dto.StatusResponse, error) {
+	err := h.mfaService.ResetMFA(ctx, cmd.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.StatusResponse{
+		Status:  "success",
+		Message: "MFA has been reset",
+	}, nil
+}
@@ -0,0 +1,34 @@
+package commands
+
+import (
+	"context"
+
+	dto "github.com/tranvuongduy2003/go-mvc/internal/application/dto/auth"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
+)
+
+type RevertEmailChangeCommand struct {
+	Token string `validate:"required"`
+}
+
+type RevertEmailChangeCommandHandler struct {
+	emailChangeService contracts.EmailChangeService
+}
+
+func NewRevertEmailChangeCommandHandler(emailChangeService contracts.EmailChangeService) *RevertEmailChangeCommandHandler {
+	return &RevertEmailChangeCommandHandler{
+		emailChangeService: emailChangeService,
+	}
+}
+
+func (h *RevertEmailChangeCommandHandler) Handle(ctx context.Context, cmd RevertEmailChangeCommand) (*dto.StatusResponse, error) {
+	err := h.emailChangeService.RevertEmailChange(ctx, cmd.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.StatusResponse{
+		Status:  "success",
+		Message: "Email change reverted and all sessions signed out",
+	}, nil
+}
@@ -0,0 +1,35 @@
+package commands
+
+import (
+	"context"
+
+	dto "github.com/tranvuongduy2003/go-mvc/internal/application/dto/auth"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
+)
+
+type RevokeSessionCommand struct {
+	UserID    string `validate:"required"`
+	SessionID string `validate:"required,uuid"`
+}
+
+type RevokeSessionCommandHandler struct {
+	sessionService contracts.SessionManagementService
+}
+
+func NewRevokeSessionCommandHandler(sessionService contracts.SessionManagementService) *RevokeSessionCommandHandler {
+	return &RevokeSessionCommandHandler{
+		sessionService: sessionService,
+	}
+}
+
+func (h *RevokeSessionCommandHandler) Handle(ctx context.Context, cmd RevokeSessionCommand) (*dto.StatusResponse, error) {
+	err := h.sessionService.RevokeSession(ctx, cmd.UserID, cmd.SessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.StatusResponse{
+		Status:  "success",
+		Message: "Session revoked successfully",
+	}, nil
+}
@@ -0,0 +1,32 @@
+package commands
+
+import (
+	"context"
+
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
+	"github.com/tranvuongduy2003/go-mvc/pkg/oauth"
+)
+
+type RevokeTokenCommand struct {
+	ClientID     string
+	ClientSecret string
+	Token        string
+}
+
+type RevokeTokenCommandHandler struct {
+	introspectionService contracts.TokenIntrospectionService
+}
+
+func NewRevokeTokenCommandHandler(introspectionService contracts.TokenIntrospectionService) *RevokeTokenCommandHandler {
+	return &RevokeTokenCommandHandler{
+		introspectionService: introspectionService,
+	}
+}
+
+func (h *RevokeTokenCommandHandler) Handle(ctx context.Context, cmd RevokeTokenCommand) error {
+	if cmd.Token == "" {
+		return oauth.NewError(oauth.ErrorInvalidRequest, "token is required")
+	}
+
+	return h.introspectionService.RevokeToken(ctx, cmd.ClientID, cmd.ClientSecret, cmd.Token)
+}
@@ -0,0 +1,33 @@
+package commands
+
+import (
+	"context"
+
+	dto "github.com/tranvuongduy2003/go-mvc/internal/application/dto/auth"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
+)
+
+type SecureAccountCommand struct {
+	Token string `validate:"required"`
+}
+
+type SecureAccountCommandHandler struct {
+	loginHistoryService contracts.LoginHistoryService
+}
+
+func NewSecureAccountCommandHandler(loginHistoryService contracts.LoginHistoryService) *SecureAccountCommandHandler {
+	return &SecureAccountCommandHandler{
+		loginHistoryService: loginHistoryService,
+	}
+}
+
+func (h *SecureAccountCommandHandler) Handle(ctx context.Context, cmd SecureAccountCommand) (*dto.StatusResponse, error) {
+	if err := h.loginHistoryService.SecureAccount(ctx, cmd.Token); err != nil {
+		return nil, err
+	}
+
+	return &dto.StatusResponse{
+		Status:  "success",
+		Message: "All sessions signed out, please reset your password",
+	}, nil
+}
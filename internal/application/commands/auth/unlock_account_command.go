@@ -0,0 +1,34 @@
+package commands
+
+import (
+	"context"
+
+	dto "github.com/tranvuongduy2003/go-mvc/internal/application/dto/auth"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
+)
+
+type UnlockAccountCommand struct {
+	UserID string `validate:"required,uuid"`
+}
+
+type UnlockAccountCommandHandler struct {
+	lockoutService contracts.AccountLockoutService
+}
+
+func NewUnlockAccountCommandHandler(lockoutService contracts.AccountLockoutService) *UnlockAccountCommandHandler {
+	return &UnlockAccountCommandHandler{
+		lockoutService: lockoutService,
+	}
+}
+
+func (h *UnlockAccountCommandHandler) Handle(ctx context.Context, cmd UnlockAccountCommand) (*dto.StatusResponse, error) {
+	err := h.lockoutService.UnlockAccount(ctx, cmd.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.StatusResponse{
+		Status:  "success",
+		Message: "Account has been unlocked",
+	}, nil
+}
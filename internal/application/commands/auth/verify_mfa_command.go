@@ -0,0 +1,32 @@
+package commands
+
+import (
+	"context"
+
+	dto "github.com/tranvuongduy2003/go-mvc/internal/application/dto/auth"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
+)
+
+type VerifyMFACommand struct {
+	ChallengeToken string `validate:"required"`
+	Code           string `validate:"required"`
+}
+
+type VerifyMFACommandHandler struct {
+	mfaService contracts.MFAService
+}
+
+func NewVerifyMFACommandHandler(mfaService contracts.MFAService) *VerifyMFACommandHandler {
+	return &VerifyMFACommandHandler{
+		mfaService: mfaService,
+	}
+}
+
+func (h *VerifyMFACommandHandler) Handle(ctx context.Context, cmd VerifyMFACommand) (*dto.LoginResponse, error) {
+	authenticatedUser, err := h.mfaService.VerifyMFAChallenge(ctx, cmd.ChallengeToken, cmd.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.ToLoginResponse(authenticatedUser), nil
+}
@@ -0,0 +1,96 @@
+package commands
+
+import (
+	"context"
+	"time"
+
+	dto "github.com/tranvuongduy2003/go-mvc/internal/application/dto/rbac"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/auth"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/user"
+	apperrors "github.com/tranvuongduy2003/go-mvc/pkg/errors"
+)
+
+// AssignUserRoleCommand gives a user a role, permanently unless ExpiresAt
+// is set. Assigning a role the user already holds replaces its expiry.
+type AssignUserRoleCommand struct {
+	UserID     string
+	RoleID     string
+	AssignedBy *string
+	ExpiresAt  *time.Time
+}
+
+type AssignUserRoleCommandHandler struct {
+	userRepo     user.UserRepository
+	roleRepo     auth.RoleRepository
+	userRoleRepo auth.UserRoleRepository
+	authzService contracts.AuthorizationService
+}
+
+func NewAssignUserRoleCommandHandler(
+	userRepo user.UserRepository,
+	roleRepo auth.RoleRepository,
+	userRoleRepo auth.UserRoleRepository,
+	authzService contracts.AuthorizationService,
+) *AssignUserRoleCommandHandler {
+	return &AssignUserRoleCommandHandler{
+		userRepo:     userRepo,
+		roleRepo:     roleRepo,
+		userRoleRepo: userRoleRepo,
+		authzService: authzService,
+	}
+}
+
+func (h *AssignUserRoleCommandHandler) Handle(ctx context.Context, cmd AssignUserRoleCommand) (*dto.UserRoleDTO, error) {
+	if err := validateID("user", cmd.UserID); err != nil {
+		return nil, err
+	}
+	if cmd.ExpiresAt != nil && !cmd.ExpiresAt.After(time.Now()) {
+		return nil, apperrors.NewValidationError("expires_at must be in the future", nil)
+	}
+
+	userEntity, err := h.userRepo.GetByID(ctx, cmd.UserID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to get user", err)
+	}
+	if userEntity == nil {
+		return nil, apperrors.NewNotFoundError("user not found")
+	}
+
+	role, err := getRole(ctx, h.roleRepo, cmd.RoleID)
+	if err != nil {
+		return nil, err
+	}
+	if !role.IsActive() {
+		return nil, apperrors.NewValidationError("cannot assign an inactive role", nil)
+	}
+
+	existing, err := h.userRoleRepo.GetUserRole(ctx, cmd.UserID, cmd.RoleID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to get user role", err)
+	}
+
+	if existing == nil {
+		err = h.userRoleRepo.AssignRoleToUser(ctx, cmd.UserID, cmd.RoleID, cmd.AssignedBy, cmd.ExpiresAt)
+	} else {
+		err = h.userRoleRepo.SetExpiration(ctx, cmd.UserID, cmd.RoleID, cmd.ExpiresAt)
+		if err == nil && !existing.IsActive {
+			err = h.userRoleRepo.ActivateUserRole(ctx, existing.ID)
+		}
+	}
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to assign role", err)
+	}
+
+	if err := h.authzService.InvalidateUserAuthorization(ctx, cmd.UserID); err != nil {
+		return nil, err
+	}
+
+	assignment, err := h.userRoleRepo.GetUserRole(ctx, cmd.UserID, cmd.RoleID)
+	if err != nil || assignment == nil {
+		return nil, apperrors.NewInternalError("failed to get user role", err)
+	}
+
+	result := dto.ToUserRoleDTO(assignment)
+	return &result, nil
+}
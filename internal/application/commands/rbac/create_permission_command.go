@@ -0,0 +1,51 @@
+package commands
+
+import (
+	"context"
+	"strings"
+
+	dto "github.com/tranvuongduy2003/go-mvc/internal/application/dto/rbac"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/auth"
+	apperrors "github.com/tranvuongduy2003/go-mvc/pkg/errors"
+)
+
+type CreatePermissionCommand struct {
+	Resource    string
+	Action      string
+	Description string
+}
+
+type CreatePermissionCommandHandler struct {
+	permissionRepo auth.PermissionRepository
+}
+
+func NewCreatePermissionCommandHandler(permissionRepo auth.PermissionRepository) *CreatePermissionCommandHandler {
+	return &CreatePermissionCommandHandler{
+		permissionRepo: permissionRepo,
+	}
+}
+
+func (h *CreatePermissionCommandHandler) Handle(ctx context.Context, cmd CreatePermissionCommand) (*dto.PermissionDTO, error) {
+	resource := strings.ToLower(strings.TrimSpace(cmd.Resource))
+	action := strings.ToLower(strings.TrimSpace(cmd.Action))
+
+	permission, err := auth.NewPermission(resource+":"+action, resource, action, cmd.Description)
+	if err != nil {
+		return nil, apperrors.NewValidationError(err.Error(), err)
+	}
+
+	exists, err := h.permissionRepo.ExistsByResourceAndAction(ctx, resource, action)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to check permission", err)
+	}
+	if exists {
+		return nil, apperrors.NewConflictError("this permission already exists", nil)
+	}
+
+	if err := h.permissionRepo.Create(ctx, permission); err != nil {
+		return nil, apperrors.NewInternalError("failed to create permission", err)
+	}
+
+	result := dto.ToPermissionDTO(permission)
+	return &result, nil
+}
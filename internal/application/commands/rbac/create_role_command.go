@@ -0,0 +1,47 @@
+package commands
+
+import (
+	"context"
+	"strings"
+
+	dto "github.com/tranvuongduy2003/go-mvc/internal/application/dto/rbac"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/auth"
+	apperrors "github.com/tranvuongduy2003/go-mvc/pkg/errors"
+)
+
+type CreateRoleCommand struct {
+	Name        string
+	Description string
+}
+
+type CreateRoleCommandHandler struct {
+	roleRepo auth.RoleRepository
+}
+
+func NewCreateRoleCommandHandler(roleRepo auth.RoleRepository) *CreateRoleCommandHandler {
+	return &CreateRoleCommandHandler{
+		roleRepo: roleRepo,
+	}
+}
+
+func (h *CreateRoleCommandHandler) Handle(ctx context.Context, cmd CreateRoleCommand) (*dto.RoleDTO, error) {
+	role, err := auth.NewRole(strings.TrimSpace(cmd.Name), cmd.Description)
+	if err != nil {
+		return nil, apperrors.NewValidationError(err.Error(), err)
+	}
+
+	exists, err := h.roleRepo.ExistsByName(ctx, role.Name().String())
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to check role name", err)
+	}
+	if exists {
+		return nil, apperrors.NewConflictError("a role with this name already exists", nil)
+	}
+
+	if err := h.roleRepo.Create(ctx, role); err != nil {
+		return nil, apperrors.NewInternalError("failed to create role", err)
+	}
+
+	result := dto.ToRoleDTO(role)
+	return &result, nil
+}
@@ -0,0 +1,49 @@
+package commands
+
+import (
+	"context"
+
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/auth"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
+	apperrors "github.com/tranvuongduy2003/go-mvc/pkg/errors"
+)
+
+type DeletePermissionCommand struct {
+	PermissionID string
+}
+
+type DeletePermissionCommandHandler struct {
+	permissionRepo     auth.PermissionRepository
+	rolePermissionRepo auth.RolePermissionRepository
+	authzService       contracts.AuthorizationService
+}
+
+func NewDeletePermissionCommandHandler(
+	permissionRepo auth.PermissionRepository,
+	rolePermissionRepo auth.RolePermissionRepository,
+	authzService contracts.AuthorizationService,
+) *DeletePermissionCommandHandler {
+	return &DeletePermissionCommandHandler{
+		permissionRepo:     permissionRepo,
+		rolePermissionRepo: rolePermissionRepo,
+		authzService:       authzService,
+	}
+}
+
+func (h *DeletePermissionCommandHandler) Handle(ctx context.Context, cmd DeletePermissionCommand) error {
+	if _, err := getPermission(ctx, h.permissionRepo, cmd.PermissionID); err != nil {
+		return err
+	}
+
+	// Collected first: deleting the permission cascades to its grants
+	grants, err := h.rolePermissionRepo.GetPermissionRoles(ctx, cmd.PermissionID)
+	if err != nil {
+		return apperrors.NewInternalError("failed to get permission roles", err)
+	}
+
+	if err := h.permissionRepo.Delete(ctx, cmd.PermissionID); err != nil {
+		return apperrors.NewInternalError("failed to delete permission", err)
+	}
+
+	return invalidatePermissionHolders(ctx, h.authzService, grants)
+}
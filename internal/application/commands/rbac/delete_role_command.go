@@ -0,0 +1,66 @@
+package commands
+
+import (
+	"context"
+
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/auth"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
+	apperrors "github.com/tranvuongduy2003/go-mvc/pkg/errors"
+)
+
+type DeleteRoleCommand struct {
+	RoleID string
+}
+
+type DeleteRoleCommandHandler struct {
+	roleRepo     auth.RoleRepository
+	userRoleRepo auth.UserRoleRepository
+	authzService contracts.AuthorizationService
+}
+
+func NewDeleteRoleCommandHandler(
+	roleRepo auth.RoleRepository,
+	userRoleRepo auth.UserRoleRepository,
+	authzService contracts.AuthorizationService,
+) *DeleteRoleCommandHandler {
+	return &DeleteRoleCommandHandler{
+		roleRepo:     roleRepo,
+		userRoleRepo: userRoleRepo,
+		authzService: authzService,
+	}
+}
+
+func (h *DeleteRoleCommandHandler) Handle(ctx context.Context, cmd DeleteRoleCommand) error {
+	if _, err := getRole(ctx, h.roleRepo, cmd.RoleID); err != nil {
+		return err
+	}
+
+	// Collected first: deleting the role cascades to its assignments
+	holders, err := h.userRoleRepo.GetRoleUsers(ctx, cmd.RoleID)
+	if err != nil {
+		return apperrors.NewInternalError("failed to get role users", err)
+	}
+
+	// Inheriting roles become top-level and lose the role's permissions
+	descendantIDs, err := h.roleRepo.GetDescendantIDs(ctx, cmd.RoleID)
+	if err != nil {
+		return apperrors.NewInternalError("failed to get inheriting roles", err)
+	}
+
+	if err := h.roleRepo.Delete(ctx, cmd.RoleID); err != nil {
+		return apperrors.NewInternalError("failed to delete role", err)
+	}
+
+	for _, holder := range holders {
+		if err := h.authzService.InvalidateUserAuthorization(ctx, holder.UserID); err != nil {
+			return err
+		}
+	}
+	for _, descendantID := range descendantIDs {
+		if err := h.authzService.InvalidateRoleAuthorization(ctx, descendantID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
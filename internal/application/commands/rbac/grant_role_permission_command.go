@@ -0,0 +1,70 @@
+package commands
+
+import (
+	"context"
+
+	dto "github.com/tranvuongduy2003/go-mvc/internal/application/dto/rbac"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/auth"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
+	apperrors "github.com/tranvuongduy2003/go-mvc/pkg/errors"
+)
+
+type GrantRolePermissionCommand struct {
+	RoleID       string
+	PermissionID string
+	GrantedBy    *string
+}
+
+type GrantRolePermissionCommandHandler struct {
+	roleRepo           auth.RoleRepository
+	permissionRepo     auth.PermissionRepository
+	rolePermissionRepo auth.RolePermissionRepository
+	authzService       contracts.AuthorizationService
+}
+
+func NewGrantRolePermissionCommandHandler(
+	roleRepo auth.RoleRepository,
+	permissionRepo auth.PermissionRepository,
+	rolePermissionRepo auth.RolePermissionRepository,
+	authzService contracts.AuthorizationService,
+) *GrantRolePermissionCommandHandler {
+	return &GrantRolePermissionCommandHandler{
+		roleRepo:           roleRepo,
+		permissionRepo:     permissionRepo,
+		rolePermissionRepo: rolePermissionRepo,
+		authzService:       authzService,
+	}
+}
+
+func (h *GrantRolePermissionCommandHandler) Handle(ctx context.Context, cmd GrantRolePermissionCommand) (*dto.RolePermissionDTO, error) {
+	if _, err := getRole(ctx, h.roleRepo, cmd.RoleID); err != nil {
+		return nil, err
+	}
+	if _, err := getPermission(ctx, h.permissionRepo, cmd.PermissionID); err != nil {
+		return nil, err
+	}
+
+	exists, err := h.rolePermissionRepo.Exists(ctx, cmd.RoleID, cmd.PermissionID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to check role permission", err)
+	}
+	if exists {
+		return nil, apperrors.NewConflictError("the role already has this permission", nil)
+	}
+
+	if err := h.rolePermissionRepo.GrantPermissionToRole(ctx, cmd.RoleID, cmd.PermissionID, cmd.GrantedBy); err != nil {
+		return nil, apperrors.NewInternalError("failed to grant permission", err)
+	}
+
+	if err := h.authzService.InvalidateRoleAuthorization(ctx, cmd.RoleID); err != nil {
+		return nil, err
+	}
+
+	grant, err := h.rolePermissionRepo.GetRolePermission(ctx, cmd.RoleID, cmd.PermissionID)
+	if err != nil || grant == nil {
+		return nil, apperrors.NewInternalError("failed to get role permission", err)
+	}
+
+	result := dto.ToRolePermissionDTO(grant)
+	return &result, nil
+}
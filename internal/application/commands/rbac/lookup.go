@@ -0,0 +1,61 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/auth"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
+	apperrors "github.com/tranvuongduy2003/go-mvc/pkg/errors"
+)
+
+// validateID rejects malformed IDs up front, so they surface as a 400
+// instead of a database error.
+func validateID(kind, id string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return apperrors.NewValidationError(fmt.Sprintf("invalid %s ID", kind), err)
+	}
+	return nil
+}
+
+func getRole(ctx context.Context, roleRepo auth.RoleRepository, roleID string) (*auth.Role, error) {
+	if err := validateID("role", roleID); err != nil {
+		return nil, err
+	}
+
+	role, err := roleRepo.GetByID(ctx, roleID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to get role", err)
+	}
+	if role == nil {
+		return nil, apperrors.NewNotFoundError("role not found")
+	}
+	return role, nil
+}
+
+func getPermission(ctx context.Context, permissionRepo auth.PermissionRepository, permissionID string) (*auth.Permission, error) {
+	if err := validateID("permission", permissionID); err != nil {
+		return nil, err
+	}
+
+	permission, err := permissionRepo.GetByID(ctx, permissionID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to get permission", err)
+	}
+	if permission == nil {
+		return nil, apperrors.NewNotFoundError("permission not found")
+	}
+	return permission, nil
+}
+
+// invalidatePermissionHolders invalidates every user holding the
+// permission through any of the given roles.
+func invalidatePermissionHolders(ctx context.Context, authzService contracts.AuthorizationService, rolePermissions []*auth.RolePermission) error {
+	for _, rolePermission := range rolePermissions {
+		if err := authzService.InvalidateRoleAuthorization(ctx, rolePermission.RoleID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package commands
+
+import (
+	"context"
+
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/auth"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
+	apperrors "github.com/tranvuongduy2003/go-mvc/pkg/errors"
+)
+
+type RevokeRolePermissionCommand struct {
+	RoleID       string
+	PermissionID string
+}
+
+type RevokeRolePermissionCommandHandler struct {
+	rolePermissionRepo auth.RolePermissionRepository
+	authzService       contracts.AuthorizationService
+}
+
+func NewRevokeRolePermissionCommandHandler(
+	rolePermissionRepo auth.RolePermissionRepository,
+	authzService contracts.AuthorizationService,
+) *RevokeRolePermissionCommandHandler {
+	return &RevokeRolePermissionCommandHandler{
+		rolePermissionRepo: rolePermissionRepo,
+		authzService:       authzService,
+	}
+}
+
+func (h *RevokeRolePermissionCommandHandler) Handle(ctx context.Context, cmd RevokeRolePermissionCommand) error {
+	if err := validateID("role", cmd.RoleID); err != nil {
+		return err
+	}
+	if err := validateID("permission", cmd.PermissionID); err != nil {
+		return err
+	}
+
+	exists, err := h.rolePermissionRepo.Exists(ctx, cmd.RoleID, cmd.PermissionID)
+	if err != nil {
+		return apperrors.NewInternalError("failed to check role permission", err)
+	}
+	if !exists {
+		return apperrors.NewNotFoundError("the role does not have this permission")
+	}
+
+	if err := h.rolePermissionRepo.RevokePermissionFromRole(ctx, cmd.RoleID, cmd.PermissionID); err != nil {
+		return apperrors.NewInternalError("failed to revoke permission", err)
+	}
+
+	return h.authzService.InvalidateRoleAuthorization(ctx, cmd.RoleID)
+}
@@ -0,0 +1,49 @@
+package commands
+
+import (
+	"context"
+
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/auth"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
+	apperrors "github.com/tranvuongduy2003/go-mvc/pkg/errors"
+)
+
+type RevokeUserRoleCommand struct {
+	UserID string
+	RoleID string
+}
+
+type RevokeUserRoleCommandHandler struct {
+	userRoleRepo auth.UserRoleRepository
+	authzService contracts.AuthorizationService
+}
+
+func NewRevokeUserRoleCommandHandler(userRoleRepo auth.UserRoleRepository, authzService contracts.AuthorizationService) *RevokeUserRoleCommandHandler {
+	return &RevokeUserRoleCommandHandler{
+		userRoleRepo: userRoleRepo,
+		authzService: authzService,
+	}
+}
+
+func (h *RevokeUserRoleCommandHandler) Handle(ctx context.Context, cmd RevokeUserRoleCommand) error {
+	if err := validateID("user", cmd.UserID); err != nil {
+		return err
+	}
+	if err := validateID("role", cmd.RoleID); err != nil {
+		return err
+	}
+
+	exists, err := h.userRoleRepo.Exists(ctx, cmd.UserID, cmd.RoleID)
+	if err != nil {
+		return apperrors.NewInternalError("failed to check user role", err)
+	}
+	if !exists {
+		return apperrors.NewNotFoundError("the user does not have this role")
+	}
+
+	if err := h.userRoleRepo.RevokeRoleFromUser(ctx, cmd.UserID, cmd.RoleID); err != nil {
+		return apperrors.NewInternalError("failed to revoke role", err)
+	}
+
+	return h.authzService.InvalidateUserAuthorization(ctx, cmd.UserID)
+}
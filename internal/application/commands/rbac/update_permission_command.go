@@ -0,0 +1,74 @@
+package commands
+
+import (
+	"context"
+
+	dto "github.com/tranvuongduy2003/go-mvc/internal/application/dto/rbac"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/auth"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
+	apperrors "github.com/tranvuongduy2003/go-mvc/pkg/errors"
+)
+
+type UpdatePermissionCommand struct {
+	PermissionID string
+	Description  *string
+	IsActive     *bool
+}
+
+type UpdatePermissionCommandHandler struct {
+	permissionRepo     auth.PermissionRepository
+	rolePermissionRepo auth.RolePermissionRepository
+	authzService       contracts.AuthorizationService
+}
+
+func NewUpdatePermissionCommandHandler(
+	permissionRepo auth.PermissionRepository,
+	rolePermissionRepo auth.RolePermissionRepository,
+	authzService contracts.AuthorizationService,
+) *UpdatePermissionCommandHandler {
+	return &UpdatePermissionCommandHandler{
+		permissionRepo:     permissionRepo,
+		rolePermissionRepo: rolePermissionRepo,
+		authzService:       authzService,
+	}
+}
+
+func (h *UpdatePermissionCommandHandler) Handle(ctx context.Context, cmd UpdatePermissionCommand) (*dto.PermissionDTO, error) {
+	permission, err := getPermission(ctx, h.permissionRepo, cmd.PermissionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if cmd.Description != nil {
+		if err := permission.UpdateDescription(*cmd.Description); err != nil {
+			return nil, apperrors.NewValidationError(err.Error(), err)
+		}
+		if err := h.permissionRepo.Update(ctx, permission); err != nil {
+			return nil, apperrors.NewInternalError("failed to update permission", err)
+		}
+	}
+
+	if cmd.IsActive != nil && *cmd.IsActive != permission.IsActive() {
+		if *cmd.IsActive {
+			err = h.permissionRepo.Activate(ctx, cmd.PermissionID)
+			permission.Activate()
+		} else {
+			err = h.permissionRepo.Deactivate(ctx, cmd.PermissionID)
+			permission.Deactivate()
+		}
+		if err != nil {
+			return nil, apperrors.NewInternalError("failed to update permission", err)
+		}
+
+		grants, err := h.rolePermissionRepo.GetPermissionRoles(ctx, cmd.PermissionID)
+		if err != nil {
+			return nil, apperrors.NewInternalError("failed to get permission roles", err)
+		}
+		if err := invalidatePermissionHolders(ctx, h.authzService, grants); err != nil {
+			return nil, err
+		}
+	}
+
+	result := dto.ToPermissionDTO(permission)
+	return &result, nil
+}
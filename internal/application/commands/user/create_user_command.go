@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
 	"github.com/tranvuongduy2003/go-mvc/internal/domain/user"
 	apperrors "github.com/tranvuongduy2003/go-mvc/pkg/errors"
 )
@@ -12,7 +13,7 @@ type CreateUserCommand struct {
 	Email    string `json:"email" validate:"required,email"`
 	Name     string `json:"name" validate:"required,min=2,max=100"`
 	Phone    string `json:"phone" validate:"omitempty"`
-	Password string `json:"password" validate:"required,min=8"`
+	Password string `json:"password" validate:"required"`
 }
 
 func (c CreateUserCommand) Validate() error {
@@ -28,19 +29,22 @@ func (c CreateUserCommand) Validate() error {
 	if c.Password == "" {
 		return errors.New("password is required")
 	}
-	if len(c.Password) < 8 {
-		return errors.New("password must be at least 8 characters")
-	}
 	return nil
 }
 
 type CreateUserCommandHandler struct {
-	userRepo user.UserRepository
+	userRepo          user.UserRepository
+	passwordHasher    user.PasswordHasher
+	passwordPolicy    contracts.PasswordPolicyService
+	emailVerification contracts.EmailVerificationService
 }
 
-func NewCreateUserCommandHandler(userRepo user.UserRepository) *CreateUserCommandHandler {
+func NewCreateUserCommandHandler(userRepo user.UserRepository, passwordHasher user.PasswordHasher, passwordPolicy contracts.PasswordPolicyService, emailVerification contracts.EmailVerificationService) *CreateUserCommandHandler {
 	return &CreateUserCommandHandler{
-		userRepo: userRepo,
+		userRepo:          userRepo,
+		passwordHasher:    passwordHasher,
+		passwordPolicy:    passwordPolicy,
+		emailVerification: emailVerification,
 	}
 }
 
@@ -53,7 +57,14 @@ func (h *CreateUserCommandHandler) Handle(ctx context.Context, cmd CreateUserCom
 		return nil, apperrors.NewConflictError("User with email "+cmd.Email+" already exists", nil)
 	}
 
-	newUser, err := user.NewUser(cmd.Email, cmd.Name, cmd.Phone, cmd.Password)
+	if err := h.passwordPolicy.ValidatePassword(ctx, cmd.Password, contracts.PasswordSubject{
+		Email: cmd.Email,
+		Name:  cmd.Name,
+	}); err != nil {
+		return nil, err
+	}
+
+	newUser, err := user.NewUser(cmd.Email, cmd.Name, cmd.Phone, cmd.Password, h.passwordHasher)
 	if err != nil {
 		return nil, apperrors.NewValidationError(err.Error(), err)
 	}
@@ -62,5 +73,15 @@ func (h *CreateUserCommandHandler) Handle(ctx context.Context, cmd CreateUserCom
 		return nil, apperrors.NewInternalError("Failed to create user", err)
 	}
 
+	// Seeds the history so the owner cannot switch back to this password
+	// later. A missing entry only weakens that check, so it does not fail the
+	// creation.
+	_ = h.passwordPolicy.RecordPassword(ctx, newUser.ID(), newUser.HashedPassword())
+
+	// The owner proves the address the same way as after registering. The
+	// account exists either way; a failed send can be retried through the
+	// resend endpoint.
+	_ = h.emailVerification.ResendVerificationEmail(ctx, newUser.Email())
+
 	return newUser, nil
 }
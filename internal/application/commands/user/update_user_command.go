@@ -5,6 +5,7 @@ import (
 	"errors"
 
 	"github.com/tranvuongduy2003/go-mvc/internal/domain/user"
+	apperrors "github.com/tranvuongduy2003/go-mvc/pkg/errors"
 )
 
 type UpdateUserCommand struct {
@@ -37,8 +38,17 @@ func (h *UpdateUserCommandHandler) Handle(ctx context.Context, cmd UpdateUserCom
 	}
 
 	if err := h.userRepo.Update(ctx, existingUser); err != nil {
-		return nil, err
+		return nil, saveUserError(err)
 	}
 
 	return existingUser, nil
 }
+
+// saveUserError reports a save that lost the race against another request
+// as a conflict the client can retry.
+func saveUserError(err error) error {
+	if errors.Is(err, user.ErrConcurrentUpdate) {
+		return apperrors.NewConflictError("User was changed by another request, please try again", err)
+	}
+	return apperrors.NewInternalError("Failed to update user", err)
+}
@@ -64,7 +64,7 @@ func (h *UploadAvatarCommandHandler) Handle(ctx context.Context, cmd UploadAvata
 
 	if err := h.userRepo.Update(ctx, user); err != nil {
 		_ = h.fileStorageService.Delete(ctx, fileKey)
-		return userDto.UserResponse{}, apperrors.NewInternalError("Failed to update user", err)
+		return userDto.UserResponse{}, saveUserError(err)
 	}
 
 	avatarEvent := events.NewUserAvatarUploadedEvent(
@@ -3,19 +3,24 @@ package dto
 import (
 	"time"
 
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/auth"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
 	"github.com/tranvuongduy2003/go-mvc/internal/domain/user"
+	"github.com/tranvuongduy2003/go-mvc/pkg/webauthn"
 )
 
 type LoginRequest struct {
-	Email    string `json:"email" validate:"required,email"`
-	Password string `json:"password" validate:"required"`
+	Email      string `json:"email" validate:"required,email"`
+	Password   string `json:"password" validate:"required"`
+	DeviceName string `json:"device_name" validate:"omitempty,max=255"`
 }
 
 type RegisterRequest struct {
-	Email    string `json:"email" validate:"required,email"`
-	Name     string `json:"name" validate:"required,min=2,max=100"`
-	Phone    string `json:"phone" validate:"omitempty"`
-	Password string `json:"password" validate:"required,min=8"`
+	Email      string `json:"email" validate:"required,email"`
+	Name       string `json:"name" validate:"required,min=2,max=100"`
+	Phone      string `json:"phone" validate:"omitempty"`
+	Password   string `json:"password" validate:"required"`
+	DeviceName string `json:"device_name" validate:"omitempty,max=255"`
 }
 
 type RefreshTokenRequest struct {
@@ -24,7 +29,7 @@ type RefreshTokenRequest struct {
 
 type ChangePasswordRequest struct {
 	OldPassword string `json:"old_password" validate:"required"`
-	NewPassword string `json:"new_password" validate:"required,min=8"`
+	NewPassword string `json:"new_password" validate:"required"`
 }
 
 type ResetPasswordRequest struct {
@@ -33,7 +38,7 @@ type ResetPasswordRequest struct {
 
 type ConfirmPasswordResetRequest struct {
 	Token       string `json:"token" validate:"required"`
-	NewPassword string `json:"new_password" validate:"required,min=8"`
+	NewPassword string `json:"new_password" validate:"required"`
 }
 
 type VerifyEmailRequest struct {
@@ -44,6 +49,45 @@ type ResendVerificationRequest struct {
 	Email string `json:"email" validate:"required,email"`
 }
 
+type ChangeEmailRequest struct {
+	NewEmail string `json:"new_email" validate:"required,email"`
+	Password string `json:"password" validate:"required"`
+}
+
+type EmailChangeTokenRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// SecureAccountRequest carries the token from the "this wasn't me" link of
+// a login alert.
+type SecureAccountRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+type MagicLinkRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type ConsumeMagicLinkRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+type MFACodeRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+type VerifyMFARequest struct {
+	ChallengeToken string `json:"challenge_token" validate:"required"`
+	Code           string `json:"code" validate:"required"`
+}
+
+// ReauthenticateRequest takes the account password, a code from the user's
+// authenticator app, or both. The code is required once MFA is enabled.
+type ReauthenticateRequest struct {
+	Password string `json:"password,omitempty"`
+	Code     string `json:"code,omitempty"`
+}
+
 type TokensDTO struct {
 	AccessToken           string    `json:"access_token"`
 	RefreshToken          string    `json:"refresh_token"`
@@ -53,33 +97,65 @@ type TokensDTO struct {
 }
 
 type AuthUserDTO struct {
-	ID        string    `json:"id"`
-	Email     string    `json:"email"`
-	Name      string    `json:"name"`
-	Phone     string    `json:"phone,omitempty"`
-	IsActive  bool      `json:"is_active"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID              string     `json:"id"`
+	Email           string     `json:"email"`
+	Name            string     `json:"name"`
+	Phone           string     `json:"phone,omitempty"`
+	IsActive        bool       `json:"is_active"`
+	MFAEnabled      bool       `json:"mfa_enabled"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+type MFAChallengeDTO struct {
+	ChallengeToken string    `json:"challenge_token"`
+	ExpiresAt      time.Time `json:"expires_at"`
 }
 
+// LoginResponse carries Tokens, or an MFAChallenge when MFARequired is set.
 type LoginResponse struct {
-	User   AuthUserDTO `json:"user"`
-	Tokens TokensDTO   `json:"tokens"`
+	User         AuthUserDTO      `json:"user"`
+	Tokens       *TokensDTO       `json:"tokens,omitempty"`
+	MFARequired  bool             `json:"mfa_required"`
+	MFAChallenge *MFAChallengeDTO `json:"mfa_challenge,omitempty"`
 }
 
+type MFAEnrollmentResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// RegisterResponse carries no tokens when the account must verify its email
+// before signing in.
 type RegisterResponse struct {
-	User   AuthUserDTO `json:"user"`
-	Tokens TokensDTO   `json:"tokens"`
+	User                 AuthUserDTO `json:"user"`
+	Tokens               *TokensDTO  `json:"tokens,omitempty"`
+	VerificationRequired bool        `json:"verification_required"`
 }
 
 type RefreshTokenResponse struct {
 	Tokens TokensDTO `json:"tokens"`
 }
 
+// UserProfileResponse reports Impersonation only while an admin is acting
+// as the user.
 type UserProfileResponse struct {
-	User        AuthUserDTO         `json:"user"`
-	Roles       []string            `json:"roles"`
-	Permissions []PermissionInfoDTO `json:"permissions"`
+	User          AuthUserDTO         `json:"user"`
+	Roles         []string            `json:"roles"`
+	Permissions   []PermissionInfoDTO `json:"permissions"`
+	Impersonation *ImpersonationDTO   `json:"impersonation,omitempty"`
+}
+
+type ImpersonationDTO struct {
+	Active     bool   `json:"active"`
+	ActorID    string `json:"actor_id"`
+	ActorEmail string `json:"actor_email,omitempty"`
+	ActorName  string `json:"actor_name,omitempty"`
 }
 
 type PermissionInfoDTO struct {
@@ -150,6 +226,109 @@ type GetEffectivePermissionsResponse struct {
 	Permissions []PermissionInfoDTO `json:"permissions"`
 }
 
+type BeginPasskeyLoginRequest struct {
+	Email string `json:"email" validate:"omitempty,email"`
+}
+
+type FinishPasskeyRegistrationRequest struct {
+	Name       string                       `json:"name" validate:"omitempty,max=100"`
+	Credential webauthn.AttestationResponse `json:"credential" validate:"required"`
+}
+
+type FinishPasskeyLoginRequest struct {
+	Credential webauthn.AssertionResponse `json:"credential" validate:"required"`
+	DeviceName string                     `json:"device_name" validate:"omitempty,max=255"`
+}
+
+type PasskeyCreationOptionsResponse struct {
+	PublicKey *webauthn.CreationOptions `json:"publicKey"`
+}
+
+type PasskeyRequestOptionsResponse struct {
+	PublicKey *webauthn.RequestOptions `json:"publicKey"`
+}
+
+type PasskeyDTO struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Transports []string   `json:"transports"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+}
+
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" validate:"required,max=100"`
+	Scopes    []string   `json:"scopes" validate:"required,min=1"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+type APIKeyDTO struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreatedAPIKeyResponse is the only response that ever contains the key.
+type CreatedAPIKeyResponse struct {
+	APIKeyDTO
+	Key string `json:"key"`
+}
+
+// ImpersonationResponse carries a short-lived access token for acting as
+// User. No refresh token is issued.
+type ImpersonationResponse struct {
+	User                 AuthUserDTO `json:"user"`
+	AccessToken          string      `json:"access_token"`
+	AccessTokenExpiresAt time.Time   `json:"access_token_expires_at"`
+	TokenType            string      `json:"token_type"`
+	SessionID            string      `json:"session_id"`
+}
+
+// ReauthenticationResponse carries an access token with a fresh auth_time.
+// The refresh token held by the client stays valid.
+type ReauthenticationResponse struct {
+	AccessToken          string    `json:"access_token"`
+	AccessTokenExpiresAt time.Time `json:"access_token_expires_at"`
+	TokenType            string    `json:"token_type"`
+	AuthTime             time.Time `json:"auth_time"`
+	AuthMethods          []string  `json:"amr"`
+}
+
+// LoginAttemptDTO is one entry of the login history. Location fields are
+// empty when the address could not be placed.
+type LoginAttemptDTO struct {
+	ID            string    `json:"id"`
+	Success       bool      `json:"success"`
+	FailureReason string    `json:"failure_reason,omitempty"`
+	Methods       []string  `json:"methods,omitempty"`
+	IPAddress     string    `json:"ip_address,omitempty"`
+	UserAgent     string    `json:"user_agent,omitempty"`
+	Browser       string    `json:"browser,omitempty"`
+	OS            string    `json:"os,omitempty"`
+	DeviceType    string    `json:"device_type,omitempty"`
+	CountryCode   string    `json:"country_code,omitempty"`
+	Country       string    `json:"country,omitempty"`
+	City          string    `json:"city,omitempty"`
+	Latitude      *float64  `json:"latitude,omitempty"`
+	Longitude     *float64  `json:"longitude,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type SessionDTO struct {
+	ID         string    `json:"id"`
+	DeviceName string    `json:"device_name,omitempty"`
+	UserAgent  string    `json:"user_agent,omitempty"`
+	IPAddress  string    `json:"ip_address,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	IsCurrent  bool      `json:"is_current"`
+}
+
 type MessageResponse struct {
 	Message string `json:"message"`
 }
@@ -159,15 +338,122 @@ type StatusResponse struct {
 	Message string `json:"message,omitempty"`
 }
 
+// ChangePasswordResponse carries the new tokens of the session the password
+// was changed from; every other session has been signed out.
+type ChangePasswordResponse struct {
+	Tokens *TokensDTO `json:"tokens,omitempty"`
+}
+
 func ToAuthUserDTO(domainUser *user.User) AuthUserDTO {
 	return AuthUserDTO{
-		ID:        domainUser.ID(),
-		Email:     domainUser.Email(),
-		Name:      domainUser.Name(),
-		Phone:     domainUser.Phone(),
-		IsActive:  domainUser.IsActive(),
-		CreatedAt: domainUser.CreatedAt(),
-		UpdatedAt: domainUser.UpdatedAt(),
+		ID:              domainUser.ID(),
+		Email:           domainUser.Email(),
+		Name:            domainUser.Name(),
+		Phone:           domainUser.Phone(),
+		IsActive:        domainUser.IsActive(),
+		MFAEnabled:      domainUser.MFA().IsEnabled(),
+		EmailVerifiedAt: domainUser.EmailVerifiedAt(),
+		CreatedAt:       domainUser.CreatedAt(),
+		UpdatedAt:       domainUser.UpdatedAt(),
+	}
+}
+
+func ToLoginResponse(authenticatedUser *contracts.AuthenticatedUser) *LoginResponse {
+	response := &LoginResponse{
+		User: ToAuthUserDTO(authenticatedUser.User),
+	}
+
+	if authenticatedUser.MFAChallenge != nil {
+		response.MFARequired = true
+		response.MFAChallenge = &MFAChallengeDTO{
+			ChallengeToken: authenticatedUser.MFAChallenge.Token,
+			ExpiresAt:      authenticatedUser.MFAChallenge.ExpiresAt,
+		}
+		return response
+	}
+
+	response.Tokens = &TokensDTO{
+		AccessToken:           authenticatedUser.Tokens.AccessToken,
+		RefreshToken:          authenticatedUser.Tokens.RefreshToken,
+		AccessTokenExpiresAt:  authenticatedUser.Tokens.AccessTokenExpiresAt,
+		RefreshTokenExpiresAt: authenticatedUser.Tokens.RefreshTokenExpiresAt,
+		TokenType:             authenticatedUser.Tokens.TokenType,
+	}
+	return response
+}
+
+func ToPasskeyDTO(credential *auth.WebAuthnCredential) PasskeyDTO {
+	return PasskeyDTO{
+		ID:         credential.ID,
+		Name:       credential.Name,
+		Transports: credential.Transports,
+		CreatedAt:  credential.CreatedAt,
+		LastUsedAt: credential.LastUsedAt,
+	}
+}
+
+func ToAPIKeyDTO(apiKey *auth.APIKey) APIKeyDTO {
+	return APIKeyDTO{
+		ID:         apiKey.ID,
+		Name:       apiKey.Name,
+		Prefix:     apiKey.Prefix,
+		Scopes:     apiKey.Scopes,
+		ExpiresAt:  apiKey.ExpiresAt,
+		LastUsedAt: apiKey.LastUsedAt,
+		CreatedAt:  apiKey.CreatedAt,
+	}
+}
+
+func ToImpersonationResponse(impersonation *contracts.Impersonation) *ImpersonationResponse {
+	return &ImpersonationResponse{
+		User:                 ToAuthUserDTO(impersonation.User),
+		AccessToken:          impersonation.AccessToken,
+		AccessTokenExpiresAt: impersonation.ExpiresAt,
+		TokenType:            impersonation.TokenType,
+		SessionID:            impersonation.SessionID,
+	}
+}
+
+func ToReauthenticationResponse(token *contracts.ElevatedToken) *ReauthenticationResponse {
+	return &ReauthenticationResponse{
+		AccessToken:          token.AccessToken,
+		AccessTokenExpiresAt: token.ExpiresAt,
+		TokenType:            token.TokenType,
+		AuthTime:             token.AuthTime,
+		AuthMethods:          token.AuthMethods,
+	}
+}
+
+func ToLoginAttemptDTO(attempt *auth.LoginAttempt) LoginAttemptDTO {
+	return LoginAttemptDTO{
+		ID:            attempt.ID,
+		Success:       attempt.Success,
+		FailureReason: attempt.FailureReason,
+		Methods:       attempt.Methods,
+		IPAddress:     attempt.IPAddress,
+		UserAgent:     attempt.UserAgent,
+		Browser:       attempt.Browser,
+		OS:            attempt.OS,
+		DeviceType:    attempt.DeviceType,
+		CountryCode:   attempt.CountryCode,
+		Country:       attempt.Country,
+		City:          attempt.City,
+		Latitude:      attempt.Latitude,
+		Longitude:     attempt.Longitude,
+		CreatedAt:     attempt.CreatedAt,
+	}
+}
+
+func ToSessionDTO(session *auth.Session, currentSessionID string) SessionDTO {
+	return SessionDTO{
+		ID:         session.ID,
+		DeviceName: session.DeviceName,
+		UserAgent:  session.UserAgent,
+		IPAddress:  session.IPAddress,
+		CreatedAt:  session.CreatedAt,
+		LastSeenAt: session.LastSeenAt,
+		ExpiresAt:  session.ExpiresAt,
+		IsCurrent:  session.ID == currentSessionID,
 	}
 }
 
@@ -0,0 +1,173 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/auth"
+)
+
+type CreateRoleRequest struct {
+	Name        string `json:"name" validate:"required,min=2,max=50"`
+	Description string `json:"description" validate:"max=255"`
+}
+
+// UpdateRoleRequest changes only the fields that are present. Role names
+// are immutable because code and other services refer to roles by name.
+type UpdateRoleRequest struct {
+	Description *string `json:"description,omitempty" validate:"omitempty,max=255"`
+	IsActive    *bool   `json:"is_active,omitempty"`
+}
+
+// SetRoleParentRequest makes the role inherit every permission of the
+// parent role and, transitively, of the parent's ancestors.
+type SetRoleParentRequest struct {
+	ParentID string `json:"parent_id" validate:"required,uuid"`
+}
+
+type CreatePermissionRequest struct {
+	Resource    string `json:"resource" validate:"required,min=2,max=50"`
+	Action      string `json:"action" validate:"required,min=2,max=50"`
+	Description string `json:"description" validate:"max=255"`
+}
+
+// UpdatePermissionRequest changes only the fields that are present.
+// Resource and action are immutable: they are the permission's name.
+type UpdatePermissionRequest struct {
+	Description *string `json:"description,omitempty" validate:"omitempty,max=255"`
+	IsActive    *bool   `json:"is_active,omitempty"`
+}
+
+type GrantPermissionRequest struct {
+	PermissionID string `json:"permission_id" validate:"required,uuid"`
+}
+
+type AssignRoleRequest struct {
+	RoleID    string     `json:"role_id" validate:"required,uuid"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+type RoleDTO struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	ParentID    *string   `json:"parent_id,omitempty"`
+	IsActive    bool      `json:"is_active"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type PermissionDTO struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Resource    string    `json:"resource"`
+	Action      string    `json:"action"`
+	Description string    `json:"description,omitempty"`
+	IsActive    bool      `json:"is_active"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type RolePermissionDTO struct {
+	ID           string    `json:"id"`
+	RoleID       string    `json:"role_id"`
+	PermissionID string    `json:"permission_id"`
+	GrantedBy    *string   `json:"granted_by,omitempty"`
+	GrantedAt    time.Time `json:"granted_at"`
+	IsActive     bool      `json:"is_active"`
+}
+
+type UserRoleDTO struct {
+	ID         string     `json:"id"`
+	UserID     string     `json:"user_id"`
+	RoleID     string     `json:"role_id"`
+	AssignedBy *string    `json:"assigned_by,omitempty"`
+	AssignedAt time.Time  `json:"assigned_at"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	IsActive   bool       `json:"is_active"`
+}
+
+func ToRoleDTO(role *auth.Role) RoleDTO {
+	var parentID *string
+	if role.ParentID() != nil {
+		id := role.ParentID().String()
+		parentID = &id
+	}
+
+	return RoleDTO{
+		ID:          role.ID().String(),
+		Name:        role.Name().String(),
+		Description: role.Description(),
+		ParentID:    parentID,
+		IsActive:    role.IsActive(),
+		CreatedAt:   role.CreatedAt(),
+		UpdatedAt:   role.UpdatedAt(),
+	}
+}
+
+func ToRoleDTOs(roles []*auth.Role) []RoleDTO {
+	result := make([]RoleDTO, len(roles))
+	for i, role := range roles {
+		result[i] = ToRoleDTO(role)
+	}
+	return result
+}
+
+func ToPermissionDTO(permission *auth.Permission) PermissionDTO {
+	return PermissionDTO{
+		ID:          permission.ID().String(),
+		Name:        permission.Name().String(),
+		Resource:    permission.Resource().String(),
+		Action:      permission.Action().String(),
+		Description: permission.Description(),
+		IsActive:    permission.IsActive(),
+		CreatedAt:   permission.CreatedAt(),
+		UpdatedAt:   permission.UpdatedAt(),
+	}
+}
+
+func ToPermissionDTOs(permissions []*auth.Permission) []PermissionDTO {
+	result := make([]PermissionDTO, len(permissions))
+	for i, permission := range permissions {
+		result[i] = ToPermissionDTO(permission)
+	}
+	return result
+}
+
+func ToRolePermissionDTO(rolePermission *auth.RolePermission) RolePermissionDTO {
+	return RolePermissionDTO{
+		ID:           rolePermission.ID,
+		RoleID:       rolePermission.RoleID,
+		PermissionID: rolePermission.PermissionID,
+		GrantedBy:    rolePermission.GrantedBy,
+		GrantedAt:    rolePermission.GrantedAt,
+		IsActive:     rolePermission.IsActive,
+	}
+}
+
+func ToRolePermissionDTOs(rolePermissions []*auth.RolePermission) []RolePermissionDTO {
+	result := make([]RolePermissionDTO, len(rolePermissions))
+	for i, rolePermission := range rolePermissions {
+		result[i] = ToRolePermissionDTO(rolePermission)
+	}
+	return result
+}
+
+func ToUserRoleDTO(userRole *auth.UserRole) UserRoleDTO {
+	return UserRoleDTO{
+		ID:         userRole.ID,
+		UserID:     userRole.UserID,
+		RoleID:     userRole.RoleID,
+		AssignedBy: userRole.AssignedBy,
+		AssignedAt: userRole.AssignedAt,
+		ExpiresAt:  userRole.ExpiresAt,
+		IsActive:   userRole.IsActive,
+	}
+}
+
+func ToUserRoleDTOs(userRoles []*auth.UserRole) []UserRoleDTO {
+	result := make([]UserRoleDTO, len(userRoles))
+	for i, userRole := range userRoles {
+		result[i] = ToUserRoleDTO(userRole)
+	}
+	return result
+}
@@ -0,0 +1,62 @@
+package event_handlers
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/messaging"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/shared/events"
+)
+
+// AuthorizationEventHandler evicts users from this instance's authorization
+// cache when another instance, or this one, reports a change.
+type AuthorizationEventHandler struct {
+	cache   contracts.AuthorizationCache
+	metrics contracts.AuthorizationCacheMetrics
+	logger  *zap.Logger
+}
+
+func NewAuthorizationEventHandler(cache contracts.AuthorizationCache, metrics contracts.AuthorizationCacheMetrics, logger *zap.Logger) *AuthorizationEventHandler {
+	return &AuthorizationEventHandler{
+		cache:   cache,
+		metrics: metrics,
+		logger:  logger,
+	}
+}
+
+func (h *AuthorizationEventHandler) HandleAuthorizationInvalidated(ctx context.Context, event messaging.Event) error {
+	var invalidated events.AuthorizationInvalidatedEvent
+	data, err := event.EventData()
+	if err != nil {
+		h.logger.Error("Failed to get event data", zap.Error(err))
+		return err
+	}
+
+	if err := json.Unmarshal(data, &invalidated); err != nil {
+		h.logger.Error("Failed to unmarshal authorization invalidated event", zap.Error(err))
+		return err
+	}
+
+	h.cache.EvictUsers(invalidated.UserIDs, invalidated.OccurredAt)
+	h.metrics.ObserveInvalidationLag(time.Since(invalidated.OccurredAt))
+
+	h.logger.Debug("Authorization cache entries evicted",
+		zap.Int("users", len(invalidated.UserIDs)),
+		zap.String("role_id", invalidated.RoleID))
+
+	return nil
+}
+
+func (h *AuthorizationEventHandler) SetupEventSubscriptions(eventBus messaging.EventBus) error {
+	if _, err := eventBus.SubscribeToEvent(events.AuthorizationInvalidatedEventType, h.HandleAuthorizationInvalidated); err != nil {
+		h.logger.Error("Failed to subscribe to authorization.invalidated events", zap.Error(err))
+		return err
+	}
+
+	h.logger.Info("Authorization event subscriptions setup successfully")
+	return nil
+}
@@ -0,0 +1,37 @@
+package auth
+
+import (
+	"context"
+
+	dto "github.com/tranvuongduy2003/go-mvc/internal/application/dto/auth"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
+)
+
+type GetLoginHistoryQuery struct {
+	UserID string `validate:"required"`
+	Limit  int
+}
+
+type GetLoginHistoryQueryHandler struct {
+	loginHistoryService contracts.LoginHistoryService
+}
+
+func NewGetLoginHistoryQueryHandler(loginHistoryService contracts.LoginHistoryService) *GetLoginHistoryQueryHandler {
+	return &GetLoginHistoryQueryHandler{
+		loginHistoryService: loginHistoryService,
+	}
+}
+
+func (h *GetLoginHistoryQueryHandler) Handle(ctx context.Context, query GetLoginHistoryQuery) ([]dto.LoginAttemptDTO, error) {
+	attempts, err := h.loginHistoryService.GetLoginHistory(ctx, query.UserID, query.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	attemptDTOs := make([]dto.LoginAttemptDTO, len(attempts))
+	for i, attempt := range attempts {
+		attemptDTOs[i] = dto.ToLoginAttemptDTO(attempt)
+	}
+
+	return attemptDTOs, nil
+}
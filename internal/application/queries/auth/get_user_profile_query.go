@@ -10,8 +10,11 @@ import (
 	apperrors "github.com/tranvuongduy2003/go-mvc/pkg/errors"
 )
 
+// GetUserProfileQuery sets ImpersonatorID when the profile is requested
+// through an impersonation token.
 type GetUserProfileQuery struct {
-	UserID string `validate:"required"`
+	UserID         string `validate:"required"`
+	ImpersonatorID string
 }
 
 type GetUserProfileQueryHandler struct {
@@ -69,9 +72,27 @@ func (h *GetUserProfileQueryHandler) Handle(ctx context.Context, query GetUserPr
 		}
 	}
 
-	return &dto.UserProfileResponse{
+	response := &dto.UserProfileResponse{
 		User:        dto.ToAuthUserDTO(user),
 		Roles:       roleNames,
 		Permissions: permissionInfos,
-	}, nil
+	}
+
+	if query.ImpersonatorID != "" {
+		impersonation := &dto.ImpersonationDTO{
+			Active:  true,
+			ActorID: query.ImpersonatorID,
+		}
+		actor, err := h.userRepo.GetByID(ctx, query.ImpersonatorID)
+		if err != nil {
+			return nil, err
+		}
+		if actor != nil {
+			impersonation.ActorEmail = actor.Email()
+			impersonation.ActorName = actor.Name()
+		}
+		response.Impersonation = impersonation
+	}
+
+	return response, nil
 }
@@ -0,0 +1,32 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
+	"github.com/tranvuongduy2003/go-mvc/pkg/oauth"
+)
+
+type IntrospectTokenQuery struct {
+	ClientID     string
+	ClientSecret string
+	Token        string
+}
+
+type IntrospectTokenQueryHandler struct {
+	introspectionService contracts.TokenIntrospectionService
+}
+
+func NewIntrospectTokenQueryHandler(introspectionService contracts.TokenIntrospectionService) *IntrospectTokenQueryHandler {
+	return &IntrospectTokenQueryHandler{
+		introspectionService: introspectionService,
+	}
+}
+
+func (h *IntrospectTokenQueryHandler) Handle(ctx context.Context, query IntrospectTokenQuery) (*contracts.TokenIntrospection, error) {
+	if query.Token == "" {
+		return nil, oauth.NewError(oauth.ErrorInvalidRequest, "token is required")
+	}
+
+	return h.introspectionService.IntrospectToken(ctx, query.ClientID, query.ClientSecret, query.Token)
+}
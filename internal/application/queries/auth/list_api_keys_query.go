@@ -0,0 +1,36 @@
+package auth
+
+import (
+	"context"
+
+	dto "github.com/tranvuongduy2003/go-mvc/internal/application/dto/auth"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
+)
+
+type ListAPIKeysQuery struct {
+	UserID string `validate:"required"`
+}
+
+type ListAPIKeysQueryHandler struct {
+	apiKeyService contracts.APIKeyService
+}
+
+func NewListAPIKeysQueryHandler(apiKeyService contracts.APIKeyService) *ListAPIKeysQueryHandler {
+	return &ListAPIKeysQueryHandler{
+		apiKeyService: apiKeyService,
+	}
+}
+
+func (h *ListAPIKeysQueryHandler) Handle(ctx context.Context, query ListAPIKeysQuery) ([]dto.APIKeyDTO, error) {
+	keys, err := h.apiKeyService.ListAPIKeys(ctx, query.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	apiKeys := make([]dto.APIKeyDTO, len(keys))
+	for i, key := range keys {
+		apiKeys[i] = dto.ToAPIKeyDTO(key)
+	}
+
+	return apiKeys, nil
+}
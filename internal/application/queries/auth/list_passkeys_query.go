@@ -0,0 +1,36 @@
+package auth
+
+import (
+	"context"
+
+	dto "github.com/tranvuongduy2003/go-mvc/internal/application/dto/auth"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
+)
+
+type ListPasskeysQuery struct {
+	UserID string `validate:"required"`
+}
+
+type ListPasskeysQueryHandler struct {
+	passkeyService contracts.PasskeyService
+}
+
+func NewListPasskeysQueryHandler(passkeyService contracts.PasskeyService) *ListPasskeysQueryHandler {
+	return &ListPasskeysQueryHandler{
+		passkeyService: passkeyService,
+	}
+}
+
+func (h *ListPasskeysQueryHandler) Handle(ctx context.Context, query ListPasskeysQuery) ([]dto.PasskeyDTO, error) {
+	credentials, err := h.passkeyService.ListPasskeys(ctx, query.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	passkeys := make([]dto.PasskeyDTO, len(credentials))
+	for i, credential := range credentials {
+		passkeys[i] = dto.ToPasskeyDTO(credential)
+	}
+
+	return passkeys, nil
+}
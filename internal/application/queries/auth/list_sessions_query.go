@@ -0,0 +1,37 @@
+package auth
+
+import (
+	"context"
+
+	dto "github.com/tranvuongduy2003/go-mvc/internal/application/dto/auth"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
+)
+
+type ListSessionsQuery struct {
+	UserID           string `validate:"required"`
+	CurrentSessionID string
+}
+
+type ListSessionsQueryHandler struct {
+	sessionService contracts.SessionManagementService
+}
+
+func NewListSessionsQueryHandler(sessionService contracts.SessionManagementService) *ListSessionsQueryHandler {
+	return &ListSessionsQueryHandler{
+		sessionService: sessionService,
+	}
+}
+
+func (h *ListSessionsQueryHandler) Handle(ctx context.Context, query ListSessionsQuery) ([]dto.SessionDTO, error) {
+	sessions, err := h.sessionService.ListSessions(ctx, query.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionDTOs := make([]dto.SessionDTO, len(sessions))
+	for i, session := range sessions {
+		sessionDTOs[i] = dto.ToSessionDTO(session, query.CurrentSessionID)
+	}
+
+	return sessionDTOs, nil
+}
@@ -0,0 +1,40 @@
+package rbac
+
+import (
+	"context"
+
+	dto "github.com/tranvuongduy2003/go-mvc/internal/application/dto/rbac"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/auth"
+	apperrors "github.com/tranvuongduy2003/go-mvc/pkg/errors"
+)
+
+type GetPermissionQuery struct {
+	PermissionID string
+}
+
+type GetPermissionQueryHandler struct {
+	permissionRepo auth.PermissionRepository
+}
+
+func NewGetPermissionQueryHandler(permissionRepo auth.PermissionRepository) *GetPermissionQueryHandler {
+	return &GetPermissionQueryHandler{
+		permissionRepo: permissionRepo,
+	}
+}
+
+func (h *GetPermissionQueryHandler) Handle(ctx context.Context, query GetPermissionQuery) (*dto.PermissionDTO, error) {
+	if err := validateID("permission", query.PermissionID); err != nil {
+		return nil, err
+	}
+
+	permission, err := h.permissionRepo.GetByID(ctx, query.PermissionID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to get permission", err)
+	}
+	if permission == nil {
+		return nil, apperrors.NewNotFoundError("permission not found")
+	}
+
+	result := dto.ToPermissionDTO(permission)
+	return &result, nil
+}
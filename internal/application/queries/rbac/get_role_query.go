@@ -0,0 +1,40 @@
+package rbac
+
+import (
+	"context"
+
+	dto "github.com/tranvuongduy2003/go-mvc/internal/application/dto/rbac"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/auth"
+	apperrors "github.com/tranvuongduy2003/go-mvc/pkg/errors"
+)
+
+type GetRoleQuery struct {
+	RoleID string
+}
+
+type GetRoleQueryHandler struct {
+	roleRepo auth.RoleRepository
+}
+
+func NewGetRoleQueryHandler(roleRepo auth.RoleRepository) *GetRoleQueryHandler {
+	return &GetRoleQueryHandler{
+		roleRepo: roleRepo,
+	}
+}
+
+func (h *GetRoleQueryHandler) Handle(ctx context.Context, query GetRoleQuery) (*dto.RoleDTO, error) {
+	if err := validateID("role", query.RoleID); err != nil {
+		return nil, err
+	}
+
+	role, err := h.roleRepo.GetByID(ctx, query.RoleID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to get role", err)
+	}
+	if role == nil {
+		return nil, apperrors.NewNotFoundError("role not found")
+	}
+
+	result := dto.ToRoleDTO(role)
+	return &result, nil
+}
@@ -0,0 +1,54 @@
+package rbac
+
+import (
+	"context"
+
+	dto "github.com/tranvuongduy2003/go-mvc/internal/application/dto/rbac"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/auth"
+	apperrors "github.com/tranvuongduy2003/go-mvc/pkg/errors"
+	"github.com/tranvuongduy2003/go-mvc/pkg/pagination"
+)
+
+type ListPermissionsQuery struct {
+	Page     int
+	Limit    int
+	Search   string
+	SortBy   string
+	SortDir  string
+	Resource string
+	Action   string
+	IsActive *bool
+}
+
+type ListPermissionsQueryHandler struct {
+	permissionRepo auth.PermissionRepository
+}
+
+func NewListPermissionsQueryHandler(permissionRepo auth.PermissionRepository) *ListPermissionsQueryHandler {
+	return &ListPermissionsQueryHandler{
+		permissionRepo: permissionRepo,
+	}
+}
+
+func (h *ListPermissionsQueryHandler) Handle(ctx context.Context, query ListPermissionsQuery) ([]dto.PermissionDTO, *pagination.Pagination, error) {
+	sortBy, sortDir, err := normalizeSort(query.SortBy, query.SortDir, "name", "resource", "action", "created_at", "updated_at")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	permissions, pag, err := h.permissionRepo.List(ctx, auth.ListPermissionsParams{
+		Page:     query.Page,
+		Limit:    query.Limit,
+		Search:   query.Search,
+		SortBy:   sortBy,
+		SortDir:  sortDir,
+		Resource: query.Resource,
+		Action:   query.Action,
+		IsActive: query.IsActive,
+	})
+	if err != nil {
+		return nil, nil, apperrors.NewInternalError("failed to list permissions", err)
+	}
+
+	return dto.ToPermissionDTOs(permissions), pag, nil
+}
@@ -0,0 +1,58 @@
+package rbac
+
+import (
+	"context"
+
+	dto "github.com/tranvuongduy2003/go-mvc/internal/application/dto/rbac"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/auth"
+	apperrors "github.com/tranvuongduy2003/go-mvc/pkg/errors"
+	"github.com/tranvuongduy2003/go-mvc/pkg/pagination"
+)
+
+type ListRolePermissionsQuery struct {
+	RoleID   string
+	Page     int
+	Limit    int
+	Resource string
+	Action   string
+	IsActive *bool
+	SortBy   string
+	SortDir  string
+}
+
+type ListRolePermissionsQueryHandler struct {
+	rolePermissionRepo auth.RolePermissionRepository
+}
+
+func NewListRolePermissionsQueryHandler(rolePermissionRepo auth.RolePermissionRepository) *ListRolePermissionsQueryHandler {
+	return &ListRolePermissionsQueryHandler{
+		rolePermissionRepo: rolePermissionRepo,
+	}
+}
+
+func (h *ListRolePermissionsQueryHandler) Handle(ctx context.Context, query ListRolePermissionsQuery) ([]dto.RolePermissionDTO, *pagination.Pagination, error) {
+	if err := validateID("role", query.RoleID); err != nil {
+		return nil, nil, err
+	}
+
+	sortBy, sortDir, err := normalizeSort(query.SortBy, query.SortDir, "granted_at", "created_at")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	grants, pag, err := h.rolePermissionRepo.List(ctx, auth.ListRolePermissionsParams{
+		Page:     query.Page,
+		Limit:    query.Limit,
+		RoleID:   query.RoleID,
+		Resource: query.Resource,
+		Action:   query.Action,
+		IsActive: query.IsActive,
+		SortBy:   sortBy,
+		SortDir:  sortDir,
+	})
+	if err != nil {
+		return nil, nil, apperrors.NewInternalError("failed to list role permissions", err)
+	}
+
+	return dto.ToRolePermissionDTOs(grants), pag, nil
+}
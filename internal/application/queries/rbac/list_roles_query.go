@@ -0,0 +1,50 @@
+package rbac
+
+import (
+	"context"
+
+	dto "github.com/tranvuongduy2003/go-mvc/internal/application/dto/rbac"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/auth"
+	apperrors "github.com/tranvuongduy2003/go-mvc/pkg/errors"
+	"github.com/tranvuongduy2003/go-mvc/pkg/pagination"
+)
+
+type ListRolesQuery struct {
+	Page     int
+	Limit    int
+	Search   string
+	SortBy   string
+	SortDir  string
+	IsActive *bool
+}
+
+type ListRolesQueryHandler struct {
+	roleRepo auth.RoleRepository
+}
+
+func NewListRolesQueryHandler(roleRepo auth.RoleRepository) *ListRolesQueryHandler {
+	return &ListRolesQueryHandler{
+		roleRepo: roleRepo,
+	}
+}
+
+func (h *ListRolesQueryHandler) Handle(ctx context.Context, query ListRolesQuery) ([]dto.RoleDTO, *pagination.Pagination, error) {
+	sortBy, sortDir, err := normalizeSort(query.SortBy, query.SortDir, "name", "created_at", "updated_at")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	roles, pag, err := h.roleRepo.List(ctx, auth.ListRolesParams{
+		Page:     query.Page,
+		Limit:    query.Limit,
+		Search:   query.Search,
+		SortBy:   sortBy,
+		SortDir:  sortDir,
+		IsActive: query.IsActive,
+	})
+	if err != nil {
+		return nil, nil, apperrors.NewInternalError("failed to list roles", err)
+	}
+
+	return dto.ToRoleDTOs(roles), pag, nil
+}
@@ -0,0 +1,67 @@
+package rbac
+
+import (
+	"context"
+
+	dto "github.com/tranvuongduy2003/go-mvc/internal/application/dto/rbac"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/auth"
+	apperrors "github.com/tranvuongduy2003/go-mvc/pkg/errors"
+	"github.com/tranvuongduy2003/go-mvc/pkg/pagination"
+)
+
+// ListUserRolesQuery lists role assignments, either the roles of a user or
+// the members of a role depending on which ID is set.
+type ListUserRolesQuery struct {
+	UserID    string
+	RoleID    string
+	Page      int
+	Limit     int
+	IsActive  *bool
+	IsExpired *bool
+	SortBy    string
+	SortDir   string
+}
+
+type ListUserRolesQueryHandler struct {
+	userRoleRepo auth.UserRoleRepository
+}
+
+func NewListUserRolesQueryHandler(userRoleRepo auth.UserRoleRepository) *ListUserRolesQueryHandler {
+	return &ListUserRolesQueryHandler{
+		userRoleRepo: userRoleRepo,
+	}
+}
+
+func (h *ListUserRolesQueryHandler) Handle(ctx context.Context, query ListUserRolesQuery) ([]dto.UserRoleDTO, *pagination.Pagination, error) {
+	if query.UserID != "" {
+		if err := validateID("user", query.UserID); err != nil {
+			return nil, nil, err
+		}
+	}
+	if query.RoleID != "" {
+		if err := validateID("role", query.RoleID); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	sortBy, sortDir, err := normalizeSort(query.SortBy, query.SortDir, "assigned_at", "expires_at", "created_at")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	assignments, pag, err := h.userRoleRepo.List(ctx, auth.ListUserRolesParams{
+		Page:      query.Page,
+		Limit:     query.Limit,
+		UserID:    query.UserID,
+		RoleID:    query.RoleID,
+		IsActive:  query.IsActive,
+		IsExpired: query.IsExpired,
+		SortBy:    sortBy,
+		SortDir:   sortDir,
+	})
+	if err != nil {
+		return nil, nil, apperrors.NewInternalError("failed to list user roles", err)
+	}
+
+	return dto.ToUserRoleDTOs(assignments), pag, nil
+}
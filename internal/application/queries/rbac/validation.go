@@ -0,0 +1,46 @@
+package rbac
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	apperrors "github.com/tranvuongduy2003/go-mvc/pkg/errors"
+)
+
+// normalizeSort checks the requested order against the columns a listing
+// allows. The repositories interpolate the column into ORDER BY, so
+// anything else is rejected here.
+func normalizeSort(sortBy, sortDir string, allowed ...string) (string, string, error) {
+	if sortBy == "" {
+		sortBy = "created_at"
+	}
+	valid := false
+	for _, column := range allowed {
+		if sortBy == column {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return "", "", apperrors.NewValidationError(fmt.Sprintf("cannot sort by %q", sortBy), nil)
+	}
+
+	sortDir = strings.ToUpper(sortDir)
+	switch sortDir {
+	case "":
+		sortDir = "DESC"
+	case "ASC", "DESC":
+	default:
+		return "", "", apperrors.NewValidationError("sort_dir must be asc or desc", nil)
+	}
+
+	return sortBy, sortDir, nil
+}
+
+func validateID(kind, id string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return apperrors.NewValidationError(fmt.Sprintf("invalid %s ID", kind), err)
+	}
+	return nil
+}
@@ -2,57 +2,178 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/auth"
 	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/job"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/messaging"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/shared/events"
 	"github.com/tranvuongduy2003/go-mvc/internal/domain/user"
 	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/cache"
+	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/config"
 	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/external"
 	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/logger"
 	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/security"
 	apperrors "github.com/tranvuongduy2003/go-mvc/pkg/errors"
+	"github.com/tranvuongduy2003/go-mvc/pkg/geoip"
 	"github.com/tranvuongduy2003/go-mvc/pkg/jwt"
+	"github.com/tranvuongduy2003/go-mvc/pkg/oidc"
+	"github.com/tranvuongduy2003/go-mvc/pkg/webauthn"
 )
 
 type AuthService struct {
-	userRepo        user.UserRepository
-	jwtService      jwt.JWTService
-	passwordHasher  *security.PasswordHasher
-	tokenGenerator  *security.TokenGenerator
-	cacheService    *cache.Service
-	smtpService     *external.SMTPService
-	logger          *logger.Logger
-	tokenBlacklist  string // Redis key prefix for blacklisted tokens
-	verificationTTL time.Duration
-	resetTokenTTL   time.Duration
+	userRepo            user.UserRepository
+	sessionRepo         auth.SessionRepository
+	credentialRepo      auth.WebAuthnCredentialRepository
+	identityRepo        auth.ExternalIdentityRepository
+	apiKeyRepo          auth.APIKeyRepository
+	auditLogRepo        auth.AuditLogRepository
+	loginAttemptRepo    auth.LoginAttemptRepository
+	jwtService          jwt.JWTService
+	passwordHasher      user.PasswordHasher
+	passwordPolicy      contracts.PasswordPolicyService
+	authzService        contracts.AuthorizationService
+	tokenGenerator      *security.TokenGenerator
+	cacheService        *cache.Service
+	smtpService         *external.SMTPService
+	jobService          job.BackgroundJobService
+	eventBus            messaging.EventBus
+	geoIP               *geoip.Reader
+	logger              *logger.Logger
+	tokenBlacklist      string // Redis key prefix for blacklisted tokens
+	refreshFamily       string // Redis key prefix for refresh token families
+	refreshTokenUse     string // Redis key prefix for consumed refresh token IDs
+	mfaChallenge        string // Redis key prefix for pending MFA logins
+	mfaEnrollment       string // Redis key prefix for unconfirmed TOTP secrets
+	mfaCodeUse          string // Redis key prefix for redeemed MFA codes
+	mfaConfig           config.MFA
+	relyingParty        *webauthn.RelyingParty
+	webAuthnCeremony    string // Redis key prefix for pending passkey ceremonies
+	loginFailures       string // Redis key prefix for failed login counters
+	loginBlocked        string // Redis key prefix for delayed or locked logins
+	lockoutConfig       config.Lockout
+	oidcProviders       map[string]*oidc.Provider
+	oidcState           string // Redis key prefix for pending OIDC authorization requests
+	oidcConfig          config.OIDC
+	passwordReset       string // Redis key prefix for password reset tokens
+	emailVerification   string // Redis key prefix for email verification tokens
+	verificationConfig  config.EmailVerification
+	emailChange         string // Redis key prefix for pending email change confirmations
+	emailChangeRevert   string // Redis key prefix for email change revert links
+	emailChangeConfig   config.EmailChange
+	magicLink           string // Redis key prefix for pending sign-in links
+	magicLinkRequests   string // Redis key prefix for sign-in link request counters
+	magicLinkConfig     config.MagicLink
+	apiKeyConfig        config.APIKeys
+	oauthClients        map[string]*auth.OAuthClient
+	oauthConfig         config.OAuth
+	impersonationConfig config.Impersonation
+	tokenAuthzConfig    config.TokenAuthorization
+	loginAlert          string // Redis key prefix for "this wasn't me" links of login alerts
+	loginHistoryConfig  config.LoginHistory
+	resetTokenTTL       time.Duration
+}
+
+// refreshTokenFamily tracks the only refresh token of a session's rotation
+// chain that may still be exchanged. Any other token from the same session
+// is a replay. The record doubles as the fast-path "session is alive" check
+// and remembers when and how the session was last authenticated, so
+// refreshed access tokens keep their auth_time and amr.
+type refreshTokenFamily struct {
+	UserID     string   `json:"user_id"`
+	CurrentJTI string   `json:"current_jti"`
+	AuthTime   int64    `json:"auth_time,omitempty"`
+	AMR        []string `json:"amr,omitempty"`
 }
 
 var _ contracts.AuthService = (*AuthService)(nil)
 var _ contracts.TokenManagementService = (*AuthService)(nil)
+var _ contracts.SessionManagementService = (*AuthService)(nil)
 var _ contracts.PasswordManagementService = (*AuthService)(nil)
 var _ contracts.EmailVerificationService = (*AuthService)(nil)
 
 func NewAuthService(
 	userRepo user.UserRepository,
+	sessionRepo auth.SessionRepository,
+	credentialRepo auth.WebAuthnCredentialRepository,
+	identityRepo auth.ExternalIdentityRepository,
+	apiKeyRepo auth.APIKeyRepository,
+	auditLogRepo auth.AuditLogRepository,
+	loginAttemptRepo auth.LoginAttemptRepository,
 	jwtService jwt.JWTService,
-	passwordHasher *security.PasswordHasher,
+	passwordHasher user.PasswordHasher,
+	passwordPolicy contracts.PasswordPolicyService,
+	authzService contracts.AuthorizationService,
 	cacheService *cache.Service,
 	smtpService *external.SMTPService,
+	jobService job.BackgroundJobService,
+	eventBus messaging.EventBus,
+	geoIP *geoip.Reader,
+	authConfig config.Auth,
 	logger *logger.Logger,
 ) *AuthService {
 	return &AuthService{
-		userRepo:        userRepo,
-		jwtService:      jwtService,
-		passwordHasher:  passwordHasher,
-		tokenGenerator:  security.NewTokenGenerator(),
-		cacheService:    cacheService,
-		smtpService:     smtpService,
-		logger:          logger,
-		tokenBlacklist:  "blacklist:token:",
-		verificationTTL: 24 * time.Hour, // Email verification valid for 24 hours
-		resetTokenTTL:   1 * time.Hour,  // Password reset valid for 1 hour
+		userRepo:         userRepo,
+		sessionRepo:      sessionRepo,
+		credentialRepo:   credentialRepo,
+		identityRepo:     identityRepo,
+		apiKeyRepo:       apiKeyRepo,
+		auditLogRepo:     auditLogRepo,
+		loginAttemptRepo: loginAttemptRepo,
+		jwtService:       jwtService,
+		passwordHasher:   passwordHasher,
+		passwordPolicy:   passwordPolicy,
+		authzService:     authzService,
+		tokenGenerator:   security.NewTokenGenerator(),
+		cacheService:     cacheService,
+		smtpService:      smtpService,
+		jobService:       jobService,
+		eventBus:         eventBus,
+		geoIP:            geoIP,
+		logger:           logger,
+		tokenBlacklist:   "blacklist:token:",
+		refreshFamily:    "refresh_family:",
+		refreshTokenUse:  "refresh_used:",
+		mfaChallenge:     "mfa_challenge:",
+		mfaEnrollment:    "mfa_enrollment:",
+		mfaCodeUse:       "mfa_code_used:",
+		mfaConfig:        authConfig.MFA,
+		relyingParty: webauthn.New(webauthn.Config{
+			RPID:             authConfig.WebAuthn.RPID,
+			RPName:           authConfig.WebAuthn.RPName,
+			Origins:          authConfig.WebAuthn.Origins,
+			Timeout:          authConfig.WebAuthn.Timeout,
+			UserVerification: authConfig.WebAuthn.UserVerification,
+		}),
+		webAuthnCeremony:    "webauthn_ceremony:",
+		loginFailures:       "login_failures:",
+		loginBlocked:        "login_blocked:",
+		lockoutConfig:       authConfig.Lockout,
+		oidcProviders:       newOIDCProviders(authConfig.OIDC.Providers),
+		oidcState:           "oidc_state:",
+		oidcConfig:          authConfig.OIDC,
+		passwordReset:       "password_reset:",
+		emailVerification:   "email_verification:",
+		verificationConfig:  authConfig.EmailVerification,
+		emailChange:         "email_change:",
+		emailChangeRevert:   "email_change_revert:",
+		emailChangeConfig:   authConfig.EmailChange,
+		magicLink:           "magic_link:",
+		magicLinkRequests:   "magic_link_requests:",
+		magicLinkConfig:     authConfig.MagicLink,
+		apiKeyConfig:        authConfig.APIKeys,
+		oauthClients:        newOAuthClients(authConfig.OAuth.Clients),
+		oauthConfig:         authConfig.OAuth,
+		impersonationConfig: authConfig.Impersonation,
+		tokenAuthzConfig:    authConfig.TokenAuthorization,
+		loginAlert:          "login_alert:",
+		loginHistoryConfig:  authConfig.LoginHistory,
+		resetTokenTTL:       1 * time.Hour, // Password reset valid for 1 hour
 	}
 }
 
@@ -61,6 +182,13 @@ func (s *AuthService) Register(ctx context.Context, req *contracts.RegisterReque
 		return nil, fmt.Errorf("invalid registration data: %w", err)
 	}
 
+	if err := s.passwordPolicy.ValidatePassword(ctx, req.Password, contracts.PasswordSubject{
+		Email: req.Email,
+		Name:  req.Name,
+	}); err != nil {
+		return nil, err
+	}
+
 	existingUser, err := s.userRepo.GetByEmail(ctx, req.Email)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check existing user: %w", err)
@@ -74,6 +202,7 @@ func (s *AuthService) Register(ctx context.Context, req *contracts.RegisterReque
 		req.Name,
 		req.Phone,
 		req.Password, // Use plain password, domain will hash it
+		s.passwordHasher,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create user entity: %w", err)
@@ -83,12 +212,21 @@ func (s *AuthService) Register(ctx context.Context, req *contracts.RegisterReque
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
 
-	userID, err := uuid.Parse(userEntity.ID())
-	if err != nil {
-		return nil, fmt.Errorf("invalid user ID format: %w", err)
+	s.recordPassword(ctx, userEntity)
+
+	// The account exists at this point; a failed send can be retried
+	// through the resend endpoint.
+	if err := s.queueVerificationEmail(ctx, userEntity); err != nil {
+		s.logger.Warnf("Failed to queue verification email for user %s: %v", userEntity.ID(), err)
 	}
 
-	tokens, err := s.generateTokens(userID, userEntity.Email())
+	// Deployments that require a verified address sign the user in only
+	// after they confirm it
+	if err := s.requireVerifiedEmail(userEntity); err != nil {
+		return &contracts.AuthenticatedUser{User: userEntity}, nil
+	}
+
+	tokens, err := s.generateTokens(ctx, userEntity, req.Client, []string{jwt.AMRPassword})
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate tokens: %w", err)
 	}
@@ -104,28 +242,56 @@ func (s *AuthService) Login(ctx context.Context, credentials *contracts.LoginCre
 		return nil, apperrors.NewValidationError("invalid credentials", err)
 	}
 
+	if err := s.checkLoginAllowed(ctx, credentials.Email, credentials.Client.IPAddress); err != nil {
+		s.recordFailedLogin(ctx, nil, credentials.Email, credentials.Client, auth.LoginFailureAccountLocked)
+		return nil, err
+	}
+
 	userEntity, err := s.userRepo.GetByEmail(ctx, credentials.Email)
 	if err != nil {
 		return nil, apperrors.NewInternalError("failed to get user", err)
 	}
 	if userEntity == nil {
+		s.recordLoginFailure(ctx, credentials.Email, credentials.Client.IPAddress, nil)
+		s.recordFailedLogin(ctx, nil, credentials.Email, credentials.Client, auth.LoginFailureInvalidCredentials)
 		return nil, apperrors.NewUnauthorizedError("invalid email or password")
 	}
 
 	if !userEntity.IsActive() {
+		s.recordFailedLogin(ctx, userEntity, credentials.Email, credentials.Client, auth.LoginFailureAccountInactive)
 		return nil, apperrors.NewUnauthorizedError("user account is inactive")
 	}
 
-	if !userEntity.VerifyPassword(credentials.Password) {
+	if !userEntity.VerifyPassword(credentials.Password, s.passwordHasher) {
+		s.recordLoginFailure(ctx, credentials.Email, credentials.Client.IPAddress, userEntity)
+		s.recordFailedLogin(ctx, userEntity, credentials.Email, credentials.Client, auth.LoginFailureInvalidCredentials)
 		return nil, apperrors.NewUnauthorizedError("invalid email or password")
 	}
 
-	userID, err := uuid.Parse(userEntity.ID())
-	if err != nil {
-		return nil, apperrors.NewInternalError("invalid user ID format", err)
+	s.upgradePasswordHash(ctx, userEntity, credentials.Password)
+
+	if err := s.requireVerifiedEmail(userEntity); err != nil {
+		s.recordFailedLogin(ctx, userEntity, credentials.Email, credentials.Client, auth.LoginFailureEmailNotVerified)
+		return nil, err
+	}
+
+	// Failures are cleared only once every factor has passed, so logging in
+	// again does not buy a fresh budget of MFA code guesses
+	if userEntity.MFA().IsEnabled() {
+		challenge, err := s.createMFAChallenge(ctx, userEntity, credentials.Client, []string{jwt.AMRPassword})
+		if err != nil {
+			return nil, apperrors.NewInternalError("failed to create MFA challenge", err)
+		}
+
+		return &contracts.AuthenticatedUser{
+			User:         userEntity,
+			MFAChallenge: challenge,
+		}, nil
 	}
 
-	tokens, err := s.generateTokens(userID, userEntity.Email())
+	s.clearLoginFailures(ctx, credentials.Email)
+
+	tokens, err := s.generateTokens(ctx, userEntity, credentials.Client, []string{jwt.AMRPassword})
 	if err != nil {
 		return nil, apperrors.NewInternalError("failed to generate tokens", err)
 	}
@@ -143,34 +309,223 @@ func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (*c
 		return nil, apperrors.NewUnauthorizedError("refresh token is invalid")
 	}
 
-	newAccessToken, err := s.jwtService.RefreshAccessToken(refreshToken)
+	claims, err := s.jwtService.ValidateToken(refreshToken)
 	if err != nil {
 		return nil, err // Already an AppError from jwt service
 	}
 
+	if claims.Type != "refresh" {
+		return nil, apperrors.NewUnauthorizedError("token is not a refresh token")
+	}
+
+	if claims.ID == "" || claims.SessionID == "" {
+		return nil, apperrors.NewUnauthorizedError("refresh token is invalid")
+	}
+
+	family, err := s.getRefreshTokenFamily(ctx, claims.SessionID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to load refresh token family", err)
+	}
+	if family == nil {
+		return nil, apperrors.NewUnauthorizedError("refresh token has been revoked")
+	}
+
+	// SetNX makes consumption atomic across instances: two concurrent
+	// exchanges of the same token cannot both succeed.
+	firstUse, err := s.cacheService.SetNX(ctx, s.refreshTokenUse+claims.ID, true, time.Until(claims.ExpiresAt.Time))
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to consume refresh token", err)
+	}
+
+	if !firstUse || family.CurrentJTI != claims.ID {
+		s.logger.Warnf("Refresh token reuse detected for user %s, revoking session %s", claims.UserID, claims.SessionID)
+		if err := s.revokeSession(ctx, claims.SessionID); err != nil {
+			s.logger.Errorf("Failed to revoke session %s: %v", claims.SessionID, err)
+		}
+		return nil, apperrors.NewUnauthorizedError("refresh token reuse detected, please log in again")
+	}
+
+	userEntity, err := s.userRepo.GetByID(ctx, claims.UserID.String())
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to get user", err)
+	}
+	if userEntity == nil || !userEntity.IsActive() {
+		return nil, apperrors.NewUnauthorizedError("refresh token is invalid")
+	}
+
+	if claims.TokenVersion != userEntity.TokenVersion() {
+		return nil, apperrors.NewUnauthorizedError("refresh token has been revoked")
+	}
+
+	tokens, err := s.issueTokens(ctx, userEntity, claims.SessionID, unixTime(family.AuthTime), family.AMR)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to generate tokens", err)
+	}
+
+	if err := s.sessionRepo.Touch(ctx, claims.SessionID, time.Now(), tokens.RefreshTokenExpiresAt); err != nil {
+		s.logger.Errorf("Failed to update session %s: %v", claims.SessionID, err)
+	}
+
+	return tokens, nil
+}
+
+// AuthenticateRefreshToken checks a refresh token without redeeming it. Only
+// the latest token of a live session is accepted.
+func (s *AuthService) AuthenticateRefreshToken(ctx context.Context, refreshToken string) (*contracts.Principal, error) {
+	if blacklisted, err := s.IsTokenBlacklisted(ctx, refreshToken); err != nil {
+		return nil, apperrors.NewInternalError("failed to check token blacklist", err)
+	} else if blacklisted {
+		return nil, apperrors.NewUnauthorizedError("refresh token is invalid")
+	}
+
 	claims, err := s.jwtService.ValidateToken(refreshToken)
 	if err != nil {
 		return nil, err // Already an AppError from jwt service
 	}
 
-	return &contracts.AuthTokens{
-		AccessToken:           newAccessToken,
-		RefreshToken:          refreshToken,
-		AccessTokenExpiresAt:  time.Unix(s.jwtService.GetAccessTokenExpirationTime(), 0),
-		RefreshTokenExpiresAt: claims.ExpiresAt.Time,
-		TokenType:             "Bearer",
+	if claims.Type != "refresh" || claims.ID == "" || claims.SessionID == "" {
+		return nil, apperrors.NewUnauthorizedError("refresh token is invalid")
+	}
+
+	family, err := s.getRefreshTokenFamily(ctx, claims.SessionID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to load refresh token family", err)
+	}
+	if family == nil || family.CurrentJTI != claims.ID {
+		return nil, apperrors.NewUnauthorizedError("refresh token has been revoked")
+	}
+
+	userEntity, err := s.userRepo.GetByID(ctx, claims.UserID.String())
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to get user", err)
+	}
+	if userEntity == nil || !userEntity.IsActive() {
+		return nil, apperrors.NewUnauthorizedError("refresh token is invalid")
+	}
+
+	if claims.TokenVersion != userEntity.TokenVersion() {
+		return nil, apperrors.NewUnauthorizedError("refresh token has been revoked")
+	}
+
+	return &contracts.Principal{
+		User:      userEntity,
+		SessionID: claims.SessionID,
 	}, nil
 }
 
-func (s *AuthService) Logout(ctx context.Context, userID string) error {
+// RevokeRefreshToken ends the session a refresh token belongs to, which
+// also invalidates every access token issued for it.
+func (s *AuthService) RevokeRefreshToken(ctx context.Context, refreshToken string) error {
+	claims, err := s.jwtService.ValidateToken(refreshToken)
+	if err != nil {
+		return err // Already an AppError from jwt service
+	}
+
+	if claims.Type != "refresh" || claims.SessionID == "" {
+		return apperrors.NewUnauthorizedError("token is not a refresh token")
+	}
+
+	if err := s.revokeSession(ctx, claims.SessionID); err != nil {
+		return apperrors.NewInternalError("failed to revoke session", err)
+	}
+
+	return nil
+}
+
+func (s *AuthService) Logout(ctx context.Context, userID, accessToken string) error {
+	claims, err := s.jwtService.ValidateToken(accessToken)
+	if err != nil {
+		return err // Already an AppError from jwt service
+	}
+
+	if claims.UserID.String() != userID {
+		return apperrors.NewUnauthorizedError("token does not belong to user")
+	}
+
+	if claims.SessionID == "" {
+		return nil
+	}
+
+	if err := s.revokeSession(ctx, claims.SessionID); err != nil {
+		return apperrors.NewInternalError("failed to revoke session", err)
+	}
+
 	return nil
 }
 
 func (s *AuthService) LogoutAll(ctx context.Context, userID string) error {
+	userEntity, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return apperrors.NewInternalError("failed to get user", err)
+	}
+	if userEntity == nil {
+		return apperrors.NewNotFoundError("user not found")
+	}
+
+	if err := s.userRepo.IncrementTokenVersion(ctx, userEntity.ID()); err != nil {
+		return apperrors.NewInternalError("failed to revoke tokens", err)
+	}
+
+	return s.revokeAllSessions(ctx, userID)
+}
+
+// revokeAllSessions ends every session of a user along with its refresh
+// token family. Callers bump the token version first, which is what turns
+// away access tokens already handed out.
+func (s *AuthService) revokeAllSessions(ctx context.Context, userID string) error {
+	sessions, err := s.sessionRepo.GetActiveByUserID(ctx, userID)
+	if err != nil {
+		return apperrors.NewInternalError("failed to list sessions", err)
+	}
+	for _, session := range sessions {
+		if err := s.revokeRefreshTokenFamily(ctx, session.ID); err != nil {
+			s.logger.Warnf("Failed to revoke refresh tokens of session %s: %v", session.ID, err)
+		}
+	}
+
+	if err := s.sessionRepo.RevokeAllByUserID(ctx, userID); err != nil {
+		return apperrors.NewInternalError("failed to revoke sessions", err)
+	}
+
+	return nil
+}
+
+func (s *AuthService) ListSessions(ctx context.Context, userID string) ([]*auth.Session, error) {
+	sessions, err := s.sessionRepo.GetActiveByUserID(ctx, userID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to list sessions", err)
+	}
+	return sessions, nil
+}
+
+func (s *AuthService) RevokeSession(ctx context.Context, userID, sessionID string) error {
+	session, err := s.sessionRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		return apperrors.NewInternalError("failed to get session", err)
+	}
+	if session == nil || session.UserID != userID {
+		return apperrors.NewNotFoundError("session not found")
+	}
+
+	if err := s.revokeSession(ctx, sessionID); err != nil {
+		return apperrors.NewInternalError("failed to revoke session", err)
+	}
+
 	return nil
 }
 
 func (s *AuthService) ValidateToken(ctx context.Context, accessToken string) (*user.User, error) {
+	principal, err := s.Authenticate(ctx, accessToken)
+	if err != nil {
+		return nil, err
+	}
+	if principal.User == nil {
+		return nil, apperrors.NewUnauthorizedError("token does not belong to a user")
+	}
+	return principal.User, nil
+}
+
+func (s *AuthService) Authenticate(ctx context.Context, accessToken string) (*contracts.Principal, error) {
 	if blacklisted, err := s.IsTokenBlacklisted(ctx, accessToken); err != nil {
 		return nil, apperrors.NewInternalError("failed to check token blacklist", err)
 	} else if blacklisted {
@@ -186,6 +541,10 @@ func (s *AuthService) ValidateToken(ctx context.Context, accessToken string) (*u
 		return nil, apperrors.NewUnauthorizedError("token is not an access token")
 	}
 
+	if claims.ClientID != "" {
+		return s.authenticateClientToken(claims)
+	}
+
 	userEntity, err := s.userRepo.GetByID(ctx, claims.UserID.String())
 	if err != nil {
 		return nil, apperrors.NewInternalError("failed to get user", err)
@@ -198,35 +557,137 @@ func (s *AuthService) ValidateToken(ctx context.Context, accessToken string) (*u
 		return nil, apperrors.NewUnauthorizedError("user account is inactive")
 	}
 
-	return userEntity, nil
-}
+	if claims.TokenVersion != userEntity.TokenVersion() {
+		return nil, apperrors.NewUnauthorizedError("token has been revoked")
+	}
 
-func (s *AuthService) ChangePassword(ctx context.Context, userID, oldPassword, newPassword string) error {
-	userEntity, err := s.userRepo.GetByID(ctx, userID)
+	// Access tokens carry the session ID of their refresh token, so revoking
+	// the session invalidates both without a per-token blacklist.
+	family, err := s.getRefreshTokenFamily(ctx, claims.SessionID)
 	if err != nil {
-		return fmt.Errorf("failed to get user: %w", err)
+		return nil, apperrors.NewInternalError("failed to load session", err)
 	}
-	if userEntity == nil {
-		return fmt.Errorf("user not found")
+	if family == nil {
+		return nil, apperrors.NewUnauthorizedError("session has been revoked")
 	}
 
-	if !userEntity.VerifyPassword(oldPassword) {
-		return fmt.Errorf("invalid old password")
+	principal := &contracts.Principal{
+		User:        userEntity,
+		SessionID:   claims.SessionID,
+		AuthTime:    unixTime(claims.AuthTime),
+		AuthMethods: claims.AMR,
+	}
+	if claims.AuthzVersion != 0 {
+		version, err := s.authzService.GetAuthorizationVersion(ctx, userEntity.ID())
+		if err != nil {
+			return nil, err
+		}
+		if version != claims.AuthzVersion {
+			return nil, apperrors.NewUnauthorizedError("permissions have changed, please refresh your token")
+		}
+		principal.Authorization = &contracts.TokenAuthorization{
+			Roles:       claims.Roles,
+			Permissions: claims.Permissions,
+		}
+	}
+	if claims.Actor != nil {
+		if err := s.authenticateActor(ctx, claims.Actor.Subject); err != nil {
+			return nil, err
+		}
+		principal.ActorID = claims.Actor.Subject
 	}
 
-	if err := s.validatePassword(newPassword); err != nil {
-		return fmt.Errorf("invalid new password: %w", err)
+	return principal, nil
+}
+
+// ChangePassword replaces the password of a signed-in user. Every other
+// session is signed out; the current one, if any, gets a fresh token pair
+// so it survives the token version bump.
+func (s *AuthService) ChangePassword(ctx context.Context, userID, sessionID, oldPassword, newPassword string) (*contracts.AuthTokens, error) {
+	userEntity, err := s.getActiveUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !userEntity.VerifyPassword(oldPassword, s.passwordHasher) {
+		return nil, apperrors.NewUnauthorizedError("invalid old password")
 	}
 
-	if err := userEntity.ChangePassword(newPassword); err != nil {
-		return fmt.Errorf("failed to update password: %w", err)
+	if err := s.validateNewPassword(ctx, userEntity, newPassword); err != nil {
+		return nil, err
+	}
+
+	if err := userEntity.ChangePassword(newPassword, s.passwordHasher); err != nil {
+		return nil, apperrors.NewInternalError("failed to update password", err)
 	}
 
 	if err := s.userRepo.Update(ctx, userEntity); err != nil {
-		return fmt.Errorf("failed to save user: %w", err)
+		return nil, saveUserError("failed to save user", err)
 	}
 
-	return nil
+	if err := s.userRepo.IncrementTokenVersion(ctx, userEntity.ID()); err != nil {
+		return nil, apperrors.NewInternalError("failed to revoke tokens", err)
+	}
+
+	s.recordPassword(ctx, userEntity)
+
+	tokens, err := s.keepCurrentSession(ctx, userEntity.ID(), sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.queuePasswordChangedEmail(ctx, userEntity); err != nil {
+		s.logger.Errorf("Failed to queue password changed email for user %s: %v", userEntity.ID(), err)
+	}
+
+	return tokens, nil
+}
+
+// keepCurrentSession revokes every session of the user except sessionID and
+// re-issues the tokens of that one under the current token version, keeping
+// its authentication time and methods. Without a live current session all
+// sessions are revoked and no tokens are returned.
+func (s *AuthService) keepCurrentSession(ctx context.Context, userID, sessionID string) (*contracts.AuthTokens, error) {
+	var family *refreshTokenFamily
+	if sessionID != "" {
+		var err error
+		family, err = s.getRefreshTokenFamily(ctx, sessionID)
+		if err != nil {
+			return nil, apperrors.NewInternalError("failed to load session", err)
+		}
+	}
+	if family == nil || family.UserID != userID {
+		return nil, s.revokeAllSessions(ctx, userID)
+	}
+
+	sessions, err := s.sessionRepo.GetActiveByUserID(ctx, userID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to list sessions", err)
+	}
+	for _, session := range sessions {
+		if session.ID == sessionID {
+			continue
+		}
+		if err := s.revokeSession(ctx, session.ID); err != nil {
+			return nil, apperrors.NewInternalError("failed to revoke session", err)
+		}
+	}
+
+	// Reload for the bumped token version
+	userEntity, err := s.getActiveUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var authTime time.Time
+	if family.AuthTime != 0 {
+		authTime = time.Unix(family.AuthTime, 0)
+	}
+	tokens, err := s.issueTokens(ctx, userEntity, sessionID, authTime, family.AMR)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to issue tokens", err)
+	}
+	return tokens, nil
 }
 
 func (s *AuthService) ResetPassword(ctx context.Context, email string) error {
@@ -238,15 +699,8 @@ func (s *AuthService) ResetPassword(ctx context.Context, email string) error {
 		return nil
 	}
 
-	resetToken, err := s.tokenGenerator.Generate(32)
+	resetToken, err := s.issueAccountToken(ctx, s.passwordReset, userEntity, s.resetTokenTTL)
 	if err != nil {
-		return apperrors.NewInternalError("failed to generate reset token", err)
-	}
-
-	cacheKey := fmt.Sprintf("password_reset:%s", resetToken)
-	cacheOptions := &cache.CacheOptions{TTL: s.resetTokenTTL}
-
-	if err := s.cacheService.Set(ctx, cacheKey, userEntity.ID(), cacheOptions); err != nil {
 		return apperrors.NewInternalError("failed to store reset token", err)
 	}
 
@@ -257,87 +711,129 @@ func (s *AuthService) ResetPassword(ctx context.Context, email string) error {
 	return nil
 }
 
+// ConfirmPasswordReset sets a new password through an emailed reset link.
+// The token is checked before the password policy runs, so a rejected
+// password leaves the link usable, and only claimed once the password is
+// acceptable. The reset signs the account out everywhere.
 func (s *AuthService) ConfirmPasswordReset(ctx context.Context, token, newPassword string) error {
-	if err := s.validatePassword(newPassword); err != nil {
-		return apperrors.NewValidationError("invalid password", err)
+	userEntity, err := s.resolveAccountToken(ctx, s.passwordReset, token, false)
+	if err != nil {
+		return apperrors.NewInternalError("failed to get reset token", err)
+	}
+	if userEntity == nil {
+		return apperrors.NewValidationError("invalid or expired reset token", nil)
 	}
 
-	cacheKey := fmt.Sprintf("password_reset:%s", token)
-	var userID string
-	if err := s.cacheService.Get(ctx, cacheKey, &userID); err != nil {
-		return apperrors.NewValidationError("invalid or expired reset token", err)
+	if err := s.validateNewPassword(ctx, userEntity, newPassword); err != nil {
+		return err
 	}
 
-	userEntity, err := s.userRepo.GetByID(ctx, userID)
+	userEntity, err = s.resolveAccountToken(ctx, s.passwordReset, token, true)
 	if err != nil {
-		return apperrors.NewInternalError("failed to get user", err)
+		return apperrors.NewInternalError("failed to get reset token", err)
 	}
 	if userEntity == nil {
-		return apperrors.NewNotFoundError("user not found")
+		return apperrors.NewValidationError("invalid or expired reset token", nil)
 	}
 
-	if err := userEntity.ChangePassword(newPassword); err != nil {
+	if err := userEntity.ChangePassword(newPassword, s.passwordHasher); err != nil {
 		return apperrors.NewInternalError("failed to update password", err)
 	}
 
 	if err := s.userRepo.Update(ctx, userEntity); err != nil {
-		return apperrors.NewInternalError("failed to save user", err)
+		return saveUserError("failed to save user", err)
+	}
+
+	// Only after the new password is saved, so no token issued meanwhile survives
+	if err := s.userRepo.IncrementTokenVersion(ctx, userEntity.ID()); err != nil {
+		return apperrors.NewInternalError("failed to revoke tokens", err)
 	}
 
-	_ = s.cacheService.Delete(ctx, cacheKey) // Ignore error, not critical
+	s.recordPassword(ctx, userEntity)
+
+	if err := s.revokeAllSessions(ctx, userEntity.ID()); err != nil {
+		return err
+	}
+
+	if err := s.queuePasswordChangedEmail(ctx, userEntity); err != nil {
+		s.logger.Errorf("Failed to queue password changed email for user %s: %v", userEntity.ID(), err)
+	}
 
 	return nil
 }
 
-func (s *AuthService) VerifyEmail(ctx context.Context, token string) error {
-	cacheKey := fmt.Sprintf("email_verification:%s", token)
-	var userID string
-	if err := s.cacheService.Get(ctx, cacheKey, &userID); err != nil {
-		return apperrors.NewValidationError("invalid or expired verification token", err)
-	}
+// queuePasswordChangedEmail tells the owner their password was just reset,
+// so a reset they did not ask for does not go unnoticed.
+func (s *AuthService) queuePasswordChangedEmail(ctx context.Context, userEntity *user.User) error {
+	_, err := s.jobService.SubmitJob(ctx, job.JobTypePasswordChanged, job.JobPayload{
+		"to":   userEntity.Email(),
+		"name": userEntity.Name(),
+		"time": time.Now().UTC().Format(time.RFC3339),
+	})
+	return err
+}
 
-	userEntity, err := s.userRepo.GetByID(ctx, userID)
+func (s *AuthService) VerifyEmail(ctx context.Context, token string) error {
+	userEntity, err := s.resolveAccountToken(ctx, s.emailVerification, token, true)
 	if err != nil {
-		return apperrors.NewInternalError("failed to get user", err)
+		return apperrors.NewInternalError("failed to get verification token", err)
 	}
 	if userEntity == nil {
-		return apperrors.NewNotFoundError("user not found")
+		return apperrors.NewValidationError("invalid or expired verification token", nil)
 	}
 
-	userEntity.Activate()
+	if err := userEntity.VerifyEmail(); err != nil {
+		return apperrors.NewConflictError(err.Error(), nil)
+	}
 
 	if err := s.userRepo.Update(ctx, userEntity); err != nil {
-		return apperrors.NewInternalError("failed to save user", err)
+		return saveUserError("failed to save user", err)
 	}
+	s.publishUserEvents(ctx, userEntity)
 
-	_ = s.cacheService.Delete(ctx, cacheKey) // Ignore error, not critical
+	// Permissions withheld until verification are now granted
+	if err := s.authzService.InvalidateUserAuthorization(ctx, userEntity.ID()); err != nil {
+		s.logger.Errorf("Failed to invalidate authorization of user %s: %v", userEntity.ID(), err)
+	}
 
 	return nil
 }
 
+// publishUserEvents announces what happened to a user once it is saved and
+// clears the recorded events, so a later save does not announce them again.
+// Delivery is best effort, like the rest of the domain events.
+func (s *AuthService) publishUserEvents(ctx context.Context, userEntity *user.User) {
+	for _, recorded := range userEntity.GetEvents() {
+		var event messaging.Event
+		switch e := recorded.(type) {
+		case *user.EmailVerified:
+			event = events.NewUserEmailVerifiedEvent(e.UserID, e.Email, int(userEntity.Version()), e.VerifiedAt)
+		case *user.UserEmailChanged:
+			event = events.NewUserEmailChangedEvent(e.UserID, e.OldEmail, e.NewEmail, int(userEntity.Version()), e.ChangedAt)
+		default:
+			continue
+		}
+
+		if err := s.eventBus.PublishEvent(ctx, event); err != nil {
+			s.logger.Warnf("Failed to publish %s for user %s: %v", event.EventType(), userEntity.ID(), err)
+		}
+	}
+	userEntity.ClearEvents()
+}
+
 func (s *AuthService) ResendVerificationEmail(ctx context.Context, email string) error {
 	userEntity, err := s.userRepo.GetByEmail(ctx, email)
 	if err != nil {
 		return apperrors.NewInternalError("failed to get user", err)
 	}
-	if userEntity == nil {
+	// Unknown and already verified addresses get the same response so the
+	// endpoint does not reveal account state.
+	if userEntity == nil || userEntity.IsEmailVerified() {
 		return nil
 	}
 
-	verificationToken, err := s.tokenGenerator.Generate(32)
-	if err != nil {
-		return apperrors.NewInternalError("failed to generate verification token", err)
-	}
-
-	cacheKey := fmt.Sprintf("email_verification:%s", verificationToken)
-	cacheOptions := &cache.CacheOptions{TTL: s.verificationTTL}
-
-	if err := s.cacheService.Set(ctx, cacheKey, userEntity.ID(), cacheOptions); err != nil {
-		return apperrors.NewInternalError("failed to store verification token", err)
-	}
-
-	if err := s.smtpService.SendVerificationEmail(ctx, userEntity.Email(), userEntity.Name(), verificationToken); err != nil {
-		s.logger.Errorf("Failed to send verification email: %v", err)
+	if err := s.queueVerificationEmail(ctx, userEntity); err != nil {
+		return apperrors.NewInternalError("failed to queue verification email", err)
 	}
 
 	return nil
@@ -377,26 +873,155 @@ func (s *AuthService) BlacklistToken(ctx context.Context, token string) error {
 	return nil
 }
 
-func (s *AuthService) generateTokens(userID uuid.UUID, email string) (*contracts.AuthTokens, error) {
-	accessToken, err := s.jwtService.GenerateAccessToken(userID, email)
+// generateTokens starts a new session for the client and issues its first
+// token pair. amr lists the methods the user has just authenticated with.
+func (s *AuthService) generateTokens(ctx context.Context, userEntity *user.User, client contracts.ClientInfo, amr []string) (*contracts.AuthTokens, error) {
+	sessionID := uuid.New().String()
+
+	tokens, err := s.issueTokens(ctx, userEntity, sessionID, time.Now(), amr)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	session := &auth.Session{
+		ID:         sessionID,
+		UserID:     userEntity.ID(),
+		DeviceName: client.DeviceName,
+		UserAgent:  client.UserAgent,
+		IPAddress:  client.IPAddress,
+		CreatedAt:  now,
+		LastSeenAt: now,
+		ExpiresAt:  tokens.RefreshTokenExpiresAt,
+	}
+	if err := s.sessionRepo.Create(ctx, session); err != nil {
+		_ = s.revokeRefreshTokenFamily(ctx, sessionID)
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	s.recordSuccessfulLogin(ctx, userEntity, client, amr)
+
+	return tokens, nil
+}
+
+// issueTokens signs a new token pair bound to the session and records the
+// refresh token as the current member of the session's rotation family.
+// authTime and amr describe the session's latest authentication.
+func (s *AuthService) issueTokens(ctx context.Context, userEntity *user.User, sessionID string, authTime time.Time, amr []string) (*contracts.AuthTokens, error) {
+	userID, err := uuid.Parse(userEntity.ID())
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID format: %w", err)
+	}
+
+	accessTokenOptions, err := s.accessTokenOptions(ctx, userEntity, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	accessTokenOptions.AuthTime = authTime
+	accessTokenOptions.AMR = amr
+
+	accessToken, err := s.jwtService.GenerateAccessToken(userID, userEntity.Email(), accessTokenOptions)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate access token: %w", err)
 	}
 
-	refreshToken, err := s.jwtService.GenerateRefreshToken(userID, email)
+	refreshTokenID := uuid.New().String()
+	refreshToken, err := s.jwtService.GenerateRefreshToken(userID, userEntity.Email(), &jwt.TokenOptions{
+		TokenID:      refreshTokenID,
+		SessionID:    sessionID,
+		TokenVersion: userEntity.TokenVersion(),
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
 	}
 
+	refreshTokenExpiresAt := time.Unix(s.jwtService.GetRefreshTokenExpirationTime(), 0)
+
+	family := refreshTokenFamily{
+		UserID:     userEntity.ID(),
+		CurrentJTI: refreshTokenID,
+	}
+	if !authTime.IsZero() {
+		family.AuthTime = authTime.Unix()
+		family.AMR = amr
+	}
+	cacheOptions := &cache.CacheOptions{TTL: time.Until(refreshTokenExpiresAt)}
+	if err := s.cacheService.Set(ctx, s.refreshFamily+sessionID, family, cacheOptions); err != nil {
+		return nil, fmt.Errorf("failed to store refresh token family: %w", err)
+	}
+
 	return &contracts.AuthTokens{
 		AccessToken:           accessToken,
 		RefreshToken:          refreshToken,
 		AccessTokenExpiresAt:  time.Unix(s.jwtService.GetAccessTokenExpirationTime(), 0),
-		RefreshTokenExpiresAt: time.Unix(s.jwtService.GetRefreshTokenExpirationTime(), 0),
+		RefreshTokenExpiresAt: refreshTokenExpiresAt,
 		TokenType:             "Bearer",
 	}, nil
 }
 
+// accessTokenOptions binds an access token to its session and, when
+// enabled, embeds the user's roles and permissions. The version is read
+// first so a change racing with the snapshot leaves it stale rather than
+// wrong.
+func (s *AuthService) accessTokenOptions(ctx context.Context, userEntity *user.User, sessionID string) (*jwt.TokenOptions, error) {
+	opts := &jwt.TokenOptions{
+		SessionID:    sessionID,
+		TokenVersion: userEntity.TokenVersion(),
+	}
+	if !s.tokenAuthzConfig.Embed {
+		return opts, nil
+	}
+
+	version, err := s.authzService.GetAuthorizationVersion(ctx, userEntity.ID())
+	if err != nil {
+		return nil, err
+	}
+	roles, err := s.authzService.GetUserRoles(ctx, userEntity.ID())
+	if err != nil {
+		return nil, err
+	}
+	permissions, err := s.authzService.GetUserPermissions(ctx, userEntity.ID())
+	if err != nil {
+		return nil, err
+	}
+
+	// Too large a set would bloat every request; such users are checked
+	// against the database instead.
+	if s.tokenAuthzConfig.MaxPermissions > 0 && len(permissions) > s.tokenAuthzConfig.MaxPermissions {
+		return opts, nil
+	}
+
+	sort.Strings(roles)
+	sort.Strings(permissions)
+	opts.Roles = roles
+	opts.Permissions = permissions
+	opts.AuthzVersion = version
+
+	return opts, nil
+}
+
+func (s *AuthService) getRefreshTokenFamily(ctx context.Context, sessionID string) (*refreshTokenFamily, error) {
+	var family refreshTokenFamily
+	if err := s.cacheService.Get(ctx, s.refreshFamily+sessionID, &family); err != nil {
+		if err == cache.ErrCacheMiss {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &family, nil
+}
+
+func (s *AuthService) revokeRefreshTokenFamily(ctx context.Context, sessionID string) error {
+	return s.cacheService.Delete(ctx, s.refreshFamily+sessionID)
+}
+
+func (s *AuthService) revokeSession(ctx context.Context, sessionID string) error {
+	if err := s.revokeRefreshTokenFamily(ctx, sessionID); err != nil {
+		return err
+	}
+	return s.sessionRepo.Revoke(ctx, sessionID)
+}
+
 func (s *AuthService) validateRegisterRequest(req *contracts.RegisterRequest) error {
 	if req.Email == "" {
 		return fmt.Errorf("email is required")
@@ -408,7 +1033,7 @@ func (s *AuthService) validateRegisterRequest(req *contracts.RegisterRequest) er
 		return fmt.Errorf("password is required")
 	}
 
-	return s.validatePassword(req.Password)
+	return nil
 }
 
 func (s *AuthService) validateLoginCredentials(credentials *contracts.LoginCredentials) error {
@@ -421,9 +1046,49 @@ func (s *AuthService) validateLoginCredentials(credentials *contracts.LoginCrede
 	return nil
 }
 
-func (s *AuthService) validatePassword(password string) error {
-	if len(password) < 8 {
-		return fmt.Errorf("password must be at least 8 characters long")
+// validateNewPassword applies the password policy, including the reuse
+// check against the user's current and previous passwords.
+func (s *AuthService) validateNewPassword(ctx context.Context, userEntity *user.User, password string) error {
+	return s.passwordPolicy.ValidatePassword(ctx, password, contracts.PasswordSubject{
+		UserID:       userEntity.ID(),
+		Email:        userEntity.Email(),
+		Name:         userEntity.Name(),
+		PasswordHash: userEntity.HashedPassword(),
+	})
+}
+
+// upgradePasswordHash moves a password hashed with an outdated algorithm or
+// parameters to the current ones while the plain password is at hand. A
+// failure only delays the upgrade to the next login.
+func (s *AuthService) upgradePasswordHash(ctx context.Context, userEntity *user.User, password string) {
+	currentHash := userEntity.HashedPassword()
+	rehashed, err := userEntity.RehashPassword(password, s.passwordHasher)
+	if err != nil {
+		s.logger.Warnf("Failed to rehash password of user %s: %v", userEntity.ID(), err)
+		return
+	}
+	if !rehashed {
+		return
+	}
+
+	// Only the hash is written, and only over the one just verified: a
+	// password changed meanwhile by another request wins
+	if err := s.userRepo.UpdatePasswordHash(ctx, userEntity.ID(), currentHash, userEntity.HashedPassword()); err != nil {
+		s.logger.Warnf("Failed to save rehashed password of user %s: %v", userEntity.ID(), err)
+	}
+}
+
+// saveUserError reports a save that lost the race against another request
+// as a conflict the client can retry, and anything else as internal.
+func saveUserError(message string, err error) error {
+	if errors.Is(err, user.ErrConcurrentUpdate) {
+		return apperrors.NewConflictError("account was changed by another request, please try again", err)
+	}
+	return apperrors.NewInternalError(message, err)
+}
+
+func (s *AuthService) recordPassword(ctx context.Context, userEntity *user.User) {
+	if err := s.passwordPolicy.RecordPassword(ctx, userEntity.ID(), userEntity.HashedPassword()); err != nil {
+		s.logger.Warnf("Failed to record password history for user %s: %v", userEntity.ID(), err)
 	}
-	return nil
 }
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"time"
+
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/user"
+	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/cache"
+)
+
+// accountToken is what a password reset or email verification link points
+// at. The link's token is only stored hashed, and PasswordFingerprint ties
+// it to the password the account had when the link was sent, so changing
+// the password invalidates every link still in flight.
+type accountToken struct {
+	UserID              string `json:"user_id"`
+	PasswordFingerprint string `json:"password_fingerprint"`
+}
+
+// issueAccountToken stores a fresh token under prefix and returns the raw
+// value for the email.
+func (s *AuthService) issueAccountToken(ctx context.Context, prefix string, userEntity *user.User, ttl time.Duration) (string, error) {
+	token, err := s.tokenGenerator.Generate(32)
+	if err != nil {
+		return "", err
+	}
+
+	stored := accountToken{
+		UserID:              userEntity.ID(),
+		PasswordFingerprint: passwordFingerprint(userEntity),
+	}
+	if err := s.cacheService.Set(ctx, prefix+hashAccountToken(token), stored, &cache.CacheOptions{TTL: ttl}); err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// resolveAccountToken returns the user a token was issued to, or nil when
+// the token is unknown, expired or issued before the last password change.
+// With consume set the token is taken with an atomic GET-and-delete, so of
+// two concurrent requests only one gets the user.
+func (s *AuthService) resolveAccountToken(ctx context.Context, prefix, token string, consume bool) (*user.User, error) {
+	if token == "" {
+		return nil, nil
+	}
+
+	key := prefix + hashAccountToken(token)
+	var stored accountToken
+	var err error
+	if consume {
+		err = s.cacheService.GetDel(ctx, key, &stored)
+	} else {
+		err = s.cacheService.Get(ctx, key, &stored)
+	}
+	if err != nil {
+		if err == cache.ErrCacheMiss {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	userEntity, err := s.userRepo.GetByID(ctx, stored.UserID)
+	if err != nil || userEntity == nil {
+		return nil, err
+	}
+	if stored.PasswordFingerprint != passwordFingerprint(userEntity) {
+		return nil, nil
+	}
+
+	return userEntity, nil
+}
+
+func hashAccountToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// passwordFingerprint identifies the password, not its encoding, so a
+// rehash on login leaves links in flight valid while a new password voids
+// them.
+func passwordFingerprint(userEntity *user.User) string {
+	sum := sha256.Sum256([]byte(userEntity.ID() + ":" + strconv.FormatInt(userEntity.PasswordChangedAt().UnixMicro(), 10)))
+	return hex.EncodeToString(sum[:])
+}
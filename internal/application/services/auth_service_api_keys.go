@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/auth"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
+	apperrors "github.com/tranvuongduy2003/go-mvc/pkg/errors"
+)
+
+const (
+	// apiKeyVisibleChars is how much of the random part stays in the prefix
+	apiKeyVisibleChars = 8
+
+	// apiKeyLastUsedInterval keeps busy keys from writing on every request
+	apiKeyLastUsedInterval = time.Minute
+)
+
+var _ contracts.APIKeyService = (*AuthService)(nil)
+
+// CreateAPIKey issues a key scoped to the given permission names. Scopes
+// only narrow what the owner can do: a scope the owner does not hold grants
+// nothing.
+func (s *AuthService) CreateAPIKey(ctx context.Context, userID string, req contracts.NewAPIKey) (*contracts.IssuedAPIKey, error) {
+	if _, err := s.getActiveUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	scopes, err := normalizeAPIKeyScopes(req.Scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt, err := s.apiKeyExpiry(req.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.apiKeyRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to get API keys", err)
+	}
+	if s.apiKeyConfig.MaxPerUser > 0 && len(existing) >= s.apiKeyConfig.MaxPerUser {
+		return nil, apperrors.NewConflictError(fmt.Sprintf("an account can have at most %d API keys", s.apiKeyConfig.MaxPerUser), nil)
+	}
+
+	secret, err := s.tokenGenerator.GenerateAPIKey()
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to generate API key", err)
+	}
+	key := s.apiKeyConfig.KeyPrefix + "_" + secret
+
+	apiKey := &auth.APIKey{
+		UserID:    userID,
+		Name:      strings.TrimSpace(req.Name),
+		Prefix:    key[:len(s.apiKeyConfig.KeyPrefix)+1+apiKeyVisibleChars],
+		KeyHash:   hashAPIKey(key),
+		Scopes:    scopes,
+		ExpiresAt: expiresAt,
+		CreatedAt: time.Now(),
+	}
+	if err := s.apiKeyRepo.Create(ctx, apiKey); err != nil {
+		return nil, apperrors.NewInternalError("failed to save API key", err)
+	}
+
+	return &contracts.IssuedAPIKey{
+		APIKey: apiKey,
+		Key:    key,
+	}, nil
+}
+
+func (s *AuthService) ListAPIKeys(ctx context.Context, userID string) ([]*auth.APIKey, error) {
+	keys, err := s.apiKeyRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to get API keys", err)
+	}
+	return keys, nil
+}
+
+func (s *AuthService) DeleteAPIKey(ctx context.Context, userID, id string) error {
+	keys, err := s.apiKeyRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return apperrors.NewInternalError("failed to get API keys", err)
+	}
+
+	for _, key := range keys {
+		if key.ID == id {
+			if err := s.apiKeyRepo.Delete(ctx, userID, id); err != nil {
+				return apperrors.NewInternalError("failed to delete API key", err)
+			}
+			return nil
+		}
+	}
+
+	return apperrors.NewNotFoundError("API key not found")
+}
+
+// AuthenticateAPIKey resolves a presented key to its owner. Keys stop
+// working as soon as they expire, are deleted or the owner is deactivated.
+func (s *AuthService) AuthenticateAPIKey(ctx context.Context, key string) (*contracts.Principal, error) {
+	if !strings.HasPrefix(key, s.apiKeyConfig.KeyPrefix+"_") {
+		return nil, apperrors.NewUnauthorizedError("invalid API key")
+	}
+
+	apiKey, err := s.apiKeyRepo.GetByHash(ctx, hashAPIKey(key))
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to get API key", err)
+	}
+	if apiKey == nil {
+		return nil, apperrors.NewUnauthorizedError("invalid API key")
+	}
+
+	now := time.Now()
+	if apiKey.IsExpired(now) {
+		return nil, apperrors.NewUnauthorizedError("API key has expired")
+	}
+
+	userEntity, err := s.userRepo.GetByID(ctx, apiKey.UserID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to get user", err)
+	}
+	if userEntity == nil || !userEntity.IsActive() {
+		return nil, apperrors.NewUnauthorizedError("user account is inactive")
+	}
+
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) >= apiKeyLastUsedInterval {
+		if err := s.apiKeyRepo.UpdateLastUsed(ctx, apiKey.ID, now); err != nil {
+			s.logger.Warnf("Failed to update last use of API key %s: %v", apiKey.ID, err)
+		} else {
+			apiKey.LastUsedAt = &now
+		}
+	}
+
+	return &contracts.Principal{
+		User:   userEntity,
+		APIKey: apiKey,
+	}, nil
+}
+
+func (s *AuthService) apiKeyExpiry(requested *time.Time) (*time.Time, error) {
+	now := time.Now()
+
+	if requested == nil {
+		if s.apiKeyConfig.DefaultTTL <= 0 {
+			return nil, nil
+		}
+		expiresAt := now.Add(s.apiKeyConfig.DefaultTTL)
+		return &expiresAt, nil
+	}
+
+	if !requested.After(now) {
+		return nil, apperrors.NewValidationError("expiry must be in the future", nil)
+	}
+	if s.apiKeyConfig.MaxTTL > 0 && requested.After(now.Add(s.apiKeyConfig.MaxTTL)) {
+		return nil, apperrors.NewValidationError(fmt.Sprintf("API keys cannot be valid for longer than %s", s.apiKeyConfig.MaxTTL), nil)
+	}
+	return requested, nil
+}
+
+func normalizeAPIKeyScopes(scopes []string) ([]string, error) {
+	seen := make(map[string]bool, len(scopes))
+	result := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		name, err := auth.NewPermissionName(scope)
+		if err != nil {
+			return nil, apperrors.NewValidationError(fmt.Sprintf("invalid scope %q", scope), err)
+		}
+		if !seen[name.String()] {
+			seen[name.String()] = true
+			result = append(result, name.String())
+		}
+	}
+	if len(result) == 0 {
+		return nil, apperrors.NewValidationError("at least one scope is required", nil)
+	}
+	return result, nil
+}
+
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
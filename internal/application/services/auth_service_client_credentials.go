@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"strings"
+
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/auth"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
+	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/config"
+	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/security"
+	apperrors "github.com/tranvuongduy2003/go-mvc/pkg/errors"
+	"github.com/tranvuongduy2003/go-mvc/pkg/jwt"
+	"github.com/tranvuongduy2003/go-mvc/pkg/oauth"
+)
+
+// unknownClientSecretHash has the length of a real hash but matches no secret
+var unknownClientSecretHash = strings.Repeat("0", 64)
+
+var _ contracts.ClientCredentialsService = (*AuthService)(nil)
+
+func (s *AuthService) AuthenticateClient(ctx context.Context, clientID, clientSecret string) (*auth.OAuthClient, error) {
+	client, ok := s.oauthClients[clientID]
+
+	// Unknown clients still go through the comparison so response times do
+	// not reveal which client IDs exist
+	expected := unknownClientSecretHash
+	if ok {
+		expected = client.SecretHash
+	}
+	matches := security.SecureCompare(oauth.HashClientSecret(clientSecret), expected)
+	if !ok || !matches {
+		return nil, oauth.NewError(oauth.ErrorInvalidClient, "client authentication failed")
+	}
+
+	return client, nil
+}
+
+// IssueClientToken implements the client_credentials grant. Without a scope
+// parameter the token gets every scope the client is registered for.
+func (s *AuthService) IssueClientToken(ctx context.Context, clientID, clientSecret, scope string) (*contracts.ClientToken, error) {
+	client, err := s.AuthenticateClient(ctx, clientID, clientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	scopes := oauth.ParseScope(scope)
+	if len(scopes) == 0 {
+		scopes = client.Scopes
+	}
+	for _, requested := range scopes {
+		if !client.AllowsScope(requested) {
+			return nil, oauth.NewError(oauth.ErrorInvalidScope, "scope "+requested+" is not allowed for this client")
+		}
+	}
+
+	ttl := client.TokenTTL
+	if ttl <= 0 {
+		ttl = s.oauthConfig.DefaultTokenTTL
+	}
+
+	accessToken, err := s.jwtService.GenerateClientToken(client.ClientID, scopes, ttl)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to generate access token", err)
+	}
+
+	return &contracts.ClientToken{
+		AccessToken: accessToken,
+		TokenType:   oauth.TokenTypeBearer,
+		ExpiresIn:   int64(ttl.Seconds()),
+		Scope:       oauth.FormatScope(scopes),
+	}, nil
+}
+
+// authenticateClientToken accepts a client token only while its client is
+// still registered, and only for the scopes the client still holds.
+func (s *AuthService) authenticateClientToken(claims *jwt.Claims) (*contracts.Principal, error) {
+	client, ok := s.oauthClients[claims.ClientID]
+	if !ok {
+		return nil, apperrors.NewUnauthorizedError("client is no longer registered")
+	}
+
+	var scopes []string
+	for _, scope := range oauth.ParseScope(claims.Scope) {
+		if client.AllowsScope(scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	return &contracts.Principal{
+		ClientID: client.ClientID,
+		Scopes:   scopes,
+	}, nil
+}
+
+func newOAuthClients(clients []config.OAuthClient) map[string]*auth.OAuthClient {
+	result := make(map[string]*auth.OAuthClient, len(clients))
+	for _, client := range clients {
+		if client.ClientID == "" || client.SecretHash == "" {
+			continue
+		}
+		result[client.ClientID] = &auth.OAuthClient{
+			ClientID:   client.ClientID,
+			Name:       client.Name,
+			SecretHash: client.SecretHash,
+			Scopes:     client.Scopes,
+			TokenTTL:   client.TokenTTL,
+		}
+	}
+	return result
+}
@@ -0,0 +1,182 @@
+package services
+
+import (
+	"context"
+
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/user"
+	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/cache"
+	apperrors "github.com/tranvuongduy2003/go-mvc/pkg/errors"
+)
+
+// pendingEmailChange is stored under the hashes of both the confirmation
+// token sent to the new address and the revert token sent to the old one.
+type pendingEmailChange struct {
+	UserID           string `json:"user_id"`
+	OldEmail         string `json:"old_email"`
+	NewEmail         string `json:"new_email"`
+	ConfirmTokenHash string `json:"confirm_token_hash,omitempty"`
+}
+
+var _ contracts.EmailChangeService = (*AuthService)(nil)
+
+// RequestEmailChange sends a confirmation link to the new address and a
+// notice with a revert link to the current one. Nothing changes until the
+// new address is confirmed.
+func (s *AuthService) RequestEmailChange(ctx context.Context, userID, newEmail, password string) error {
+	userEntity, err := s.getActiveUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if !userEntity.VerifyPassword(password, s.passwordHasher) {
+		return apperrors.NewUnauthorizedError("invalid password")
+	}
+
+	email, err := user.NewEmail(newEmail)
+	if err != nil {
+		return apperrors.NewValidationError("invalid email", err)
+	}
+	if email.String() == userEntity.Email() {
+		return apperrors.NewValidationError("new email must differ from the current email", nil)
+	}
+
+	if err := s.ensureEmailAvailable(ctx, email.String()); err != nil {
+		return err
+	}
+
+	confirmToken, err := s.tokenGenerator.Generate(32)
+	if err != nil {
+		return apperrors.NewInternalError("failed to generate confirmation token", err)
+	}
+	revertToken, err := s.tokenGenerator.Generate(32)
+	if err != nil {
+		return apperrors.NewInternalError("failed to generate revert token", err)
+	}
+
+	change := pendingEmailChange{
+		UserID:   userEntity.ID(),
+		OldEmail: userEntity.Email(),
+		NewEmail: email.String(),
+	}
+
+	confirmOptions := &cache.CacheOptions{TTL: s.emailChangeConfig.ConfirmTTL}
+	change.ConfirmTokenHash = hashAccountToken(confirmToken)
+	if err := s.cacheService.Set(ctx, s.emailChange+change.ConfirmTokenHash, change, confirmOptions); err != nil {
+		return apperrors.NewInternalError("failed to store email change", err)
+	}
+
+	revertOptions := &cache.CacheOptions{TTL: s.emailChangeConfig.RevertTTL}
+	if err := s.cacheService.Set(ctx, s.emailChangeRevert+hashAccountToken(revertToken), change, revertOptions); err != nil {
+		return apperrors.NewInternalError("failed to store email change", err)
+	}
+
+	if err := s.smtpService.SendEmailChangeConfirmation(ctx, change.NewEmail, userEntity.Name(), confirmToken); err != nil {
+		s.logger.Errorf("Failed to send email change confirmation: %v", err)
+	}
+	if err := s.smtpService.SendEmailChangeNotice(ctx, change.OldEmail, userEntity.Name(), change.NewEmail, revertToken); err != nil {
+		s.logger.Errorf("Failed to send email change notice: %v", err)
+	}
+
+	return nil
+}
+
+func (s *AuthService) ConfirmEmailChange(ctx context.Context, token string) error {
+	change, err := s.consumeEmailChange(ctx, s.emailChange, token)
+	if err != nil {
+		return err
+	}
+
+	userEntity, err := s.userRepo.GetByID(ctx, change.UserID)
+	if err != nil {
+		return apperrors.NewInternalError("failed to get user", err)
+	}
+	if userEntity == nil || userEntity.Email() != change.OldEmail {
+		return apperrors.NewConflictError("email change is no longer valid", nil)
+	}
+
+	// The address may have been claimed since the change was requested
+	if err := s.ensureEmailAvailable(ctx, change.NewEmail); err != nil {
+		return err
+	}
+
+	return s.applyEmailChange(ctx, userEntity, change.NewEmail)
+}
+
+// RevertEmailChange lets the owner of the old address cancel a pending
+// change or undo a confirmed one. Either way every session is signed out,
+// since someone with access to the account asked for the change.
+func (s *AuthService) RevertEmailChange(ctx context.Context, token string) error {
+	change, err := s.consumeEmailChange(ctx, s.emailChangeRevert, token)
+	if err != nil {
+		return err
+	}
+
+	userEntity, err := s.userRepo.GetByID(ctx, change.UserID)
+	if err != nil {
+		return apperrors.NewInternalError("failed to get user", err)
+	}
+	if userEntity == nil {
+		return apperrors.NewNotFoundError("user not found")
+	}
+
+	switch userEntity.Email() {
+	case change.OldEmail:
+		if change.ConfirmTokenHash != "" {
+			_ = s.cacheService.Delete(ctx, s.emailChange+change.ConfirmTokenHash)
+		}
+		return s.LogoutAll(ctx, userEntity.ID())
+	case change.NewEmail:
+		if err := s.ensureEmailAvailable(ctx, change.OldEmail); err != nil {
+			return err
+		}
+		return s.applyEmailChange(ctx, userEntity, change.OldEmail)
+	default:
+		return apperrors.NewConflictError("email change is no longer valid", nil)
+	}
+}
+
+func (s *AuthService) applyEmailChange(ctx context.Context, userEntity *user.User, email string) error {
+	if err := userEntity.ChangeEmail(email); err != nil {
+		return apperrors.NewValidationError("invalid email", err)
+	}
+	if err := s.userRepo.Update(ctx, userEntity); err != nil {
+		return saveUserError("failed to save user", err)
+	}
+	s.publishUserEvents(ctx, userEntity)
+
+	if err := s.userRepo.IncrementTokenVersion(ctx, userEntity.ID()); err != nil {
+		return apperrors.NewInternalError("failed to revoke tokens", err)
+	}
+
+	return s.revokeAllSessions(ctx, userEntity.ID())
+}
+
+func (s *AuthService) ensureEmailAvailable(ctx context.Context, email string) error {
+	exists, err := s.userRepo.ExistsByEmail(ctx, email)
+	if err != nil {
+		return apperrors.NewInternalError("failed to check email", err)
+	}
+	if exists {
+		return apperrors.NewConflictError("email is already in use", nil)
+	}
+	return nil
+}
+
+// consumeEmailChange takes a pending change with an atomic GET-and-delete,
+// so of two concurrent requests with the same token only one gets it.
+func (s *AuthService) consumeEmailChange(ctx context.Context, prefix, token string) (*pendingEmailChange, error) {
+	if token == "" {
+		return nil, apperrors.NewValidationError("invalid or expired token", nil)
+	}
+
+	var change pendingEmailChange
+	if err := s.cacheService.GetDel(ctx, prefix+hashAccountToken(token), &change); err != nil {
+		if err == cache.ErrCacheMiss {
+			return nil, apperrors.NewValidationError("invalid or expired token", nil)
+		}
+		return nil, apperrors.NewInternalError("failed to get email change", err)
+	}
+
+	return &change, nil
+}
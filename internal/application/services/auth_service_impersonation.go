@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/auth"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
+	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/cache"
+	apperrors "github.com/tranvuongduy2003/go-mvc/pkg/errors"
+)
+
+var _ contracts.ImpersonationService = (*AuthService)(nil)
+
+// ImpersonateUser lets an admin act as another user for a short while. The
+// token gets a session of its own, which the user sees in their session
+// list and can revoke, and names the admin in its "act" claim so every
+// request made with it can be attributed.
+func (s *AuthService) ImpersonateUser(ctx context.Context, adminID, userID string, client contracts.ClientInfo) (*contracts.Impersonation, error) {
+	if adminID == userID {
+		return nil, apperrors.NewValidationError("you cannot impersonate yourself", nil)
+	}
+
+	admin, err := s.getActiveUser(ctx, adminID)
+	if err != nil {
+		return nil, err
+	}
+
+	userEntity, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to get user", err)
+	}
+	if userEntity == nil {
+		return nil, apperrors.NewNotFoundError("user not found")
+	}
+	if !userEntity.IsActive() {
+		return nil, apperrors.NewConflictError("cannot impersonate an inactive user", nil)
+	}
+
+	uid, err := uuid.Parse(userEntity.ID())
+	if err != nil {
+		return nil, apperrors.NewInternalError("invalid user ID format", err)
+	}
+
+	ttl := s.impersonationConfig.TokenTTL
+	sessionID := uuid.New().String()
+
+	opts, err := s.accessTokenOptions(ctx, userEntity, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	opts.ActorID = admin.ID()
+	opts.Expiry = ttl
+
+	accessToken, err := s.jwtService.GenerateAccessToken(uid, userEntity.Email(), opts)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to generate access token", err)
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(ttl)
+
+	// No refresh token is issued, so the family only marks the session alive
+	// until the access token expires.
+	family := refreshTokenFamily{UserID: userEntity.ID()}
+	if err := s.cacheService.Set(ctx, s.refreshFamily+sessionID, family, &cache.CacheOptions{TTL: ttl}); err != nil {
+		return nil, apperrors.NewInternalError("failed to store impersonation session", err)
+	}
+
+	session := &auth.Session{
+		ID:         sessionID,
+		UserID:     userEntity.ID(),
+		DeviceName: fmt.Sprintf("Impersonated by %s", admin.Email()),
+		UserAgent:  client.UserAgent,
+		IPAddress:  client.IPAddress,
+		CreatedAt:  now,
+		LastSeenAt: now,
+		ExpiresAt:  expiresAt,
+	}
+	if err := s.sessionRepo.Create(ctx, session); err != nil {
+		_ = s.revokeRefreshTokenFamily(ctx, sessionID)
+		return nil, apperrors.NewInternalError("failed to create session", err)
+	}
+
+	if err := s.auditLogRepo.Create(ctx, &auth.AuditLogEntry{
+		ActorID:   admin.ID(),
+		UserID:    userEntity.ID(),
+		SessionID: sessionID,
+		Action:    auth.AuditActionImpersonationStarted,
+		IPAddress: client.IPAddress,
+		UserAgent: client.UserAgent,
+		CreatedAt: now,
+	}); err != nil {
+		_ = s.revokeSession(ctx, sessionID)
+		return nil, apperrors.NewInternalError("failed to record impersonation", err)
+	}
+
+	s.logger.Infof("Admin %s started impersonating user %s in session %s", admin.ID(), userEntity.ID(), sessionID)
+
+	return &contracts.Impersonation{
+		User:        userEntity,
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		SessionID:   sessionID,
+		ExpiresAt:   expiresAt,
+	}, nil
+}
+
+func (s *AuthService) RecordImpersonatedRequest(ctx context.Context, entry *auth.AuditLogEntry) error {
+	entry.Action = auth.AuditActionImpersonatedRequest
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+
+	if err := s.auditLogRepo.Create(ctx, entry); err != nil {
+		return apperrors.NewInternalError("failed to record impersonated request", err)
+	}
+	return nil
+}
+
+// authenticateActor keeps an impersonation token working only while the
+// user behind it is still an active admin, so demoting an admin ends the
+// impersonations they started.
+func (s *AuthService) authenticateActor(ctx context.Context, actorID string) error {
+	actor, err := s.userRepo.GetByID(ctx, actorID)
+	if err != nil {
+		return apperrors.NewInternalError("failed to get user", err)
+	}
+	if actor == nil || !actor.IsActive() {
+		return apperrors.NewUnauthorizedError("impersonation is no longer allowed")
+	}
+
+	isAdmin, err := s.authzService.IsAdmin(ctx, actorID)
+	if err != nil {
+		return apperrors.NewInternalError("failed to check impersonator roles", err)
+	}
+	if !isAdmin {
+		return apperrors.NewUnauthorizedError("impersonation is no longer allowed")
+	}
+	return nil
+}
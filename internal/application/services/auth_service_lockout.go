@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/job"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/user"
+	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/cache"
+	apperrors "github.com/tranvuongduy2003/go-mvc/pkg/errors"
+)
+
+var _ contracts.AccountLockoutService = (*AuthService)(nil)
+
+// checkLoginAllowed rejects a login while the email or the client IP is
+// serving a delay or lock from earlier failures. It runs before the password
+// is checked so a locked account cannot be probed.
+func (s *AuthService) checkLoginAllowed(ctx context.Context, email, ipAddress string) error {
+	for _, key := range s.lockoutKeys(email, ipAddress) {
+		remaining, err := s.cacheService.TTL(ctx, s.loginBlocked+key)
+		if err != nil {
+			return apperrors.NewInternalError("failed to check login lockout", err)
+		}
+		if remaining > 0 {
+			return apperrors.NewRateLimitedError(fmt.Sprintf(
+				"too many failed login attempts, try again in %d seconds",
+				int(math.Ceil(remaining.Seconds())),
+			))
+		}
+	}
+	return nil
+}
+
+// recordLoginFailure counts a failed password or MFA code for both the email and the IP
+// and blocks further attempts as the counters grow. The counters live in
+// Redis so every API instance enforces the same state.
+func (s *AuthService) recordLoginFailure(ctx context.Context, email, ipAddress string, userEntity *user.User) {
+	emailKey := "email:" + normalizeEmail(email)
+	failures, err := s.incrementLoginFailures(ctx, emailKey)
+	if err != nil {
+		s.logger.Warnf("Failed to record failed login for %s: %v", email, err)
+	} else if delay := s.lockoutDelay(failures); delay > 0 {
+		s.blockLogin(ctx, emailKey, delay)
+
+		if failures == int64(s.lockoutConfig.MaxAttempts) && userEntity != nil {
+			s.notifyUnusualSignIn(ctx, userEntity, int(failures), ipAddress, time.Now().Add(delay))
+		}
+	}
+
+	if ipAddress == "" {
+		return
+	}
+	ipKey := "ip:" + ipAddress
+	failures, err = s.incrementLoginFailures(ctx, ipKey)
+	if err != nil {
+		s.logger.Warnf("Failed to record failed login from %s: %v", ipAddress, err)
+	} else if failures >= int64(s.lockoutConfig.IPMaxAttempts) {
+		s.blockLogin(ctx, ipKey, s.lockoutConfig.LockDuration)
+	}
+}
+
+// clearLoginFailures resets the email counter once a login passed every factor.
+// The IP counter is left to expire so one valid account cannot be used to
+// launder guesses against others from the same address.
+func (s *AuthService) clearLoginFailures(ctx context.Context, email string) {
+	emailKey := "email:" + normalizeEmail(email)
+	if err := s.cacheService.Delete(ctx, s.loginFailures+emailKey); err != nil {
+		s.logger.Warnf("Failed to clear failed logins for %s: %v", email, err)
+	}
+}
+
+func (s *AuthService) UnlockAccount(ctx context.Context, userID string) error {
+	userEntity, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return apperrors.NewInternalError("failed to get user", err)
+	}
+	if userEntity == nil {
+		return apperrors.NewNotFoundError("user not found")
+	}
+
+	emailKey := "email:" + normalizeEmail(userEntity.Email())
+	if err := s.cacheService.Delete(ctx, s.loginFailures+emailKey); err != nil {
+		return apperrors.NewInternalError("failed to unlock account", err)
+	}
+	if err := s.cacheService.Delete(ctx, s.loginBlocked+emailKey); err != nil {
+		return apperrors.NewInternalError("failed to unlock account", err)
+	}
+
+	return nil
+}
+
+func (s *AuthService) incrementLoginFailures(ctx context.Context, key string) (int64, error) {
+	failures, err := s.cacheService.Increment(ctx, s.loginFailures+key)
+	if err != nil {
+		return 0, err
+	}
+	if failures == 1 {
+		if err := s.cacheService.Expire(ctx, s.loginFailures+key, s.lockoutConfig.Window); err != nil {
+			return 0, err
+		}
+	}
+	return failures, nil
+}
+
+// lockoutDelay returns how long the next attempt must wait after the given
+// number of consecutive failures.
+func (s *AuthService) lockoutDelay(failures int64) time.Duration {
+	if failures >= int64(s.lockoutConfig.MaxAttempts) {
+		return s.lockoutConfig.LockDuration
+	}
+
+	excess := failures - int64(s.lockoutConfig.FreeAttempts)
+	if excess <= 0 {
+		return 0
+	}
+
+	delay := s.lockoutConfig.BaseDelay
+	for i := int64(1); i < excess && delay < s.lockoutConfig.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > s.lockoutConfig.MaxDelay {
+		delay = s.lockoutConfig.MaxDelay
+	}
+	return delay
+}
+
+func (s *AuthService) blockLogin(ctx context.Context, key string, duration time.Duration) {
+	cacheOptions := &cache.CacheOptions{TTL: duration}
+	if err := s.cacheService.Set(ctx, s.loginBlocked+key, time.Now().Add(duration), cacheOptions); err != nil {
+		s.logger.Warnf("Failed to block login for %s: %v", key, err)
+	}
+}
+
+// notifyUnusualSignIn queues the warning rather than sending it, keeping
+// mail delivery off the failing login.
+func (s *AuthService) notifyUnusualSignIn(ctx context.Context, userEntity *user.User, failures int, ipAddress string, lockedUntil time.Time) {
+	_, err := s.jobService.SubmitJob(ctx, job.JobTypeUnusualSignIn, job.JobPayload{
+		"to":              userEntity.Email(),
+		"name":            userEntity.Name(),
+		"failed_attempts": failures,
+		"ip_address":      ipAddress,
+		"locked_until":    lockedUntil.UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		s.logger.Warnf("Failed to queue unusual sign-in notification for user %s: %v", userEntity.ID(), err)
+	}
+}
+
+func (s *AuthService) lockoutKeys(email, ipAddress string) []string {
+	keys := []string{"email:" + normalizeEmail(email)}
+	if ipAddress != "" {
+		keys = append(keys, "ip:"+ipAddress)
+	}
+	return keys
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,206 @@
+package services
+
+import (
+	"context"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/auth"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/job"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/user"
+	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/cache"
+	apperrors "github.com/tranvuongduy2003/go-mvc/pkg/errors"
+	"github.com/tranvuongduy2003/go-mvc/pkg/geoip"
+	"github.com/tranvuongduy2003/go-mvc/pkg/useragent"
+)
+
+const (
+	loginAlertNewDevice        = "new_device"
+	loginAlertImpossibleTravel = "impossible_travel"
+
+	// impossibleTravelMinDistance ignores jumps that GeoIP inaccuracy or a
+	// change of mobile network can explain on their own, in kilometres.
+	impossibleTravelMinDistance = 300
+)
+
+var _ contracts.LoginHistoryService = (*AuthService)(nil)
+
+func (s *AuthService) GetLoginHistory(ctx context.Context, userID string, limit int) ([]*auth.LoginAttempt, error) {
+	if limit <= 0 || limit > s.loginHistoryConfig.PageSize {
+		limit = s.loginHistoryConfig.PageSize
+	}
+
+	attempts, err := s.loginAttemptRepo.GetByUserID(ctx, userID, limit)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to get login history", err)
+	}
+	return attempts, nil
+}
+
+// SecureAccount is the "this wasn't me" action of a login alert. The link
+// works once; signing out everywhere also revokes the suspicious session.
+func (s *AuthService) SecureAccount(ctx context.Context, token string) error {
+	if token == "" {
+		return apperrors.NewUnauthorizedError("link is invalid or has expired")
+	}
+
+	var userID string
+	if err := s.cacheService.GetDel(ctx, s.loginAlert+token, &userID); err != nil {
+		if err == cache.ErrCacheMiss {
+			return apperrors.NewUnauthorizedError("link is invalid or has expired")
+		}
+		return apperrors.NewInternalError("failed to get login alert", err)
+	}
+
+	if err := s.LogoutAll(ctx, userID); err != nil {
+		return err
+	}
+
+	s.logger.Infof("User %s reported a login they did not make, all sessions revoked", userID)
+	return nil
+}
+
+// recordSuccessfulLogin adds a login to the history and alerts the user
+// when it comes from a new device or implies impossible travel. Nothing
+// here may fail the login itself.
+func (s *AuthService) recordSuccessfulLogin(ctx context.Context, userEntity *user.User, client contracts.ClientInfo, amr []string) {
+	attempt := s.newLoginAttempt(userEntity, userEntity.Email(), client)
+	attempt.Success = true
+	attempt.Methods = amr
+
+	// Compared against the history before this login joins it
+	reason, err := s.detectSuspiciousLogin(ctx, attempt)
+	if err != nil {
+		s.logger.Warnf("Failed to check login of user %s for anomalies: %v", userEntity.ID(), err)
+	}
+
+	if err := s.loginAttemptRepo.Create(ctx, attempt); err != nil {
+		s.logger.Warnf("Failed to record login of user %s: %v", userEntity.ID(), err)
+	}
+
+	if reason != "" {
+		if err := s.queueLoginAlert(ctx, userEntity, attempt, reason); err != nil {
+			s.logger.Warnf("Failed to queue login alert for user %s: %v", userEntity.ID(), err)
+		}
+	}
+}
+
+// recordFailedLogin adds a failed attempt to the history. userEntity is
+// nil when the email does not belong to any account.
+func (s *AuthService) recordFailedLogin(ctx context.Context, userEntity *user.User, email string, client contracts.ClientInfo, reason string) {
+	attempt := s.newLoginAttempt(userEntity, email, client)
+	attempt.FailureReason = reason
+
+	if err := s.loginAttemptRepo.Create(ctx, attempt); err != nil {
+		s.logger.Warnf("Failed to record failed login for %s: %v", email, err)
+	}
+}
+
+func (s *AuthService) newLoginAttempt(userEntity *user.User, email string, client contracts.ClientInfo) *auth.LoginAttempt {
+	info := useragent.Parse(client.UserAgent)
+
+	attempt := &auth.LoginAttempt{
+		Email:      normalizeEmail(email),
+		IPAddress:  client.IPAddress,
+		UserAgent:  client.UserAgent,
+		Browser:    info.Browser,
+		OS:         info.OS,
+		DeviceType: info.Device,
+		DeviceKey:  strings.Join([]string{info.Browser, info.OS, info.Device}, "/"),
+		CreatedAt:  time.Now(),
+	}
+	if userEntity != nil {
+		attempt.UserID = userEntity.ID()
+	}
+
+	if location := s.locate(client.IPAddress); location != nil {
+		attempt.CountryCode = location.CountryCode
+		attempt.Country = location.Country
+		attempt.City = location.City
+		if location.HasCoordinates {
+			attempt.Latitude = &location.Latitude
+			attempt.Longitude = &location.Longitude
+		}
+	}
+
+	return attempt
+}
+
+func (s *AuthService) locate(ipAddress string) *geoip.Location {
+	if s.geoIP == nil {
+		return nil
+	}
+
+	ip := net.ParseIP(ipAddress)
+	if ip == nil {
+		return nil
+	}
+
+	location, err := s.geoIP.Lookup(ip)
+	if err != nil {
+		s.logger.Warnf("GeoIP lookup of %s failed: %v", ipAddress, err)
+		return nil
+	}
+	return location
+}
+
+// detectSuspiciousLogin compares a login with the user's earlier ones. The
+// very first login has nothing to compare with and never alerts.
+func (s *AuthService) detectSuspiciousLogin(ctx context.Context, attempt *auth.LoginAttempt) (string, error) {
+	last, err := s.loginAttemptRepo.GetLastSuccessful(ctx, attempt.UserID)
+	if err != nil || last == nil {
+		return "", err
+	}
+
+	if last.HasLocation() && attempt.HasLocation() {
+		distance := geoip.Distance(
+			&geoip.Location{Latitude: *last.Latitude, Longitude: *last.Longitude},
+			&geoip.Location{Latitude: *attempt.Latitude, Longitude: *attempt.Longitude},
+		)
+		hours := attempt.CreatedAt.Sub(last.CreatedAt).Hours()
+		if distance >= impossibleTravelMinDistance && distance > s.loginHistoryConfig.ImpossibleTravelSpeed*hours {
+			return loginAlertImpossibleTravel, nil
+		}
+	}
+
+	known, err := s.loginAttemptRepo.HasSucceededFromDevice(ctx, attempt.UserID, attempt.DeviceKey)
+	if err != nil {
+		return "", err
+	}
+	if !known {
+		return loginAlertNewDevice, nil
+	}
+
+	return "", nil
+}
+
+func (s *AuthService) queueLoginAlert(ctx context.Context, userEntity *user.User, attempt *auth.LoginAttempt, reason string) error {
+	token, err := s.tokenGenerator.Generate(32)
+	if err != nil {
+		return err
+	}
+
+	cacheOptions := &cache.CacheOptions{TTL: s.loginHistoryConfig.AlertLinkTTL}
+	if err := s.cacheService.Set(ctx, s.loginAlert+token, userEntity.ID(), cacheOptions); err != nil {
+		return err
+	}
+
+	location := "Unknown location"
+	if attempt.City != "" || attempt.Country != "" {
+		location = (&geoip.Location{City: attempt.City, Country: attempt.Country}).String()
+	}
+
+	_, err = s.jobService.SubmitJob(ctx, job.JobTypeLoginAlert, job.JobPayload{
+		"to":         userEntity.Email(),
+		"name":       userEntity.Name(),
+		"reason":     reason,
+		"device":     useragent.Info{Browser: attempt.Browser, OS: attempt.OS}.String(),
+		"location":   location,
+		"ip_address": attempt.IPAddress,
+		"time":       attempt.CreatedAt.UTC().Format(time.RFC3339),
+		"token":      token,
+	})
+	return err
+}
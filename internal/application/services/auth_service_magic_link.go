@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/job"
+	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/cache"
+	apperrors "github.com/tranvuongduy2003/go-mvc/pkg/errors"
+	"github.com/tranvuongduy2003/go-mvc/pkg/jwt"
+)
+
+type magicLink struct {
+	UserID string `json:"user_id"`
+}
+
+var _ contracts.MagicLinkService = (*AuthService)(nil)
+
+// RequestMagicLink emails a single-use sign-in link. Unknown or inactive
+// addresses get the same response without an email, so the endpoint does
+// not reveal which accounts exist. The email is queued rather than sent
+// inline, so the response time does not reveal it either.
+func (s *AuthService) RequestMagicLink(ctx context.Context, email string) (*contracts.MagicLinkRequest, error) {
+	if err := s.limitMagicLinkRequests(ctx, email); err != nil {
+		return nil, err
+	}
+
+	nonce, err := s.tokenGenerator.Generate(16)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to generate nonce", err)
+	}
+	expiresAt := time.Now().Add(s.magicLinkConfig.TokenTTL)
+
+	userEntity, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to get user", err)
+	}
+	if userEntity != nil && userEntity.IsActive() {
+		token, err := s.tokenGenerator.Generate(32)
+		if err != nil {
+			return nil, apperrors.NewInternalError("failed to generate sign-in token", err)
+		}
+
+		cacheOptions := &cache.CacheOptions{TTL: s.magicLinkConfig.TokenTTL}
+		if err := s.cacheService.Set(ctx, s.magicLinkKey(token, nonce), magicLink{UserID: userEntity.ID()}, cacheOptions); err != nil {
+			return nil, apperrors.NewInternalError("failed to store sign-in token", err)
+		}
+
+		if _, err := s.jobService.SubmitJob(ctx, job.JobTypeMagicLink, job.JobPayload{
+			"to":         userEntity.Email(),
+			"name":       userEntity.Name(),
+			"token":      token,
+			"expires_at": expiresAt.UTC().Format(time.RFC3339),
+		}); err != nil {
+			s.logger.Errorf("Failed to queue magic link email: %v", err)
+		}
+	}
+
+	return &contracts.MagicLinkRequest{
+		Nonce:     nonce,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// ConsumeMagicLink signs in with a link token. The token is only found
+// together with the nonce held by the browser that requested it, and is
+// removed atomically so it works once across all instances. Following the
+// link proves control of the address, so it also verifies the email.
+func (s *AuthService) ConsumeMagicLink(ctx context.Context, token, nonce string, client contracts.ClientInfo) (*contracts.AuthenticatedUser, error) {
+	if token == "" || nonce == "" {
+		return nil, apperrors.NewUnauthorizedError("sign-in link is invalid or has expired")
+	}
+
+	var link magicLink
+	if err := s.cacheService.GetDel(ctx, s.magicLinkKey(token, nonce), &link); err != nil {
+		if err == cache.ErrCacheMiss {
+			return nil, apperrors.NewUnauthorizedError("sign-in link is invalid or has expired")
+		}
+		return nil, apperrors.NewInternalError("failed to get sign-in token", err)
+	}
+
+	userEntity, err := s.getActiveUser(ctx, link.UserID)
+	if err != nil {
+		return nil, apperrors.NewUnauthorizedError("user account is inactive")
+	}
+
+	if !userEntity.IsEmailVerified() {
+		if err := userEntity.VerifyEmail(); err == nil {
+			if err := s.userRepo.Update(ctx, userEntity); err != nil {
+				return nil, saveUserError("failed to save user", err)
+			}
+			s.publishUserEvents(ctx, userEntity)
+			if err := s.authzService.InvalidateUserAuthorization(ctx, userEntity.ID()); err != nil {
+				s.logger.Errorf("Failed to invalidate authorization of user %s: %v", userEntity.ID(), err)
+			}
+		}
+	}
+
+	if userEntity.MFA().IsEnabled() {
+		challenge, err := s.createMFAChallenge(ctx, userEntity, client, []string{jwt.AMREmailLink})
+		if err != nil {
+			return nil, apperrors.NewInternalError("failed to create MFA challenge", err)
+		}
+
+		return &contracts.AuthenticatedUser{
+			User:         userEntity,
+			MFAChallenge: challenge,
+		}, nil
+	}
+
+	tokens, err := s.generateTokens(ctx, userEntity, client, []string{jwt.AMREmailLink})
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to generate tokens", err)
+	}
+
+	return &contracts.AuthenticatedUser{
+		User:   userEntity,
+		Tokens: tokens,
+	}, nil
+}
+
+func (s *AuthService) limitMagicLinkRequests(ctx context.Context, email string) error {
+	key := s.magicLinkRequests + normalizeEmail(email)
+
+	requests, err := s.cacheService.Increment(ctx, key)
+	if err != nil {
+		return apperrors.NewInternalError("failed to check sign-in link requests", err)
+	}
+	if requests == 1 {
+		if err := s.cacheService.Expire(ctx, key, s.magicLinkConfig.RequestWindow); err != nil {
+			return apperrors.NewInternalError("failed to check sign-in link requests", err)
+		}
+	}
+
+	if requests > int64(s.magicLinkConfig.MaxRequests) {
+		remaining, err := s.cacheService.TTL(ctx, key)
+		if err != nil || remaining <= 0 {
+			remaining = s.magicLinkConfig.RequestWindow
+		}
+		return apperrors.NewRateLimitedError(fmt.Sprintf(
+			"too many sign-in link requests, try again in %d seconds",
+			int(math.Ceil(remaining.Seconds())),
+		))
+	}
+
+	return nil
+}
+
+// magicLinkKey stores links under a hash of token and nonce, so neither is
+// kept in plain form and a token presented without its nonce finds nothing.
+func (s *AuthService) magicLinkKey(token, nonce string) string {
+	sum := sha256.Sum256([]byte(token + ":" + nonce))
+	return s.magicLink + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,340 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/auth"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/user"
+	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/cache"
+	apperrors "github.com/tranvuongduy2003/go-mvc/pkg/errors"
+	"github.com/tranvuongduy2003/go-mvc/pkg/jwt"
+	"github.com/tranvuongduy2003/go-mvc/pkg/totp"
+)
+
+// mfaChallenge is the state behind an "mfa_pending" login. It remembers the
+// client so the session created after verification records the right device,
+// and the first factor so the session's amr lists both.
+type mfaChallenge struct {
+	UserID string               `json:"user_id"`
+	Client contracts.ClientInfo `json:"client"`
+	AMR    []string             `json:"amr,omitempty"`
+}
+
+var _ contracts.MFAService = (*AuthService)(nil)
+
+func (s *AuthService) BeginMFAEnrollment(ctx context.Context, userID string) (*contracts.MFAEnrollment, error) {
+	userEntity, err := s.getActiveUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if userEntity.MFA().IsEnabled() {
+		return nil, apperrors.NewConflictError("MFA is already enabled", nil)
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to generate MFA secret", err)
+	}
+
+	cacheOptions := &cache.CacheOptions{TTL: s.mfaConfig.EnrollmentTTL}
+	if err := s.cacheService.Set(ctx, s.mfaEnrollment+userID, secret, cacheOptions); err != nil {
+		return nil, apperrors.NewInternalError("failed to store MFA enrollment", err)
+	}
+
+	return &contracts.MFAEnrollment{
+		Secret: secret,
+		URI:    totp.URI(s.mfaConfig.Issuer, userEntity.Email(), secret),
+	}, nil
+}
+
+func (s *AuthService) ConfirmMFAEnrollment(ctx context.Context, userID, code string) ([]string, error) {
+	userEntity, err := s.getActiveUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var secret string
+	if err := s.cacheService.Get(ctx, s.mfaEnrollment+userID, &secret); err != nil {
+		if err == cache.ErrCacheMiss {
+			return nil, apperrors.NewValidationError("no pending MFA enrollment", nil)
+		}
+		return nil, apperrors.NewInternalError("failed to get MFA enrollment", err)
+	}
+
+	if ok, err := s.consumeTOTPCode(ctx, userID, secret, code); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, apperrors.NewUnauthorizedError("invalid MFA code")
+	}
+
+	recoveryCodes, hashes, err := s.generateRecoveryCodes()
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to generate recovery codes", err)
+	}
+
+	if err := userEntity.EnableMFA(secret, hashes); err != nil {
+		return nil, apperrors.NewConflictError(err.Error(), nil)
+	}
+
+	if err := s.userRepo.Update(ctx, userEntity); err != nil {
+		return nil, saveUserError("failed to enable MFA", err)
+	}
+
+	if err := s.cacheService.Delete(ctx, s.mfaEnrollment+userID); err != nil {
+		s.logger.Warnf("Failed to clear MFA enrollment for user %s: %v", userID, err)
+	}
+
+	return recoveryCodes, nil
+}
+
+func (s *AuthService) RegenerateRecoveryCodes(ctx context.Context, userID, code string) ([]string, error) {
+	userEntity, err := s.getActiveUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !userEntity.MFA().IsEnabled() {
+		return nil, apperrors.NewValidationError("MFA is not enabled", nil)
+	}
+
+	if ok, err := s.consumeTOTPCode(ctx, userID, userEntity.MFA().Secret(), code); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, apperrors.NewUnauthorizedError("invalid MFA code")
+	}
+
+	recoveryCodes, hashes, err := s.generateRecoveryCodes()
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to generate recovery codes", err)
+	}
+
+	if err := userEntity.ReplaceRecoveryCodes(hashes); err != nil {
+		return nil, apperrors.NewValidationError(err.Error(), nil)
+	}
+
+	if err := s.userRepo.Update(ctx, userEntity); err != nil {
+		return nil, saveUserError("failed to update recovery codes", err)
+	}
+
+	return recoveryCodes, nil
+}
+
+func (s *AuthService) VerifyMFAChallenge(ctx context.Context, challengeToken, code string) (*contracts.AuthenticatedUser, error) {
+	challengeKey := s.mfaChallenge + challengeToken
+
+	var challenge mfaChallenge
+	if err := s.cacheService.Get(ctx, challengeKey, &challenge); err != nil {
+		if err == cache.ErrCacheMiss {
+			return nil, apperrors.NewUnauthorizedError("MFA challenge is invalid or has expired")
+		}
+		return nil, apperrors.NewInternalError("failed to get MFA challenge", err)
+	}
+
+	attemptsKey := challengeKey + ":attempts"
+	attempts, err := s.cacheService.Increment(ctx, attemptsKey)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to record MFA attempt", err)
+	}
+	if attempts == 1 {
+		_ = s.cacheService.Expire(ctx, attemptsKey, s.mfaConfig.ChallengeTTL)
+	}
+	if attempts > int64(s.mfaConfig.MaxAttempts) {
+		_ = s.cacheService.Delete(ctx, challengeKey)
+		return nil, apperrors.NewUnauthorizedError("too many MFA attempts, please log in again")
+	}
+
+	userEntity, err := s.getActiveUser(ctx, challenge.UserID)
+	if err != nil {
+		return nil, apperrors.NewUnauthorizedError("MFA challenge is invalid or has expired")
+	}
+
+	if !userEntity.MFA().IsEnabled() {
+		return nil, apperrors.NewUnauthorizedError("MFA challenge is invalid or has expired")
+	}
+
+	// Wrong codes count against the same lockout as wrong passwords, which
+	// also applies to challenges issued before the lock
+	if err := s.checkLoginAllowed(ctx, userEntity.Email(), challenge.Client.IPAddress); err != nil {
+		return nil, err
+	}
+
+	factor, err := s.verifyMFACode(ctx, userEntity, code)
+	if err != nil {
+		return nil, err
+	}
+	if factor == "" {
+		s.recordLoginFailure(ctx, userEntity.Email(), challenge.Client.IPAddress, userEntity)
+		s.recordFailedLogin(ctx, userEntity, userEntity.Email(), challenge.Client, auth.LoginFailureInvalidMFACode)
+		return nil, apperrors.NewUnauthorizedError("invalid MFA code")
+	}
+
+	// The challenge is single-use; losing the race means another request already redeemed it
+	consumed, err := s.cacheService.SetNX(ctx, challengeKey+":used", true, s.mfaConfig.ChallengeTTL)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to consume MFA challenge", err)
+	}
+	if !consumed {
+		return nil, apperrors.NewUnauthorizedError("MFA challenge is invalid or has expired")
+	}
+	// Only spent once the challenge is ours, so losing the race keeps the code
+	if factor == jwt.AMRRecovery {
+		if err := s.consumeRecoveryCode(ctx, userEntity, code); err != nil {
+			return nil, err
+		}
+	}
+	_ = s.cacheService.Delete(ctx, challengeKey)
+	_ = s.cacheService.Delete(ctx, attemptsKey)
+	s.clearLoginFailures(ctx, userEntity.Email())
+
+	amr := append(challenge.AMR, factor, jwt.AMRMultiFactor)
+	tokens, err := s.generateTokens(ctx, userEntity, challenge.Client, amr)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to generate tokens", err)
+	}
+
+	return &contracts.AuthenticatedUser{
+		User:   userEntity,
+		Tokens: tokens,
+	}, nil
+}
+
+func (s *AuthService) ResetMFA(ctx context.Context, userID string) error {
+	userEntity, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return apperrors.NewInternalError("failed to get user", err)
+	}
+	if userEntity == nil {
+		return apperrors.NewNotFoundError("user not found")
+	}
+
+	userEntity.ResetMFA()
+
+	if err := s.userRepo.Update(ctx, userEntity); err != nil {
+		return saveUserError("failed to reset MFA", err)
+	}
+
+	if err := s.cacheService.Delete(ctx, s.mfaEnrollment+userID); err != nil {
+		s.logger.Warnf("Failed to clear MFA enrollment for user %s: %v", userID, err)
+	}
+
+	return nil
+}
+
+// createMFAChallenge parks a login that passed its first factor, described
+// by amr, until the second factor is presented.
+func (s *AuthService) createMFAChallenge(ctx context.Context, userEntity *user.User, client contracts.ClientInfo, amr []string) (*contracts.MFAChallenge, error) {
+	token, err := s.tokenGenerator.Generate(32)
+	if err != nil {
+		return nil, err
+	}
+
+	challenge := mfaChallenge{
+		UserID: userEntity.ID(),
+		Client: client,
+		AMR:    amr,
+	}
+	cacheOptions := &cache.CacheOptions{TTL: s.mfaConfig.ChallengeTTL}
+	if err := s.cacheService.Set(ctx, s.mfaChallenge+token, challenge, cacheOptions); err != nil {
+		return nil, err
+	}
+
+	return &contracts.MFAChallenge{
+		Token:     token,
+		ExpiresAt: time.Now().Add(s.mfaConfig.ChallengeTTL),
+	}, nil
+}
+
+// consumeTOTPCode validates code and marks its time step as used so the same
+// code cannot be replayed while it is still within the accepted window.
+func (s *AuthService) consumeTOTPCode(ctx context.Context, userID, secret, code string) (bool, error) {
+	counter, ok := totp.Validate(secret, code, time.Now(), 1)
+	if !ok {
+		return false, nil
+	}
+
+	key := fmt.Sprintf("%s%s:%d", s.mfaCodeUse, userID, counter)
+	fresh, err := s.cacheService.SetNX(ctx, key, true, 3*totp.Period)
+	if err != nil {
+		return false, apperrors.NewInternalError("failed to record MFA code use", err)
+	}
+
+	return fresh, nil
+}
+
+// verifyMFACode returns the factor the code matched, jwt.AMROTP or
+// jwt.AMRRecovery, or "" when it matches neither. A matching recovery code
+// is left unspent; consumeRecoveryCode spends it.
+func (s *AuthService) verifyMFACode(ctx context.Context, userEntity *user.User, code string) (string, error) {
+	verified, err := s.consumeTOTPCode(ctx, userEntity.ID(), userEntity.MFA().Secret(), code)
+	if err != nil {
+		return "", err
+	}
+	if verified {
+		return jwt.AMROTP, nil
+	}
+
+	if userEntity.MFA().HasRecoveryCode(hashRecoveryCode(code)) {
+		return jwt.AMRRecovery, nil
+	}
+	return "", nil
+}
+
+func (s *AuthService) consumeRecoveryCode(ctx context.Context, userEntity *user.User, code string) error {
+	codeHash := hashRecoveryCode(code)
+
+	// Guards against two concurrent logins redeeming the same code
+	claimed, err := s.cacheService.SetNX(ctx, s.mfaCodeUse+userEntity.ID()+":"+codeHash, true, 24*time.Hour)
+	if err != nil {
+		return apperrors.NewInternalError("failed to record recovery code use", err)
+	}
+	if !claimed || !userEntity.UseRecoveryCode(codeHash) {
+		return apperrors.NewUnauthorizedError("recovery code has already been used")
+	}
+
+	if err := s.userRepo.Update(ctx, userEntity); err != nil {
+		return saveUserError("failed to consume recovery code", err)
+	}
+
+	return nil
+}
+
+func (s *AuthService) generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, s.mfaConfig.RecoveryCodeCount)
+	hashes := make([]string, s.mfaConfig.RecoveryCodeCount)
+	for i := range codes {
+		raw, err := s.tokenGenerator.Generate(5)
+		if err != nil {
+			return nil, nil, err
+		}
+		codes[i] = raw[:5] + "-" + raw[5:]
+		hashes[i] = hashRecoveryCode(codes[i])
+	}
+	return codes, hashes, nil
+}
+
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+func (s *AuthService) getActiveUser(ctx context.Context, userID string) (*user.User, error) {
+	userEntity, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to get user", err)
+	}
+	if userEntity == nil {
+		return nil, apperrors.NewNotFoundError("user not found")
+	}
+	if !userEntity.IsActive() {
+		return nil, apperrors.NewUnauthorizedError("user account is inactive")
+	}
+	return userEntity, nil
+}
@@ -0,0 +1,266 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/auth"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/user"
+	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/cache"
+	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/config"
+	apperrors "github.com/tranvuongduy2003/go-mvc/pkg/errors"
+	"github.com/tranvuongduy2003/go-mvc/pkg/jwt"
+	"github.com/tranvuongduy2003/go-mvc/pkg/oidc"
+)
+
+// oidcLoginState is what the callback needs to finish an authorization
+// request: the PKCE verifier and the nonce expected in the ID token.
+type oidcLoginState struct {
+	Provider     string `json:"provider"`
+	Nonce        string `json:"nonce"`
+	CodeVerifier string `json:"code_verifier"`
+}
+
+var _ contracts.ExternalLoginService = (*AuthService)(nil)
+
+func (s *AuthService) BeginOIDCLogin(ctx context.Context, providerName string) (*contracts.OIDCAuthorization, error) {
+	provider, ok := s.oidcProviders[providerName]
+	if !ok {
+		return nil, apperrors.NewNotFoundError("identity provider not found")
+	}
+
+	state, err := oidc.GenerateVerifier()
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to generate state", err)
+	}
+	nonce, err := oidc.GenerateVerifier()
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to generate nonce", err)
+	}
+	codeVerifier, err := oidc.GenerateVerifier()
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to generate code verifier", err)
+	}
+
+	authURL, err := provider.AuthCodeURL(ctx, state, nonce, codeVerifier)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to build authorization request", err)
+	}
+
+	loginState := oidcLoginState{
+		Provider:     providerName,
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+	}
+	cacheOptions := &cache.CacheOptions{TTL: s.oidcConfig.StateTTL}
+	if err := s.cacheService.Set(ctx, s.oidcState+state, loginState, cacheOptions); err != nil {
+		return nil, apperrors.NewInternalError("failed to store login state", err)
+	}
+
+	return &contracts.OIDCAuthorization{
+		URL:       authURL,
+		State:     state,
+		ExpiresAt: time.Now().Add(s.oidcConfig.StateTTL),
+	}, nil
+}
+
+// FinishOIDCLogin redeems the authorization code and signs in the user
+// behind the ID token. Unknown identities are linked to the account with
+// the same email only when both the provider and the account have verified
+// that email; otherwise a new account is created.
+func (s *AuthService) FinishOIDCLogin(ctx context.Context, providerName, state, code string, client contracts.ClientInfo) (*contracts.AuthenticatedUser, error) {
+	provider, ok := s.oidcProviders[providerName]
+	if !ok {
+		return nil, apperrors.NewNotFoundError("identity provider not found")
+	}
+
+	loginState, err := s.consumeOIDCState(ctx, state)
+	if err != nil {
+		return nil, err
+	}
+	if loginState.Provider != providerName {
+		return nil, apperrors.NewUnauthorizedError("login state is invalid or has expired")
+	}
+
+	token, err := provider.Exchange(ctx, code, loginState.CodeVerifier)
+	if err != nil {
+		s.logger.Warnf("OIDC code exchange with %s failed: %v", providerName, err)
+		return nil, apperrors.NewUnauthorizedError("failed to exchange authorization code")
+	}
+
+	claims, err := provider.VerifyIDToken(ctx, token.IDToken, loginState.Nonce)
+	if err != nil {
+		s.logger.Warnf("OIDC ID token from %s rejected: %v", providerName, err)
+		return nil, apperrors.NewUnauthorizedError("invalid ID token")
+	}
+
+	userEntity, err := s.resolveExternalUser(ctx, providerName, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	if !userEntity.IsActive() {
+		return nil, apperrors.NewUnauthorizedError("user account is inactive")
+	}
+
+	if err := s.requireVerifiedEmail(userEntity); err != nil {
+		return nil, err
+	}
+
+	if userEntity.MFA().IsEnabled() {
+		challenge, err := s.createMFAChallenge(ctx, userEntity, client, []string{jwt.AMRFederated})
+		if err != nil {
+			return nil, apperrors.NewInternalError("failed to create MFA challenge", err)
+		}
+
+		return &contracts.AuthenticatedUser{
+			User:         userEntity,
+			MFAChallenge: challenge,
+		}, nil
+	}
+
+	tokens, err := s.generateTokens(ctx, userEntity, client, []string{jwt.AMRFederated})
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to generate tokens", err)
+	}
+
+	return &contracts.AuthenticatedUser{
+		User:   userEntity,
+		Tokens: tokens,
+	}, nil
+}
+
+func (s *AuthService) consumeOIDCState(ctx context.Context, state string) (*oidcLoginState, error) {
+	if state == "" {
+		return nil, apperrors.NewUnauthorizedError("login state is invalid or has expired")
+	}
+
+	key := s.oidcState + state
+
+	var loginState oidcLoginState
+	if err := s.cacheService.Get(ctx, key, &loginState); err != nil {
+		if err == cache.ErrCacheMiss {
+			return nil, apperrors.NewUnauthorizedError("login state is invalid or has expired")
+		}
+		return nil, apperrors.NewInternalError("failed to get login state", err)
+	}
+
+	consumed, err := s.cacheService.SetNX(ctx, key+":used", true, s.oidcConfig.StateTTL)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to consume login state", err)
+	}
+	if !consumed {
+		return nil, apperrors.NewUnauthorizedError("login state is invalid or has expired")
+	}
+	_ = s.cacheService.Delete(ctx, key)
+
+	return &loginState, nil
+}
+
+func (s *AuthService) resolveExternalUser(ctx context.Context, providerName string, claims *oidc.IDTokenClaims) (*user.User, error) {
+	identity, err := s.identityRepo.GetByProviderSubject(ctx, providerName, claims.Subject)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to get external identity", err)
+	}
+
+	if identity != nil {
+		userEntity, err := s.userRepo.GetByID(ctx, identity.UserID)
+		if err != nil {
+			return nil, apperrors.NewInternalError("failed to get user", err)
+		}
+		if userEntity == nil {
+			return nil, apperrors.NewUnauthorizedError("user account no longer exists")
+		}
+		if err := s.identityRepo.UpdateLastLogin(ctx, identity.ID, time.Now()); err != nil {
+			s.logger.Warnf("Failed to update last login of external identity %s: %v", identity.ID, err)
+		}
+		return userEntity, nil
+	}
+
+	if claims.Email == "" {
+		return nil, apperrors.NewUnauthorizedError("identity provider did not share an email address")
+	}
+
+	userEntity, err := s.userRepo.GetByEmail(ctx, claims.Email)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to get user", err)
+	}
+
+	// An unverified local account may have been registered by someone else
+	// ahead of the real owner; linking it would hand them the owner's login.
+	if userEntity != nil && (!claims.EmailVerified || !userEntity.IsEmailVerified()) {
+		return nil, apperrors.NewConflictError("an account with this email already exists", nil)
+	}
+
+	if userEntity == nil {
+		if userEntity, err = s.createExternalUser(ctx, claims); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	if err := s.identityRepo.Create(ctx, &auth.ExternalIdentity{
+		UserID:      userEntity.ID(),
+		Provider:    providerName,
+		Subject:     claims.Subject,
+		Email:       claims.Email,
+		CreatedAt:   now,
+		LastLoginAt: &now,
+	}); err != nil {
+		return nil, apperrors.NewInternalError("failed to link external identity", err)
+	}
+
+	return userEntity, nil
+}
+
+// createExternalUser registers an account for a first-time external login.
+// The account gets a random password nobody knows; the owner can set one
+// through the password reset flow.
+func (s *AuthService) createExternalUser(ctx context.Context, claims *oidc.IDTokenClaims) (*user.User, error) {
+	password, err := s.tokenGenerator.Generate(24)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to generate password", err)
+	}
+
+	name := strings.TrimSpace(claims.Name)
+	if name == "" {
+		name = strings.SplitN(claims.Email, "@", 2)[0]
+	}
+	if len(name) < 2 {
+		name = claims.Email
+	}
+
+	userEntity, err := user.NewUser(claims.Email, name, "", password, s.passwordHasher)
+	if err != nil {
+		return nil, apperrors.NewValidationError("identity provider returned an unusable profile", err)
+	}
+
+	if claims.EmailVerified {
+		if err := userEntity.VerifyEmail(); err != nil {
+			return nil, apperrors.NewInternalError("failed to mark email as verified", err)
+		}
+	}
+
+	if err := s.userRepo.Create(ctx, userEntity); err != nil {
+		return nil, apperrors.NewInternalError("failed to create user", err)
+	}
+	s.publishUserEvents(ctx, userEntity)
+
+	return userEntity, nil
+}
+
+func newOIDCProviders(providers map[string]config.OIDCProvider) map[string]*oidc.Provider {
+	result := make(map[string]*oidc.Provider, len(providers))
+	for name, provider := range providers {
+		result[name] = oidc.NewProvider(oidc.Config{
+			Issuer:       provider.Issuer,
+			ClientID:     provider.ClientID,
+			ClientSecret: provider.ClientSecret,
+			RedirectURL:  provider.RedirectURL,
+			Scopes:       provider.Scopes,
+		}, nil)
+	}
+	return result
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/auth"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/user"
+	apperrors "github.com/tranvuongduy2003/go-mvc/pkg/errors"
+	"github.com/tranvuongduy2003/go-mvc/pkg/oidc"
+)
+
+// stubUserRepository serves a single user by ID or email. Methods the tests do
+// not expect to be called panic through the nil embedded interface.
+type stubUserRepository struct {
+	user.UserRepository
+	existing *user.User
+}
+
+func (r *stubUserRepository) GetByID(ctx context.Context, id string) (*user.User, error) {
+	if r.existing != nil && r.existing.ID() == id {
+		return r.existing, nil
+	}
+	return nil, nil
+}
+
+func (r *stubUserRepository) GetByEmail(ctx context.Context, email string) (*user.User, error) {
+	if r.existing != nil && r.existing.Email() == email {
+		return r.existing, nil
+	}
+	return nil, nil
+}
+
+type stubIdentityRepository struct {
+	auth.ExternalIdentityRepository
+	created []*auth.ExternalIdentity
+}
+
+func (r *stubIdentityRepository) GetByProviderSubject(ctx context.Context, provider, subject string) (*auth.ExternalIdentity, error) {
+	return nil, nil
+}
+
+func (r *stubIdentityRepository) Create(ctx context.Context, identity *auth.ExternalIdentity) error {
+	r.created = append(r.created, identity)
+	return nil
+}
+
+func localUser(t *testing.T, email string, verified bool) *user.User {
+	t.Helper()
+
+	now := time.Now()
+	var verifiedAt *time.Time
+	if verified {
+		verifiedAt = &now
+	}
+	u, err := user.ReconstructUser(uuid.NewString(), email, "Local User", "", "hash", "", "", true, now, now, 1, 1, now, user.MFA{}, verifiedAt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u
+}
+
+func TestResolveExternalUserLinksOnlyVerifiedAccounts(t *testing.T) {
+	const email = "owner@example.com"
+
+	tests := map[string]struct {
+		localVerified    bool
+		providerVerified bool
+		link             bool
+	}{
+		"both verified":         {localVerified: true, providerVerified: true, link: true},
+		"local unverified":      {localVerified: false, providerVerified: true},
+		"provider unverified":   {localVerified: true, providerVerified: false},
+		"neither side verified": {localVerified: false, providerVerified: false},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			existing := localUser(t, email, test.localVerified)
+			identities := &stubIdentityRepository{}
+			service := &AuthService{
+				userRepo:     &stubUserRepository{existing: existing},
+				identityRepo: identities,
+			}
+
+			claims := &oidc.IDTokenClaims{Email: email, EmailVerified: test.providerVerified}
+			claims.Subject = "subject"
+
+			resolved, err := service.resolveExternalUser(context.Background(), "provider", claims)
+			if !test.link {
+				var appErr *apperrors.AppError
+				if !errors.As(err, &appErr) || appErr.Type != apperrors.ErrorTypeConflict {
+					t.Fatalf("err = %v, want a conflict", err)
+				}
+				if len(identities.created) != 0 {
+					t.Fatal("identity was linked to an unverified account")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resolved != existing {
+				t.Fatal("resolved a different user than the local account")
+			}
+			if len(identities.created) != 1 || identities.created[0].UserID != existing.ID() {
+				t.Fatalf("linked identities = %+v, want one for the local account", identities.created)
+			}
+		})
+	}
+}
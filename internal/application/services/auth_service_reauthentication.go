@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/user"
+	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/cache"
+	apperrors "github.com/tranvuongduy2003/go-mvc/pkg/errors"
+	"github.com/tranvuongduy2003/go-mvc/pkg/jwt"
+)
+
+var _ contracts.ReauthenticationService = (*AuthService)(nil)
+
+// Reauthenticate steps up an existing session. Only an access token is
+// issued: rotating the refresh token here would turn the client's copy into
+// a replay and end the session.
+func (s *AuthService) Reauthenticate(ctx context.Context, userID, sessionID string, proof contracts.Reauthentication, client contracts.ClientInfo) (*contracts.ElevatedToken, error) {
+	if proof.Password == "" && proof.Code == "" {
+		return nil, apperrors.NewValidationError("provide a password or an MFA code", nil)
+	}
+	if sessionID == "" {
+		return nil, apperrors.NewUnauthorizedError("re-authentication requires a session token")
+	}
+
+	userEntity, err := s.getActiveUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkLoginAllowed(ctx, userEntity.Email(), client.IPAddress); err != nil {
+		return nil, err
+	}
+
+	amr, err := s.verifyReauthentication(ctx, userEntity, proof)
+	if err != nil {
+		return nil, err
+	}
+	if amr == nil {
+		s.recordLoginFailure(ctx, userEntity.Email(), client.IPAddress, userEntity)
+		switch {
+		case proof.Password == "":
+			return nil, apperrors.NewUnauthorizedError("invalid MFA code")
+		case proof.Code == "":
+			return nil, apperrors.NewUnauthorizedError("invalid password")
+		default:
+			return nil, apperrors.NewUnauthorizedError("invalid password or MFA code")
+		}
+	}
+	s.clearLoginFailures(ctx, userEntity.Email())
+
+	family, err := s.getRefreshTokenFamily(ctx, sessionID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to load session", err)
+	}
+	if family == nil || family.UserID != userEntity.ID() {
+		return nil, apperrors.NewUnauthorizedError("session has been revoked")
+	}
+
+	authTime := time.Now()
+	if err := s.storeFamilyAuthentication(ctx, sessionID, authTime, amr); err != nil {
+		if err == cache.ErrCacheMiss {
+			return nil, apperrors.NewUnauthorizedError("session has been revoked")
+		}
+		return nil, apperrors.NewInternalError("failed to update session", err)
+	}
+
+	uid, err := uuid.Parse(userEntity.ID())
+	if err != nil {
+		return nil, apperrors.NewInternalError("invalid user ID format", err)
+	}
+
+	opts, err := s.accessTokenOptions(ctx, userEntity, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	opts.AuthTime = authTime
+	opts.AMR = amr
+
+	accessToken, err := s.jwtService.GenerateAccessToken(uid, userEntity.Email(), opts)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to generate access token", err)
+	}
+
+	return &contracts.ElevatedToken{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresAt:   time.Unix(s.jwtService.GetAccessTokenExpirationTime(), 0),
+		AuthTime:    authTime,
+		AuthMethods: amr,
+	}, nil
+}
+
+// verifyReauthentication returns the methods the proof satisfied, or nil
+// when it is wrong. Users with MFA enabled must present a code, so a
+// password alone never unlocks what their second factor protects. Codes are
+// checked against the authenticator; recovery codes are kept for signing
+// in.
+func (s *AuthService) verifyReauthentication(ctx context.Context, userEntity *user.User, proof contracts.Reauthentication) ([]string, error) {
+	mfaEnabled := userEntity.MFA().IsEnabled()
+	if mfaEnabled && proof.Code == "" {
+		return nil, apperrors.NewValidationError("an MFA code is required", nil)
+	}
+	if !mfaEnabled && proof.Code != "" {
+		return nil, apperrors.NewValidationError("MFA is not enabled", nil)
+	}
+
+	var amr []string
+	if proof.Password != "" {
+		if !userEntity.VerifyPassword(proof.Password, s.passwordHasher) {
+			return nil, nil
+		}
+		amr = append(amr, jwt.AMRPassword)
+	}
+
+	if proof.Code != "" {
+		verified, err := s.consumeTOTPCode(ctx, userEntity.ID(), userEntity.MFA().Secret(), proof.Code)
+		if err != nil || !verified {
+			return nil, err
+		}
+		amr = append(amr, jwt.AMROTP)
+	}
+
+	if len(amr) > 1 {
+		amr = append(amr, jwt.AMRMultiFactor)
+	}
+	return amr, nil
+}
+
+// storeFamilyAuthentication records a step-up on the session's refresh
+// token family. Only auth_time and amr are written, so a refresh rotating
+// the family at the same moment keeps its new token.
+func (s *AuthService) storeFamilyAuthentication(ctx context.Context, sessionID string, authTime time.Time, amr []string) error {
+	return s.cacheService.MergeFields(ctx, s.refreshFamily+sessionID, map[string]interface{}{
+		"auth_time": authTime.Unix(),
+		"amr":       amr,
+	})
+}
+
+// unixTime converts an optional Unix timestamp, mapping 0 to the zero time.
+func unixTime(seconds int64) time.Time {
+	if seconds == 0 {
+		return time.Time{}
+	}
+	return time.Unix(seconds, 0)
+}
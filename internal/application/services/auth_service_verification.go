@@ -0,0 +1,38 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/job"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/user"
+	apperrors "github.com/tranvuongduy2003/go-mvc/pkg/errors"
+)
+
+// queueVerificationEmail issues a fresh verification token and hands the
+// email to the job queue so the request does not wait on SMTP.
+func (s *AuthService) queueVerificationEmail(ctx context.Context, userEntity *user.User) error {
+	verificationToken, err := s.issueAccountToken(ctx, s.emailVerification, userEntity, s.verificationConfig.TokenTTL)
+	if err != nil {
+		return fmt.Errorf("failed to store verification token: %w", err)
+	}
+
+	if _, err := s.jobService.SubmitJob(ctx, job.JobTypeVerificationEmail, job.JobPayload{
+		"to":    userEntity.Email(),
+		"name":  userEntity.Name(),
+		"token": verificationToken,
+	}); err != nil {
+		return fmt.Errorf("failed to submit verification email job: %w", err)
+	}
+
+	return nil
+}
+
+// requireVerifiedEmail blocks sign-in for unverified accounts when the
+// deployment requires a confirmed address before login.
+func (s *AuthService) requireVerifiedEmail(userEntity *user.User) error {
+	if s.verificationConfig.RequiredForLogin && !userEntity.IsEmailVerified() {
+		return apperrors.NewForbiddenError("email address is not verified")
+	}
+	return nil
+}
@@ -0,0 +1,276 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"time"
+
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/auth"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
+	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/cache"
+	apperrors "github.com/tranvuongduy2003/go-mvc/pkg/errors"
+	"github.com/tranvuongduy2003/go-mvc/pkg/jwt"
+	"github.com/tranvuongduy2003/go-mvc/pkg/webauthn"
+)
+
+const (
+	ceremonyRegistration = "registration"
+	ceremonyLogin        = "login"
+)
+
+// webAuthnCeremony is the server side state of a pending registration or
+// login, keyed by the challenge handed to the browser.
+type webAuthnCeremony struct {
+	Type   string `json:"type"`
+	UserID string `json:"user_id,omitempty"`
+}
+
+var _ contracts.PasskeyService = (*AuthService)(nil)
+
+func (s *AuthService) BeginPasskeyRegistration(ctx context.Context, userID string) (*webauthn.CreationOptions, error) {
+	userEntity, err := s.getActiveUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	credentials, err := s.credentialRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to get passkeys", err)
+	}
+
+	options, err := s.relyingParty.BeginRegistration(webauthn.UserEntity{
+		ID:          webauthn.Base64URL(userEntity.ID()),
+		Name:        userEntity.Email(),
+		DisplayName: userEntity.Name(),
+	}, toCredentialDescriptors(credentials))
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to start passkey registration", err)
+	}
+
+	if err := s.storeWebAuthnCeremony(ctx, options.Challenge, webAuthnCeremony{
+		Type:   ceremonyRegistration,
+		UserID: userID,
+	}); err != nil {
+		return nil, apperrors.NewInternalError("failed to store passkey registration", err)
+	}
+
+	return options, nil
+}
+
+func (s *AuthService) FinishPasskeyRegistration(ctx context.Context, userID, name string, response *webauthn.AttestationResponse) (*auth.WebAuthnCredential, error) {
+	challenge, ceremony, err := s.consumeWebAuthnCeremony(ctx, response.Response.ClientDataJSON, ceremonyRegistration)
+	if err != nil {
+		return nil, err
+	}
+	if ceremony.UserID != userID {
+		return nil, apperrors.NewUnauthorizedError("passkey registration is invalid or has expired")
+	}
+
+	verified, err := s.relyingParty.FinishRegistration(challenge, response)
+	if err != nil {
+		return nil, apperrors.NewUnauthorizedError(err.Error())
+	}
+
+	existing, err := s.credentialRepo.GetByCredentialID(ctx, verified.ID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to check passkey", err)
+	}
+	if existing != nil {
+		return nil, apperrors.NewConflictError("passkey is already registered", nil)
+	}
+
+	credential := &auth.WebAuthnCredential{
+		UserID:       userID,
+		Name:         name,
+		CredentialID: verified.ID,
+		PublicKey:    verified.PublicKey,
+		Algorithm:    verified.Algorithm,
+		SignCount:    verified.SignCount,
+		Transports:   verified.Transports,
+		AAGUID:       verified.AAGUID,
+		CreatedAt:    time.Now(),
+	}
+	if err := s.credentialRepo.Create(ctx, credential); err != nil {
+		return nil, apperrors.NewInternalError("failed to save passkey", err)
+	}
+
+	return credential, nil
+}
+
+// BeginPasskeyLogin issues an assertion challenge. Without an email the
+// browser offers any discoverable passkey. With one, the challenge lists the
+// account's passkeys, which tells that the account exists and has passkeys;
+// clients that must not reveal this should leave the email out.
+func (s *AuthService) BeginPasskeyLogin(ctx context.Context, email string) (*webauthn.RequestOptions, error) {
+	var allow []webauthn.CredentialDescriptor
+	if email != "" {
+		userEntity, err := s.userRepo.GetByEmail(ctx, email)
+		if err != nil {
+			return nil, apperrors.NewInternalError("failed to get user", err)
+		}
+		if userEntity != nil {
+			credentials, err := s.credentialRepo.GetByUserID(ctx, userEntity.ID())
+			if err != nil {
+				return nil, apperrors.NewInternalError("failed to get passkeys", err)
+			}
+			allow = toCredentialDescriptors(credentials)
+		}
+	}
+
+	options, err := s.relyingParty.BeginLogin(allow)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to start passkey login", err)
+	}
+
+	if err := s.storeWebAuthnCeremony(ctx, options.Challenge, webAuthnCeremony{
+		Type: ceremonyLogin,
+	}); err != nil {
+		return nil, apperrors.NewInternalError("failed to store passkey login", err)
+	}
+
+	return options, nil
+}
+
+// FinishPasskeyLogin verifies the assertion and opens a session. A passkey
+// proves possession; only when the authenticator also verified the user is
+// that two factors, so users with MFA enabled get an MFA challenge otherwise.
+func (s *AuthService) FinishPasskeyLogin(ctx context.Context, response *webauthn.AssertionResponse, client contracts.ClientInfo) (*contracts.AuthenticatedUser, error) {
+	challenge, _, err := s.consumeWebAuthnCeremony(ctx, response.Response.ClientDataJSON, ceremonyLogin)
+	if err != nil {
+		return nil, err
+	}
+
+	credential, err := s.credentialRepo.GetByCredentialID(ctx, response.RawID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to get passkey", err)
+	}
+	if credential == nil {
+		return nil, apperrors.NewUnauthorizedError("unknown passkey")
+	}
+	if len(response.Response.UserHandle) > 0 && string(response.Response.UserHandle) != credential.UserID {
+		return nil, apperrors.NewUnauthorizedError("passkey does not belong to this user")
+	}
+
+	assertion, err := s.relyingParty.FinishLogin(challenge, credential.PublicKey, credential.SignCount, response)
+	if err != nil {
+		if errors.Is(err, webauthn.ErrClonedAuthenticator) {
+			s.logger.Warnf("Possible cloned passkey %s for user %s", credential.ID, credential.UserID)
+		}
+		if owner, _ := s.userRepo.GetByID(ctx, credential.UserID); owner != nil {
+			s.recordFailedLogin(ctx, owner, owner.Email(), client, auth.LoginFailurePasskeyRejected)
+		}
+		return nil, apperrors.NewUnauthorizedError("passkey verification failed")
+	}
+
+	userEntity, err := s.getActiveUser(ctx, credential.UserID)
+	if err != nil {
+		return nil, apperrors.NewUnauthorizedError("user account is inactive")
+	}
+
+	if err := s.requireVerifiedEmail(userEntity); err != nil {
+		return nil, err
+	}
+
+	if err := s.credentialRepo.UpdateSignCount(ctx, credential.ID, assertion.SignCount, time.Now()); err != nil {
+		return nil, apperrors.NewInternalError("failed to update passkey", err)
+	}
+
+	amr := []string{jwt.AMRHardwareKey}
+	if assertion.UserVerified {
+		amr = append(amr, jwt.AMRMultiFactor)
+	} else if userEntity.MFA().IsEnabled() {
+		challenge, err := s.createMFAChallenge(ctx, userEntity, client, amr)
+		if err != nil {
+			return nil, apperrors.NewInternalError("failed to create MFA challenge", err)
+		}
+
+		return &contracts.AuthenticatedUser{
+			User:         userEntity,
+			MFAChallenge: challenge,
+		}, nil
+	}
+
+	tokens, err := s.generateTokens(ctx, userEntity, client, amr)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to generate tokens", err)
+	}
+
+	return &contracts.AuthenticatedUser{
+		User:   userEntity,
+		Tokens: tokens,
+	}, nil
+}
+
+func (s *AuthService) ListPasskeys(ctx context.Context, userID string) ([]*auth.WebAuthnCredential, error) {
+	credentials, err := s.credentialRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to get passkeys", err)
+	}
+	return credentials, nil
+}
+
+func (s *AuthService) DeletePasskey(ctx context.Context, userID, id string) error {
+	credentials, err := s.credentialRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return apperrors.NewInternalError("failed to get passkeys", err)
+	}
+
+	for _, credential := range credentials {
+		if credential.ID == id {
+			if err := s.credentialRepo.Delete(ctx, userID, id); err != nil {
+				return apperrors.NewInternalError("failed to delete passkey", err)
+			}
+			return nil
+		}
+	}
+
+	return apperrors.NewNotFoundError("passkey not found")
+}
+
+func (s *AuthService) storeWebAuthnCeremony(ctx context.Context, challenge []byte, ceremony webAuthnCeremony) error {
+	key := s.webAuthnCeremony + base64.RawURLEncoding.EncodeToString(challenge)
+	return s.cacheService.Set(ctx, key, ceremony, &cache.CacheOptions{TTL: s.relyingParty.Timeout()})
+}
+
+// consumeWebAuthnCeremony looks up the ceremony answered by the client data
+// and removes it, so every challenge can be redeemed at most once.
+func (s *AuthService) consumeWebAuthnCeremony(ctx context.Context, clientDataJSON []byte, ceremonyType string) ([]byte, *webAuthnCeremony, error) {
+	challenge, err := webauthn.ChallengeFromClientData(clientDataJSON)
+	if err != nil {
+		return nil, nil, apperrors.NewValidationError("invalid passkey response", err)
+	}
+
+	key := s.webAuthnCeremony + base64.RawURLEncoding.EncodeToString(challenge)
+
+	var ceremony webAuthnCeremony
+	if err := s.cacheService.Get(ctx, key, &ceremony); err != nil {
+		if err == cache.ErrCacheMiss {
+			return nil, nil, apperrors.NewUnauthorizedError("passkey challenge is invalid or has expired")
+		}
+		return nil, nil, apperrors.NewInternalError("failed to get passkey challenge", err)
+	}
+
+	consumed, err := s.cacheService.SetNX(ctx, key+":used", true, s.relyingParty.Timeout())
+	if err != nil {
+		return nil, nil, apperrors.NewInternalError("failed to consume passkey challenge", err)
+	}
+	if !consumed || ceremony.Type != ceremonyType {
+		return nil, nil, apperrors.NewUnauthorizedError("passkey challenge is invalid or has expired")
+	}
+	_ = s.cacheService.Delete(ctx, key)
+
+	return challenge, &ceremony, nil
+}
+
+func toCredentialDescriptors(credentials []*auth.WebAuthnCredential) []webauthn.CredentialDescriptor {
+	descriptors := make([]webauthn.CredentialDescriptor, 0, len(credentials))
+	for _, credential := range credentials {
+		descriptors = append(descriptors, webauthn.CredentialDescriptor{
+			Type:       "public-key",
+			ID:         credential.CredentialID,
+			Transports: credential.Transports,
+		})
+	}
+	return descriptors
+}
@@ -3,10 +3,13 @@ package services
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/tranvuongduy2003/go-mvc/internal/domain/auth"
 	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
 	"github.com/tranvuongduy2003/go-mvc/internal/domain/user"
+	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/cache"
+	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/config"
 	apperrors "github.com/tranvuongduy2003/go-mvc/pkg/errors"
 )
 
@@ -16,6 +19,10 @@ type authorizationService struct {
 	permissionRepo     auth.PermissionRepository
 	userRoleRepo       auth.UserRoleRepository
 	rolePermissionRepo auth.RolePermissionRepository
+	cacheService       *cache.Service
+	unverifiedDenied   map[string]bool // Permissions withheld until the user's email is verified
+	inheritRoles       bool            // Whether holding a role counts as holding its ancestors
+	authzVersion       string          // Redis key prefix for per-user authorization versions
 }
 
 func NewAuthorizationService(
@@ -24,28 +31,40 @@ func NewAuthorizationService(
 	permissionRepo auth.PermissionRepository,
 	userRoleRepo auth.UserRoleRepository,
 	rolePermissionRepo auth.RolePermissionRepository,
+	cacheService *cache.Service,
+	verificationConfig config.EmailVerification,
+	hierarchyConfig config.RoleHierarchy,
 ) contracts.AuthorizationService {
+	unverifiedDenied := make(map[string]bool, len(verificationConfig.RestrictedPermissions))
+	for _, permission := range verificationConfig.RestrictedPermissions {
+		unverifiedDenied[permission] = true
+	}
+
 	return &authorizationService{
 		userRepo:           userRepo,
 		roleRepo:           roleRepo,
 		permissionRepo:     permissionRepo,
 		userRoleRepo:       userRoleRepo,
 		rolePermissionRepo: rolePermissionRepo,
+		cacheService:       cacheService,
+		unverifiedDenied:   unverifiedDenied,
+		inheritRoles:       hierarchyConfig.InheritRoles,
+		authzVersion:       "authz_version:",
 	}
 }
 
 func (s *authorizationService) UserHasPermission(ctx context.Context, userID, resource, action string) (bool, error) {
-	userRoles, err := s.userRoleRepo.GetActiveUserRoles(ctx, userID)
-	if err != nil {
-		return false, apperrors.NewInternalError("failed to get user roles", err)
+	if withheld, err := s.isWithheldUntilVerified(ctx, userID, resource+":"+action); err != nil || withheld {
+		return false, err
 	}
 
-	if len(userRoles) == 0 {
-		return false, nil
+	roles, err := s.effectiveRoles(ctx, userID)
+	if err != nil {
+		return false, err
 	}
 
-	for _, userRole := range userRoles {
-		hasPermission, err := s.rolePermissionRepo.RoleHasResourceAction(ctx, userRole.RoleID, resource, action)
+	for _, role := range roles {
+		hasPermission, err := s.rolePermissionRepo.RoleHasResourceAction(ctx, role.ID().String(), resource, action)
 		if err != nil {
 			return false, apperrors.NewInternalError("failed to check role permission", err)
 		}
@@ -58,17 +77,17 @@ func (s *authorizationService) UserHasPermission(ctx context.Context, userID, re
 }
 
 func (s *authorizationService) UserHasPermissionByName(ctx context.Context, userID, permissionName string) (bool, error) {
-	userRoles, err := s.userRoleRepo.GetActiveUserRoles(ctx, userID)
-	if err != nil {
-		return false, fmt.Errorf("failed to get user roles: %w", err)
+	if withheld, err := s.isWithheldUntilVerified(ctx, userID, permissionName); err != nil || withheld {
+		return false, err
 	}
 
-	if len(userRoles) == 0 {
-		return false, nil
+	roles, err := s.effectiveRoles(ctx, userID)
+	if err != nil {
+		return false, err
 	}
 
-	for _, userRole := range userRoles {
-		hasPermission, err := s.rolePermissionRepo.RoleHasPermissionByName(ctx, userRole.RoleID, permissionName)
+	for _, role := range roles {
+		hasPermission, err := s.rolePermissionRepo.RoleHasPermissionByName(ctx, role.ID().String(), permissionName)
 		if err != nil {
 			return false, fmt.Errorf("failed to check role permission by name: %w", err)
 		}
@@ -81,10 +100,31 @@ func (s *authorizationService) UserHasPermissionByName(ctx context.Context, user
 }
 
 func (s *authorizationService) UserHasRole(ctx context.Context, userID, roleName string) (bool, error) {
+	if s.inheritRoles {
+		names, err := s.effectiveRoleNames(ctx, userID)
+		if err != nil {
+			return false, err
+		}
+		return names[roleName], nil
+	}
+
 	return s.userRoleRepo.UserHasRoleName(ctx, userID, roleName)
 }
 
 func (s *authorizationService) UserHasAnyRole(ctx context.Context, userID string, roleNames []string) (bool, error) {
+	if s.inheritRoles {
+		names, err := s.effectiveRoleNames(ctx, userID)
+		if err != nil {
+			return false, err
+		}
+		for _, roleName := range roleNames {
+			if names[roleName] {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
 	for _, roleName := range roleNames {
 		hasRole, err := s.userRoleRepo.UserHasRoleName(ctx, userID, roleName)
 		if err != nil {
@@ -98,6 +138,19 @@ func (s *authorizationService) UserHasAnyRole(ctx context.Context, userID string
 }
 
 func (s *authorizationService) UserHasAllRoles(ctx context.Context, userID string, roleNames []string) (bool, error) {
+	if s.inheritRoles {
+		names, err := s.effectiveRoleNames(ctx, userID)
+		if err != nil {
+			return false, err
+		}
+		for _, roleName := range roleNames {
+			if !names[roleName] {
+				return false, nil
+			}
+		}
+		return true, nil
+	}
+
 	for _, roleName := range roleNames {
 		hasRole, err := s.userRoleRepo.UserHasRoleName(ctx, userID, roleName)
 		if err != nil {
@@ -111,19 +164,19 @@ func (s *authorizationService) UserHasAllRoles(ctx context.Context, userID strin
 }
 
 func (s *authorizationService) GetUserPermissions(ctx context.Context, userID string) ([]string, error) {
-	userRoles, err := s.userRoleRepo.GetActiveUserRoles(ctx, userID)
+	roles, err := s.effectiveRoles(ctx, userID)
 	if err != nil {
-		return nil, apperrors.NewInternalError("failed to get user roles", err)
+		return nil, err
 	}
 
-	if len(userRoles) == 0 {
+	if len(roles) == 0 {
 		return []string{}, nil
 	}
 
 	permissionsMap := make(map[string]bool)
 
-	for _, userRole := range userRoles {
-		rolePermissions, err := s.rolePermissionRepo.GetActiveRolePermissions(ctx, userRole.RoleID)
+	for _, role := range roles {
+		rolePermissions, err := s.rolePermissionRepo.GetActiveRolePermissions(ctx, role.ID().String())
 		if err != nil {
 			return nil, apperrors.NewInternalError("failed to get role permissions", err)
 		}
@@ -139,6 +192,16 @@ func (s *authorizationService) GetUserPermissions(ctx context.Context, userID st
 		}
 	}
 
+	for permission := range permissionsMap {
+		withheld, err := s.isWithheldUntilVerified(ctx, userID, permission)
+		if err != nil {
+			return nil, err
+		}
+		if withheld {
+			delete(permissionsMap, permission)
+		}
+	}
+
 	permissions := make([]string, 0, len(permissionsMap))
 	for permission := range permissionsMap {
 		permissions = append(permissions, permission)
@@ -147,7 +210,33 @@ func (s *authorizationService) GetUserPermissions(ctx context.Context, userID st
 	return permissions, nil
 }
 
+func (s *authorizationService) GetEffectiveRoles(ctx context.Context, userID string) ([]string, error) {
+	roles, err := s.effectiveRoles(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	roleNames := make([]string, 0, len(roles))
+	for _, role := range roles {
+		roleNames = append(roleNames, role.Name().String())
+	}
+	return roleNames, nil
+}
+
 func (s *authorizationService) GetUserRoles(ctx context.Context, userID string) ([]string, error) {
+	if s.inheritRoles {
+		roles, err := s.effectiveRoles(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+
+		roleNames := make([]string, 0, len(roles))
+		for _, role := range roles {
+			roleNames = append(roleNames, role.Name().String())
+		}
+		return roleNames, nil
+	}
+
 	userRoles, err := s.userRoleRepo.GetActiveUserRoles(ctx, userID)
 	if err != nil {
 		return nil, apperrors.NewInternalError("failed to get user roles", err)
@@ -200,40 +289,36 @@ func (s *authorizationService) CanAccessResource(ctx context.Context, userID, re
 }
 
 func (s *authorizationService) IsAdmin(ctx context.Context, userID string) (bool, error) {
-	return s.UserHasRole(ctx, userID, "admin")
+	return s.UserHasRole(ctx, userID, auth.RoleNameAdmin)
 }
 
 func (s *authorizationService) IsModerator(ctx context.Context, userID string) (bool, error) {
-	return s.UserHasAnyRole(ctx, userID, []string{"admin", "moderator"})
+	return s.UserHasAnyRole(ctx, userID, []string{auth.RoleNameAdmin, auth.RoleNameModerator})
 }
 
+// GetEffectivePermissions resolves permissions through the role hierarchy.
+// GrantedBy names the role the permission comes from: the assigned role
+// itself when it has the permission directly, otherwise the nearest
+// ancestor granting it.
 func (s *authorizationService) GetEffectivePermissions(ctx context.Context, userID string) ([]contracts.PermissionInfo, error) {
-	userRoles, err := s.userRoleRepo.GetActiveUserRoles(ctx, userID)
+	roles, err := s.effectiveRoles(ctx, userID)
 	if err != nil {
-		return nil, apperrors.NewInternalError("failed to get user roles", err)
+		return nil, err
 	}
 
-	if len(userRoles) == 0 {
+	if len(roles) == 0 {
 		return []contracts.PermissionInfo{}, nil
 	}
 
 	permissionsMap := make(map[string]contracts.PermissionInfo)
 
-	for _, userRole := range userRoles {
-		rolePermissions, err := s.rolePermissionRepo.GetActiveRolePermissions(ctx, userRole.RoleID)
+	for _, role := range roles {
+		rolePermissions, err := s.rolePermissionRepo.GetActiveRolePermissions(ctx, role.ID().String())
 		if err != nil {
 			return nil, apperrors.NewInternalError("failed to get role permissions", err)
 		}
 
-		role, err := s.roleRepo.GetByID(ctx, userRole.RoleID)
-		if err != nil {
-			return nil, apperrors.NewInternalError("failed to get role", err)
-		}
-
-		roleName := "unknown"
-		if role != nil {
-			roleName = role.Name().String()
-		}
+		roleName := role.Name().String()
 
 		for _, rolePerm := range rolePermissions {
 			permission, err := s.permissionRepo.GetByID(ctx, rolePerm.PermissionID)
@@ -263,3 +348,71 @@ func (s *authorizationService) GetEffectivePermissions(ctx context.Context, user
 
 	return permissions, nil
 }
+
+// GetAuthorizationVersion returns the user's authorization stamp. A missing
+// stamp (never set, or lost with the cache) is replaced by a fresh one, so
+// snapshots of unknown age are never trusted.
+func (s *authorizationService) GetAuthorizationVersion(ctx context.Context, userID string) (int64, error) {
+	key := s.authzVersion + userID
+
+	var version int64
+	err := s.cacheService.Get(ctx, key, &version)
+	if err == nil {
+		return version, nil
+	}
+	if err != cache.ErrCacheMiss {
+		return 0, apperrors.NewInternalError("failed to get authorization version", err)
+	}
+
+	if _, err := s.cacheService.SetNX(ctx, key, time.Now().UnixNano(), 0); err != nil {
+		return 0, apperrors.NewInternalError("failed to set authorization version", err)
+	}
+	// Another instance may have won the race, so read back what was stored
+	if err := s.cacheService.Get(ctx, key, &version); err != nil {
+		return 0, apperrors.NewInternalError("failed to get authorization version", err)
+	}
+
+	return version, nil
+}
+
+func (s *authorizationService) InvalidateUserAuthorization(ctx context.Context, userID string) error {
+	if err := s.cacheService.Set(ctx, s.authzVersion+userID, time.Now().UnixNano(), nil); err != nil {
+		return apperrors.NewInternalError("failed to invalidate authorization", err)
+	}
+	return nil
+}
+
+// InvalidateRoleAuthorization also covers the roles inheriting from the
+// role, whose holders get its permissions too.
+func (s *authorizationService) InvalidateRoleAuthorization(ctx context.Context, roleID string) error {
+	userIDs, err := roleHolderIDs(ctx, s.roleRepo, s.userRoleRepo, roleID)
+	if err != nil {
+		return err
+	}
+
+	for _, userID := range userIDs {
+		if err := s.InvalidateUserAuthorization(ctx, userID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// isWithheldUntilVerified reports whether the permission is configured as
+// restricted and the user has not verified their email yet.
+func (s *authorizationService) isWithheldUntilVerified(ctx context.Context, userID, permission string) (bool, error) {
+	if !s.unverifiedDenied[permission] {
+		return false, nil
+	}
+
+	userEntity, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return false, apperrors.NewInternalError("failed to get user", err)
+	}
+	if userEntity == nil {
+		return true, nil
+	}
+
+	return !userEntity.IsEmailVerified(), nil
+}
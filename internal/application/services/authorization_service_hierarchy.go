@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/auth"
+	apperrors "github.com/tranvuongduy2003/go-mvc/pkg/errors"
+)
+
+// effectiveRoles returns the user's active roles followed by the roles they
+// inherit from, each once. Assigned roles come first so a permission granted
+// directly is reported as such. An inactive ancestor grants nothing and cuts
+// off the roles above it, as deactivating an assigned role does.
+func (s *authorizationService) effectiveRoles(ctx context.Context, userID string) ([]*auth.Role, error) {
+	assigned, err := s.roleRepo.GetActiveRolesByUserID(ctx, userID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to get user roles", err)
+	}
+
+	roles := make([]*auth.Role, 0, len(assigned))
+	seen := make(map[string]bool, len(assigned))
+	for _, role := range assigned {
+		if !seen[role.ID().String()] {
+			seen[role.ID().String()] = true
+			roles = append(roles, role)
+		}
+	}
+
+	for _, role := range assigned {
+		ancestors, err := s.roleRepo.GetAncestors(ctx, role.ID().String())
+		if err != nil {
+			return nil, apperrors.NewInternalError("failed to get inherited roles", err)
+		}
+
+		for _, ancestor := range ancestors {
+			if !ancestor.IsActive() {
+				break
+			}
+			if !seen[ancestor.ID().String()] {
+				seen[ancestor.ID().String()] = true
+				roles = append(roles, ancestor)
+			}
+		}
+	}
+
+	return roles, nil
+}
+
+// effectiveRoleNames is the set of role names role checks match against
+// when inheritance is honoured.
+func (s *authorizationService) effectiveRoleNames(ctx context.Context, userID string) (map[string]bool, error) {
+	roles, err := s.effectiveRoles(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]bool, len(roles))
+	for _, role := range roles {
+		names[role.Name().String()] = true
+	}
+	return names, nil
+}
+
+// roleHolderIDs returns the users holding the role or a role inheriting
+// from it, each once.
+func roleHolderIDs(ctx context.Context, roleRepo auth.RoleRepository, userRoleRepo auth.UserRoleRepository, roleID string) ([]string, error) {
+	descendantIDs, err := roleRepo.GetDescendantIDs(ctx, roleID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to get inheriting roles", err)
+	}
+
+	var userIDs []string
+	seen := make(map[string]bool)
+	for _, id := range append([]string{roleID}, descendantIDs...) {
+		userRoles, err := userRoleRepo.GetRoleUsers(ctx, id)
+		if err != nil {
+			return nil, apperrors.NewInternalError("failed to get role users", err)
+		}
+
+		for _, userRole := range userRoles {
+			if !seen[userRole.UserID] {
+				seen[userRole.UserID] = true
+				userIDs = append(userIDs, userRole.UserID)
+			}
+		}
+	}
+
+	return userIDs, nil
+}
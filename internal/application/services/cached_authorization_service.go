@@ -0,0 +1,482 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/auth"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/messaging"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/shared/events"
+	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/cache"
+	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/config"
+	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/logger"
+	apperrors "github.com/tranvuongduy2003/go-mvc/pkg/errors"
+)
+
+const (
+	authzCacheTierLocal = "local"
+	authzCacheTierRedis = "redis"
+)
+
+// authorizationSnapshot is everything the checks need to know about a user.
+// Version is the user's authorization version when the snapshot was built,
+// so a shared snapshot built before a change is recognised as stale after
+// it.
+type authorizationSnapshot struct {
+	Version        int64                      `json:"version"`
+	Roles          []string                   `json:"roles"`
+	EffectiveRoles []string                   `json:"effective_roles"`
+	Permissions    []string                   `json:"permissions"` // Granted, so without those withheld until verification
+	Effective      []contracts.PermissionInfo `json:"effective"`
+	ValidUntil     *time.Time                 `json:"valid_until,omitempty"` // When the first of the user's role assignments expires
+}
+
+func (s *authorizationSnapshot) expired(now time.Time) bool {
+	return s.ValidUntil != nil && !now.Before(*s.ValidUntil)
+}
+
+type cachedAuthorization struct {
+	snapshot    *authorizationSnapshot
+	roles       map[string]bool
+	permissions map[string]bool
+	loadedAt    time.Time
+	expiresAt   time.Time
+	lastRead    time.Time
+}
+
+// CachedAuthorizationService keeps each user's roles and permissions in
+// process and in Redis in front of the authorization service, so a check
+// costs no query once the user is cached. Changes go through the Invalidate
+// methods, which evict the users here and publish an event for the other
+// instances to do the same.
+type CachedAuthorizationService struct {
+	authzService contracts.AuthorizationService
+	roleRepo     auth.RoleRepository
+	userRoleRepo auth.UserRoleRepository
+	cacheService *cache.Service
+	eventBus     messaging.EventBus
+	metrics      contracts.AuthorizationCacheMetrics
+	config       config.AuthorizationCache
+	logger       *logger.Logger
+	snapshotKey  string // Redis key prefix for per-user snapshots
+
+	mu        sync.Mutex
+	entries   map[string]*cachedAuthorization
+	evictions uint64 // Bumped by every eviction, so loads overlapping one are not kept
+}
+
+func NewCachedAuthorizationService(
+	authzService contracts.AuthorizationService,
+	roleRepo auth.RoleRepository,
+	userRoleRepo auth.UserRoleRepository,
+	cacheService *cache.Service,
+	eventBus messaging.EventBus,
+	metrics contracts.AuthorizationCacheMetrics,
+	cacheConfig config.AuthorizationCache,
+	logger *logger.Logger,
+) *CachedAuthorizationService {
+	return &CachedAuthorizationService{
+		authzService: authzService,
+		roleRepo:     roleRepo,
+		userRoleRepo: userRoleRepo,
+		cacheService: cacheService,
+		eventBus:     eventBus,
+		metrics:      metrics,
+		config:       cacheConfig,
+		logger:       logger,
+		snapshotKey:  "authz_snapshot:",
+		entries:      make(map[string]*cachedAuthorization),
+	}
+}
+
+func (c *CachedAuthorizationService) UserHasPermission(ctx context.Context, userID, resource, action string) (bool, error) {
+	entry, err := c.authorization(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	// Permission names are always resource:action
+	return entry.permissions[resource+":"+action], nil
+}
+
+func (c *CachedAuthorizationService) UserHasPermissionByName(ctx context.Context, userID, permissionName string) (bool, error) {
+	entry, err := c.authorization(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return entry.permissions[permissionName], nil
+}
+
+func (c *CachedAuthorizationService) UserHasRole(ctx context.Context, userID, roleName string) (bool, error) {
+	entry, err := c.authorization(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return entry.roles[roleName], nil
+}
+
+func (c *CachedAuthorizationService) UserHasAnyRole(ctx context.Context, userID string, roleNames []string) (bool, error) {
+	entry, err := c.authorization(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	for _, roleName := range roleNames {
+		if entry.roles[roleName] {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (c *CachedAuthorizationService) UserHasAllRoles(ctx context.Context, userID string, roleNames []string) (bool, error) {
+	entry, err := c.authorization(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	for _, roleName := range roleNames {
+		if !entry.roles[roleName] {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// GetUserPermissions and the other getters return copies, as callers may
+// modify what they get.
+func (c *CachedAuthorizationService) GetUserPermissions(ctx context.Context, userID string) ([]string, error) {
+	entry, err := c.authorization(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return append([]string{}, entry.snapshot.Permissions...), nil
+}
+
+func (c *CachedAuthorizationService) GetUserRoles(ctx context.Context, userID string) ([]string, error) {
+	entry, err := c.authorization(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return append([]string{}, entry.snapshot.Roles...), nil
+}
+
+func (c *CachedAuthorizationService) GetEffectiveRoles(ctx context.Context, userID string) ([]string, error) {
+	entry, err := c.authorization(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return append([]string{}, entry.snapshot.EffectiveRoles...), nil
+}
+
+func (c *CachedAuthorizationService) CheckMultiplePermissions(ctx context.Context, userID string, permissions []string) (map[string]bool, error) {
+	entry, err := c.authorization(ctx, userID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to check permissions", err)
+	}
+
+	result := make(map[string]bool, len(permissions))
+	for _, permission := range permissions {
+		result[permission] = entry.permissions[permission]
+	}
+	return result, nil
+}
+
+func (c *CachedAuthorizationService) CanAccessResource(ctx context.Context, userID, resource, action string) error {
+	hasPermission, err := c.UserHasPermission(ctx, userID, resource, action)
+	if err != nil {
+		return apperrors.NewInternalError("failed to check permission", err)
+	}
+
+	if !hasPermission {
+		return apperrors.NewForbiddenError(fmt.Sprintf("access denied: user does not have permission to %s on %s", action, resource))
+	}
+
+	return nil
+}
+
+func (c *CachedAuthorizationService) IsAdmin(ctx context.Context, userID string) (bool, error) {
+	return c.UserHasRole(ctx, userID, auth.RoleNameAdmin)
+}
+
+func (c *CachedAuthorizationService) IsModerator(ctx context.Context, userID string) (bool, error) {
+	return c.UserHasAnyRole(ctx, userID, []string{auth.RoleNameAdmin, auth.RoleNameModerator})
+}
+
+func (c *CachedAuthorizationService) GetEffectivePermissions(ctx context.Context, userID string) ([]contracts.PermissionInfo, error) {
+	entry, err := c.authorization(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return append([]contracts.PermissionInfo{}, entry.snapshot.Effective...), nil
+}
+
+func (c *CachedAuthorizationService) GetAuthorizationVersion(ctx context.Context, userID string) (int64, error) {
+	return c.authzService.GetAuthorizationVersion(ctx, userID)
+}
+
+func (c *CachedAuthorizationService) InvalidateUserAuthorization(ctx context.Context, userID string) error {
+	if err := c.authzService.InvalidateUserAuthorization(ctx, userID); err != nil {
+		return err
+	}
+
+	c.publishInvalidation(ctx, []string{userID}, "")
+	return nil
+}
+
+// InvalidateRoleAuthorization resolves the role's holders once, here, and
+// names them in the event, so no instance has to query for them and
+// holders of a role that is being deleted are still found.
+func (c *CachedAuthorizationService) InvalidateRoleAuthorization(ctx context.Context, roleID string) error {
+	userIDs, err := roleHolderIDs(ctx, c.roleRepo, c.userRoleRepo, roleID)
+	if err != nil {
+		return err
+	}
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	for _, userID := range userIDs {
+		if err := c.authzService.InvalidateUserAuthorization(ctx, userID); err != nil {
+			return err
+		}
+	}
+
+	c.publishInvalidation(ctx, userIDs, roleID)
+	return nil
+}
+
+// EvictUsers drops the users' local entries. An entry loaded before the
+// change and read after it served stale authorization, which is counted.
+func (c *CachedAuthorizationService) EvictUsers(userIDs []string, changedAt time.Time) {
+	c.mu.Lock()
+	c.evictions++
+	staleReads := 0
+	for _, userID := range userIDs {
+		entry, ok := c.entries[userID]
+		if !ok {
+			continue
+		}
+		if entry.loadedAt.Before(changedAt) && entry.lastRead.After(changedAt) {
+			staleReads++
+		}
+		delete(c.entries, userID)
+	}
+	size := len(c.entries)
+	c.mu.Unlock()
+
+	if staleReads > 0 {
+		c.metrics.IncrementStaleReads(staleReads)
+	}
+	c.metrics.SetLocalEntries(size)
+}
+
+// publishInvalidation evicts the users here at once and tells the other
+// instances. Their Redis snapshots are already stale, as the authorization
+// version moved; should the event be lost, their local entries live at
+// most LocalTTL.
+func (c *CachedAuthorizationService) publishInvalidation(ctx context.Context, userIDs []string, roleID string) {
+	event := events.NewAuthorizationInvalidatedEvent(userIDs, roleID)
+	c.EvictUsers(userIDs, event.OccurredAt)
+
+	if err := c.eventBus.PublishEvent(ctx, event); err != nil {
+		c.logger.Warnf("Failed to publish authorization invalidation for %d users: %v", len(userIDs), err)
+	}
+}
+
+// authorization returns the user's cached authorization, loading it on a
+// miss.
+func (c *CachedAuthorizationService) authorization(ctx context.Context, userID string) (*cachedAuthorization, error) {
+	if entry := c.localEntry(userID); entry != nil {
+		c.metrics.RecordLookup(authzCacheTierLocal, true)
+		return entry, nil
+	}
+	c.metrics.RecordLookup(authzCacheTierLocal, false)
+
+	evictions := c.evictionCount()
+
+	snapshot, err := c.sharedSnapshot(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	entry := &cachedAuthorization{
+		snapshot:    snapshot,
+		roles:       toSet(snapshot.Roles),
+		permissions: toSet(snapshot.Permissions),
+		loadedAt:    now,
+		expiresAt:   now.Add(c.config.LocalTTL),
+		lastRead:    now,
+	}
+	if snapshot.ValidUntil != nil && snapshot.ValidUntil.Before(entry.expiresAt) {
+		entry.expiresAt = *snapshot.ValidUntil
+	}
+
+	c.storeLocalEntry(userID, entry, evictions)
+	return entry, nil
+}
+
+// sharedSnapshot returns the user's snapshot from Redis when it is still
+// current, building and storing it otherwise. Redis failures only cost the
+// shared tier: the snapshot is built from the repositories.
+func (c *CachedAuthorizationService) sharedSnapshot(ctx context.Context, userID string) (*authorizationSnapshot, error) {
+	if c.config.RedisTTL <= 0 {
+		return c.buildSnapshot(ctx, userID, 0)
+	}
+
+	// Read the version before building, so a change made meanwhile leaves
+	// the stored snapshot behind the version rather than ahead of the data
+	version, err := c.authzService.GetAuthorizationVersion(ctx, userID)
+	if err != nil {
+		c.logger.Warnf("Failed to get authorization version of user %s, bypassing the shared cache: %v", userID, err)
+		return c.buildSnapshot(ctx, userID, 0)
+	}
+
+	key := c.snapshotKey + userID
+
+	var snapshot authorizationSnapshot
+	err = c.cacheService.Get(ctx, key, &snapshot)
+	// Snapshots stored before effective roles were part of them lack the field
+	if err == nil && snapshot.Version == version && !snapshot.expired(time.Now()) && snapshot.EffectiveRoles != nil {
+		c.metrics.RecordLookup(authzCacheTierRedis, true)
+		return &snapshot, nil
+	}
+	if err != nil && err != cache.ErrCacheMiss {
+		c.logger.Warnf("Failed to get authorization snapshot of user %s: %v", userID, err)
+	}
+	c.metrics.RecordLookup(authzCacheTierRedis, false)
+
+	built, err := c.buildSnapshot(ctx, userID, version)
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := c.config.RedisTTL
+	if built.ValidUntil != nil {
+		if remaining := time.Until(*built.ValidUntil); remaining < ttl {
+			ttl = remaining
+		}
+	}
+	if ttl > 0 {
+		if err := c.cacheService.Set(ctx, key, built, &cache.CacheOptions{TTL: ttl}); err != nil {
+			c.logger.Warnf("Failed to store authorization snapshot of user %s: %v", userID, err)
+		}
+	}
+
+	return built, nil
+}
+
+func (c *CachedAuthorizationService) buildSnapshot(ctx context.Context, userID string, version int64) (*authorizationSnapshot, error) {
+	roles, err := c.authzService.GetUserRoles(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	effectiveRoles, err := c.authzService.GetEffectiveRoles(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	permissions, err := c.authzService.GetUserPermissions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	effective, err := c.authzService.GetEffectivePermissions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Expiring assignments change authorization without any event, so the
+	// snapshot must not outlive the first of them
+	userRoles, err := c.userRoleRepo.GetActiveUserRoles(ctx, userID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to get user roles", err)
+	}
+
+	snapshot := &authorizationSnapshot{
+		Version:        version,
+		Roles:          roles,
+		EffectiveRoles: effectiveRoles,
+		Permissions:    permissions,
+		Effective:      effective,
+	}
+	for _, userRole := range userRoles {
+		if userRole.ExpiresAt != nil && (snapshot.ValidUntil == nil || userRole.ExpiresAt.Before(*snapshot.ValidUntil)) {
+			expiresAt := *userRole.ExpiresAt
+			snapshot.ValidUntil = &expiresAt
+		}
+	}
+
+	return snapshot, nil
+}
+
+func (c *CachedAuthorizationService) localEntry(userID string) *cachedAuthorization {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[userID]
+	if !ok {
+		return nil
+	}
+
+	now := time.Now()
+	if !now.Before(entry.expiresAt) {
+		delete(c.entries, userID)
+		return nil
+	}
+
+	entry.lastRead = now
+	return entry
+}
+
+// storeLocalEntry keeps the entry unless an eviction happened since its
+// load began, as the load may have read what the eviction was for. A full
+// cache drops expired entries first, then an arbitrary one.
+func (c *CachedAuthorizationService) storeLocalEntry(userID string, entry *cachedAuthorization, evictions uint64) {
+	if c.config.LocalTTL <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	if c.evictions != evictions {
+		c.mu.Unlock()
+		return
+	}
+
+	if _, exists := c.entries[userID]; !exists && c.config.LocalMaxEntries > 0 && len(c.entries) >= c.config.LocalMaxEntries {
+		now := time.Now()
+		for id, existing := range c.entries {
+			if !now.Before(existing.expiresAt) {
+				delete(c.entries, id)
+			}
+		}
+		for id := range c.entries {
+			if len(c.entries) < c.config.LocalMaxEntries {
+				break
+			}
+			delete(c.entries, id)
+		}
+	}
+
+	c.entries[userID] = entry
+	size := len(c.entries)
+	c.mu.Unlock()
+
+	c.metrics.SetLocalEntries(size)
+}
+
+func (c *CachedAuthorizationService) evictionCount() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.evictions
+}
+
+func toSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		set[value] = true
+	}
+	return set
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/auth"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/user"
+	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/config"
+	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/logger"
+	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/security"
+	apperrors "github.com/tranvuongduy2003/go-mvc/pkg/errors"
+)
+
+type passwordPolicyService struct {
+	policy         *security.PasswordPolicy
+	breached       *security.BreachedPasswordList
+	historyRepo    auth.PasswordHistoryRepository
+	passwordHasher user.PasswordHasher
+	historySize    int
+	logger         *logger.Logger
+}
+
+func NewPasswordPolicyService(
+	historyRepo auth.PasswordHistoryRepository,
+	passwordHasher user.PasswordHasher,
+	policyConfig config.PasswordPolicy,
+	logger *logger.Logger,
+) contracts.PasswordPolicyService {
+	var blocklist []string
+	if policyConfig.BlocklistFile != "" {
+		passwords, err := security.LoadPasswordBlocklist(policyConfig.BlocklistFile)
+		if err != nil {
+			logger.Warnf("Password blocklist not loaded, using built-in list only: %v", err)
+		}
+		blocklist = passwords
+	}
+
+	var breached *security.BreachedPasswordList
+	if policyConfig.BreachedHashesDir != "" {
+		if _, err := os.Stat(policyConfig.BreachedHashesDir); err != nil {
+			logger.Warnf("Breached password check disabled: %v", err)
+		} else {
+			breached = security.NewBreachedPasswordList(policyConfig.BreachedHashesDir)
+		}
+	}
+
+	// A configured maximum cannot exceed what the hasher reads
+	maxLength := policyConfig.MaxLength
+	if limit := user.MaxPasswordBytes(passwordHasher); limit > 0 && (maxLength <= 0 || maxLength > limit) {
+		maxLength = limit
+	}
+
+	return &passwordPolicyService{
+		policy: security.NewPasswordPolicy(security.PasswordRules{
+			MinLength:      policyConfig.MinLength,
+			MaxLength:      maxLength,
+			RequireUpper:   policyConfig.RequireUpper,
+			RequireLower:   policyConfig.RequireLower,
+			RequireDigit:   policyConfig.RequireDigit,
+			RequireSpecial: policyConfig.RequireSpecial,
+		}, blocklist),
+		breached:       breached,
+		historyRepo:    historyRepo,
+		passwordHasher: passwordHasher,
+		historySize:    policyConfig.HistorySize,
+		logger:         logger,
+	}
+}
+
+func (s *passwordPolicyService) ValidatePassword(ctx context.Context, password string, subject contracts.PasswordSubject) error {
+	if err := s.policy.Validate(password, subject.Email, subject.Name); err != nil {
+		return apperrors.NewValidationError(err.Error(), nil)
+	}
+
+	if s.breached != nil {
+		breached, err := s.breached.IsBreached(password)
+		if err != nil {
+			// An unreadable range file should not lock users out of
+			// changing their password
+			s.logger.Warnf("Breached password check failed: %v", err)
+		} else if breached {
+			return apperrors.NewValidationError("password has appeared in a data breach, please choose another", nil)
+		}
+	}
+
+	if s.historySize <= 0 || subject.UserID == "" {
+		return nil
+	}
+
+	if subject.PasswordHash != "" && s.passwordHasher.Verify(password, subject.PasswordHash) {
+		return apperrors.NewValidationError("password was used recently, please choose another", nil)
+	}
+
+	history, err := s.historyRepo.GetRecent(ctx, subject.UserID, s.historySize)
+	if err != nil {
+		return apperrors.NewInternalError("failed to get password history", err)
+	}
+	for _, entry := range history {
+		if s.passwordHasher.Verify(password, entry.PasswordHash) {
+			return apperrors.NewValidationError("password was used recently, please choose another", nil)
+		}
+	}
+
+	return nil
+}
+
+// RecordPassword adds the hash of a newly set password to the history and
+// drops entries beyond the configured size.
+func (s *passwordPolicyService) RecordPassword(ctx context.Context, userID, passwordHash string) error {
+	if s.historySize <= 0 {
+		return nil
+	}
+
+	if err := s.historyRepo.Create(ctx, &auth.PasswordHistoryEntry{
+		UserID:       userID,
+		PasswordHash: passwordHash,
+		CreatedAt:    time.Now(),
+	}); err != nil {
+		return apperrors.NewInternalError("failed to record password history", err)
+	}
+
+	if err := s.historyRepo.Prune(ctx, userID, s.historySize); err != nil {
+		return apperrors.NewInternalError("failed to prune password history", err)
+	}
+
+	return nil
+}
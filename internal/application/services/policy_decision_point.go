@@ -0,0 +1,262 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/auth"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
+	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/config"
+	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/logger"
+	apperrors "github.com/tranvuongduy2003/go-mvc/pkg/errors"
+	"github.com/tranvuongduy2003/go-mvc/pkg/policy"
+)
+
+// policyAttributeRoots are the attribute paths a policy condition may use.
+var policyAttributeRoots = []string{"subject", "resource", "action", "environment"}
+
+// policyFile is the layout of a policy file:
+//
+//	policies:
+//	  - name: owners-edit-documents
+//	    effect: allow
+//	    resources: [documents]
+//	    actions: [update]
+//	    condition: resource.owner_id == subject.id
+type policyFile struct {
+	Policies []struct {
+		Name        string   `yaml:"name"`
+		Description string   `yaml:"description"`
+		Effect      string   `yaml:"effect"`
+		Resources   []string `yaml:"resources"`
+		Actions     []string `yaml:"actions"`
+		Condition   string   `yaml:"condition"`
+	} `yaml:"policies"`
+}
+
+type compiledPolicy struct {
+	policy    *auth.Policy
+	condition *policy.Expression // Nil when the policy has no condition
+}
+
+type policyDecisionPoint struct {
+	policyRepo auth.PolicyRepository
+	config     config.Policies
+	logger     *logger.Logger
+
+	mu       sync.RWMutex
+	policies []compiledPolicy
+	loaded   bool
+	loadedAt time.Time
+
+	reloadMu sync.Mutex // Lets one request reload stale policies while the others wait
+}
+
+func NewPolicyDecisionPoint(policyRepo auth.PolicyRepository, policiesConfig config.Policies, logger *logger.Logger) contracts.PolicyDecisionPoint {
+	return &policyDecisionPoint{
+		policyRepo: policyRepo,
+		config:     policiesConfig,
+		logger:     logger,
+	}
+}
+
+// Decide applies deny-overrides: a matching deny policy decides at once, a
+// matching allow policy only when no deny matches. A deny policy whose
+// condition fails to evaluate denies, while a failing allow policy is
+// skipped, so errors never grant access.
+func (p *policyDecisionPoint) Decide(ctx context.Context, request contracts.AccessRequest) (*contracts.PolicyDecision, error) {
+	policies, err := p.currentPolicies(ctx)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to load policies", err)
+	}
+
+	resourceType, _ := request.Resource["type"].(string)
+	vars := map[string]interface{}{
+		"subject":     request.Subject,
+		"resource":    request.Resource,
+		"action":      request.Action,
+		"environment": request.Environment,
+	}
+
+	allowedBy := ""
+	for _, compiled := range policies {
+		if !compiled.policy.AppliesTo(resourceType, request.Action) {
+			continue
+		}
+
+		matched := true
+		if compiled.condition != nil {
+			matched, err = compiled.condition.Evaluate(vars)
+			if err != nil {
+				p.logger.Warnf("Policy %s failed to evaluate: %v", compiled.policy.Name, err)
+				if compiled.policy.Effect == auth.PolicyEffectDeny {
+					return &contracts.PolicyDecision{
+						Policy: compiled.policy.Name,
+						Reason: "a deny policy could not be evaluated",
+					}, nil
+				}
+				continue
+			}
+		}
+		if !matched {
+			continue
+		}
+
+		if compiled.policy.Effect == auth.PolicyEffectDeny {
+			reason := compiled.policy.Description
+			if reason == "" {
+				reason = "denied by policy"
+			}
+			return &contracts.PolicyDecision{Policy: compiled.policy.Name, Reason: reason}, nil
+		}
+		if allowedBy == "" {
+			allowedBy = compiled.policy.Name
+		}
+	}
+
+	if allowedBy == "" {
+		return &contracts.PolicyDecision{Reason: "no policy allows this request"}, nil
+	}
+	return &contracts.PolicyDecision{Allowed: true, Policy: allowedBy}, nil
+}
+
+func (p *policyDecisionPoint) Reload(ctx context.Context) error {
+	policies, err := p.loadPolicies(ctx)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// Stale policies are retried after another interval rather than on
+	// every request
+	p.loadedAt = time.Now()
+	if err != nil {
+		return err
+	}
+
+	p.policies = policies
+	p.loaded = true
+	return nil
+}
+
+// currentPolicies returns the loaded policies, loading them first when
+// they never were or have outlived the refresh interval. A failed refresh
+// keeps the previous policies.
+func (p *policyDecisionPoint) currentPolicies(ctx context.Context) ([]compiledPolicy, error) {
+	if policies, fresh := p.snapshot(); fresh {
+		return policies, nil
+	}
+
+	p.reloadMu.Lock()
+	defer p.reloadMu.Unlock()
+
+	// Another request may have reloaded while this one waited
+	if policies, fresh := p.snapshot(); fresh {
+		return policies, nil
+	}
+
+	if err := p.Reload(ctx); err != nil {
+		p.mu.RLock()
+		defer p.mu.RUnlock()
+		if !p.loaded {
+			return nil, err
+		}
+		p.logger.Warnf("Failed to reload policies, keeping the previous ones: %v", err)
+		return p.policies, nil
+	}
+
+	policies, _ := p.snapshot()
+	return policies, nil
+}
+
+func (p *policyDecisionPoint) snapshot() ([]compiledPolicy, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	fresh := p.loaded && (p.config.RefreshInterval <= 0 || time.Since(p.loadedAt) < p.config.RefreshInterval)
+	return p.policies, fresh
+}
+
+// loadPolicies reads and compiles every policy. Any invalid policy fails
+// the whole load: silently dropping a deny policy would widen access.
+func (p *policyDecisionPoint) loadPolicies(ctx context.Context) ([]compiledPolicy, error) {
+	var policies []*auth.Policy
+
+	for _, pattern := range p.config.Files {
+		paths, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid policy file pattern %q: %w", pattern, err)
+		}
+		for _, path := range paths {
+			filePolicies, err := readPolicyFile(path)
+			if err != nil {
+				return nil, err
+			}
+			policies = append(policies, filePolicies...)
+		}
+	}
+
+	if p.config.Database {
+		dbPolicies, err := p.policyRepo.GetActive(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get policies: %w", err)
+		}
+		policies = append(policies, dbPolicies...)
+	}
+
+	compiled := make([]compiledPolicy, 0, len(policies))
+	names := make(map[string]bool, len(policies))
+	for _, candidate := range policies {
+		if err := candidate.Validate(); err != nil {
+			return nil, fmt.Errorf("policy %q: %w", candidate.Name, err)
+		}
+		if names[candidate.Name] {
+			return nil, fmt.Errorf("policy %q is defined more than once", candidate.Name)
+		}
+		names[candidate.Name] = true
+
+		entry := compiledPolicy{policy: candidate}
+		if candidate.Condition != "" {
+			condition, err := policy.Compile(candidate.Condition, policyAttributeRoots...)
+			if err != nil {
+				return nil, fmt.Errorf("policy %q: invalid condition: %w", candidate.Name, err)
+			}
+			entry.condition = condition
+		}
+		compiled = append(compiled, entry)
+	}
+
+	return compiled, nil
+}
+
+func readPolicyFile(path string) ([]*auth.Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy file %s: %w", path, err)
+	}
+
+	var file policyFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse policy file %s: %w", path, err)
+	}
+
+	policies := make([]*auth.Policy, 0, len(file.Policies))
+	for _, entry := range file.Policies {
+		policies = append(policies, &auth.Policy{
+			Name:        entry.Name,
+			Description: entry.Description,
+			Effect:      entry.Effect,
+			Resources:   entry.Resources,
+			Actions:     entry.Actions,
+			Condition:   entry.Condition,
+			IsActive:    true,
+		})
+	}
+
+	return policies, nil
+}
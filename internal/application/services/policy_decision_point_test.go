@@ -0,0 +1,249 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
+	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/config"
+	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/logger"
+)
+
+// stubAuthorizationService answers role lookups for the attribute loader.
+// GetUserRoles is deliberately left to the nil embedded interface: policies
+// must see effective roles.
+type stubAuthorizationService struct {
+	contracts.AuthorizationService
+	effectiveRoles map[string][]string
+}
+
+func (s *stubAuthorizationService) GetEffectiveRoles(ctx context.Context, userID string) ([]string, error) {
+	return s.effectiveRoles[userID], nil
+}
+
+func newTestPolicyDecisionPoint(t *testing.T, files ...string) contracts.PolicyDecisionPoint {
+	t.Helper()
+
+	log, err := logger.NewLogger(config.Logger{Level: "fatal", Encoding: "json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewPolicyDecisionPoint(nil, config.Policies{Files: files}, log)
+}
+
+func writePolicyFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "policies.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestDecideAppliesDenyOverrides(t *testing.T) {
+	path := writePolicyFile(t, `
+policies:
+  - name: owners-edit
+    effect: allow
+    resources: [documents]
+    actions: [update]
+    condition: resource.owner_id == subject.id
+  - name: editors-edit
+    effect: allow
+    resources: [documents]
+    actions: ["*"]
+    condition: '"EDITOR" in subject.roles'
+  - name: no-editing-locked
+    effect: deny
+    description: Locked documents cannot be changed
+    resources: [documents]
+    actions: [update]
+    condition: resource.locked == true
+  - name: broken-deny
+    effect: deny
+    resources: [documents]
+    actions: [delete]
+    condition: resource.title > 1
+  - name: broken-allow
+    effect: allow
+    resources: [documents]
+    actions: [archive]
+    condition: resource.title > 1
+`)
+	pdp := newTestPolicyDecisionPoint(t, path)
+
+	tests := map[string]struct {
+		subject  contracts.Attributes
+		resource contracts.Attributes
+		action   string
+		allowed  bool
+		policy   string
+	}{
+		"owner": {
+			subject:  contracts.Attributes{"id": "u1"},
+			resource: contracts.Attributes{"type": "documents", "owner_id": "u1"},
+			action:   "update",
+			allowed:  true,
+			policy:   "owners-edit",
+		},
+		"second allow matches": {
+			subject:  contracts.Attributes{"id": "u2", "roles": []string{"EDITOR"}},
+			resource: contracts.Attributes{"type": "documents", "owner_id": "u1"},
+			action:   "update",
+			allowed:  true,
+			policy:   "editors-edit",
+		},
+		"deny overrides allow": {
+			subject:  contracts.Attributes{"id": "u1", "roles": []string{"EDITOR"}},
+			resource: contracts.Attributes{"type": "documents", "owner_id": "u1", "locked": true},
+			action:   "update",
+			policy:   "no-editing-locked",
+		},
+		"deny condition not met": {
+			subject:  contracts.Attributes{"id": "u1"},
+			resource: contracts.Attributes{"type": "documents", "owner_id": "u1", "locked": false},
+			action:   "update",
+			allowed:  true,
+			policy:   "owners-edit",
+		},
+		"missing attribute does not trigger the deny": {
+			subject:  contracts.Attributes{"id": "u1"},
+			resource: contracts.Attributes{"type": "documents", "owner_id": "u1"},
+			action:   "update",
+			allowed:  true,
+			policy:   "owners-edit",
+		},
+		"missing attributes do not match each other": {
+			subject:  contracts.Attributes{},
+			resource: contracts.Attributes{"type": "documents"},
+			action:   "update",
+		},
+		"no policy allows": {
+			subject:  contracts.Attributes{"id": "u2"},
+			resource: contracts.Attributes{"type": "documents", "owner_id": "u1"},
+			action:   "update",
+		},
+		"other resource type": {
+			subject:  contracts.Attributes{"id": "u1", "roles": []string{"EDITOR"}},
+			resource: contracts.Attributes{"type": "invoices", "owner_id": "u1"},
+			action:   "update",
+		},
+		"failing deny denies": {
+			subject:  contracts.Attributes{"id": "u1", "roles": []string{"EDITOR"}},
+			resource: contracts.Attributes{"type": "documents", "title": "Plan"},
+			action:   "delete",
+			policy:   "broken-deny",
+		},
+		"failing allow is skipped": {
+			subject:  contracts.Attributes{"id": "u1", "roles": []string{"EDITOR"}},
+			resource: contracts.Attributes{"type": "documents", "title": "Plan"},
+			action:   "archive",
+			allowed:  true,
+			policy:   "editors-edit",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			decision, err := pdp.Decide(context.Background(), contracts.AccessRequest{
+				Subject:  test.subject,
+				Resource: test.resource,
+				Action:   test.action,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if decision.Allowed != test.allowed || decision.Policy != test.policy {
+				t.Fatalf("decision = %+v, want allowed = %v by %q", decision, test.allowed, test.policy)
+			}
+		})
+	}
+}
+
+func TestDecideRejectsInvalidPolicies(t *testing.T) {
+	tests := map[string]string{
+		"unknown attribute root": `
+policies:
+  - name: typo
+    effect: allow
+    resources: [documents]
+    actions: [read]
+    condition: subjct.id == resource.owner_id
+`,
+		"invalid effect": `
+policies:
+  - name: maybe
+    effect: perhaps
+    resources: [documents]
+    actions: [read]
+`,
+		"duplicate name": `
+policies:
+  - name: twice
+    effect: allow
+    resources: [documents]
+    actions: [read]
+  - name: twice
+    effect: deny
+    resources: [documents]
+    actions: [read]
+`,
+	}
+
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			pdp := newTestPolicyDecisionPoint(t, writePolicyFile(t, content))
+			if _, err := pdp.Decide(context.Background(), contracts.AccessRequest{Action: "read"}); err == nil {
+				t.Fatal("Decide succeeded with an invalid policy file")
+			}
+		})
+	}
+}
+
+// The shipped user policies must keep administrators from being
+// impersonated, including those who are administrators only through a
+// role inheriting from ADMIN.
+func TestUserPoliciesDenyImpersonatingAdmins(t *testing.T) {
+	pdp := newTestPolicyDecisionPoint(t, filepath.Join("..", "..", "..", "configs", "policies", "users.yaml"))
+
+	target := localUser(t, "target@example.com", true)
+
+	tests := map[string]struct {
+		roles   []string
+		allowed bool
+	}{
+		"user":                            {roles: []string{"USER"}, allowed: true},
+		"no roles":                        {roles: []string{}, allowed: true},
+		"admin":                           {roles: []string{"ADMIN"}},
+		"admin through an inherited role": {roles: []string{"SUPPORT_LEAD", "ADMIN"}},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			loader := NewUserAttributeLoader(&stubUserRepository{existing: target}, &stubAuthorizationService{
+				effectiveRoles: map[string][]string{target.ID(): test.roles},
+			})
+
+			resource, err := loader.LoadAttributes(context.Background(), target.ID())
+			if err != nil {
+				t.Fatal(err)
+			}
+			resource["type"] = UserResourceType
+
+			decision, err := pdp.Decide(context.Background(), contracts.AccessRequest{
+				Subject:  contracts.Attributes{"id": "admin"},
+				Resource: resource,
+				Action:   "impersonate",
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if decision.Allowed != test.allowed {
+				t.Fatalf("decision = %+v, want allowed = %v", decision, test.allowed)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
+	apperrors "github.com/tranvuongduy2003/go-mvc/pkg/errors"
+	"github.com/tranvuongduy2003/go-mvc/pkg/jwt"
+	"github.com/tranvuongduy2003/go-mvc/pkg/oauth"
+)
+
+type tokenIntrospectionService struct {
+	authService   contracts.AuthService
+	tokenService  contracts.TokenManagementService
+	clientService contracts.ClientCredentialsService
+	authzService  contracts.AuthorizationService
+	jwtService    jwt.JWTService
+}
+
+func NewTokenIntrospectionService(
+	authService contracts.AuthService,
+	tokenService contracts.TokenManagementService,
+	clientService contracts.ClientCredentialsService,
+	authzService contracts.AuthorizationService,
+	jwtService jwt.JWTService,
+) contracts.TokenIntrospectionService {
+	return &tokenIntrospectionService{
+		authService:   authService,
+		tokenService:  tokenService,
+		clientService: clientService,
+		authzService:  authzService,
+		jwtService:    jwtService,
+	}
+}
+
+// IntrospectToken reports whether a token would be accepted right now. Only
+// clients granted the introspection scope may ask. For user tokens the
+// scope lists the user's permissions; for client tokens it is the scope
+// granted to the client.
+func (s *tokenIntrospectionService) IntrospectToken(ctx context.Context, clientID, clientSecret, token string) (*contracts.TokenIntrospection, error) {
+	client, err := s.clientService.AuthenticateClient(ctx, clientID, clientSecret)
+	if err != nil {
+		return nil, err
+	}
+	if !client.AllowsScope(oauth.ScopeTokenIntrospect) {
+		return nil, oauth.NewError(oauth.ErrorUnauthorizedClient, "client is not allowed to introspect tokens")
+	}
+
+	inactive := &contracts.TokenIntrospection{Active: false}
+
+	claims, err := s.jwtService.ValidateToken(token)
+	if err != nil {
+		return inactive, nil
+	}
+
+	var principal *contracts.Principal
+	switch claims.Type {
+	case "access":
+		principal, err = s.authService.Authenticate(ctx, token)
+	case "refresh":
+		principal, err = s.tokenService.AuthenticateRefreshToken(ctx, token)
+	default:
+		return inactive, nil
+	}
+	if err != nil {
+		if isInternalError(err) {
+			return nil, err
+		}
+		return inactive, nil
+	}
+
+	introspection := &contracts.TokenIntrospection{
+		Active:    true,
+		Subject:   claims.Subject,
+		Audience:  claims.Audience,
+		Issuer:    claims.Issuer,
+		TokenID:   claims.ID,
+		SessionID: principal.SessionID,
+	}
+	if claims.ExpiresAt != nil {
+		introspection.ExpiresAt = claims.ExpiresAt.Unix()
+	}
+	if claims.IssuedAt != nil {
+		introspection.IssuedAt = claims.IssuedAt.Unix()
+	}
+	if claims.NotBefore != nil {
+		introspection.NotBefore = claims.NotBefore.Unix()
+	}
+	if claims.Type == "access" {
+		introspection.TokenType = oauth.TokenTypeBearer
+	}
+
+	if principal.IsClient() {
+		introspection.ClientID = principal.ClientID
+		introspection.Scope = oauth.FormatScope(principal.Scopes)
+		return introspection, nil
+	}
+
+	userID := principal.User.ID()
+	roles, err := s.authzService.GetUserRoles(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	permissions, err := s.authzService.GetUserPermissions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(roles)
+	sort.Strings(permissions)
+
+	introspection.Username = principal.User.Email()
+	introspection.Roles = roles
+	introspection.Scope = oauth.FormatScope(permissions)
+	if principal.IsImpersonated() {
+		introspection.Actor = &contracts.TokenActor{Subject: principal.ActorID}
+	}
+
+	return introspection, nil
+}
+
+// RevokeToken follows RFC 7009: tokens that are invalid or already revoked
+// are not an error. Revoking a refresh token ends its session, taking the
+// access tokens of the session with it. A client token can only be revoked
+// by the client it was issued to; user tokens only by clients granted the
+// revocation scope.
+func (s *tokenIntrospectionService) RevokeToken(ctx context.Context, clientID, clientSecret, token string) error {
+	client, err := s.clientService.AuthenticateClient(ctx, clientID, clientSecret)
+	if err != nil {
+		return err
+	}
+
+	claims, err := s.jwtService.ValidateToken(token)
+	if err != nil {
+		return nil
+	}
+
+	if claims.ClientID != "" && claims.ClientID != client.ClientID {
+		return oauth.NewError(oauth.ErrorUnauthorizedClient, "token was issued to another client")
+	}
+	if claims.ClientID == "" && !client.AllowsScope(oauth.ScopeTokenRevoke) {
+		return oauth.NewError(oauth.ErrorUnauthorizedClient, "client is not allowed to revoke user tokens")
+	}
+
+	switch claims.Type {
+	case "access":
+		if err := s.tokenService.BlacklistToken(ctx, token); err != nil {
+			return apperrors.NewInternalError("failed to revoke token", err)
+		}
+	case "refresh":
+		return s.tokenService.RevokeRefreshToken(ctx, token)
+	}
+
+	return nil
+}
+
+func isInternalError(err error) bool {
+	var appErr *apperrors.AppError
+	return errors.As(err, &appErr) && appErr.Type == apperrors.ErrorTypeInternal
+}
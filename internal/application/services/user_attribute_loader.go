@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/user"
+	apperrors "github.com/tranvuongduy2003/go-mvc/pkg/errors"
+)
+
+// UserResourceType is the resource type of user accounts in policies.
+const UserResourceType = "users"
+
+// userAttributeLoader exposes user accounts to policies, both as the
+// resource of requests about an account and as the subject of every
+// request a user makes. A user owns their own account, hence owner_id.
+// Roles include the inherited ones even when role inheritance is off, so a
+// deny policy on a role also covers the roles deriving from it.
+type userAttributeLoader struct {
+	userRepo     user.UserRepository
+	authzService contracts.AuthorizationService
+}
+
+func NewUserAttributeLoader(userRepo user.UserRepository, authzService contracts.AuthorizationService) contracts.ResourceAttributeLoader {
+	return &userAttributeLoader{
+		userRepo:     userRepo,
+		authzService: authzService,
+	}
+}
+
+func (l *userAttributeLoader) ResourceType() string {
+	return UserResourceType
+}
+
+func (l *userAttributeLoader) LoadAttributes(ctx context.Context, id string) (contracts.Attributes, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, nil
+	}
+
+	userEntity, err := l.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to get user", err)
+	}
+	if userEntity == nil {
+		return nil, nil
+	}
+
+	roles, err := l.authzService.GetEffectiveRoles(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return contracts.Attributes{
+		"id":             userEntity.ID(),
+		"owner_id":       userEntity.ID(),
+		"email":          userEntity.Email(),
+		"name":           userEntity.Name(),
+		"is_active":      userEntity.IsActive(),
+		"email_verified": userEntity.IsEmailVerified(),
+		"mfa_enabled":    userEntity.MFA().IsEnabled(),
+		"roles":          roles,
+		"created_at":     userEntity.CreatedAt(),
+	}, nil
+}
@@ -36,12 +36,9 @@ func (v *UserValidator) ValidateCreateUserRequest(req userDto.CreateUserRequest)
 		errors["name"] = "Name must not exceed 100 characters"
 	}
 
+	// Length and composition are checked by the password policy
 	if req.Password == "" {
 		errors["password"] = "Password is required"
-	} else if len(req.Password) < 8 {
-		errors["password"] = "Password must be at least 8 characters long"
-	} else if !isValidPassword(req.Password) {
-		errors["password"] = "Password must contain at least one uppercase letter, one lowercase letter, and one number"
 	}
 
 	if req.Phone != "" && !isValidPhone(req.Phone) {
@@ -99,14 +96,6 @@ func isValidEmail(email string) bool {
 	return re.MatchString(email)
 }
 
-func isValidPassword(password string) bool {
-	hasUpper := regexp.MustCompile(`[A-Z]`).MatchString(password)
-	hasLower := regexp.MustCompile(`[a-z]`).MatchString(password)
-	hasDigit := regexp.MustCompile(`\d`).MatchString(password)
-
-	return hasUpper && hasLower && hasDigit
-}
-
 func isValidPhone(phone string) bool {
 	phoneRegex := `^(\+84|84|0)[1-9][0-9]{8,9}$`
 	re := regexp.MustCompile(phoneRegex)
@@ -0,0 +1,32 @@
+package auth
+
+import "time"
+
+// APIKey is a long-lived credential owned by a user. Only a SHA-256 hash of
+// the key is stored; Prefix is the leading part shown to the owner so keys
+// can be told apart. Scopes lists the permission names the key may use,
+// which never extends beyond what the owner is granted.
+type APIKey struct {
+	ID         string
+	UserID     string
+	Name       string
+	Prefix     string
+	KeyHash    string
+	Scopes     []string
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	CreatedAt  time.Time
+}
+
+func (k *APIKey) IsExpired(now time.Time) bool {
+	return k.ExpiresAt != nil && !now.Before(*k.ExpiresAt)
+}
+
+func (k *APIKey) HasScope(permission string) bool {
+	for _, scope := range k.Scopes {
+		if scope == permission {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,18 @@
+package auth
+
+import (
+	"context"
+	"time"
+)
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *APIKey) error
+
+	GetByHash(ctx context.Context, keyHash string) (*APIKey, error)
+
+	GetByUserID(ctx context.Context, userID string) ([]*APIKey, error)
+
+	UpdateLastUsed(ctx context.Context, id string, lastUsedAt time.Time) error
+
+	Delete(ctx context.Context, userID, id string) error
+}
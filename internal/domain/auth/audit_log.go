@@ -0,0 +1,24 @@
+package auth
+
+import "time"
+
+const (
+	AuditActionImpersonationStarted = "impersonation.started"
+	AuditActionImpersonatedRequest  = "impersonation.request"
+)
+
+// AuditLogEntry records something done to or on behalf of a user. ActorID
+// is whoever actually acted: it differs from UserID when an admin
+// impersonates the user.
+type AuditLogEntry struct {
+	ID        string
+	ActorID   string
+	UserID    string
+	SessionID string
+	Action    string
+	Method    string
+	Path      string
+	IPAddress string
+	UserAgent string
+	CreatedAt time.Time
+}
@@ -0,0 +1,9 @@
+package auth
+
+import "context"
+
+type AuditLogRepository interface {
+	Create(ctx context.Context, entry *AuditLogEntry) error
+
+	GetByUserID(ctx context.Context, userID string, limit int) ([]*AuditLogEntry, error)
+}
@@ -0,0 +1,15 @@
+package auth
+
+import "time"
+
+// ExternalIdentity links an account at an OpenID provider, identified by
+// the provider's subject, to a local user.
+type ExternalIdentity struct {
+	ID          string
+	UserID      string
+	Provider    string
+	Subject     string
+	Email       string
+	CreatedAt   time.Time
+	LastLoginAt *time.Time
+}
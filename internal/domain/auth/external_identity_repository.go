@@ -0,0 +1,16 @@
+package auth
+
+import (
+	"context"
+	"time"
+)
+
+type ExternalIdentityRepository interface {
+	Create(ctx context.Context, identity *ExternalIdentity) error
+
+	GetByProviderSubject(ctx context.Context, provider, subject string) (*ExternalIdentity, error)
+
+	GetByUserID(ctx context.Context, userID string) ([]*ExternalIdentity, error)
+
+	UpdateLastLogin(ctx context.Context, id string, lastLoginAt time.Time) error
+}
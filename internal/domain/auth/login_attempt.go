@@ -0,0 +1,44 @@
+package auth
+
+import "time"
+
+// Reasons recorded for failed login attempts.
+const (
+	LoginFailureInvalidCredentials = "invalid_credentials"
+	LoginFailureAccountInactive    = "account_inactive"
+	LoginFailureAccountLocked      = "account_locked"
+	LoginFailureEmailNotVerified   = "email_not_verified"
+	LoginFailureInvalidMFACode     = "invalid_mfa_code"
+	LoginFailurePasskeyRejected    = "passkey_rejected"
+)
+
+// LoginAttempt is one entry of a user's login history. UserID is empty for
+// attempts against unknown emails. Methods holds the amr values of a
+// successful login. DeviceKey identifies the client by its parsed browser,
+// operating system and device type, and is how a new device is told apart
+// from a known one.
+type LoginAttempt struct {
+	ID            string
+	UserID        string
+	Email         string
+	Success       bool
+	FailureReason string
+	Methods       []string
+	IPAddress     string
+	UserAgent     string
+	Browser       string
+	OS            string
+	DeviceType    string
+	DeviceKey     string
+	CountryCode   string
+	Country       string
+	City          string
+	Latitude      *float64
+	Longitude     *float64
+	CreatedAt     time.Time
+}
+
+// HasLocation reports whether the attempt could be placed on a map.
+func (a *LoginAttempt) HasLocation() bool {
+	return a.Latitude != nil && a.Longitude != nil
+}
@@ -0,0 +1,17 @@
+package auth
+
+import "context"
+
+type LoginAttemptRepository interface {
+	Create(ctx context.Context, attempt *LoginAttempt) error
+
+	GetByUserID(ctx context.Context, userID string, limit int) ([]*LoginAttempt, error)
+
+	// GetLastSuccessful returns the user's most recent successful login, or
+	// nil if there is none.
+	GetLastSuccessful(ctx context.Context, userID string) (*LoginAttempt, error)
+
+	// HasSucceededFromDevice reports whether the user has signed in from the
+	// device before.
+	HasSucceededFromDevice(ctx context.Context, userID, deviceKey string) (bool, error)
+}
@@ -0,0 +1,22 @@
+package auth
+
+import "time"
+
+// OAuthClient is a machine client allowed to obtain tokens with the
+// client_credentials grant. Scopes are the permission names it may request.
+type OAuthClient struct {
+	ClientID   string
+	Name       string
+	SecretHash string
+	Scopes     []string
+	TokenTTL   time.Duration
+}
+
+func (c *OAuthClient) AllowsScope(scope string) bool {
+	for _, allowed := range c.Scopes {
+		if allowed == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,12 @@
+package auth
+
+import "time"
+
+// PasswordHistoryEntry keeps the hash of a password a user has set, so the
+// password policy can refuse recent ones.
+type PasswordHistoryEntry struct {
+	ID           string
+	UserID       string
+	PasswordHash string
+	CreatedAt    time.Time
+}
@@ -0,0 +1,13 @@
+package auth
+
+import "context"
+
+type PasswordHistoryRepository interface {
+	Create(ctx context.Context, entry *PasswordHistoryEntry) error
+
+	// GetRecent returns up to limit entries, newest first.
+	GetRecent(ctx context.Context, userID string, limit int) ([]*PasswordHistoryEntry, error)
+
+	// Prune deletes all but the newest keep entries of the user.
+	Prune(ctx context.Context, userID string, keep int) error
+}
@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"errors"
+	"time"
+)
+
+// Policy effects. When both an allow and a deny policy match a request,
+// the deny wins.
+const (
+	PolicyEffectAllow = "allow"
+	PolicyEffectDeny  = "deny"
+)
+
+// PolicyWildcard in Resources or Actions matches any resource type or
+// action.
+const PolicyWildcard = "*"
+
+// Policy is an attribute-based access rule. It applies to requests for one
+// of its Actions on one of its Resources, and matches when Condition, an
+// expression over subject, resource, action and environment attributes,
+// holds. An empty Condition always holds.
+type Policy struct {
+	ID          string
+	Name        string
+	Description string
+	Effect      string
+	Resources   []string
+	Actions     []string
+	Condition   string
+	IsActive    bool
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// Validate checks the parts of the policy that do not need the expression
+// language; the condition is compiled by whoever evaluates it.
+func (p *Policy) Validate() error {
+	if p.Name == "" {
+		return errors.New("policy name cannot be empty")
+	}
+	if p.Effect != PolicyEffectAllow && p.Effect != PolicyEffectDeny {
+		return errors.New("policy effect must be allow or deny")
+	}
+	if len(p.Resources) == 0 {
+		return errors.New("policy must name at least one resource")
+	}
+	if len(p.Actions) == 0 {
+		return errors.New("policy must name at least one action")
+	}
+	return nil
+}
+
+// AppliesTo reports whether the policy covers the action on the resource
+// type, regardless of its condition.
+func (p *Policy) AppliesTo(resource, action string) bool {
+	return matchesAny(p.Resources, resource) && matchesAny(p.Actions, action)
+}
+
+func matchesAny(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == PolicyWildcard || candidate == value {
+			return true
+		}
+	}
+	return false
+}
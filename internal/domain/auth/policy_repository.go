@@ -0,0 +1,8 @@
+package auth
+
+import "context"
+
+type PolicyRepository interface {
+	// GetActive returns every active policy.
+	GetActive(ctx context.Context) ([]*Policy, error)
+}
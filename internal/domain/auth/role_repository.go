@@ -34,6 +34,15 @@ type RoleRepository interface {
 	GetRolesByUserID(ctx context.Context, userID string) ([]*Role, error)
 
 	GetActiveRolesByUserID(ctx context.Context, userID string) ([]*Role, error)
+
+	// SetParent stores the role's parent; a nil parentID makes it top-level.
+	SetParent(ctx context.Context, id string, parentID *string) error
+
+	// GetAncestors returns the roles the role inherits from, nearest first.
+	GetAncestors(ctx context.Context, id string) ([]*Role, error)
+
+	// GetDescendantIDs returns the IDs of every role inheriting from the role.
+	GetDescendantIDs(ctx context.Context, id string) ([]string, error)
 }
 
 type ListRolesParams struct {
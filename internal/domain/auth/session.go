@@ -0,0 +1,19 @@
+package auth
+
+import "time"
+
+type Session struct {
+	ID         string
+	UserID     string
+	DeviceName string
+	UserAgent  string
+	IPAddress  string
+	CreatedAt  time.Time
+	LastSeenAt time.Time
+	ExpiresAt  time.Time
+	RevokedAt  *time.Time
+}
+
+func (s *Session) IsActive() bool {
+	return s.RevokedAt == nil && s.ExpiresAt.After(time.Now())
+}
@@ -0,0 +1,20 @@
+package auth
+
+import (
+	"context"
+	"time"
+)
+
+type SessionRepository interface {
+	Create(ctx context.Context, session *Session) error
+
+	GetByID(ctx context.Context, id string) (*Session, error)
+
+	GetActiveByUserID(ctx context.Context, userID string) ([]*Session, error)
+
+	Touch(ctx context.Context, id string, lastSeenAt, expiresAt time.Time) error
+
+	Revoke(ctx context.Context, id string) error
+
+	RevokeAllByUserID(ctx context.Context, userID string) error
+}
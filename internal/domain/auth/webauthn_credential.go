@@ -0,0 +1,19 @@
+package auth
+
+import "time"
+
+// WebAuthnCredential is a passkey registered to a user. PublicKey holds the
+// COSE encoded key returned by the authenticator.
+type WebAuthnCredential struct {
+	ID           string
+	UserID       string
+	Name         string
+	CredentialID []byte
+	PublicKey    []byte
+	Algorithm    int64
+	SignCount    uint32
+	Transports   []string
+	AAGUID       []byte
+	CreatedAt    time.Time
+	LastUsedAt   *time.Time
+}
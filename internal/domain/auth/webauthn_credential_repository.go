@@ -0,0 +1,18 @@
+package auth
+
+import (
+	"context"
+	"time"
+)
+
+type WebAuthnCredentialRepository interface {
+	Create(ctx context.Context, credential *WebAuthnCredential) error
+
+	GetByCredentialID(ctx context.Context, credentialID []byte) (*WebAuthnCredential, error)
+
+	GetByUserID(ctx context.Context, userID string) ([]*WebAuthnCredential, error)
+
+	UpdateSignCount(ctx context.Context, id string, signCount uint32, lastUsedAt time.Time) error
+
+	Delete(ctx context.Context, userID, id string) error
+}
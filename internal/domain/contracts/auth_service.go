@@ -4,7 +4,9 @@ import (
 	"context"
 	"time"
 
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/auth"
 	"github.com/tranvuongduy2003/go-mvc/internal/domain/user"
+	"github.com/tranvuongduy2003/go-mvc/pkg/webauthn"
 )
 
 type AuthTokens struct {
@@ -15,21 +17,105 @@ type AuthTokens struct {
 	TokenType             string    `json:"token_type"`
 }
 
+type ClientInfo struct {
+	DeviceName string `json:"device_name"`
+	UserAgent  string `json:"user_agent"`
+	IPAddress  string `json:"ip_address"`
+}
+
 type LoginCredentials struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Email    string     `json:"email"`
+	Password string     `json:"password"`
+	Client   ClientInfo `json:"client"`
 }
 
 type RegisterRequest struct {
-	Email    string `json:"email"`
-	Name     string `json:"name"`
-	Phone    string `json:"phone"`
-	Password string `json:"password"`
+	Email    string     `json:"email"`
+	Name     string     `json:"name"`
+	Phone    string     `json:"phone"`
+	Password string     `json:"password"`
+	Client   ClientInfo `json:"client"`
 }
 
+// AuthenticatedUser carries either Tokens or, when the account requires a
+// second factor, an MFAChallenge to be redeemed through MFAService.
 type AuthenticatedUser struct {
-	User   *user.User  `json:"user"`
-	Tokens *AuthTokens `json:"tokens"`
+	User         *user.User    `json:"user"`
+	Tokens       *AuthTokens   `json:"tokens,omitempty"`
+	MFAChallenge *MFAChallenge `json:"mfa_challenge,omitempty"`
+}
+
+type MFAChallenge struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type MFAEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+// OIDCAuthorization is the provider URL the browser is sent to. State must
+// come back on the callback and is also bound to the browser by the caller.
+type OIDCAuthorization struct {
+	URL       string    `json:"url"`
+	State     string    `json:"state"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Principal is the identity behind a validated access token or API key.
+// APIKey is set only for API key requests, whose permissions are further
+// limited to the key's scopes. Client credentials tokens have no User; they
+// carry the ClientID and are authorized by Scopes alone. ActorID names the
+// admin behind an impersonation token. Authorization is the role and
+// permission snapshot of an access token, when it carries one.
+type Principal struct {
+	User          *user.User
+	SessionID     string
+	APIKey        *auth.APIKey
+	ClientID      string
+	Scopes        []string
+	ActorID       string
+	Authorization *TokenAuthorization
+	AuthTime      time.Time // Zero when the token does not say
+	AuthMethods   []string
+}
+
+// TokenAuthorization is the snapshot of roles and permissions embedded into
+// an access token. It is only handed out while still current.
+type TokenAuthorization struct {
+	Roles       []string
+	Permissions []string
+}
+
+func (a *TokenAuthorization) HasRole(roleName string) bool {
+	for _, role := range a.Roles {
+		if role == roleName {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *TokenAuthorization) HasPermission(permissionName string) bool {
+	for _, permission := range a.Permissions {
+		if permission == permissionName {
+			return true
+		}
+	}
+	return false
+}
+
+// IsClient reports whether the principal is an OAuth client acting on its
+// own behalf.
+func (p *Principal) IsClient() bool {
+	return p.ClientID != ""
+}
+
+// IsImpersonated reports whether someone other than the user is acting
+// through the principal.
+func (p *Principal) IsImpersonated() bool {
+	return p.ActorID != ""
 }
 
 type AuthService interface {
@@ -41,38 +127,258 @@ type AuthService interface {
 
 	ValidateToken(ctx context.Context, accessToken string) (*user.User, error)
 
+	Authenticate(ctx context.Context, accessToken string) (*Principal, error)
+
 	GetUserFromToken(ctx context.Context, token string) (*user.User, error)
 }
 
 type TokenManagementService interface {
-	Logout(ctx context.Context, userID string) error
+	Logout(ctx context.Context, userID, accessToken string) error
 
 	LogoutAll(ctx context.Context, userID string) error
 
 	IsTokenBlacklisted(ctx context.Context, token string) (bool, error)
 
 	BlacklistToken(ctx context.Context, token string) error
+
+	AuthenticateRefreshToken(ctx context.Context, refreshToken string) (*Principal, error)
+
+	RevokeRefreshToken(ctx context.Context, refreshToken string) error
+}
+
+type SessionManagementService interface {
+	ListSessions(ctx context.Context, userID string) ([]*auth.Session, error)
+
+	RevokeSession(ctx context.Context, userID, sessionID string) error
+}
+
+type MFAService interface {
+	BeginMFAEnrollment(ctx context.Context, userID string) (*MFAEnrollment, error)
+
+	ConfirmMFAEnrollment(ctx context.Context, userID, code string) ([]string, error)
+
+	RegenerateRecoveryCodes(ctx context.Context, userID, code string) ([]string, error)
+
+	VerifyMFAChallenge(ctx context.Context, challengeToken, code string) (*AuthenticatedUser, error)
+
+	ResetMFA(ctx context.Context, userID string) error
+}
+
+type PasskeyService interface {
+	BeginPasskeyRegistration(ctx context.Context, userID string) (*webauthn.CreationOptions, error)
+
+	FinishPasskeyRegistration(ctx context.Context, userID, name string, response *webauthn.AttestationResponse) (*auth.WebAuthnCredential, error)
+
+	BeginPasskeyLogin(ctx context.Context, email string) (*webauthn.RequestOptions, error)
+
+	FinishPasskeyLogin(ctx context.Context, response *webauthn.AssertionResponse, client ClientInfo) (*AuthenticatedUser, error)
+
+	ListPasskeys(ctx context.Context, userID string) ([]*auth.WebAuthnCredential, error)
+
+	DeletePasskey(ctx context.Context, userID, id string) error
+}
+
+type ExternalLoginService interface {
+	BeginOIDCLogin(ctx context.Context, provider string) (*OIDCAuthorization, error)
+
+	FinishOIDCLogin(ctx context.Context, provider, state, code string, client ClientInfo) (*AuthenticatedUser, error)
+}
+
+// MagicLinkRequest is returned to the browser that asked for a sign-in
+// link. The link only works together with Nonce.
+type MagicLinkRequest struct {
+	Nonce     string
+	ExpiresAt time.Time
+}
+
+type MagicLinkService interface {
+	RequestMagicLink(ctx context.Context, email string) (*MagicLinkRequest, error)
+
+	ConsumeMagicLink(ctx context.Context, token, nonce string, client ClientInfo) (*AuthenticatedUser, error)
+}
+
+// NewAPIKey describes a key to issue. A nil ExpiresAt picks the configured
+// default lifetime.
+type NewAPIKey struct {
+	Name      string
+	Scopes    []string
+	ExpiresAt *time.Time
+}
+
+// IssuedAPIKey carries the plaintext key, which is only ever returned once.
+type IssuedAPIKey struct {
+	APIKey *auth.APIKey
+	Key    string
+}
+
+type APIKeyService interface {
+	CreateAPIKey(ctx context.Context, userID string, req NewAPIKey) (*IssuedAPIKey, error)
+
+	ListAPIKeys(ctx context.Context, userID string) ([]*auth.APIKey, error)
+
+	DeleteAPIKey(ctx context.Context, userID, id string) error
+
+	AuthenticateAPIKey(ctx context.Context, key string) (*Principal, error)
+}
+
+// ClientToken is the token response of the client_credentials grant
+// (RFC 6749 section 4.4.3).
+type ClientToken struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+	Scope       string `json:"scope,omitempty"`
+}
+
+// ClientCredentialsService returns *oauth.Error for failures the client
+// must see in the OAuth error format.
+type ClientCredentialsService interface {
+	AuthenticateClient(ctx context.Context, clientID, clientSecret string) (*auth.OAuthClient, error)
+
+	IssueClientToken(ctx context.Context, clientID, clientSecret, scope string) (*ClientToken, error)
+}
+
+// TokenIntrospection is the introspection response of RFC 7662. Inactive
+// tokens only ever report Active false.
+type TokenIntrospection struct {
+	Active    bool        `json:"active"`
+	Scope     string      `json:"scope,omitempty"`
+	ClientID  string      `json:"client_id,omitempty"`
+	Username  string      `json:"username,omitempty"`
+	TokenType string      `json:"token_type,omitempty"`
+	ExpiresAt int64       `json:"exp,omitempty"`
+	IssuedAt  int64       `json:"iat,omitempty"`
+	NotBefore int64       `json:"nbf,omitempty"`
+	Subject   string      `json:"sub,omitempty"`
+	Audience  []string    `json:"aud,omitempty"`
+	Issuer    string      `json:"iss,omitempty"`
+	TokenID   string      `json:"jti,omitempty"`
+	Roles     []string    `json:"roles,omitempty"`
+	SessionID string      `json:"sid,omitempty"`
+	Actor     *TokenActor `json:"act,omitempty"`
+}
+
+// TokenActor identifies who is acting through an impersonation token
+// (RFC 8693 section 4.1).
+type TokenActor struct {
+	Subject string `json:"sub"`
+}
+
+// TokenIntrospectionService lets registered OAuth clients check and revoke
+// tokens centrally. Both calls authenticate the client first and return
+// *oauth.Error for protocol failures. Tokens are self-describing JWTs, so no
+// token_type_hint is needed.
+type TokenIntrospectionService interface {
+	IntrospectToken(ctx context.Context, clientID, clientSecret, token string) (*TokenIntrospection, error)
+
+	RevokeToken(ctx context.Context, clientID, clientSecret, token string) error
+}
+
+// Impersonation is an access token that lets an admin act as User. It is
+// bound to a session of its own and cannot be refreshed.
+type Impersonation struct {
+	User        *user.User
+	AccessToken string
+	TokenType   string
+	SessionID   string
+	ExpiresAt   time.Time
+}
+
+// Reauthentication is the proof presented to step up a session: the account
+// password, a current code from the user's authenticator, or both. Users
+// with MFA enabled must include the code.
+type Reauthentication struct {
+	Password string
+	Code     string
+}
+
+// ElevatedToken is an access token with a fresh auth_time. The session's
+// refresh token stays valid, and tokens refreshed from it keep the new
+// auth_time until it ages out.
+type ElevatedToken struct {
+	AccessToken string
+	TokenType   string
+	ExpiresAt   time.Time
+	AuthTime    time.Time
+	AuthMethods []string
+}
+
+type ReauthenticationService interface {
+	// Reauthenticate verifies the proof for the user behind the session and
+	// records it as the session's latest authentication. Failures count
+	// towards the login lockout.
+	Reauthenticate(ctx context.Context, userID, sessionID string, proof Reauthentication, client ClientInfo) (*ElevatedToken, error)
+}
+
+// LoginHistoryService exposes the record kept of every sign-in attempt and
+// the "this wasn't me" action offered by login alerts.
+type LoginHistoryService interface {
+	GetLoginHistory(ctx context.Context, userID string, limit int) ([]*auth.LoginAttempt, error)
+
+	// SecureAccount redeems the link of a login alert and signs out every
+	// session of the account.
+	SecureAccount(ctx context.Context, token string) error
+}
+
+type ImpersonationService interface {
+	ImpersonateUser(ctx context.Context, adminID, userID string, client ClientInfo) (*Impersonation, error)
+
+	// RecordImpersonatedRequest adds a request made through an impersonation
+	// token to the audit log.
+	RecordImpersonatedRequest(ctx context.Context, entry *auth.AuditLogEntry) error
+}
+
+type AccountLockoutService interface {
+	UnlockAccount(ctx context.Context, userID string) error
 }
 
 type PasswordManagementService interface {
-	ChangePassword(ctx context.Context, userID, oldPassword, newPassword string) error
+	// ChangePassword returns fresh tokens for sessionID, the only session
+	// that stays signed in, or nil when there is no such session.
+	ChangePassword(ctx context.Context, userID, sessionID, oldPassword, newPassword string) (*AuthTokens, error)
 
 	ResetPassword(ctx context.Context, email string) error
 
 	ConfirmPasswordReset(ctx context.Context, token, newPassword string) error
 }
 
+// PasswordSubject identifies whose password is being checked. UserID and
+// PasswordHash are empty at registration.
+type PasswordSubject struct {
+	UserID       string
+	Email        string
+	Name         string
+	PasswordHash string // The current password, which counts as the most recent one
+}
+
+type PasswordPolicyService interface {
+	ValidatePassword(ctx context.Context, password string, subject PasswordSubject) error
+
+	RecordPassword(ctx context.Context, userID, passwordHash string) error
+}
+
 type EmailVerificationService interface {
 	VerifyEmail(ctx context.Context, token string) error
 
 	ResendVerificationEmail(ctx context.Context, email string) error
 }
 
+type EmailChangeService interface {
+	RequestEmailChange(ctx context.Context, userID, newEmail, password string) error
+
+	ConfirmEmailChange(ctx context.Context, token string) error
+
+	RevertEmailChange(ctx context.Context, token string) error
+}
+
 type AuthorizationService interface {
 	UserHasPermission(ctx context.Context, userID, resource, action string) (bool, error)
 
 	UserHasPermissionByName(ctx context.Context, userID, permissionName string) (bool, error)
 
+	// UserHasRole and the other role checks count roles inherited through
+	// the hierarchy only when role inheritance is enabled; permissions are
+	// always inherited.
 	UserHasRole(ctx context.Context, userID, roleName string) (bool, error)
 
 	UserHasAnyRole(ctx context.Context, userID string, roleNames []string) (bool, error)
@@ -83,6 +389,11 @@ type AuthorizationService interface {
 
 	GetUserRoles(ctx context.Context, userID string) ([]string, error)
 
+	// GetEffectiveRoles names the user's roles and every role they inherit,
+	// following the hierarchy whether or not role inheritance is enabled.
+	// Checks that must not miss a role held through inheritance use it.
+	GetEffectiveRoles(ctx context.Context, userID string) ([]string, error)
+
 	CheckMultiplePermissions(ctx context.Context, userID string, permissions []string) (map[string]bool, error)
 
 	CanAccessResource(ctx context.Context, userID, resource, action string) error
@@ -92,6 +403,43 @@ type AuthorizationService interface {
 	IsModerator(ctx context.Context, userID string) (bool, error)
 
 	GetEffectivePermissions(ctx context.Context, userID string) ([]PermissionInfo, error)
+
+	// GetAuthorizationVersion stamps the user's current roles and
+	// permissions. The stamp changes whenever either may have changed, which
+	// makes tokens embedding an older snapshot stale.
+	GetAuthorizationVersion(ctx context.Context, userID string) (int64, error)
+
+	InvalidateUserAuthorization(ctx context.Context, userID string) error
+
+	// InvalidateRoleAuthorization invalidates every user holding the role or
+	// a role inheriting from it, for changes to the role itself or its
+	// permissions.
+	InvalidateRoleAuthorization(ctx context.Context, roleID string) error
+}
+
+// AuthorizationCache holds users' authorization in process, which each
+// instance must evict when told of a change.
+type AuthorizationCache interface {
+	// EvictUsers drops the cached authorization of users whose roles or
+	// permissions changed at changedAt.
+	EvictUsers(userIDs []string, changedAt time.Time)
+}
+
+// AuthorizationCacheMetrics records how the authorization cache performs.
+// Tier is "local" for the in-process cache and "redis" for the shared one.
+type AuthorizationCacheMetrics interface {
+	RecordLookup(tier string, hit bool)
+
+	// ObserveInvalidationLag records how long after a change this instance
+	// evicted the affected entries, the window in which it could serve
+	// stale authorization.
+	ObserveInvalidationLag(lag time.Duration)
+
+	// IncrementStaleReads counts entries that were read after the change
+	// that invalidated them.
+	IncrementStaleReads(count int)
+
+	SetLocalEntries(count int)
 }
 
 type PermissionInfo struct {
@@ -0,0 +1,43 @@
+package contracts
+
+import "context"
+
+// Attributes describe one party of an access request. Values are strings,
+// numbers, booleans, lists or nested Attributes.
+type Attributes map[string]interface{}
+
+// AccessRequest is what the policy decision point rules on. Resource must
+// carry a "type" attribute naming the resource type the policies target.
+type AccessRequest struct {
+	Subject     Attributes
+	Resource    Attributes
+	Action      string
+	Environment Attributes
+}
+
+// PolicyDecision is the outcome of an access request. Policy names the
+// policy that decided it, and is empty when no policy matched.
+type PolicyDecision struct {
+	Allowed bool
+	Policy  string
+	Reason  string
+}
+
+// PolicyDecisionPoint evaluates access requests against the configured
+// attribute-based policies. A request no allow policy matches is denied,
+// and a matching deny policy overrides every allow.
+type PolicyDecisionPoint interface {
+	Decide(ctx context.Context, request AccessRequest) (*PolicyDecision, error)
+
+	// Reload reads the policies again from their files and the database.
+	// On failure the previously loaded policies stay in effect.
+	Reload(ctx context.Context) error
+}
+
+// ResourceAttributeLoader fetches the attributes of a resource for policy
+// evaluation. It returns nil when the resource does not exist.
+type ResourceAttributeLoader interface {
+	ResourceType() string
+
+	LoadAttributes(ctx context.Context, id string) (Attributes, error)
+}
@@ -32,7 +32,7 @@ type CreateUserRequest struct {
 	Email    string `json:"email" validate:"required,email"`
 	Name     string `json:"name" validate:"required,min=2,max=100"`
 	Phone    string `json:"phone" validate:"omitempty"`
-	Password string `json:"password" validate:"required,min=8"`
+	Password string `json:"password" validate:"required"`
 }
 
 type UpdateUserRequest struct {
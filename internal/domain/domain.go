@@ -3,8 +3,8 @@ package domain
 import (
 	"go.uber.org/fx"
 
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/user"
 	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/logger"
-	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/security"
 )
 
 var DomainModule = fx.Module("domain",
@@ -13,6 +13,6 @@ var DomainModule = fx.Module("domain",
 
 type DomainParams struct {
 	fx.In
-	PasswordHasher *security.PasswordHasher
+	PasswordHasher user.PasswordHasher
 	Logger         *logger.Logger
 }
@@ -5,16 +5,21 @@ import (
 )
 
 const (
-	JobTypeEmail          = "email"
-	JobTypeEmailTemplate  = "email_template"
-	JobTypeFileProcessing = "file_processing"
-	JobTypeImageResize    = "image_resize"
-	JobTypeDataCleanup    = "data_cleanup"
-	JobTypeUserCleanup    = "user_cleanup"
-	JobTypeBackup         = "backup"
-	JobTypeExport         = "export"
-	JobTypeNotification   = "notification"
-	JobTypeAnalytics      = "analytics"
+	JobTypeEmail             = "email"
+	JobTypeEmailTemplate     = "email_template"
+	JobTypeVerificationEmail = "verification_email"
+	JobTypeLoginAlert        = "login_alert"
+	JobTypePasswordChanged   = "password_changed"
+	JobTypeUnusualSignIn     = "unusual_sign_in"
+	JobTypeMagicLink         = "magic_link"
+	JobTypeFileProcessing    = "file_processing"
+	JobTypeImageResize       = "image_resize"
+	JobTypeDataCleanup       = "data_cleanup"
+	JobTypeUserCleanup       = "user_cleanup"
+	JobTypeBackup            = "backup"
+	JobTypeExport            = "export"
+	JobTypeNotification      = "notification"
+	JobTypeAnalytics         = "analytics"
 )
 
 type EmailJob struct {
@@ -0,0 +1,60 @@
+package events
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuthorizationInvalidatedEventType is published whenever the roles or
+// permissions of some users may have changed: a role assignment, a change
+// to a role, its permissions or its place in the hierarchy.
+const AuthorizationInvalidatedEventType = "authorization.invalidated"
+
+// AuthorizationInvalidatedEvent lists the users whose authorization changed.
+// RoleID is set when the change was to a role rather than to one user.
+type AuthorizationInvalidatedEvent struct {
+	ID           string    `json:"id"`
+	UserIDs      []string  `json:"user_ids"`
+	RoleID       string    `json:"role_id,omitempty"`
+	AggregateID_ string    `json:"aggregate_id"`
+	Version_     int       `json:"version"`
+	OccurredAt   time.Time `json:"occurred_at"`
+}
+
+func NewAuthorizationInvalidatedEvent(userIDs []string, roleID string) *AuthorizationInvalidatedEvent {
+	aggregateID := roleID
+	if aggregateID == "" && len(userIDs) == 1 {
+		aggregateID = userIDs[0]
+	}
+
+	return &AuthorizationInvalidatedEvent{
+		ID:           uuid.New().String(),
+		UserIDs:      userIDs,
+		RoleID:       roleID,
+		AggregateID_: aggregateID,
+		Version_:     1,
+		OccurredAt:   time.Now(),
+	}
+}
+
+func (e *AuthorizationInvalidatedEvent) EventType() string {
+	return AuthorizationInvalidatedEventType
+}
+
+func (e *AuthorizationInvalidatedEvent) EventData() ([]byte, error) {
+	return json.Marshal(e)
+}
+
+func (e *AuthorizationInvalidatedEvent) AggregateID() string {
+	return e.AggregateID_
+}
+
+func (e *AuthorizationInvalidatedEvent) Version() int {
+	return e.Version_
+}
+
+func (e *AuthorizationInvalidatedEvent) Timestamp() int64 {
+	return e.OccurredAt.Unix()
+}
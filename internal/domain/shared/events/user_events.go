@@ -132,3 +132,85 @@ func (e *UserAvatarUploadedEvent) Version() int {
 func (e *UserAvatarUploadedEvent) Timestamp() int64 {
 	return e.OccurredAt.Unix()
 }
+
+type UserEmailVerifiedEvent struct {
+	ID           string    `json:"id"`
+	UserID       string    `json:"user_id"`
+	Email        string    `json:"email"`
+	AggregateID_ string    `json:"aggregate_id"`
+	Version_     int       `json:"version"`
+	OccurredAt   time.Time `json:"occurred_at"`
+}
+
+func NewUserEmailVerifiedEvent(userID, email string, version int, verifiedAt time.Time) *UserEmailVerifiedEvent {
+	return &UserEmailVerifiedEvent{
+		ID:           uuid.New().String(),
+		UserID:       userID,
+		Email:        email,
+		AggregateID_: userID,
+		Version_:     version,
+		OccurredAt:   verifiedAt,
+	}
+}
+
+func (e *UserEmailVerifiedEvent) EventType() string {
+	return "user.email.verified"
+}
+
+func (e *UserEmailVerifiedEvent) EventData() ([]byte, error) {
+	return json.Marshal(e)
+}
+
+func (e *UserEmailVerifiedEvent) AggregateID() string {
+	return e.AggregateID_
+}
+
+func (e *UserEmailVerifiedEvent) Version() int {
+	return e.Version_
+}
+
+func (e *UserEmailVerifiedEvent) Timestamp() int64 {
+	return e.OccurredAt.Unix()
+}
+
+type UserEmailChangedEvent struct {
+	ID           string    `json:"id"`
+	UserID       string    `json:"user_id"`
+	OldEmail     string    `json:"old_email"`
+	NewEmail     string    `json:"new_email"`
+	AggregateID_ string    `json:"aggregate_id"`
+	Version_     int       `json:"version"`
+	OccurredAt   time.Time `json:"occurred_at"`
+}
+
+func NewUserEmailChangedEvent(userID, oldEmail, newEmail string, version int, changedAt time.Time) *UserEmailChangedEvent {
+	return &UserEmailChangedEvent{
+		ID:           uuid.New().String(),
+		UserID:       userID,
+		OldEmail:     oldEmail,
+		NewEmail:     newEmail,
+		AggregateID_: userID,
+		Version_:     version,
+		OccurredAt:   changedAt,
+	}
+}
+
+func (e *UserEmailChangedEvent) EventType() string {
+	return "user.email.changed"
+}
+
+func (e *UserEmailChangedEvent) EventData() ([]byte, error) {
+	return json.Marshal(e)
+}
+
+func (e *UserEmailChangedEvent) AggregateID() string {
+	return e.AggregateID_
+}
+
+func (e *UserEmailChangedEvent) Version() int {
+	return e.Version_
+}
+
+func (e *UserEmailChangedEvent) Timestamp() int64 {
+	return e.OccurredAt.Unix()
+}
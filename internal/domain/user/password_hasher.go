@@ -0,0 +1,30 @@
+package user
+
+// PasswordHasher turns plain passwords into storable hashes. Implementations
+// live in the infrastructure layer so the algorithm can change without the
+// domain knowing.
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+
+	Verify(password, hash string) bool
+
+	// NeedsRehash reports whether the hash was produced by an algorithm or
+	// with parameters other than the ones currently configured.
+	NeedsRehash(hash string) bool
+}
+
+// PasswordByteLimiter is implemented by hashers that only read a bounded
+// number of bytes of the password, such as bcrypt. Longer passwords would
+// be silently truncated, so they must be rejected instead.
+type PasswordByteLimiter interface {
+	MaxPasswordBytes() int
+}
+
+// MaxPasswordBytes returns the byte limit of the hasher, or 0 when it reads
+// the whole password.
+func MaxPasswordBytes(hasher PasswordHasher) int {
+	if limiter, ok := hasher.(PasswordByteLimiter); ok {
+		return limiter.MaxPasswordBytes()
+	}
+	return 0
+}
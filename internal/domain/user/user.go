@@ -1,14 +1,15 @@
 package user
 
 import (
+	"crypto/subtle"
 	"errors"
+	"fmt"
 	"regexp"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/tranvuongduy2003/go-mvc/internal/domain/shared/events"
-	"golang.org/x/crypto/bcrypt"
 )
 
 type User struct {
@@ -23,6 +24,13 @@ type User struct {
 	updatedAt time.Time
 	version   int64
 	events    []events.DomainEvent
+
+	persistedVersion int64 // The version stored in the database, 0 before the first save
+
+	tokenVersion      int64 // Embedded into issued tokens; bumping it revokes all of them
+	passwordChangedAt time.Time
+	mfa               MFA
+	emailVerifiedAt   *time.Time
 }
 
 type UserID struct {
@@ -145,23 +153,22 @@ type Password struct {
 	hashedValue string
 }
 
-func NewPassword(plainPassword string) (Password, error) {
+func NewPassword(plainPassword string, hasher PasswordHasher) (Password, error) {
 	if plainPassword == "" {
 		return Password{}, errors.New("password cannot be empty")
 	}
-	if len(plainPassword) < 8 {
-		return Password{}, errors.New("password must be at least 8 characters")
-	}
-	if len(plainPassword) > 72 {
-		return Password{}, errors.New("password cannot exceed 72 characters")
+	// Length rules belong to the password policy; only the hasher's own
+	// limit is enforced here
+	if limit := MaxPasswordBytes(hasher); limit > 0 && len(plainPassword) > limit {
+		return Password{}, fmt.Errorf("password cannot exceed %d bytes", limit)
 	}
 
-	hashedBytes, err := bcrypt.GenerateFromPassword([]byte(plainPassword), bcrypt.DefaultCost)
+	hashedValue, err := hasher.Hash(plainPassword)
 	if err != nil {
 		return Password{}, errors.New("failed to hash password")
 	}
 
-	return Password{hashedValue: string(hashedBytes)}, nil
+	return Password{hashedValue: hashedValue}, nil
 }
 
 func NewHashedPassword(hashedPassword string) Password {
@@ -172,9 +179,52 @@ func (p Password) Hash() string {
 	return p.hashedValue
 }
 
-func (p Password) VerifyPassword(plainPassword string) bool {
-	err := bcrypt.CompareHashAndPassword([]byte(p.hashedValue), []byte(plainPassword))
-	return err == nil
+func (p Password) VerifyPassword(plainPassword string, hasher PasswordHasher) bool {
+	return hasher.Verify(plainPassword, p.hashedValue)
+}
+
+// MFA holds the confirmed TOTP enrollment. Recovery codes are stored as
+// hashes and removed once used.
+type MFA struct {
+	secret        string
+	enabledAt     *time.Time
+	recoveryCodes []string
+}
+
+func NewMFA(secret string, enabledAt *time.Time, recoveryCodes []string) MFA {
+	return MFA{
+		secret:        secret,
+		enabledAt:     enabledAt,
+		recoveryCodes: recoveryCodes,
+	}
+}
+
+func (m MFA) Secret() string {
+	return m.secret
+}
+
+func (m MFA) EnabledAt() *time.Time {
+	return m.enabledAt
+}
+
+func (m MFA) IsEnabled() bool {
+	return m.enabledAt != nil && m.secret != ""
+}
+
+func (m MFA) RecoveryCodes() []string {
+	return m.recoveryCodes
+}
+
+// HasRecoveryCode reports whether an unused recovery code has the given
+// hash, without consuming it.
+func (m MFA) HasRecoveryCode(codeHash string) bool {
+	found := false
+	for _, hash := range m.recoveryCodes {
+		if subtle.ConstantTimeCompare([]byte(hash), []byte(codeHash)) == 1 {
+			found = true
+		}
+	}
+	return found
 }
 
 type UserCreated struct {
@@ -193,13 +243,28 @@ type UserUpdated struct {
 	UpdatedAt time.Time
 }
 
+type EmailVerified struct {
+	*events.BaseDomainEvent
+	UserID     string
+	Email      string
+	VerifiedAt time.Time
+}
+
+type UserEmailChanged struct {
+	*events.BaseDomainEvent
+	UserID    string
+	OldEmail  string
+	NewEmail  string
+	ChangedAt time.Time
+}
+
 type UserDeleted struct {
 	*events.BaseDomainEvent
 	UserID    string
 	DeletedAt time.Time
 }
 
-func NewUser(email, name, phone, password string) (*User, error) {
+func NewUser(email, name, phone, password string, hasher PasswordHasher) (*User, error) {
 	userID := NewUserID()
 
 	emailVO, err := NewEmail(email)
@@ -217,24 +282,25 @@ func NewUser(email, name, phone, password string) (*User, error) {
 		return nil, err
 	}
 
-	passwordVO, err := NewPassword(password)
+	passwordVO, err := NewPassword(password, hasher)
 	if err != nil {
 		return nil, err
 	}
 
 	now := time.Now()
 	user := &User{
-		id:        userID,
-		email:     emailVO,
-		name:      nameVO,
-		phone:     phoneVO,
-		password:  passwordVO,
-		avatar:    NewAvatar("", ""), // Empty avatar initially
-		isActive:  true,
-		createdAt: now,
-		updatedAt: now,
-		version:   1,
-		events:    make([]events.DomainEvent, 0),
+		id:                userID,
+		email:             emailVO,
+		name:              nameVO,
+		phone:             phoneVO,
+		password:          passwordVO,
+		avatar:            NewAvatar("", ""), // Empty avatar initially
+		isActive:          true,
+		createdAt:         now,
+		updatedAt:         now,
+		version:           1,
+		passwordChangedAt: passwordTimestamp(now),
+		events:            make([]events.DomainEvent, 0),
 	}
 
 	userUUID, _ := uuid.Parse(userID.String())
@@ -253,7 +319,7 @@ func NewUser(email, name, phone, password string) (*User, error) {
 	return user, nil
 }
 
-func ReconstructUser(id, email, name, phone, hashedPassword, avatarFileKey, avatarCDNUrl string, isActive bool, createdAt, updatedAt time.Time, version int64) (*User, error) {
+func ReconstructUser(id, email, name, phone, hashedPassword, avatarFileKey, avatarCDNUrl string, isActive bool, createdAt, updatedAt time.Time, version, tokenVersion int64, passwordChangedAt time.Time, mfa MFA, emailVerifiedAt *time.Time) (*User, error) {
 	userID, err := NewUserIDFromString(id)
 	if err != nil {
 		return nil, err
@@ -277,17 +343,22 @@ func ReconstructUser(id, email, name, phone, hashedPassword, avatarFileKey, avat
 	avatarVO := NewAvatar(avatarFileKey, avatarCDNUrl)
 
 	return &User{
-		id:        userID,
-		email:     emailVO,
-		name:      nameVO,
-		phone:     phoneVO,
-		password:  NewHashedPassword(hashedPassword),
-		avatar:    avatarVO,
-		isActive:  isActive,
-		createdAt: createdAt,
-		updatedAt: updatedAt,
-		version:   version,
-		events:    make([]events.DomainEvent, 0),
+		id:                userID,
+		email:             emailVO,
+		name:              nameVO,
+		phone:             phoneVO,
+		password:          NewHashedPassword(hashedPassword),
+		avatar:            avatarVO,
+		isActive:          isActive,
+		createdAt:         createdAt,
+		updatedAt:         updatedAt,
+		version:           version,
+		persistedVersion:  version,
+		tokenVersion:      tokenVersion,
+		passwordChangedAt: passwordChangedAt,
+		mfa:               mfa,
+		emailVerifiedAt:   emailVerifiedAt,
+		events:            make([]events.DomainEvent, 0),
 	}, nil
 }
 
@@ -327,10 +398,44 @@ func (u *User) Version() int64 {
 	return u.version
 }
 
+// PersistedVersion is the version the user had when it was loaded or last
+// saved. Repositories only overwrite the stored user if it still has it.
+func (u *User) PersistedVersion() int64 {
+	return u.persistedVersion
+}
+
+// MarkPersisted records that the user was saved with the given version.
+func (u *User) MarkPersisted(version int64) {
+	u.version = version
+	u.persistedVersion = version
+}
+
 func (u *User) Avatar() Avatar {
 	return u.avatar
 }
 
+func (u *User) TokenVersion() int64 {
+	return u.tokenVersion
+}
+
+// PasswordChangedAt is when the current password was set. Re-encoding it
+// with RehashPassword keeps the time, as the password itself is the same.
+func (u *User) PasswordChangedAt() time.Time {
+	return u.passwordChangedAt
+}
+
+func (u *User) MFA() MFA {
+	return u.mfa
+}
+
+func (u *User) EmailVerifiedAt() *time.Time {
+	return u.emailVerifiedAt
+}
+
+func (u *User) IsEmailVerified() bool {
+	return u.emailVerifiedAt != nil
+}
+
 func (u *User) UpdateProfile(name, phone string) error {
 	nameVO, err := NewName(name)
 	if err != nil {
@@ -363,19 +468,44 @@ func (u *User) UpdateProfile(name, phone string) error {
 	return nil
 }
 
-func (u *User) ChangePassword(newPassword string) error {
-	passwordVO, err := NewPassword(newPassword)
+func (u *User) ChangePassword(newPassword string, hasher PasswordHasher) error {
+	passwordVO, err := NewPassword(newPassword, hasher)
 	if err != nil {
 		return err
 	}
 
+	now := time.Now()
 	u.password = passwordVO
-	u.updatedAt = time.Now()
+	u.passwordChangedAt = passwordTimestamp(now)
+	u.updatedAt = now
 	u.version++
 
 	return nil
 }
 
+// RehashPassword re-encodes the current password with the hasher's present
+// algorithm and parameters. It needs the plain password, so it can only run
+// right after the user has proven it.
+func (u *User) RehashPassword(plainPassword string, hasher PasswordHasher) (bool, error) {
+	if !hasher.NeedsRehash(u.password.Hash()) {
+		return false, nil
+	}
+
+	passwordVO, err := NewPassword(plainPassword, hasher)
+	if err != nil {
+		return false, err
+	}
+	u.password = passwordVO
+
+	return true, nil
+}
+
+// passwordTimestamp drops what the database cannot store, so a user compares
+// the same before and after a round trip.
+func passwordTimestamp(t time.Time) time.Time {
+	return t.UTC().Truncate(time.Microsecond)
+}
+
 func (u *User) UpdateAvatar(fileKey, cdnUrl string) {
 	u.avatar = NewAvatar(fileKey, cdnUrl)
 	u.updatedAt = time.Now()
@@ -403,8 +533,116 @@ func (u *User) Activate() {
 	u.version++
 }
 
-func (u *User) VerifyPassword(password string) bool {
-	return u.password.VerifyPassword(password)
+func (u *User) VerifyEmail() error {
+	if u.emailVerifiedAt != nil {
+		return errors.New("email is already verified")
+	}
+
+	now := time.Now()
+	u.emailVerifiedAt = &now
+	u.updatedAt = now
+	u.version++
+
+	userUUID, _ := uuid.Parse(u.id.String())
+	u.addEvent(&EmailVerified{
+		BaseDomainEvent: events.NewBaseDomainEvent("EmailVerified", userUUID, "User", map[string]interface{}{
+			"user_id": u.id.String(),
+			"email":   u.email.String(),
+		}),
+		UserID:     u.id.String(),
+		Email:      u.email.String(),
+		VerifiedAt: now,
+	})
+
+	return nil
+}
+
+// ChangeEmail switches the account to an address its owner has just proven
+// control of, so the new address counts as verified.
+func (u *User) ChangeEmail(email string) error {
+	emailVO, err := NewEmail(email)
+	if err != nil {
+		return err
+	}
+	if emailVO == u.email {
+		return errors.New("new email must differ from the current email")
+	}
+
+	oldEmail := u.email.String()
+	now := time.Now()
+	u.email = emailVO
+	u.emailVerifiedAt = &now
+	u.updatedAt = now
+	u.version++
+
+	userUUID, _ := uuid.Parse(u.id.String())
+	u.addEvent(&UserEmailChanged{
+		BaseDomainEvent: events.NewBaseDomainEvent("UserEmailChanged", userUUID, "User", map[string]interface{}{
+			"user_id":   u.id.String(),
+			"old_email": oldEmail,
+			"new_email": emailVO.String(),
+		}),
+		UserID:    u.id.String(),
+		OldEmail:  oldEmail,
+		NewEmail:  emailVO.String(),
+		ChangedAt: now,
+	})
+
+	return nil
+}
+
+func (u *User) EnableMFA(secret string, recoveryCodes []string) error {
+	if u.mfa.IsEnabled() {
+		return errors.New("MFA is already enabled")
+	}
+	if secret == "" {
+		return errors.New("MFA secret cannot be empty")
+	}
+
+	now := time.Now()
+	u.mfa = NewMFA(secret, &now, recoveryCodes)
+	u.updatedAt = now
+	u.version++
+
+	return nil
+}
+
+func (u *User) ReplaceRecoveryCodes(recoveryCodes []string) error {
+	if !u.mfa.IsEnabled() {
+		return errors.New("MFA is not enabled")
+	}
+
+	u.mfa.recoveryCodes = recoveryCodes
+	u.updatedAt = time.Now()
+	u.version++
+
+	return nil
+}
+
+// UseRecoveryCode consumes the recovery code with the given hash.
+func (u *User) UseRecoveryCode(codeHash string) bool {
+	for i, hash := range u.mfa.recoveryCodes {
+		if subtle.ConstantTimeCompare([]byte(hash), []byte(codeHash)) == 1 {
+			remaining := make([]string, 0, len(u.mfa.recoveryCodes)-1)
+			remaining = append(remaining, u.mfa.recoveryCodes[:i]...)
+			remaining = append(remaining, u.mfa.recoveryCodes[i+1:]...)
+			u.mfa.recoveryCodes = remaining
+			u.updatedAt = time.Now()
+			u.version++
+			return true
+		}
+	}
+	return false
+}
+
+func (u *User) ResetMFA() {
+	u.mfa = MFA{}
+	u.updatedAt = time.Now()
+	u.version++
+}
+
+func (u *User) VerifyPassword(password string, hasher PasswordHasher) bool {
+	return u.password.VerifyPassword(password, hasher)
 }
 
 func (u *User) addEvent(event events.DomainEvent) {
@@ -2,10 +2,15 @@ package user
 
 import (
 	"context"
+	"errors"
 
 	"github.com/tranvuongduy2003/go-mvc/pkg/pagination"
 )
 
+// ErrConcurrentUpdate is returned when the user was saved by another request
+// after this copy was loaded. The caller should reload and retry.
+var ErrConcurrentUpdate = errors.New("user was modified concurrently")
+
 type UserRepository interface {
 	Create(ctx context.Context, user *User) error
 
@@ -13,8 +18,19 @@ type UserRepository interface {
 
 	GetByEmail(ctx context.Context, email string) (*User, error)
 
+	// Update saves the user only if nobody saved it since it was loaded and
+	// returns ErrConcurrentUpdate otherwise.
 	Update(ctx context.Context, user *User) error
 
+	// UpdatePasswordHash replaces the password hash with a re-encoding of the
+	// same password, only if it is still currentHash. It returns
+	// ErrConcurrentUpdate otherwise and writes no other column.
+	UpdatePasswordHash(ctx context.Context, id, currentHash, newHash string) error
+
+	// IncrementTokenVersion bumps the user's token version in place, which
+	// revokes every token issued so far. Update never writes the version.
+	IncrementTokenVersion(ctx context.Context, id string) error
+
 	Delete(ctx context.Context, id string) error
 
 	List(ctx context.Context, params ListUsersParams) ([]*User, *pagination.Pagination, error)
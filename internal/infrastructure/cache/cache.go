@@ -73,6 +73,75 @@ func (s *Service) Get(ctx context.Context, key string, dest interface{}) error {
 	return nil
 }
 
+// GetDel reads and removes a key in one atomic step (Redis GETDEL), so at
+// most one caller across all instances receives the value.
+func (s *Service) GetDel(ctx context.Context, key string, dest interface{}) error {
+	s.logger.Debugf("Getting and deleting cache key: %s", key)
+
+	data, err := s.client.GetDel(ctx, key).Result()
+	if err != nil {
+		if err == redis.Nil {
+			s.logger.Debugf("Cache miss for key: %s", key)
+			return ErrCacheMiss
+		}
+		s.logger.Errorf("Failed to get and delete cache key %s: %v", key, err)
+		return fmt.Errorf("failed to get and delete cache: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(data), dest); err != nil {
+		s.logger.Errorf("Failed to unmarshal cache value for key %s: %v", key, err)
+		return fmt.Errorf("failed to unmarshal cache value: %w", err)
+	}
+
+	s.logger.Debugf("Successfully got and deleted cache key: %s", key)
+	return nil
+}
+
+// mergeFieldsScript overwrites top-level fields of a JSON value in place,
+// keeping its expiry. It returns 0 when the key does not exist.
+var mergeFieldsScript = redis.NewScript(`
+local current = redis.call('GET', KEYS[1])
+if not current then
+	return 0
+end
+local value = cjson.decode(current)
+for field, fieldValue in pairs(cjson.decode(ARGV[1])) do
+	value[field] = fieldValue
+end
+local ttl = redis.call('PTTL', KEYS[1])
+if ttl > 0 then
+	redis.call('SET', KEYS[1], cjson.encode(value), 'PX', ttl)
+else
+	redis.call('SET', KEYS[1], cjson.encode(value))
+end
+return 1
+`)
+
+// MergeFields updates some top-level fields of a JSON object stored by Set
+// in one atomic step, leaving the other fields and the expiry untouched.
+// It returns ErrCacheMiss when the key does not exist.
+func (s *Service) MergeFields(ctx context.Context, key string, fields map[string]interface{}) error {
+	s.logger.Debugf("Merging fields into cache key: %s", key)
+
+	data, err := json.Marshal(fields)
+	if err != nil {
+		s.logger.Errorf("Failed to marshal fields for cache key %s: %v", key, err)
+		return fmt.Errorf("failed to marshal fields: %w", err)
+	}
+
+	merged, err := mergeFieldsScript.Run(ctx, s.client, []string{key}, data).Int()
+	if err != nil {
+		s.logger.Errorf("Failed to merge fields into cache key %s: %v", key, err)
+		return fmt.Errorf("failed to merge fields: %w", err)
+	}
+	if merged == 0 {
+		return ErrCacheMiss
+	}
+
+	s.logger.Debugf("Successfully merged fields into cache key: %s", key)
+	return nil
+}
+
 func (s *Service) Delete(ctx context.Context, key string) error {
 	s.logger.Debugf("Deleting cache key: %s", key)
 
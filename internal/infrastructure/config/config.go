@@ -15,6 +15,7 @@ type AppConfig struct {
 	Redis     Redis     `mapstructure:"redis"`
 	Logger    Logger    `mapstructure:"logger"`
 	JWT       JWT       `mapstructure:"jwt"`
+	Auth      Auth      `mapstructure:"auth"`
 	Metrics   Metrics   `mapstructure:"metrics"`
 	Tracing   Tracing   `mapstructure:"tracing"`
 	RateLimit RateLimit `mapstructure:"rate_limit"`
@@ -115,6 +116,229 @@ type JWT struct {
 	RefreshExpiry time.Duration `mapstructure:"refresh_expiry"`
 	Issuer        string        `mapstructure:"issuer"`
 	Audience      string        `mapstructure:"audience"`
+
+	// Asymmetric signing keys. When empty, tokens are signed with Secret (HS256).
+	Keys                []JWTKey      `mapstructure:"keys"`
+	RotationGracePeriod time.Duration `mapstructure:"rotation_grace_period"`
+}
+
+type JWTKey struct {
+	ID             string `mapstructure:"id"`
+	Algorithm      string `mapstructure:"algorithm"` // RS256, ES256 or EdDSA
+	PrivateKeyPath string `mapstructure:"private_key_path"`
+	PublicKeyPath  string `mapstructure:"public_key_path"` // Verification-only keys omit the private key
+	ActiveFrom     string `mapstructure:"active_from"`     // RFC3339, schedules when the key starts signing
+}
+
+type Auth struct {
+	MFA                MFA                `mapstructure:"mfa"`
+	WebAuthn           WebAuthn           `mapstructure:"webauthn"`
+	Lockout            Lockout            `mapstructure:"lockout"`
+	OIDC               OIDC               `mapstructure:"oidc"`
+	EmailVerification  EmailVerification  `mapstructure:"email_verification"`
+	EmailChange        EmailChange        `mapstructure:"email_change"`
+	PasswordPolicy     PasswordPolicy     `mapstructure:"password_policy"`
+	PasswordHashing    PasswordHashing    `mapstructure:"password_hashing"`
+	MagicLink          MagicLink          `mapstructure:"magic_link"`
+	APIKeys            APIKeys            `mapstructure:"api_keys"`
+	OAuth              OAuth              `mapstructure:"oauth"`
+	Impersonation      Impersonation      `mapstructure:"impersonation"`
+	TokenAuthorization TokenAuthorization `mapstructure:"token_authorization"`
+	StepUp             StepUp             `mapstructure:"step_up"`
+	LoginHistory       LoginHistory       `mapstructure:"login_history"`
+	RoleHierarchy      RoleHierarchy      `mapstructure:"role_hierarchy"`
+	Policies           Policies           `mapstructure:"policies"`
+	AuthorizationCache AuthorizationCache `mapstructure:"authorization_cache"`
+}
+
+type MFA struct {
+	Issuer            string        `mapstructure:"issuer"`
+	ChallengeTTL      time.Duration `mapstructure:"challenge_ttl"`
+	EnrollmentTTL     time.Duration `mapstructure:"enrollment_ttl"`
+	MaxAttempts       int           `mapstructure:"max_attempts"`
+	RecoveryCodeCount int           `mapstructure:"recovery_code_count"`
+}
+
+type WebAuthn struct {
+	RPID             string        `mapstructure:"rp_id"`
+	RPName           string        `mapstructure:"rp_name"`
+	Origins          []string      `mapstructure:"origins"`
+	Timeout          time.Duration `mapstructure:"timeout"`
+	UserVerification string        `mapstructure:"user_verification"`
+}
+
+// Lockout throttles failed password logins. After FreeAttempts failures for
+// an email, each further failure delays the next attempt (doubling from
+// BaseDelay up to MaxDelay) until MaxAttempts locks it for LockDuration.
+type Lockout struct {
+	FreeAttempts  int           `mapstructure:"free_attempts"`
+	MaxAttempts   int           `mapstructure:"max_attempts"`
+	IPMaxAttempts int           `mapstructure:"ip_max_attempts"`
+	BaseDelay     time.Duration `mapstructure:"base_delay"`
+	MaxDelay      time.Duration `mapstructure:"max_delay"`
+	LockDuration  time.Duration `mapstructure:"lock_duration"`
+	Window        time.Duration `mapstructure:"window"`
+}
+
+// EmailVerification controls what an account may do before its owner has
+// confirmed the email address. RestrictedPermissions lists permission names
+// (or "resource:action" pairs) that stay denied until then.
+type EmailVerification struct {
+	TokenTTL              time.Duration `mapstructure:"token_ttl"`
+	RequiredForLogin      bool          `mapstructure:"required_for_login"`
+	RestrictedPermissions []string      `mapstructure:"restricted_permissions"`
+}
+
+// EmailChange bounds how long the confirmation link sent to a new address
+// and the revert link sent to the old address stay valid.
+type EmailChange struct {
+	ConfirmTTL time.Duration `mapstructure:"confirm_ttl"`
+	RevertTTL  time.Duration `mapstructure:"revert_ttl"`
+}
+
+// PasswordPolicy applies to every password a user sets. BreachedHashesDir
+// points at SHA-1 range files ("<PREFIX>.txt" holding "SUFFIX:COUNT" lines);
+// leaving it empty disables the breach check. HistorySize is how many
+// previous passwords may not be reused.
+type PasswordPolicy struct {
+	MinLength         int    `mapstructure:"min_length"`
+	MaxLength         int    `mapstructure:"max_length"`
+	RequireUpper      bool   `mapstructure:"require_upper"`
+	RequireLower      bool   `mapstructure:"require_lower"`
+	RequireDigit      bool   `mapstructure:"require_digit"`
+	RequireSpecial    bool   `mapstructure:"require_special"`
+	BlocklistFile     string `mapstructure:"blocklist_file"`
+	BreachedHashesDir string `mapstructure:"breached_hashes_dir"`
+	HistorySize       int    `mapstructure:"history_size"`
+}
+
+// PasswordHashing selects the algorithm for new hashes ("argon2id" or
+// "bcrypt"). Hashes made with the other algorithm or older parameters keep
+// verifying and are upgraded on the user's next login.
+type PasswordHashing struct {
+	Algorithm  string `mapstructure:"algorithm"`
+	BcryptCost int    `mapstructure:"bcrypt_cost"`
+	Argon2     Argon2 `mapstructure:"argon2"`
+}
+
+type Argon2 struct {
+	Memory      uint32 `mapstructure:"memory"` // KiB
+	Iterations  uint32 `mapstructure:"iterations"`
+	Parallelism uint8  `mapstructure:"parallelism"`
+	SaltLength  uint32 `mapstructure:"salt_length"`
+	KeyLength   uint32 `mapstructure:"key_length"`
+}
+
+// MagicLink limits passwordless sign-in links to MaxRequests per email
+// within RequestWindow, each valid for TokenTTL.
+type MagicLink struct {
+	TokenTTL      time.Duration `mapstructure:"token_ttl"`
+	MaxRequests   int           `mapstructure:"max_requests"`
+	RequestWindow time.Duration `mapstructure:"request_window"`
+}
+
+// APIKeys governs personal access tokens. Keys are issued as
+// "<KeyPrefix>_<random>", expire after DefaultTTL unless the owner picks
+// another expiry, and never live longer than MaxTTL.
+type APIKeys struct {
+	KeyPrefix  string        `mapstructure:"key_prefix"`
+	MaxPerUser int           `mapstructure:"max_per_user"`
+	DefaultTTL time.Duration `mapstructure:"default_ttl"`
+	MaxTTL     time.Duration `mapstructure:"max_ttl"`
+}
+
+// OAuth is the authorization server used for service-to-service calls.
+// Clients registered here exchange their secret for an access token through
+// the client_credentials grant.
+type OAuth struct {
+	DefaultTokenTTL time.Duration `mapstructure:"default_token_ttl"`
+	Clients         []OAuthClient `mapstructure:"clients"`
+}
+
+// OAuthClient is a confidential client. SecretHash is the hex SHA-256 of the
+// client secret and Scopes the permission names it may request. A zero
+// TokenTTL falls back to OAuth.DefaultTokenTTL.
+type OAuthClient struct {
+	ClientID   string        `mapstructure:"client_id"`
+	Name       string        `mapstructure:"name"`
+	SecretHash string        `mapstructure:"secret_hash"`
+	Scopes     []string      `mapstructure:"scopes"`
+	TokenTTL   time.Duration `mapstructure:"token_ttl"`
+}
+
+// Impersonation bounds how long an admin may act as another user with a
+// single token. There is no refresh token; the admin asks again.
+type Impersonation struct {
+	TokenTTL time.Duration `mapstructure:"token_ttl"`
+}
+
+// TokenAuthorization embeds the user's roles and permissions into access
+// tokens so requests can be authorized without database lookups. Users with
+// more than MaxPermissions permissions get tokens without the snapshot and
+// are checked against the database as before.
+type TokenAuthorization struct {
+	Embed          bool `mapstructure:"embed"`
+	MaxPermissions int  `mapstructure:"max_permissions"`
+}
+
+// StepUp is how recently a user must have proved their identity before
+// sensitive account changes are allowed. Older sessions re-authenticate
+// through /auth/reauthenticate first.
+type StepUp struct {
+	MaxAge time.Duration `mapstructure:"max_age"`
+}
+
+// LoginHistory configures the record of login attempts and the alerts sent
+// for suspicious ones. GeoIPDatabase is the path of a MaxMind DB file such
+// as GeoLite2-City; when empty, locations and travel checks are disabled.
+// A login implying travel faster than ImpossibleTravelSpeed (km/h) since
+// the previous one triggers an alert, as does a login from a new device.
+type LoginHistory struct {
+	GeoIPDatabase         string        `mapstructure:"geoip_database"`
+	PageSize              int           `mapstructure:"page_size"`
+	ImpossibleTravelSpeed float64       `mapstructure:"impossible_travel_speed"`
+	AlertLinkTTL          time.Duration `mapstructure:"alert_link_ttl"`
+}
+
+// RoleHierarchy configures how parent roles are treated. Permissions are
+// always inherited from ancestor roles; with InheritRoles set, holding a
+// role also counts as holding its ancestors in role checks and in the role
+// lists embedded into tokens.
+type RoleHierarchy struct {
+	InheritRoles bool `mapstructure:"inherit_roles"`
+}
+
+// Policies configures the attribute-based policy decision point. Policies
+// are read from the files matching the Files glob patterns and, with
+// Database set, from the policies table, and are reloaded every
+// RefreshInterval.
+type Policies struct {
+	Files           []string      `mapstructure:"files"`
+	Database        bool          `mapstructure:"database"`
+	RefreshInterval time.Duration `mapstructure:"refresh_interval"`
+}
+
+// AuthorizationCache configures caching of each user's roles and
+// permissions. Entries are evicted as soon as authorization changes, on every
+// instance; the TTLs only bound how long a missed eviction can go unnoticed.
+type AuthorizationCache struct {
+	Enabled         bool          `mapstructure:"enabled"`
+	LocalTTL        time.Duration `mapstructure:"local_ttl"`
+	LocalMaxEntries int           `mapstructure:"local_max_entries"`
+	RedisTTL        time.Duration `mapstructure:"redis_ttl"`
+}
+
+type OIDC struct {
+	StateTTL  time.Duration           `mapstructure:"state_ttl"`
+	Providers map[string]OIDCProvider `mapstructure:"providers"` // Keyed by the name used in the login URL
+}
+
+type OIDCProvider struct {
+	Issuer       string   `mapstructure:"issuer"`
+	ClientID     string   `mapstructure:"client_id"`
+	ClientSecret string   `mapstructure:"client_secret"`
+	RedirectURL  string   `mapstructure:"redirect_url"`
+	Scopes       []string `mapstructure:"scopes"`
 }
 
 type Metrics struct {
@@ -295,6 +519,76 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("jwt.issuer", "go-mvc-enterprise")
 	v.SetDefault("jwt.audience", "go-mvc-enterprise")
 
+	v.SetDefault("auth.mfa.issuer", "go-mvc")
+	v.SetDefault("auth.mfa.challenge_ttl", "5m")
+	v.SetDefault("auth.mfa.enrollment_ttl", "10m")
+	v.SetDefault("auth.mfa.max_attempts", 5)
+	v.SetDefault("auth.mfa.recovery_code_count", 10)
+
+	v.SetDefault("auth.webauthn.rp_id", "localhost")
+	v.SetDefault("auth.webauthn.rp_name", "go-mvc")
+	v.SetDefault("auth.webauthn.origins", []string{"http://localhost:3000"})
+	v.SetDefault("auth.webauthn.timeout", "5m")
+	v.SetDefault("auth.webauthn.user_verification", "preferred")
+
+	v.SetDefault("auth.lockout.free_attempts", 3)
+	v.SetDefault("auth.lockout.max_attempts", 10)
+	v.SetDefault("auth.lockout.ip_max_attempts", 50)
+	v.SetDefault("auth.lockout.base_delay", "1s")
+	v.SetDefault("auth.lockout.max_delay", "1m")
+	v.SetDefault("auth.lockout.lock_duration", "15m")
+	v.SetDefault("auth.lockout.window", "15m")
+
+	v.SetDefault("auth.oidc.state_ttl", "10m")
+
+	v.SetDefault("auth.email_verification.token_ttl", "24h")
+	v.SetDefault("auth.email_verification.required_for_login", false)
+	v.SetDefault("auth.email_verification.restricted_permissions", []string{})
+
+	v.SetDefault("auth.email_change.confirm_ttl", "1h")
+	v.SetDefault("auth.email_change.revert_ttl", "168h")
+
+	v.SetDefault("auth.password_policy.min_length", 8)
+	v.SetDefault("auth.password_policy.max_length", 72)
+	v.SetDefault("auth.password_policy.require_upper", true)
+	v.SetDefault("auth.password_policy.require_lower", true)
+	v.SetDefault("auth.password_policy.require_digit", true)
+	v.SetDefault("auth.password_policy.require_special", false)
+	v.SetDefault("auth.password_policy.history_size", 5)
+
+	v.SetDefault("auth.password_hashing.algorithm", "argon2id")
+	v.SetDefault("auth.password_hashing.bcrypt_cost", 12)
+	v.SetDefault("auth.password_hashing.argon2.memory", 65536)
+	v.SetDefault("auth.password_hashing.argon2.iterations", 3)
+	v.SetDefault("auth.password_hashing.argon2.parallelism", 2)
+	v.SetDefault("auth.password_hashing.argon2.salt_length", 16)
+	v.SetDefault("auth.password_hashing.argon2.key_length", 32)
+
+	v.SetDefault("auth.magic_link.token_ttl", "15m")
+	v.SetDefault("auth.magic_link.max_requests", 3)
+	v.SetDefault("auth.magic_link.request_window", "15m")
+	v.SetDefault("auth.api_keys.key_prefix", "gmvc")
+	v.SetDefault("auth.api_keys.max_per_user", 10)
+	v.SetDefault("auth.api_keys.default_ttl", "2160h")
+	v.SetDefault("auth.api_keys.max_ttl", "8760h")
+	v.SetDefault("auth.oauth.default_token_ttl", "15m")
+	v.SetDefault("auth.impersonation.token_ttl", "15m")
+	v.SetDefault("auth.token_authorization.embed", false)
+	v.SetDefault("auth.token_authorization.max_permissions", 100)
+	v.SetDefault("auth.step_up.max_age", "10m")
+	v.SetDefault("auth.login_history.geoip_database", "")
+	v.SetDefault("auth.login_history.page_size", 50)
+	v.SetDefault("auth.login_history.impossible_travel_speed", 1000)
+	v.SetDefault("auth.login_history.alert_link_ttl", "168h")
+	v.SetDefault("auth.role_hierarchy.inherit_roles", false)
+	v.SetDefault("auth.policies.files", []string{"configs/policies/*.yaml"})
+	v.SetDefault("auth.policies.database", true)
+	v.SetDefault("auth.policies.refresh_interval", "1m")
+	v.SetDefault("auth.authorization_cache.enabled", true)
+	v.SetDefault("auth.authorization_cache.local_ttl", "1m")
+	v.SetDefault("auth.authorization_cache.local_max_entries", 10000)
+	v.SetDefault("auth.authorization_cache.redis_ttl", "15m")
+
 	v.SetDefault("metrics.enabled", true)
 	v.SetDefault("metrics.path", "/metrics")
 	v.SetDefault("metrics.port", 9090)
@@ -322,8 +616,8 @@ func validateConfig(config *AppConfig) error {
 		return fmt.Errorf("server.http.port must be between 1 and 65535")
 	}
 
-	if config.JWT.Secret == "" && config.App.Environment == "production" {
-		return fmt.Errorf("jwt.secret is required in production")
+	if config.JWT.Secret == "" && len(config.JWT.Keys) == 0 && config.App.Environment == "production" {
+		return fmt.Errorf("jwt.secret or jwt.keys is required in production")
 	}
 
 	return nil
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/smtp"
+	"time"
 
 	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/config"
 	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/logger"
@@ -77,9 +78,6 @@ The Team
 }
 
 func (s *SMTPService) SendPasswordResetEmail(ctx context.Context, to, firstName, resetToken string) error {
-	s.logger.Infof("🔥 SMTP DEBUG: SendPasswordResetEmail called with to=%s, firstName=%s, token=%s", to, firstName, resetToken)
-	s.logger.Infof("🔥 SMTP DEBUG: Config - host=%s, port=%s, from=%s, useTLS=%t", s.host, s.port, s.from, s.useTLS)
-
 	subject := "Password Reset Request"
 	body := fmt.Sprintf(`
 Hello %s,
@@ -97,6 +95,123 @@ The Team
 	return s.SendEmail(ctx, []string{to}, subject, body)
 }
 
+func (s *SMTPService) SendPasswordChangedEmail(ctx context.Context, to, firstName string, at time.Time) error {
+	subject := "Your Password Was Changed"
+	body := fmt.Sprintf(`
+Hello %s,
+
+The password for your account was reset on %s, and every device signed in to your account has been signed out.
+
+If this was you, no action is needed. If this wasn't you, request a new password reset right away and contact support.
+
+Best regards,
+The Team
+`, firstName, at.UTC().Format(time.RFC1123))
+
+	return s.SendEmail(ctx, []string{to}, subject, body)
+}
+
+func (s *SMTPService) SendUnusualSignInEmail(ctx context.Context, to, firstName string, failedAttempts int, ipAddress string, lockedUntil time.Time) error {
+	subject := "Unusual Sign-in Attempts on Your Account"
+	body := fmt.Sprintf(`
+Hello %s,
+
+We noticed %d failed sign-in attempts on your account, most recently from IP address %s.
+
+To protect your account, password sign-in has been temporarily locked until %s.
+
+If this was you, you can try again after that time. If not, we recommend resetting your password.
+
+Best regards,
+The Team
+`, firstName, failedAttempts, ipAddress, lockedUntil.UTC().Format(time.RFC1123))
+
+	return s.SendEmail(ctx, []string{to}, subject, body)
+}
+
+func (s *SMTPService) SendLoginAlertEmail(ctx context.Context, to, firstName, reason, device, location, ipAddress string, at time.Time, secureToken string) error {
+	subject := "New Sign-in to Your Account"
+	notice := "Your account was just signed in to from a device we haven't seen before."
+	if reason == "impossible_travel" {
+		subject = "Suspicious Sign-in to Your Account"
+		notice = "Your account was just signed in to from a location too far from your previous sign-in to have travelled there in time."
+	}
+
+	body := fmt.Sprintf(`
+Hello %s,
+
+%s
+
+Device: %s
+Location: %s
+IP address: %s
+Time: %s
+
+If this was you, no action is needed. If this wasn't you, click the link below to sign out every session, then reset your password:
+
+http://localhost:8080/api/v1/auth/secure-account?token=%s
+
+Best regards,
+The Team
+`, firstName, notice, device, location, ipAddress, at.UTC().Format(time.RFC1123), secureToken)
+
+	return s.SendEmail(ctx, []string{to}, subject, body)
+}
+
+func (s *SMTPService) SendEmailChangeConfirmation(ctx context.Context, to, firstName, confirmToken string) error {
+	subject := "Confirm Your New Email Address"
+	body := fmt.Sprintf(`
+Hello %s,
+
+You asked to use this address for your account. Please click the link below to confirm the change:
+
+http://localhost:8080/api/v1/auth/email/confirm?token=%s
+
+If you didn't request this, please ignore this email.
+
+Best regards,
+The Team
+`, firstName, confirmToken)
+
+	return s.SendEmail(ctx, []string{to}, subject, body)
+}
+
+func (s *SMTPService) SendEmailChangeNotice(ctx context.Context, to, firstName, newEmail, revertToken string) error {
+	subject := "Your Email Address Is Being Changed"
+	body := fmt.Sprintf(`
+Hello %s,
+
+A request was made to change the email address of your account to %s.
+
+If this wasn't you, click the link below to cancel the change, or undo it if it has already been confirmed. All sessions will be signed out:
+
+http://localhost:8080/api/v1/auth/email/revert?token=%s
+
+Best regards,
+The Team
+`, firstName, newEmail, revertToken)
+
+	return s.SendEmail(ctx, []string{to}, subject, body)
+}
+
+func (s *SMTPService) SendMagicLinkEmail(ctx context.Context, to, firstName, token string, expiresAt time.Time) error {
+	subject := "Your Sign-in Link"
+	body := fmt.Sprintf(`
+Hello %s,
+
+Click the link below to sign in. It works once, only in the browser where you requested it, and expires at %s:
+
+http://localhost:8080/api/v1/auth/magic-link/consume?token=%s
+
+If you didn't request this, you can safely ignore this email.
+
+Best regards,
+The Team
+`, firstName, expiresAt.UTC().Format(time.RFC1123), token)
+
+	return s.SendEmail(ctx, []string{to}, subject, body)
+}
+
 func (s *SMTPService) buildMessage(to []string, subject, body string) string {
 	message := fmt.Sprintf("To: %s\r\n", to[0])
 	if len(to) > 1 {
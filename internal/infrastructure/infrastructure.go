@@ -21,6 +21,7 @@ import (
 	postgresRepos "github.com/tranvuongduy2003/go-mvc/internal/infrastructure/persistence/postgres/repositories"
 	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/security"
 	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/tracing"
+	"github.com/tranvuongduy2003/go-mvc/pkg/geoip"
 )
 
 var InfrastructureModule = fx.Module("infrastructure",
@@ -42,6 +43,15 @@ var InfrastructureModule = fx.Module("infrastructure",
 		NewPermissionRepository,
 		NewUserRoleRepository,
 		NewRolePermissionRepository,
+		NewSessionRepository,
+		NewWebAuthnCredentialRepository,
+		NewExternalIdentityRepository,
+		NewPasswordHistoryRepository,
+		NewAPIKeyRepository,
+		NewAuditLogRepository,
+		NewLoginAttemptRepository,
+		NewPolicyRepository,
+		NewGeoIPReader,
 	),
 )
 
@@ -66,8 +76,15 @@ func NewDatabase(params DatabaseParams) *gorm.DB {
 	return params.Manager.Primary()
 }
 
-func NewPasswordHasher() *security.PasswordHasher {
-	return security.NewPasswordHasher(12) // Default cost of 12
+func NewPasswordHasher(cfg *config.AppConfig) (user.PasswordHasher, error) {
+	hashing := cfg.Auth.PasswordHashing
+	return security.NewPasswordHasher(hashing.Algorithm, hashing.BcryptCost, security.Argon2Params{
+		Memory:      hashing.Argon2.Memory,
+		Iterations:  hashing.Argon2.Iterations,
+		Parallelism: hashing.Argon2.Parallelism,
+		SaltLength:  hashing.Argon2.SaltLength,
+		KeyLength:   hashing.Argon2.KeyLength,
+	})
 }
 
 func NewTracingService(cfg *config.AppConfig) (*tracing.TracingService, error) {
@@ -94,6 +111,54 @@ func NewRolePermissionRepository(db *gorm.DB) auth.RolePermissionRepository {
 	return postgresRepos.NewRolePermissionRepository(db)
 }
 
+func NewSessionRepository(db *gorm.DB) auth.SessionRepository {
+	return postgresRepos.NewSessionRepository(db)
+}
+
+func NewWebAuthnCredentialRepository(db *gorm.DB) auth.WebAuthnCredentialRepository {
+	return postgresRepos.NewWebAuthnCredentialRepository(db)
+}
+
+func NewExternalIdentityRepository(db *gorm.DB) auth.ExternalIdentityRepository {
+	return postgresRepos.NewExternalIdentityRepository(db)
+}
+
+func NewPasswordHistoryRepository(db *gorm.DB) auth.PasswordHistoryRepository {
+	return postgresRepos.NewPasswordHistoryRepository(db)
+}
+
+func NewAPIKeyRepository(db *gorm.DB) auth.APIKeyRepository {
+	return postgresRepos.NewAPIKeyRepository(db)
+}
+
+func NewAuditLogRepository(db *gorm.DB) auth.AuditLogRepository {
+	return postgresRepos.NewAuditLogRepository(db)
+}
+
+func NewLoginAttemptRepository(db *gorm.DB) auth.LoginAttemptRepository {
+	return postgresRepos.NewLoginAttemptRepository(db)
+}
+
+func NewPolicyRepository(db *gorm.DB) auth.PolicyRepository {
+	return postgresRepos.NewPolicyRepository(db)
+}
+
+// NewGeoIPReader loads the offline GeoIP database. Without one, login
+// history is recorded without locations and travel checks are skipped.
+func NewGeoIPReader(cfg *config.AppConfig, logger *logger.Logger) *geoip.Reader {
+	path := cfg.Auth.LoginHistory.GeoIPDatabase
+	if path == "" {
+		return nil
+	}
+
+	reader, err := geoip.Open(path)
+	if err != nil {
+		logger.Warnf("GeoIP database %s not loaded, login locations are disabled: %v", path, err)
+		return nil
+	}
+	return reader
+}
+
 func NewTokenGenerator() *security.TokenGenerator {
 	return security.NewTokenGenerator()
 }
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/job"
+	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/external"
+)
+
+// LoginAlertJobHandler delivers the alert queued when a login comes from a
+// new device or an implausible location.
+type LoginAlertJobHandler struct {
+	smtpService *external.SMTPService
+	metrics     job.JobMetrics
+}
+
+func NewLoginAlertJobHandler(smtpService *external.SMTPService, metrics job.JobMetrics) *LoginAlertJobHandler {
+	return &LoginAlertJobHandler{
+		smtpService: smtpService,
+		metrics:     metrics,
+	}
+}
+
+func (h *LoginAlertJobHandler) Execute(ctx context.Context, executedJob job.Job) error {
+	start := time.Now()
+	defer func() {
+		if h.metrics != nil {
+			h.metrics.ObserveJobDuration(executedJob.GetType(), time.Since(start))
+		}
+	}()
+
+	payload := executedJob.GetPayload()
+	to, _ := payload["to"].(string)
+	name, _ := payload["name"].(string)
+	reason, _ := payload["reason"].(string)
+	device, _ := payload["device"].(string)
+	location, _ := payload["location"].(string)
+	ipAddress, _ := payload["ip_address"].(string)
+	token, _ := payload["token"].(string)
+
+	if to == "" || token == "" {
+		return fmt.Errorf("login alert job %s is missing recipient or token", executedJob.GetID())
+	}
+
+	at := time.Now()
+	if value, _ := payload["time"].(string); value != "" {
+		if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+			at = parsed
+		}
+	}
+
+	if err := h.smtpService.SendLoginAlertEmail(ctx, to, name, reason, device, location, ipAddress, at, token); err != nil {
+		return fmt.Errorf("failed to send login alert: %w", err)
+	}
+
+	return nil
+}
+
+func (h *LoginAlertJobHandler) GetJobType() string {
+	return job.JobTypeLoginAlert
+}
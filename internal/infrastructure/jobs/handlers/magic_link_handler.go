@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/job"
+	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/external"
+)
+
+// MagicLinkJobHandler delivers the sign-in link queued by a magic link
+// request.
+type MagicLinkJobHandler struct {
+	smtpService *external.SMTPService
+	metrics     job.JobMetrics
+}
+
+func NewMagicLinkJobHandler(smtpService *external.SMTPService, metrics job.JobMetrics) *MagicLinkJobHandler {
+	return &MagicLinkJobHandler{
+		smtpService: smtpService,
+		metrics:     metrics,
+	}
+}
+
+func (h *MagicLinkJobHandler) Execute(ctx context.Context, executedJob job.Job) error {
+	start := time.Now()
+	defer func() {
+		if h.metrics != nil {
+			h.metrics.ObserveJobDuration(executedJob.GetType(), time.Since(start))
+		}
+	}()
+
+	payload := executedJob.GetPayload()
+	to, _ := payload["to"].(string)
+	name, _ := payload["name"].(string)
+	token, _ := payload["token"].(string)
+
+	if to == "" || token == "" {
+		return fmt.Errorf("magic link job %s is missing recipient or token", executedJob.GetID())
+	}
+
+	expiresAt, err := time.Parse(time.RFC3339, fmt.Sprint(payload["expires_at"]))
+	if err != nil {
+		return fmt.Errorf("magic link job %s has an invalid expiry: %w", executedJob.GetID(), err)
+	}
+	// A link delivered after it expired would only confuse its recipient
+	if time.Now().After(expiresAt) {
+		return nil
+	}
+
+	if err := h.smtpService.SendMagicLinkEmail(ctx, to, name, token, expiresAt); err != nil {
+		return fmt.Errorf("failed to send magic link email: %w", err)
+	}
+
+	return nil
+}
+
+func (h *MagicLinkJobHandler) GetJobType() string {
+	return job.JobTypeMagicLink
+}
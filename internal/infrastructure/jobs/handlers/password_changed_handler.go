@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/job"
+	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/external"
+)
+
+// PasswordChangedJobHandler delivers the notice queued after a password
+// reset.
+type PasswordChangedJobHandler struct {
+	smtpService *external.SMTPService
+	metrics     job.JobMetrics
+}
+
+func NewPasswordChangedJobHandler(smtpService *external.SMTPService, metrics job.JobMetrics) *PasswordChangedJobHandler {
+	return &PasswordChangedJobHandler{
+		smtpService: smtpService,
+		metrics:     metrics,
+	}
+}
+
+func (h *PasswordChangedJobHandler) Execute(ctx context.Context, executedJob job.Job) error {
+	start := time.Now()
+	defer func() {
+		if h.metrics != nil {
+			h.metrics.ObserveJobDuration(executedJob.GetType(), time.Since(start))
+		}
+	}()
+
+	payload := executedJob.GetPayload()
+	to, _ := payload["to"].(string)
+	name, _ := payload["name"].(string)
+
+	if to == "" {
+		return fmt.Errorf("password changed job %s is missing recipient", executedJob.GetID())
+	}
+
+	at := time.Now()
+	if value, _ := payload["time"].(string); value != "" {
+		if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+			at = parsed
+		}
+	}
+
+	if err := h.smtpService.SendPasswordChangedEmail(ctx, to, name, at); err != nil {
+		return fmt.Errorf("failed to send password changed email: %w", err)
+	}
+
+	return nil
+}
+
+func (h *PasswordChangedJobHandler) GetJobType() string {
+	return job.JobTypePasswordChanged
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/job"
+	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/external"
+)
+
+// UnusualSignInJobHandler delivers the warning queued when an account gets
+// locked after repeated failed sign-ins.
+type UnusualSignInJobHandler struct {
+	smtpService *external.SMTPService
+	metrics     job.JobMetrics
+}
+
+func NewUnusualSignInJobHandler(smtpService *external.SMTPService, metrics job.JobMetrics) *UnusualSignInJobHandler {
+	return &UnusualSignInJobHandler{
+		smtpService: smtpService,
+		metrics:     metrics,
+	}
+}
+
+func (h *UnusualSignInJobHandler) Execute(ctx context.Context, executedJob job.Job) error {
+	start := time.Now()
+	defer func() {
+		if h.metrics != nil {
+			h.metrics.ObserveJobDuration(executedJob.GetType(), time.Since(start))
+		}
+	}()
+
+	payload := executedJob.GetPayload()
+	to, _ := payload["to"].(string)
+	name, _ := payload["name"].(string)
+	ipAddress, _ := payload["ip_address"].(string)
+
+	if to == "" {
+		return fmt.Errorf("unusual sign-in job %s is missing recipient", executedJob.GetID())
+	}
+
+	// Payloads round-trip through JSON, so numbers come back as float64
+	failures := 0
+	switch value := payload["failed_attempts"].(type) {
+	case float64:
+		failures = int(value)
+	case int:
+		failures = value
+	}
+
+	var lockedUntil time.Time
+	if value, _ := payload["locked_until"].(string); value != "" {
+		if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+			lockedUntil = parsed
+		}
+	}
+
+	if err := h.smtpService.SendUnusualSignInEmail(ctx, to, name, failures, ipAddress, lockedUntil); err != nil {
+		return fmt.Errorf("failed to send unusual sign-in email: %w", err)
+	}
+
+	return nil
+}
+
+func (h *UnusualSignInJobHandler) GetJobType() string {
+	return job.JobTypeUnusualSignIn
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/job"
+	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/external"
+)
+
+// VerificationEmailJobHandler delivers the email verification link queued by
+// registration and resend requests.
+type VerificationEmailJobHandler struct {
+	smtpService *external.SMTPService
+	metrics     job.JobMetrics
+}
+
+func NewVerificationEmailJobHandler(smtpService *external.SMTPService, metrics job.JobMetrics) *VerificationEmailJobHandler {
+	return &VerificationEmailJobHandler{
+		smtpService: smtpService,
+		metrics:     metrics,
+	}
+}
+
+func (h *VerificationEmailJobHandler) Execute(ctx context.Context, executedJob job.Job) error {
+	start := time.Now()
+	defer func() {
+		if h.metrics != nil {
+			h.metrics.ObserveJobDuration(executedJob.GetType(), time.Since(start))
+		}
+	}()
+
+	payload := executedJob.GetPayload()
+	to, _ := payload["to"].(string)
+	name, _ := payload["name"].(string)
+	token, _ := payload["token"].(string)
+
+	if to == "" || token == "" {
+		return fmt.Errorf("verification email job %s is missing recipient or token", executedJob.GetID())
+	}
+
+	if err := h.smtpService.SendVerificationEmail(ctx, to, name, token); err != nil {
+		return fmt.Errorf("failed to send verification email: %w", err)
+	}
+
+	return nil
+}
+
+func (h *VerificationEmailJobHandler) GetJobType() string {
+	return job.JobTypeVerificationEmail
+}
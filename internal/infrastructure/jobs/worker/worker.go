@@ -159,6 +159,7 @@ type WorkerPool struct {
 	workers     map[string]*Worker
 	queue       job.JobQueue
 	workerCount int
+	handlers    []job.JobHandler // Handed to every worker, including those started later
 	mu          sync.RWMutex
 	running     bool
 	shutdown    chan struct{}
@@ -202,6 +203,9 @@ func (wp *WorkerPool) Start(ctx context.Context) error {
 		worker := NewWorker(workerID, wp.queue)
 
 		wp.mu.Lock()
+		for _, handler := range wp.handlers {
+			worker.RegisterHandler(handler)
+		}
 		wp.workers[workerID] = worker
 		wp.mu.Unlock()
 
@@ -293,8 +297,10 @@ func (wp *WorkerPool) RemoveWorker(workerID string) {
 }
 
 func (wp *WorkerPool) RegisterHandler(handler job.JobHandler) {
-	wp.mu.RLock()
-	defer wp.mu.RUnlock()
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+
+	wp.handlers = append(wp.handlers, handler)
 
 	for _, worker := range wp.workers {
 		worker.RegisterHandler(handler)
@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// AuthorizationCacheCollector exports the authorization cache metrics. The
+// hit ratio of a tier is its hits over all its lookups.
+type AuthorizationCacheCollector struct {
+	lookups         *prometheus.CounterVec
+	invalidationLag prometheus.Histogram
+	staleReads      prometheus.Counter
+	localEntries    prometheus.Gauge
+}
+
+func NewAuthorizationCacheCollector() *AuthorizationCacheCollector {
+	return &AuthorizationCacheCollector{
+		lookups: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "go_mvc",
+				Subsystem: "authz_cache",
+				Name:      "lookups_total",
+				Help:      "Total number of authorization cache lookups",
+			},
+			[]string{"tier", "result"},
+		),
+
+		invalidationLag: promauto.NewHistogram(
+			prometheus.HistogramOpts{
+				Namespace: "go_mvc",
+				Subsystem: "authz_cache",
+				Name:      "invalidation_lag_seconds",
+				Help:      "Time between an authorization change and its eviction from the local cache",
+				Buckets:   prometheus.ExponentialBuckets(0.001, 2, 15), // 1ms to ~16s
+			},
+		),
+
+		staleReads: promauto.NewCounter(
+			prometheus.CounterOpts{
+				Namespace: "go_mvc",
+				Subsystem: "authz_cache",
+				Name:      "stale_reads_total",
+				Help:      "Local cache entries read after the change that invalidated them",
+			},
+		),
+
+		localEntries: promauto.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: "go_mvc",
+				Subsystem: "authz_cache",
+				Name:      "local_entries",
+				Help:      "Number of users in the local authorization cache",
+			},
+		),
+	}
+}
+
+func (m *AuthorizationCacheCollector) RecordLookup(tier string, hit bool) {
+	result := "hit"
+	if !hit {
+		result = "miss"
+	}
+	m.lookups.WithLabelValues(tier, result).Inc()
+}
+
+func (m *AuthorizationCacheCollector) ObserveInvalidationLag(lag time.Duration) {
+	if lag < 0 {
+		lag = 0 // Clock skew between instances
+	}
+	m.invalidationLag.Observe(lag.Seconds())
+}
+
+func (m *AuthorizationCacheCollector) IncrementStaleReads(count int) {
+	m.staleReads.Add(float64(count))
+}
+
+func (m *AuthorizationCacheCollector) SetLocalEntries(count int) {
+	m.localEntries.Set(float64(count))
+}
@@ -0,0 +1,4 @@
+ALTER TABLE users
+ADD COLUMN token_version BIGINT NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN users.token_version IS 'Incremented to revoke every token issued to the user';
@@ -0,0 +1,24 @@
+-- Create user_sessions table tracking one row per login (device)
+CREATE TABLE IF NOT EXISTS user_sessions (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL,
+    device_name VARCHAR(255),
+    user_agent VARCHAR(500),
+    ip_address VARCHAR(45),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_seen_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+
+    CONSTRAINT fk_user_sessions_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Create indexes for performance
+CREATE INDEX IF NOT EXISTS idx_user_sessions_user_id ON user_sessions(user_id);
+CREATE INDEX IF NOT EXISTS idx_user_sessions_expires_at ON user_sessions(expires_at);
+CREATE INDEX IF NOT EXISTS idx_user_sessions_active ON user_sessions(user_id, expires_at) WHERE revoked_at IS NULL;
+
+-- Add comments for documentation
+COMMENT ON TABLE user_sessions IS 'Authenticated sessions; the id is embedded as the sid claim of issued tokens';
+COMMENT ON COLUMN user_sessions.last_seen_at IS 'Last time the session refreshed its tokens';
+COMMENT ON COLUMN user_sessions.revoked_at IS 'Set when the session is signed out or revoked';
@@ -0,0 +1,4 @@
+ALTER TABLE users
+DROP COLUMN IF EXISTS mfa_recovery_codes,
+DROP COLUMN IF EXISTS mfa_enabled_at,
+DROP COLUMN IF EXISTS mfa_secret;
@@ -0,0 +1,7 @@
+ALTER TABLE users
+ADD COLUMN mfa_secret VARCHAR(64),
+ADD COLUMN mfa_enabled_at TIMESTAMP WITH TIME ZONE,
+ADD COLUMN mfa_recovery_codes JSONB NOT NULL DEFAULT '[]';
+
+COMMENT ON COLUMN users.mfa_secret IS 'Base32 TOTP secret, set once enrollment is confirmed';
+COMMENT ON COLUMN users.mfa_recovery_codes IS 'SHA-256 hashes of unused MFA recovery codes';
@@ -0,0 +1,25 @@
+-- Create webauthn_credentials table storing passkeys registered by users
+CREATE TABLE IF NOT EXISTS webauthn_credentials (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL,
+    name VARCHAR(100),
+    credential_id BYTEA NOT NULL,
+    public_key BYTEA NOT NULL,
+    algorithm BIGINT NOT NULL,
+    sign_count BIGINT NOT NULL DEFAULT 0,
+    transports JSONB NOT NULL DEFAULT '[]',
+    aaguid BYTEA,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_used_at TIMESTAMP WITH TIME ZONE,
+
+    CONSTRAINT fk_webauthn_credentials_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Create indexes for performance
+CREATE UNIQUE INDEX IF NOT EXISTS idx_webauthn_credentials_credential_id ON webauthn_credentials(credential_id);
+CREATE INDEX IF NOT EXISTS idx_webauthn_credentials_user_id ON webauthn_credentials(user_id);
+
+-- Add comments for documentation
+COMMENT ON TABLE webauthn_credentials IS 'Passkeys (WebAuthn credentials) registered by users';
+COMMENT ON COLUMN webauthn_credentials.public_key IS 'COSE encoded credential public key';
+COMMENT ON COLUMN webauthn_credentials.sign_count IS 'Last signature counter reported by the authenticator, used to detect cloned keys';
@@ -0,0 +1,20 @@
+-- Create external_identities table linking OpenID provider accounts to users
+CREATE TABLE IF NOT EXISTS external_identities (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL,
+    provider VARCHAR(50) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    email VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_login_at TIMESTAMP WITH TIME ZONE,
+
+    CONSTRAINT fk_external_identities_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Create indexes for performance
+CREATE UNIQUE INDEX IF NOT EXISTS idx_external_identities_provider_subject ON external_identities(provider, subject);
+CREATE INDEX IF NOT EXISTS idx_external_identities_user_id ON external_identities(user_id);
+
+-- Add comments for documentation
+COMMENT ON TABLE external_identities IS 'Accounts at external OpenID providers linked to users';
+COMMENT ON COLUMN external_identities.subject IS 'Stable subject identifier (sub claim) issued by the provider';
@@ -0,0 +1,10 @@
+ALTER TABLE users
+ADD COLUMN email_verified_at TIMESTAMP WITH TIME ZONE;
+
+-- Existing accounts could already sign in before verification existed;
+-- treat them as verified since registration so they are not locked out
+UPDATE users
+SET email_verified_at = created_at
+WHERE email_verified_at IS NULL;
+
+COMMENT ON COLUMN users.email_verified_at IS 'When the user confirmed ownership of the email address; NULL while unverified';
@@ -0,0 +1,24 @@
+-- Create api_keys table storing personal access tokens issued to users
+CREATE TABLE IF NOT EXISTS api_keys (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    prefix VARCHAR(32) NOT NULL,
+    key_hash VARCHAR(64) NOT NULL,
+    scopes JSONB NOT NULL DEFAULT '[]',
+    expires_at TIMESTAMP WITH TIME ZONE,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_api_keys_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Create indexes for performance
+CREATE UNIQUE INDEX IF NOT EXISTS idx_api_keys_key_hash ON api_keys(key_hash);
+CREATE INDEX IF NOT EXISTS idx_api_keys_user_id ON api_keys(user_id);
+
+-- Add comments for documentation
+COMMENT ON TABLE api_keys IS 'Personal access tokens and API keys owned by users';
+COMMENT ON COLUMN api_keys.prefix IS 'Leading characters of the key, shown to the owner to identify it';
+COMMENT ON COLUMN api_keys.key_hash IS 'SHA-256 hash of the full key; the key itself is never stored';
+COMMENT ON COLUMN api_keys.scopes IS 'Permission names the key may use, on top of the owner''s own permissions';
@@ -0,0 +1,23 @@
+-- Create audit_logs table recording actions taken on behalf of users
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    actor_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    session_id UUID,
+    action VARCHAR(64) NOT NULL,
+    method VARCHAR(10),
+    path VARCHAR(2048),
+    ip_address VARCHAR(45),
+    user_agent VARCHAR(500),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes for performance
+CREATE INDEX IF NOT EXISTS idx_audit_logs_actor_id ON audit_logs(actor_id);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_user_id_created_at ON audit_logs(user_id, created_at DESC);
+
+-- Add comments for documentation
+COMMENT ON TABLE audit_logs IS 'Append-only trail of actions taken on behalf of users, such as admin impersonation';
+COMMENT ON COLUMN audit_logs.actor_id IS 'User who actually performed the action; differs from user_id during impersonation';
+COMMENT ON COLUMN audit_logs.user_id IS 'User the action was performed as or against';
+COMMENT ON COLUMN audit_logs.action IS 'What happened, e.g. impersonation.started or impersonation.request';
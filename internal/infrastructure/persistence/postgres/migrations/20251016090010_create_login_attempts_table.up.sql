@@ -0,0 +1,34 @@
+-- Create login_attempts table recording every sign-in attempt
+CREATE TABLE IF NOT EXISTS login_attempts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID,
+    email VARCHAR(255) NOT NULL,
+    success BOOLEAN NOT NULL,
+    failure_reason VARCHAR(64),
+    methods JSONB NOT NULL DEFAULT '[]',
+    ip_address VARCHAR(45),
+    user_agent VARCHAR(500),
+    browser VARCHAR(64),
+    os VARCHAR(64),
+    device_type VARCHAR(16),
+    device_key VARCHAR(160),
+    country_code VARCHAR(2),
+    country VARCHAR(100),
+    city VARCHAR(100),
+    latitude DOUBLE PRECISION,
+    longitude DOUBLE PRECISION,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_login_attempts_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Create indexes for performance
+CREATE INDEX IF NOT EXISTS idx_login_attempts_user_id_created_at ON login_attempts(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_login_attempts_user_id_device_key ON login_attempts(user_id, device_key) WHERE success;
+
+-- Add comments for documentation
+COMMENT ON TABLE login_attempts IS 'Login history: every successful or failed sign-in attempt';
+COMMENT ON COLUMN login_attempts.user_id IS 'Account the attempt was for; NULL when the email is unknown';
+COMMENT ON COLUMN login_attempts.methods IS 'Authentication methods (amr values) of a successful login';
+COMMENT ON COLUMN login_attempts.device_key IS 'Browser, OS and device type of the client, used to spot new devices';
+COMMENT ON COLUMN login_attempts.latitude IS 'Approximate position from the offline GeoIP database';
@@ -0,0 +1 @@
+DELETE FROM permissions WHERE name IN ('user_roles:list', 'user_roles:create', 'user_roles:delete');
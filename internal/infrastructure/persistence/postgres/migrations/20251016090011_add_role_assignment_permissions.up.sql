@@ -0,0 +1,16 @@
+-- Permissions guarding role assignment. They are separate from users:update
+-- so that managing profiles does not allow granting oneself a role.
+INSERT INTO permissions (name, resource, action, description) VALUES
+    ('user_roles:list', 'user_roles', 'list', 'List role assignments'),
+    ('user_roles:create', 'user_roles', 'create', 'Assign roles to users'),
+    ('user_roles:delete', 'user_roles', 'delete', 'Revoke roles from users')
+ON CONFLICT (name) DO NOTHING;
+
+-- Admin gets the new permissions, like every other permission
+INSERT INTO role_permissions (role_id, permission_id, granted_at)
+SELECT r.id, p.id, CURRENT_TIMESTAMP
+FROM roles r
+CROSS JOIN permissions p
+WHERE r.name = 'ADMIN'
+    AND p.name IN ('user_roles:list', 'user_roles:create', 'user_roles:delete')
+ON CONFLICT (role_id, permission_id) DO NOTHING;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_roles_parent_id;
+
+ALTER TABLE roles
+DROP CONSTRAINT IF EXISTS roles_parent_check,
+DROP COLUMN IF EXISTS parent_id;
@@ -0,0 +1,14 @@
+ALTER TABLE roles
+ADD COLUMN parent_id UUID REFERENCES roles(id) ON DELETE SET NULL,
+ADD CONSTRAINT roles_parent_check CHECK (parent_id <> id);
+
+CREATE INDEX IF NOT EXISTS idx_roles_parent_id ON roles(parent_id);
+
+COMMENT ON COLUMN roles.parent_id IS 'Role this one inherits permissions from; NULL for a top-level role';
+
+-- Default hierarchy: ADMIN inherits from MODERATOR, which inherits from USER
+UPDATE roles SET parent_id = (SELECT id FROM roles WHERE name = 'USER')
+WHERE name = 'MODERATOR' AND parent_id IS NULL;
+
+UPDATE roles SET parent_id = (SELECT id FROM roles WHERE name = 'MODERATOR')
+WHERE name = 'ADMIN' AND parent_id IS NULL;
@@ -0,0 +1,25 @@
+-- Create policies table for attribute-based access control
+CREATE TABLE IF NOT EXISTS policies (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL UNIQUE,
+    description VARCHAR(255),
+    effect VARCHAR(5) NOT NULL,
+    resources JSONB NOT NULL DEFAULT '[]',
+    actions JSONB NOT NULL DEFAULT '[]',
+    condition TEXT NOT NULL DEFAULT '',
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT policies_effect_check CHECK (effect IN ('allow', 'deny'))
+);
+
+-- Create indexes for performance
+CREATE INDEX IF NOT EXISTS idx_policies_is_active ON policies(is_active);
+
+-- Add comments for documentation
+COMMENT ON TABLE policies IS 'Attribute-based access policies, evaluated together with those loaded from files';
+COMMENT ON COLUMN policies.effect IS 'allow or deny; a matching deny overrides any allow';
+COMMENT ON COLUMN policies.resources IS 'Resource types the policy applies to; "*" matches any';
+COMMENT ON COLUMN policies.actions IS 'Actions the policy applies to; "*" matches any';
+COMMENT ON COLUMN policies.condition IS 'Expression over subject, resource, action and environment attributes; empty always holds';
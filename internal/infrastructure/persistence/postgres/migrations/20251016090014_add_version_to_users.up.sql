@@ -0,0 +1,4 @@
+ALTER TABLE users
+ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+
+COMMENT ON COLUMN users.version IS 'Incremented on every save; updates only apply to the version they were loaded at';
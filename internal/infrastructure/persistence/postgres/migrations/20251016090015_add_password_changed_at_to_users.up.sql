@@ -0,0 +1,4 @@
+ALTER TABLE users
+ADD COLUMN password_changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+
+COMMENT ON COLUMN users.password_changed_at IS 'When the password was last set; re-encoding the same password does not move it';
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+)
+
+type APIKeyModel struct {
+	ID         string     `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
+	UserID     string     `gorm:"type:uuid;not null;index" json:"user_id"`
+	Name       string     `gorm:"size:100;not null" json:"name"`
+	Prefix     string     `gorm:"size:32;not null" json:"prefix"`
+	KeyHash    string     `gorm:"column:key_hash;size:64;not null;uniqueIndex" json:"-"`
+	Scopes     []string   `gorm:"type:jsonb;serializer:json" json:"scopes"`
+	ExpiresAt  *time.Time `gorm:"column:expires_at" json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `gorm:"column:last_used_at" json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime" json:"created_at"`
+
+	User UserModel `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"user,omitempty"`
+}
+
+func (APIKeyModel) TableName() string {
+	return "api_keys"
+}
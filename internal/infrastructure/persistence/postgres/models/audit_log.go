@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+)
+
+// AuditLogModel has no foreign keys on purpose: the trail must outlive the
+// users and sessions it mentions.
+type AuditLogModel struct {
+	ID        string    `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
+	ActorID   string    `gorm:"column:actor_id;type:uuid;not null;index" json:"actor_id"`
+	UserID    string    `gorm:"type:uuid;not null;index" json:"user_id"`
+	SessionID string    `gorm:"column:session_id;type:uuid" json:"session_id,omitempty"`
+	Action    string    `gorm:"size:64;not null" json:"action"`
+	Method    string    `gorm:"size:10" json:"method,omitempty"`
+	Path      string    `gorm:"size:2048" json:"path,omitempty"`
+	IPAddress string    `gorm:"column:ip_address;size:45" json:"ip_address,omitempty"`
+	UserAgent string    `gorm:"column:user_agent;size:500" json:"user_agent,omitempty"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (AuditLogModel) TableName() string {
+	return "audit_logs"
+}
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+)
+
+type ExternalIdentityModel struct {
+	ID          string     `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
+	UserID      string     `gorm:"type:uuid;not null;index" json:"user_id"`
+	Provider    string     `gorm:"size:50;not null;uniqueIndex:idx_external_identities_provider_subject" json:"provider"`
+	Subject     string     `gorm:"size:255;not null;uniqueIndex:idx_external_identities_provider_subject" json:"subject"`
+	Email       string     `gorm:"size:255" json:"email"`
+	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	LastLoginAt *time.Time `gorm:"column:last_login_at" json:"last_login_at,omitempty"`
+
+	User UserModel `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"user,omitempty"`
+}
+
+func (ExternalIdentityModel) TableName() string {
+	return "external_identities"
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+)
+
+// LoginAttemptModel keeps UserID nullable so attempts against unknown
+// emails are recorded too.
+type LoginAttemptModel struct {
+	ID            string    `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
+	UserID        *string   `gorm:"type:uuid;index" json:"user_id,omitempty"`
+	Email         string    `gorm:"size:255;not null" json:"email"`
+	Success       bool      `gorm:"not null" json:"success"`
+	FailureReason string    `gorm:"column:failure_reason;size:64" json:"failure_reason,omitempty"`
+	Methods       []string  `gorm:"type:jsonb;serializer:json" json:"methods"`
+	IPAddress     string    `gorm:"column:ip_address;size:45" json:"ip_address,omitempty"`
+	UserAgent     string    `gorm:"column:user_agent;size:500" json:"user_agent,omitempty"`
+	Browser       string    `gorm:"size:64" json:"browser,omitempty"`
+	OS            string    `gorm:"column:os;size:64" json:"os,omitempty"`
+	DeviceType    string    `gorm:"column:device_type;size:16" json:"device_type,omitempty"`
+	DeviceKey     string    `gorm:"column:device_key;size:160" json:"device_key,omitempty"`
+	CountryCode   string    `gorm:"column:country_code;size:2" json:"country_code,omitempty"`
+	Country       string    `gorm:"size:100" json:"country,omitempty"`
+	City          string    `gorm:"size:100" json:"city,omitempty"`
+	Latitude      *float64  `json:"latitude,omitempty"`
+	Longitude     *float64  `json:"longitude,omitempty"`
+	CreatedAt     time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (LoginAttemptModel) TableName() string {
+	return "login_attempts"
+}
@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+)
+
+type PasswordHistoryModel struct {
+	ID           string    `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
+	UserID       string    `gorm:"type:uuid;not null;index:idx_password_history_user_id_created_at" json:"user_id"`
+	PasswordHash string    `gorm:"size:255;not null" json:"-"`
+	CreatedAt    time.Time `gorm:"autoCreateTime;index:idx_password_history_user_id_created_at" json:"created_at"`
+
+	User UserModel `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"user,omitempty"`
+}
+
+func (PasswordHistoryModel) TableName() string {
+	return "password_history"
+}
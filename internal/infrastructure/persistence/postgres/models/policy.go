@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+)
+
+type PolicyModel struct {
+	ID          string    `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
+	Name        string    `gorm:"uniqueIndex;not null;size:100" json:"name"`
+	Description string    `gorm:"size:255" json:"description"`
+	Effect      string    `gorm:"size:5;not null" json:"effect"`
+	Resources   []string  `gorm:"type:jsonb;serializer:json" json:"resources"`
+	Actions     []string  `gorm:"type:jsonb;serializer:json" json:"actions"`
+	Condition   string    `gorm:"column:condition;type:text" json:"condition"`
+	IsActive    bool      `gorm:"default:true" json:"is_active"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (PolicyModel) TableName() string {
+	return "policies"
+}
@@ -8,6 +8,7 @@ type RoleModel struct {
 	ID          string    `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
 	Name        string    `gorm:"uniqueIndex;not null;size:50" json:"name"`
 	Description string    `gorm:"size:255" json:"description"`
+	ParentID    *string   `gorm:"type:uuid" json:"parent_id"`
 	IsActive    bool      `gorm:"default:true" json:"is_active"`
 	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+)
+
+type SessionModel struct {
+	ID         string     `gorm:"primaryKey;type:uuid" json:"id"`
+	UserID     string     `gorm:"type:uuid;not null;index" json:"user_id"`
+	DeviceName string     `gorm:"column:device_name;size:255" json:"device_name"`
+	UserAgent  string     `gorm:"column:user_agent;size:500" json:"user_agent"`
+	IPAddress  string     `gorm:"column:ip_address;size:45" json:"ip_address"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	LastSeenAt time.Time  `gorm:"column:last_seen_at;not null" json:"last_seen_at"`
+	ExpiresAt  time.Time  `gorm:"column:expires_at;not null;index" json:"expires_at"`
+	RevokedAt  *time.Time `gorm:"column:revoked_at" json:"revoked_at,omitempty"`
+
+	User UserModel `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"user,omitempty"`
+}
+
+func (SessionModel) TableName() string {
+	return "user_sessions"
+}
@@ -5,16 +5,23 @@ import (
 )
 
 type UserModel struct {
-	ID            string    `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
-	Email         string    `gorm:"uniqueIndex;not null;size:255" json:"email"`
-	Name          string    `gorm:"not null;size:100" json:"name"`
-	PasswordHash  string    `gorm:"column:password_hash;not null;size:255" json:"-"`
-	Phone         string    `gorm:"size:20" json:"phone"`
-	AvatarFileKey string    `gorm:"column:avatar_file_key;size:500" json:"avatar_file_key"`
-	AvatarCDNUrl  string    `gorm:"column:avatar_cdn_url;size:1000" json:"avatar_cdn_url"`
-	IsActive      bool      `gorm:"default:true" json:"is_active"`
-	CreatedAt     time.Time `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt     time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+	ID                string     `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
+	Email             string     `gorm:"uniqueIndex;not null;size:255" json:"email"`
+	Name              string     `gorm:"not null;size:100" json:"name"`
+	PasswordHash      string     `gorm:"column:password_hash;not null;size:255" json:"-"`
+	PasswordChangedAt time.Time  `gorm:"column:password_changed_at;not null" json:"-"`
+	Phone             string     `gorm:"size:20" json:"phone"`
+	AvatarFileKey     string     `gorm:"column:avatar_file_key;size:500" json:"avatar_file_key"`
+	AvatarCDNUrl      string     `gorm:"column:avatar_cdn_url;size:1000" json:"avatar_cdn_url"`
+	IsActive          bool       `gorm:"default:true" json:"is_active"`
+	TokenVersion      int64      `gorm:"column:token_version;not null;default:0" json:"-"`
+	Version           int64      `gorm:"column:version;not null;default:1" json:"-"`
+	MFASecret         string     `gorm:"column:mfa_secret;size:64" json:"-"`
+	MFAEnabledAt      *time.Time `gorm:"column:mfa_enabled_at" json:"-"`
+	MFARecoveryCodes  []string   `gorm:"column:mfa_recovery_codes;type:jsonb;serializer:json" json:"-"`
+	EmailVerifiedAt   *time.Time `gorm:"column:email_verified_at" json:"email_verified_at,omitempty"`
+	CreatedAt         time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt         time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
 }
 
 func (UserModel) TableName() string {
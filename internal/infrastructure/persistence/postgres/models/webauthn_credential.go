@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+)
+
+type WebAuthnCredentialModel struct {
+	ID           string     `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
+	UserID       string     `gorm:"type:uuid;not null;index" json:"user_id"`
+	Name         string     `gorm:"size:100" json:"name"`
+	CredentialID []byte     `gorm:"column:credential_id;not null;uniqueIndex" json:"-"`
+	PublicKey    []byte     `gorm:"column:public_key;not null" json:"-"`
+	Algorithm    int64      `gorm:"not null" json:"algorithm"`
+	SignCount    int64      `gorm:"column:sign_count;not null;default:0" json:"sign_count"`
+	Transports   []string   `gorm:"type:jsonb;serializer:json" json:"transports"`
+	AAGUID       []byte     `gorm:"column:aaguid" json:"-"`
+	CreatedAt    time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	LastUsedAt   *time.Time `gorm:"column:last_used_at" json:"last_used_at,omitempty"`
+
+	User UserModel `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"user,omitempty"`
+}
+
+func (WebAuthnCredentialModel) TableName() string {
+	return "webauthn_credentials"
+}
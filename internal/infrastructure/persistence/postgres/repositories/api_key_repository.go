@@ -0,0 +1,99 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/auth"
+	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/persistence/postgres/models"
+	"gorm.io/gorm"
+)
+
+type apiKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewAPIKeyRepository(db *gorm.DB) auth.APIKeyRepository {
+	return &apiKeyRepository{
+		db: db,
+	}
+}
+
+func (r *apiKeyRepository) Create(ctx context.Context, key *auth.APIKey) error {
+	keyModel := r.domainToModel(key)
+	if err := r.db.WithContext(ctx).Create(keyModel).Error; err != nil {
+		return err
+	}
+	key.ID = keyModel.ID
+	key.CreatedAt = keyModel.CreatedAt
+	return nil
+}
+
+func (r *apiKeyRepository) GetByHash(ctx context.Context, keyHash string) (*auth.APIKey, error) {
+	var keyModel models.APIKeyModel
+	if err := r.db.WithContext(ctx).Where("key_hash = ?", keyHash).First(&keyModel).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return r.modelToDomain(&keyModel), nil
+}
+
+func (r *apiKeyRepository) GetByUserID(ctx context.Context, userID string) ([]*auth.APIKey, error) {
+	var keyModels []models.APIKeyModel
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at ASC").Find(&keyModels).Error; err != nil {
+		return nil, err
+	}
+
+	keys := make([]*auth.APIKey, 0, len(keyModels))
+	for _, model := range keyModels {
+		keys = append(keys, r.modelToDomain(&model))
+	}
+
+	return keys, nil
+}
+
+func (r *apiKeyRepository) UpdateLastUsed(ctx context.Context, id string, lastUsedAt time.Time) error {
+	if err := r.db.WithContext(ctx).Model(&models.APIKeyModel{}).
+		Where("id = ?", id).
+		Update("last_used_at", lastUsedAt).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *apiKeyRepository) Delete(ctx context.Context, userID, id string) error {
+	if err := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).Delete(&models.APIKeyModel{}).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *apiKeyRepository) domainToModel(key *auth.APIKey) *models.APIKeyModel {
+	return &models.APIKeyModel{
+		ID:         key.ID,
+		UserID:     key.UserID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		KeyHash:    key.KeyHash,
+		Scopes:     key.Scopes,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+}
+
+func (r *apiKeyRepository) modelToDomain(keyModel *models.APIKeyModel) *auth.APIKey {
+	return &auth.APIKey{
+		ID:         keyModel.ID,
+		UserID:     keyModel.UserID,
+		Name:       keyModel.Name,
+		Prefix:     keyModel.Prefix,
+		KeyHash:    keyModel.KeyHash,
+		Scopes:     keyModel.Scopes,
+		ExpiresAt:  keyModel.ExpiresAt,
+		LastUsedAt: keyModel.LastUsedAt,
+		CreatedAt:  keyModel.CreatedAt,
+	}
+}
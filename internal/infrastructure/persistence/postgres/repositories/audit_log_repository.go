@@ -0,0 +1,77 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/auth"
+	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/persistence/postgres/models"
+	"gorm.io/gorm"
+)
+
+type auditLogRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditLogRepository(db *gorm.DB) auth.AuditLogRepository {
+	return &auditLogRepository{
+		db: db,
+	}
+}
+
+func (r *auditLogRepository) Create(ctx context.Context, entry *auth.AuditLogEntry) error {
+	entryModel := r.domainToModel(entry)
+	if err := r.db.WithContext(ctx).Create(entryModel).Error; err != nil {
+		return err
+	}
+	entry.ID = entryModel.ID
+	entry.CreatedAt = entryModel.CreatedAt
+	return nil
+}
+
+func (r *auditLogRepository) GetByUserID(ctx context.Context, userID string, limit int) ([]*auth.AuditLogEntry, error) {
+	var entryModels []models.AuditLogModel
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&entryModels).Error; err != nil {
+		return nil, err
+	}
+
+	entries := make([]*auth.AuditLogEntry, 0, len(entryModels))
+	for _, model := range entryModels {
+		entries = append(entries, r.modelToDomain(&model))
+	}
+
+	return entries, nil
+}
+
+func (r *auditLogRepository) domainToModel(entry *auth.AuditLogEntry) *models.AuditLogModel {
+	return &models.AuditLogModel{
+		ID:        entry.ID,
+		ActorID:   entry.ActorID,
+		UserID:    entry.UserID,
+		SessionID: entry.SessionID,
+		Action:    entry.Action,
+		Method:    entry.Method,
+		Path:      entry.Path,
+		IPAddress: entry.IPAddress,
+		UserAgent: entry.UserAgent,
+		CreatedAt: entry.CreatedAt,
+	}
+}
+
+func (r *auditLogRepository) modelToDomain(entryModel *models.AuditLogModel) *auth.AuditLogEntry {
+	return &auth.AuditLogEntry{
+		ID:        entryModel.ID,
+		ActorID:   entryModel.ActorID,
+		UserID:    entryModel.UserID,
+		SessionID: entryModel.SessionID,
+		Action:    entryModel.Action,
+		Method:    entryModel.Method,
+		Path:      entryModel.Path,
+		IPAddress: entryModel.IPAddress,
+		UserAgent: entryModel.UserAgent,
+		CreatedAt: entryModel.CreatedAt,
+	}
+}
@@ -0,0 +1,87 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/auth"
+	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/persistence/postgres/models"
+	"gorm.io/gorm"
+)
+
+type externalIdentityRepository struct {
+	db *gorm.DB
+}
+
+func NewExternalIdentityRepository(db *gorm.DB) auth.ExternalIdentityRepository {
+	return &externalIdentityRepository{
+		db: db,
+	}
+}
+
+func (r *externalIdentityRepository) Create(ctx context.Context, identity *auth.ExternalIdentity) error {
+	identityModel := r.domainToModel(identity)
+	if err := r.db.WithContext(ctx).Create(identityModel).Error; err != nil {
+		return err
+	}
+	identity.ID = identityModel.ID
+	return nil
+}
+
+func (r *externalIdentityRepository) GetByProviderSubject(ctx context.Context, provider, subject string) (*auth.ExternalIdentity, error) {
+	var identityModel models.ExternalIdentityModel
+	if err := r.db.WithContext(ctx).Where("provider = ? AND subject = ?", provider, subject).First(&identityModel).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return r.modelToDomain(&identityModel), nil
+}
+
+func (r *externalIdentityRepository) GetByUserID(ctx context.Context, userID string) ([]*auth.ExternalIdentity, error) {
+	var identityModels []models.ExternalIdentityModel
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at ASC").Find(&identityModels).Error; err != nil {
+		return nil, err
+	}
+
+	identities := make([]*auth.ExternalIdentity, 0, len(identityModels))
+	for _, model := range identityModels {
+		identities = append(identities, r.modelToDomain(&model))
+	}
+
+	return identities, nil
+}
+
+func (r *externalIdentityRepository) UpdateLastLogin(ctx context.Context, id string, lastLoginAt time.Time) error {
+	if err := r.db.WithContext(ctx).Model(&models.ExternalIdentityModel{}).
+		Where("id = ?", id).
+		Update("last_login_at", lastLoginAt).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *externalIdentityRepository) domainToModel(identity *auth.ExternalIdentity) *models.ExternalIdentityModel {
+	return &models.ExternalIdentityModel{
+		ID:          identity.ID,
+		UserID:      identity.UserID,
+		Provider:    identity.Provider,
+		Subject:     identity.Subject,
+		Email:       identity.Email,
+		CreatedAt:   identity.CreatedAt,
+		LastLoginAt: identity.LastLoginAt,
+	}
+}
+
+func (r *externalIdentityRepository) modelToDomain(identityModel *models.ExternalIdentityModel) *auth.ExternalIdentity {
+	return &auth.ExternalIdentity{
+		ID:          identityModel.ID,
+		UserID:      identityModel.UserID,
+		Provider:    identityModel.Provider,
+		Subject:     identityModel.Subject,
+		Email:       identityModel.Email,
+		CreatedAt:   identityModel.CreatedAt,
+		LastLoginAt: identityModel.LastLoginAt,
+	}
+}
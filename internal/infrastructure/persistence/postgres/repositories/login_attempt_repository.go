@@ -0,0 +1,126 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/auth"
+	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/persistence/postgres/models"
+	"gorm.io/gorm"
+)
+
+type loginAttemptRepository struct {
+	db *gorm.DB
+}
+
+func NewLoginAttemptRepository(db *gorm.DB) auth.LoginAttemptRepository {
+	return &loginAttemptRepository{
+		db: db,
+	}
+}
+
+func (r *loginAttemptRepository) Create(ctx context.Context, attempt *auth.LoginAttempt) error {
+	attemptModel := r.domainToModel(attempt)
+	if err := r.db.WithContext(ctx).Create(attemptModel).Error; err != nil {
+		return err
+	}
+	attempt.ID = attemptModel.ID
+	attempt.CreatedAt = attemptModel.CreatedAt
+	return nil
+}
+
+func (r *loginAttemptRepository) GetByUserID(ctx context.Context, userID string, limit int) ([]*auth.LoginAttempt, error) {
+	var attemptModels []models.LoginAttemptModel
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&attemptModels).Error; err != nil {
+		return nil, err
+	}
+
+	attempts := make([]*auth.LoginAttempt, 0, len(attemptModels))
+	for _, model := range attemptModels {
+		attempts = append(attempts, r.modelToDomain(&model))
+	}
+
+	return attempts, nil
+}
+
+func (r *loginAttemptRepository) GetLastSuccessful(ctx context.Context, userID string) (*auth.LoginAttempt, error) {
+	var attemptModel models.LoginAttemptModel
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ? AND success = ?", userID, true).
+		Order("created_at DESC").
+		First(&attemptModel).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return r.modelToDomain(&attemptModel), nil
+}
+
+func (r *loginAttemptRepository) HasSucceededFromDevice(ctx context.Context, userID, deviceKey string) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).
+		Model(&models.LoginAttemptModel{}).
+		Where("user_id = ? AND success = ? AND device_key = ?", userID, true, deviceKey).
+		Limit(1).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *loginAttemptRepository) domainToModel(attempt *auth.LoginAttempt) *models.LoginAttemptModel {
+	attemptModel := &models.LoginAttemptModel{
+		ID:            attempt.ID,
+		Email:         attempt.Email,
+		Success:       attempt.Success,
+		FailureReason: attempt.FailureReason,
+		Methods:       attempt.Methods,
+		IPAddress:     attempt.IPAddress,
+		UserAgent:     attempt.UserAgent,
+		Browser:       attempt.Browser,
+		OS:            attempt.OS,
+		DeviceType:    attempt.DeviceType,
+		DeviceKey:     attempt.DeviceKey,
+		CountryCode:   attempt.CountryCode,
+		Country:       attempt.Country,
+		City:          attempt.City,
+		Latitude:      attempt.Latitude,
+		Longitude:     attempt.Longitude,
+		CreatedAt:     attempt.CreatedAt,
+	}
+	if attempt.UserID != "" {
+		attemptModel.UserID = &attempt.UserID
+	}
+	return attemptModel
+}
+
+func (r *loginAttemptRepository) modelToDomain(attemptModel *models.LoginAttemptModel) *auth.LoginAttempt {
+	attempt := &auth.LoginAttempt{
+		ID:            attemptModel.ID,
+		Email:         attemptModel.Email,
+		Success:       attemptModel.Success,
+		FailureReason: attemptModel.FailureReason,
+		Methods:       attemptModel.Methods,
+		IPAddress:     attemptModel.IPAddress,
+		UserAgent:     attemptModel.UserAgent,
+		Browser:       attemptModel.Browser,
+		OS:            attemptModel.OS,
+		DeviceType:    attemptModel.DeviceType,
+		DeviceKey:     attemptModel.DeviceKey,
+		CountryCode:   attemptModel.CountryCode,
+		Country:       attemptModel.Country,
+		City:          attemptModel.City,
+		Latitude:      attemptModel.Latitude,
+		Longitude:     attemptModel.Longitude,
+		CreatedAt:     attemptModel.CreatedAt,
+	}
+	if attemptModel.UserID != nil {
+		attempt.UserID = *attemptModel.UserID
+	}
+	return attempt
+}
@@ -0,0 +1,68 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/auth"
+	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/persistence/postgres/models"
+	"gorm.io/gorm"
+)
+
+type passwordHistoryRepository struct {
+	db *gorm.DB
+}
+
+func NewPasswordHistoryRepository(db *gorm.DB) auth.PasswordHistoryRepository {
+	return &passwordHistoryRepository{
+		db: db,
+	}
+}
+
+func (r *passwordHistoryRepository) Create(ctx context.Context, entry *auth.PasswordHistoryEntry) error {
+	entryModel := &models.PasswordHistoryModel{
+		ID:           entry.ID,
+		UserID:       entry.UserID,
+		PasswordHash: entry.PasswordHash,
+		CreatedAt:    entry.CreatedAt,
+	}
+	if err := r.db.WithContext(ctx).Create(entryModel).Error; err != nil {
+		return err
+	}
+	entry.ID = entryModel.ID
+	return nil
+}
+
+func (r *passwordHistoryRepository) GetRecent(ctx context.Context, userID string, limit int) ([]*auth.PasswordHistoryEntry, error) {
+	var entryModels []models.PasswordHistoryModel
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&entryModels).Error; err != nil {
+		return nil, err
+	}
+
+	entries := make([]*auth.PasswordHistoryEntry, 0, len(entryModels))
+	for _, model := range entryModels {
+		entries = append(entries, &auth.PasswordHistoryEntry{
+			ID:           model.ID,
+			UserID:       model.UserID,
+			PasswordHash: model.PasswordHash,
+			CreatedAt:    model.CreatedAt,
+		})
+	}
+
+	return entries, nil
+}
+
+func (r *passwordHistoryRepository) Prune(ctx context.Context, userID string, keep int) error {
+	recent := r.db.Model(&models.PasswordHistoryModel{}).
+		Select("id").
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(keep)
+
+	return r.db.WithContext(ctx).
+		Where("user_id = ? AND id NOT IN (?)", userID, recent).
+		Delete(&models.PasswordHistoryModel{}).Error
+}
@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/auth"
+	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/persistence/postgres/models"
+	"gorm.io/gorm"
+)
+
+type policyRepository struct {
+	db *gorm.DB
+}
+
+func NewPolicyRepository(db *gorm.DB) auth.PolicyRepository {
+	return &policyRepository{
+		db: db,
+	}
+}
+
+func (r *policyRepository) GetActive(ctx context.Context) ([]*auth.Policy, error) {
+	var policyModels []models.PolicyModel
+	if err := r.db.WithContext(ctx).
+		Where("is_active = ?", true).
+		Order("name ASC").
+		Find(&policyModels).Error; err != nil {
+		return nil, err
+	}
+
+	policies := make([]*auth.Policy, 0, len(policyModels))
+	for _, model := range policyModels {
+		policies = append(policies, r.modelToDomain(&model))
+	}
+
+	return policies, nil
+}
+
+func (r *policyRepository) modelToDomain(model *models.PolicyModel) *auth.Policy {
+	return &auth.Policy{
+		ID:          model.ID,
+		Name:        model.Name,
+		Description: model.Description,
+		Effect:      model.Effect,
+		Resources:   model.Resources,
+		Actions:     model.Actions,
+		Condition:   model.Condition,
+		IsActive:    model.IsActive,
+		CreatedAt:   model.CreatedAt,
+		UpdatedAt:   model.UpdatedAt,
+	}
+}
@@ -137,19 +137,19 @@ func (r *rolePermissionRepository) List(ctx context.Context, params auth.ListRol
 	query := r.db.WithContext(ctx).Model(&models.RolePermissionModel{})
 
 	if params.RoleID != "" {
-		query = query.Where("role_id = ?", params.RoleID)
+		query = query.Where("role_permissions.role_id = ?", params.RoleID)
 	}
 
 	if params.PermissionID != "" {
-		query = query.Where("permission_id = ?", params.PermissionID)
+		query = query.Where("role_permissions.permission_id = ?", params.PermissionID)
 	}
 
 	if params.GrantedBy != "" {
-		query = query.Where("granted_by = ?", params.GrantedBy)
+		query = query.Where("role_permissions.granted_by = ?", params.GrantedBy)
 	}
 
 	if params.IsActive != nil {
-		query = query.Where("is_active = ?", *params.IsActive)
+		query = query.Where("role_permissions.is_active = ?", *params.IsActive)
 	}
 
 	if params.Resource != "" || params.Action != "" {
@@ -179,7 +179,8 @@ func (r *rolePermissionRepository) List(ctx context.Context, params auth.ListRol
 	if sortDir != "ASC" && sortDir != "DESC" {
 		sortDir = "DESC"
 	}
-	query = query.Order(sortBy + " " + sortDir)
+	// Qualified because the resource and action filters join permissions
+	query = query.Order("role_permissions." + sortBy + " " + sortDir)
 
 	query = query.Offset(paginationObj.Offset()).Limit(paginationObj.PageSize)
 
@@ -10,6 +10,10 @@ import (
 	"gorm.io/gorm"
 )
 
+// maxRoleHierarchyDepth bounds the recursive hierarchy queries, so a cycle
+// written to the table directly cannot make them run forever.
+const maxRoleHierarchyDepth = 32
+
 type roleRepository struct {
 	db *gorm.DB
 }
@@ -221,11 +225,88 @@ func (r *roleRepository) GetActiveRolesByUserID(ctx context.Context, userID stri
 	return roles, nil
 }
 
+func (r *roleRepository) SetParent(ctx context.Context, id string, parentID *string) error {
+	if err := r.db.WithContext(ctx).Model(&models.RoleModel{}).Where("id = ?", id).Update("parent_id", parentID).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *roleRepository) GetAncestors(ctx context.Context, id string) ([]*auth.Role, error) {
+	var roleModels []models.RoleModel
+
+	err := r.db.WithContext(ctx).Raw(`
+		WITH RECURSIVE ancestors AS (
+			SELECT parent.*, 1 AS depth
+			FROM roles child
+			INNER JOIN roles parent ON parent.id = child.parent_id
+			WHERE child.id = ?
+			UNION ALL
+			SELECT parent.*, ancestors.depth + 1
+			FROM ancestors
+			INNER JOIN roles parent ON parent.id = ancestors.parent_id
+			WHERE ancestors.depth < ? AND parent.id <> ?
+		)
+		SELECT id, name, description, parent_id, is_active, created_at, updated_at, version
+		FROM ancestors
+		ORDER BY depth`, id, maxRoleHierarchyDepth, id).Scan(&roleModels).Error
+	if err != nil {
+		return nil, err
+	}
+
+	roles := make([]*auth.Role, 0, len(roleModels))
+	seen := make(map[string]bool, len(roleModels))
+	for _, roleModel := range roleModels {
+		// A cycle above the role repeats itself until the depth limit
+		if seen[roleModel.ID] {
+			break
+		}
+		seen[roleModel.ID] = true
+
+		roleEntity, err := r.modelToDomain(&roleModel)
+		if err != nil {
+			return nil, err
+		}
+		roles = append(roles, roleEntity)
+	}
+
+	return roles, nil
+}
+
+func (r *roleRepository) GetDescendantIDs(ctx context.Context, id string) ([]string, error) {
+	var ids []string
+
+	err := r.db.WithContext(ctx).Raw(`
+		WITH RECURSIVE descendants AS (
+			SELECT id, 1 AS depth
+			FROM roles
+			WHERE parent_id = ?
+			UNION ALL
+			SELECT child.id, descendants.depth + 1
+			FROM descendants
+			INNER JOIN roles child ON child.parent_id = descendants.id
+			WHERE descendants.depth < ? AND child.id <> ?
+		)
+		SELECT DISTINCT id FROM descendants`, id, maxRoleHierarchyDepth, id).Scan(&ids).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
 func (r *roleRepository) domainToModel(roleEntity *auth.Role) *models.RoleModel {
+	var parentID *string
+	if roleEntity.ParentID() != nil {
+		id := roleEntity.ParentID().String()
+		parentID = &id
+	}
+
 	return &models.RoleModel{
 		ID:          roleEntity.ID().String(),
 		Name:        roleEntity.Name().String(),
 		Description: roleEntity.Description(),
+		ParentID:    parentID,
 		IsActive:    roleEntity.IsActive(),
 		CreatedAt:   roleEntity.CreatedAt(),
 		UpdatedAt:   roleEntity.UpdatedAt(),
@@ -238,6 +319,7 @@ func (r *roleRepository) modelToDomain(roleModel *models.RoleModel) (*auth.Role,
 		roleModel.ID,
 		roleModel.Name,
 		roleModel.Description,
+		roleModel.ParentID,
 		roleModel.IsActive,
 		roleModel.CreatedAt,
 		roleModel.UpdatedAt,
@@ -0,0 +1,116 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/auth"
+	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/persistence/postgres/models"
+	"gorm.io/gorm"
+)
+
+type sessionRepository struct {
+	db *gorm.DB
+}
+
+func NewSessionRepository(db *gorm.DB) auth.SessionRepository {
+	return &sessionRepository{
+		db: db,
+	}
+}
+
+func (r *sessionRepository) Create(ctx context.Context, session *auth.Session) error {
+	sessionModel := r.domainToModel(session)
+	if err := r.db.WithContext(ctx).Create(sessionModel).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *sessionRepository) GetByID(ctx context.Context, id string) (*auth.Session, error) {
+	var sessionModel models.SessionModel
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&sessionModel).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return r.modelToDomain(&sessionModel), nil
+}
+
+func (r *sessionRepository) GetActiveByUserID(ctx context.Context, userID string) ([]*auth.Session, error) {
+	var sessionModels []models.SessionModel
+
+	query := r.db.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_seen_at DESC")
+
+	if err := query.Find(&sessionModels).Error; err != nil {
+		return nil, err
+	}
+
+	sessions := make([]*auth.Session, 0, len(sessionModels))
+	for _, model := range sessionModels {
+		sessions = append(sessions, r.modelToDomain(&model))
+	}
+
+	return sessions, nil
+}
+
+func (r *sessionRepository) Touch(ctx context.Context, id string, lastSeenAt, expiresAt time.Time) error {
+	if err := r.db.WithContext(ctx).Model(&models.SessionModel{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"last_seen_at": lastSeenAt,
+			"expires_at":   expiresAt,
+		}).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *sessionRepository) Revoke(ctx context.Context, id string) error {
+	if err := r.db.WithContext(ctx).Model(&models.SessionModel{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now()).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *sessionRepository) RevokeAllByUserID(ctx context.Context, userID string) error {
+	if err := r.db.WithContext(ctx).Model(&models.SessionModel{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *sessionRepository) domainToModel(session *auth.Session) *models.SessionModel {
+	return &models.SessionModel{
+		ID:         session.ID,
+		UserID:     session.UserID,
+		DeviceName: session.DeviceName,
+		UserAgent:  session.UserAgent,
+		IPAddress:  session.IPAddress,
+		CreatedAt:  session.CreatedAt,
+		LastSeenAt: session.LastSeenAt,
+		ExpiresAt:  session.ExpiresAt,
+		RevokedAt:  session.RevokedAt,
+	}
+}
+
+func (r *sessionRepository) modelToDomain(sessionModel *models.SessionModel) *auth.Session {
+	return &auth.Session{
+		ID:         sessionModel.ID,
+		UserID:     sessionModel.UserID,
+		DeviceName: sessionModel.DeviceName,
+		UserAgent:  sessionModel.UserAgent,
+		IPAddress:  sessionModel.IPAddress,
+		CreatedAt:  sessionModel.CreatedAt,
+		LastSeenAt: sessionModel.LastSeenAt,
+		ExpiresAt:  sessionModel.ExpiresAt,
+		RevokedAt:  sessionModel.RevokedAt,
+	}
+}
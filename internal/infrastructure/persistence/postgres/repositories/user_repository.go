@@ -3,6 +3,7 @@ package repositories
 import (
 	"context"
 	"strings"
+	"time"
 
 	"github.com/tranvuongduy2003/go-mvc/internal/domain/user"
 	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/persistence/postgres/models"
@@ -25,6 +26,7 @@ func (r *userRepository) Create(ctx context.Context, u *user.User) error {
 	if err := r.db.WithContext(ctx).Create(userModel).Error; err != nil {
 		return err
 	}
+	u.MarkPersisted(userModel.Version)
 	return nil
 }
 
@@ -52,7 +54,46 @@ func (r *userRepository) GetByEmail(ctx context.Context, email string) (*user.Us
 
 func (r *userRepository) Update(ctx context.Context, u *user.User) error {
 	userModel := r.domainToModel(u)
-	if err := r.db.WithContext(ctx).Model(&userModel).Where("id = ?", userModel.ID).Updates(userModel).Error; err != nil {
+	userModel.Version = u.PersistedVersion() + 1
+	// Select every column so cleared fields (e.g. a reset MFA secret) are persisted too.
+	// token_version is left out: it only moves through IncrementTokenVersion. The
+	// version check keeps a stale copy of the user from overwriting newer changes
+	result := r.db.WithContext(ctx).Model(&models.UserModel{}).
+		Where("id = ? AND version = ?", userModel.ID, u.PersistedVersion()).
+		Select("*").Omit("id", "created_at", "token_version").
+		Updates(userModel)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return user.ErrConcurrentUpdate
+	}
+	u.MarkPersisted(userModel.Version)
+	return nil
+}
+
+// UpdatePasswordHash swaps the stored hash only while it still is
+// currentHash, so a password changed in the meantime is never overwritten.
+// Nothing else in the row is touched.
+func (r *userRepository) UpdatePasswordHash(ctx context.Context, id, currentHash, newHash string) error {
+	result := r.db.WithContext(ctx).Model(&models.UserModel{}).
+		Where("id = ? AND password_hash = ?", id, currentHash).
+		UpdateColumn("password_hash", newHash)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return user.ErrConcurrentUpdate
+	}
+	return nil
+}
+
+func (r *userRepository) IncrementTokenVersion(ctx context.Context, id string) error {
+	if err := r.db.WithContext(ctx).Model(&models.UserModel{}).Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"token_version": gorm.Expr("token_version + 1"),
+			"updated_at":    time.Now(),
+		}).Error; err != nil {
 		return err
 	}
 	return nil
@@ -144,16 +185,23 @@ func (r *userRepository) Count(ctx context.Context) (int64, error) {
 
 func (r *userRepository) domainToModel(u *user.User) *models.UserModel {
 	return &models.UserModel{
-		ID:            u.ID(),
-		Email:         u.Email(),
-		Name:          u.Name(),
-		Phone:         u.Phone(),
-		PasswordHash:  u.HashedPassword(),
-		AvatarFileKey: u.Avatar().FileKey(),
-		AvatarCDNUrl:  u.Avatar().CDNUrl(),
-		IsActive:      u.IsActive(),
-		CreatedAt:     u.CreatedAt(),
-		UpdatedAt:     u.UpdatedAt(),
+		ID:                u.ID(),
+		Email:             u.Email(),
+		Name:              u.Name(),
+		Phone:             u.Phone(),
+		PasswordHash:      u.HashedPassword(),
+		PasswordChangedAt: u.PasswordChangedAt(),
+		AvatarFileKey:     u.Avatar().FileKey(),
+		AvatarCDNUrl:      u.Avatar().CDNUrl(),
+		IsActive:          u.IsActive(),
+		TokenVersion:      u.TokenVersion(),
+		Version:           u.Version(),
+		MFASecret:         u.MFA().Secret(),
+		MFAEnabledAt:      u.MFA().EnabledAt(),
+		MFARecoveryCodes:  u.MFA().RecoveryCodes(),
+		EmailVerifiedAt:   u.EmailVerifiedAt(),
+		CreatedAt:         u.CreatedAt(),
+		UpdatedAt:         u.UpdatedAt(),
 	}
 }
 
@@ -169,6 +217,10 @@ func (r *userRepository) modelToDomain(m *models.UserModel) (*user.User, error)
 		m.IsActive,
 		m.CreatedAt,
 		m.UpdatedAt,
-		1, // version - we'll start with 1 for reconstructed users
+		m.Version,
+		m.TokenVersion,
+		m.PasswordChangedAt,
+		user.NewMFA(m.MFASecret, m.MFAEnabledAt, m.MFARecoveryCodes),
+		m.EmailVerifiedAt,
 	)
 }
@@ -0,0 +1,105 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/auth"
+	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/persistence/postgres/models"
+	"gorm.io/gorm"
+)
+
+type webAuthnCredentialRepository struct {
+	db *gorm.DB
+}
+
+func NewWebAuthnCredentialRepository(db *gorm.DB) auth.WebAuthnCredentialRepository {
+	return &webAuthnCredentialRepository{
+		db: db,
+	}
+}
+
+func (r *webAuthnCredentialRepository) Create(ctx context.Context, credential *auth.WebAuthnCredential) error {
+	credentialModel := r.domainToModel(credential)
+	if err := r.db.WithContext(ctx).Create(credentialModel).Error; err != nil {
+		return err
+	}
+	credential.ID = credentialModel.ID
+	return nil
+}
+
+func (r *webAuthnCredentialRepository) GetByCredentialID(ctx context.Context, credentialID []byte) (*auth.WebAuthnCredential, error) {
+	var credentialModel models.WebAuthnCredentialModel
+	if err := r.db.WithContext(ctx).Where("credential_id = ?", credentialID).First(&credentialModel).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return r.modelToDomain(&credentialModel), nil
+}
+
+func (r *webAuthnCredentialRepository) GetByUserID(ctx context.Context, userID string) ([]*auth.WebAuthnCredential, error) {
+	var credentialModels []models.WebAuthnCredentialModel
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at ASC").Find(&credentialModels).Error; err != nil {
+		return nil, err
+	}
+
+	credentials := make([]*auth.WebAuthnCredential, 0, len(credentialModels))
+	for _, model := range credentialModels {
+		credentials = append(credentials, r.modelToDomain(&model))
+	}
+
+	return credentials, nil
+}
+
+func (r *webAuthnCredentialRepository) UpdateSignCount(ctx context.Context, id string, signCount uint32, lastUsedAt time.Time) error {
+	if err := r.db.WithContext(ctx).Model(&models.WebAuthnCredentialModel{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"sign_count":   int64(signCount),
+			"last_used_at": lastUsedAt,
+		}).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *webAuthnCredentialRepository) Delete(ctx context.Context, userID, id string) error {
+	if err := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).Delete(&models.WebAuthnCredentialModel{}).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *webAuthnCredentialRepository) domainToModel(credential *auth.WebAuthnCredential) *models.WebAuthnCredentialModel {
+	return &models.WebAuthnCredentialModel{
+		ID:           credential.ID,
+		UserID:       credential.UserID,
+		Name:         credential.Name,
+		CredentialID: credential.CredentialID,
+		PublicKey:    credential.PublicKey,
+		Algorithm:    credential.Algorithm,
+		SignCount:    int64(credential.SignCount),
+		Transports:   credential.Transports,
+		AAGUID:       credential.AAGUID,
+		CreatedAt:    credential.CreatedAt,
+		LastUsedAt:   credential.LastUsedAt,
+	}
+}
+
+func (r *webAuthnCredentialRepository) modelToDomain(credentialModel *models.WebAuthnCredentialModel) *auth.WebAuthnCredential {
+	return &auth.WebAuthnCredential{
+		ID:           credentialModel.ID,
+		UserID:       credentialModel.UserID,
+		Name:         credentialModel.Name,
+		CredentialID: credentialModel.CredentialID,
+		PublicKey:    credentialModel.PublicKey,
+		Algorithm:    credentialModel.Algorithm,
+		SignCount:    uint32(credentialModel.SignCount),
+		Transports:   credentialModel.Transports,
+		AAGUID:       credentialModel.AAGUID,
+		CreatedAt:    credentialModel.CreatedAt,
+		LastUsedAt:   credentialModel.LastUsedAt,
+	}
+}
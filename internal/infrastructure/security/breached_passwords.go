@@ -0,0 +1,58 @@
+package security
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// BreachedPasswordList looks passwords up in an offline copy of a breached
+// password corpus split into k-anonymity range files, as published by Have I
+// Been Pwned: one file per 5 character SHA-1 prefix (e.g. "21BD1.txt"), each
+// line holding the remaining 35 characters and a count ("SUFFIX:COUNT").
+type BreachedPasswordList struct {
+	dir string
+}
+
+func NewBreachedPasswordList(dir string) *BreachedPasswordList {
+	return &BreachedPasswordList{dir: dir}
+}
+
+// IsBreached reports whether the password appears in the corpus. A missing
+// range file means no known breach for that prefix.
+func (l *BreachedPasswordList) IsBreached(password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:5], hash[5:]
+
+	file, err := os.Open(filepath.Join(l.dir, prefix+".txt"))
+	if errors.Is(err, os.ErrNotExist) {
+		file, err = os.Open(filepath.Join(l.dir, prefix))
+	}
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to open breached password range %s: %w", prefix, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		candidate, _, _ := strings.Cut(line, ":")
+		if strings.EqualFold(candidate, suffix) {
+			return true, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return false, fmt.Errorf("failed to read breached password range %s: %w", prefix, err)
+	}
+
+	return false, nil
+}
@@ -0,0 +1,215 @@
+package security
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	AlgorithmArgon2id = "argon2id"
+	AlgorithmBcrypt   = "bcrypt"
+
+	bcryptMaxPasswordBytes = 72
+)
+
+// algorithmHasher is one hashing scheme. Identifies tells whether a stored
+// hash was produced by it, so hashes of several schemes can coexist while a
+// user base migrates.
+type algorithmHasher interface {
+	Hash(password string) (string, error)
+	Verify(password, hash string) bool
+	NeedsRehash(hash string) bool
+	Identifies(hash string) bool
+}
+
+// PasswordHasher hashes new passwords with the configured algorithm and
+// verifies hashes of every supported one. NeedsRehash reports hashes made
+// with another algorithm or outdated parameters.
+type PasswordHasher struct {
+	preferred algorithmHasher
+	hashers   []algorithmHasher
+}
+
+func NewPasswordHasher(algorithm string, bcryptCost int, argon2Params Argon2Params) (*PasswordHasher, error) {
+	argon2idHasher := NewArgon2idHasher(argon2Params)
+	bcryptHasher := NewBcryptHasher(bcryptCost)
+
+	hasher := &PasswordHasher{
+		hashers: []algorithmHasher{argon2idHasher, bcryptHasher},
+	}
+
+	switch algorithm {
+	case AlgorithmArgon2id, "":
+		hasher.preferred = argon2idHasher
+	case AlgorithmBcrypt:
+		hasher.preferred = bcryptHasher
+	default:
+		return nil, fmt.Errorf("unsupported password hashing algorithm %q", algorithm)
+	}
+
+	return hasher, nil
+}
+
+func (h *PasswordHasher) Hash(password string) (string, error) {
+	return h.preferred.Hash(password)
+}
+
+// MaxPasswordBytes reports the limit of the algorithm new passwords are
+// hashed with.
+func (h *PasswordHasher) MaxPasswordBytes() int {
+	if limiter, ok := h.preferred.(interface{ MaxPasswordBytes() int }); ok {
+		return limiter.MaxPasswordBytes()
+	}
+	return 0
+}
+
+func (h *PasswordHasher) Verify(password, hash string) bool {
+	for _, hasher := range h.hashers {
+		if hasher.Identifies(hash) {
+			return hasher.Verify(password, hash)
+		}
+	}
+	return false
+}
+
+func (h *PasswordHasher) NeedsRehash(hash string) bool {
+	if !h.preferred.Identifies(hash) {
+		return true
+	}
+	return h.preferred.NeedsRehash(hash)
+}
+
+type BcryptHasher struct {
+	cost int
+}
+
+func NewBcryptHasher(cost int) *BcryptHasher {
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		cost = bcrypt.DefaultCost
+	}
+	return &BcryptHasher{cost: cost}
+}
+
+func (h *BcryptHasher) Hash(password string) (string, error) {
+	bytes, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+	return string(bytes), nil
+}
+
+// MaxPasswordBytes is the number of bytes bcrypt reads; it ignores the rest.
+func (h *BcryptHasher) MaxPasswordBytes() int {
+	return bcryptMaxPasswordBytes
+}
+
+func (h *BcryptHasher) Verify(password, hash string) bool {
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	return err == nil
+}
+
+func (h *BcryptHasher) NeedsRehash(hash string) bool {
+	cost, err := bcrypt.Cost([]byte(hash))
+	return err != nil || cost != h.cost
+}
+
+func (h *BcryptHasher) Identifies(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$")
+}
+
+// Argon2Params are the Argon2id cost parameters. Memory is in KiB.
+type Argon2Params struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// Argon2idHasher produces PHC string format hashes:
+// $argon2id$v=19$m=<memory>,t=<iterations>,p=<parallelism>$<salt>$<hash>
+type Argon2idHasher struct {
+	params Argon2Params
+}
+
+func NewArgon2idHasher(params Argon2Params) *Argon2idHasher {
+	return &Argon2idHasher{params: params}
+}
+
+func (h *Argon2idHasher) Hash(password string) (string, error) {
+	salt := make([]byte, h.params.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	key := argon2.IDKey([]byte(password), salt, h.params.Iterations, h.params.Memory, h.params.Parallelism, h.params.KeyLength)
+
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version,
+		h.params.Memory,
+		h.params.Iterations,
+		h.params.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+func (h *Argon2idHasher) Verify(password, hash string) bool {
+	params, salt, key, err := decodeArgon2idHash(hash)
+	if err != nil {
+		return false
+	}
+
+	candidate := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, uint32(len(key)))
+	return subtle.ConstantTimeCompare(candidate, key) == 1
+}
+
+func (h *Argon2idHasher) NeedsRehash(hash string) bool {
+	params, _, _, err := decodeArgon2idHash(hash)
+	return err != nil || params != h.params
+}
+
+func (h *Argon2idHasher) Identifies(hash string) bool {
+	return strings.HasPrefix(hash, "$argon2id$")
+}
+
+func decodeArgon2idHash(hash string) (Argon2Params, []byte, []byte, error) {
+	var params Argon2Params
+
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return params, nil, nil, fmt.Errorf("invalid argon2id hash")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, fmt.Errorf("unsupported argon2 version")
+	}
+
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return params, nil, nil, fmt.Errorf("invalid argon2id parameters: %w", err)
+	}
+	if params.Iterations == 0 || params.Parallelism == 0 {
+		return params, nil, nil, fmt.Errorf("invalid argon2id parameters")
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, fmt.Errorf("invalid argon2id salt: %w", err)
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return params, nil, nil, fmt.Errorf("invalid argon2id hash value")
+	}
+
+	params.SaltLength = uint32(len(salt))
+	params.KeyLength = uint32(len(key))
+
+	return params, salt, key, nil
+}
@@ -0,0 +1,140 @@
+package security
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// commonPasswords is always part of the blocklist. Deployments can extend it
+// with a file of one password per line.
+var commonPasswords = []string{
+	"123456", "123456789", "12345678", "1234567890", "12345", "1234567",
+	"password", "password1", "password12", "password123", "passw0rd",
+	"qwerty", "qwerty123", "qwertyuiop", "1q2w3e4r", "1q2w3e4r5t",
+	"abc123", "abcd1234", "111111", "000000", "123123", "654321",
+	"iloveyou", "letmein", "welcome", "welcome1", "welcome123",
+	"admin", "admin123", "administrator", "root", "changeme",
+	"monkey", "dragon", "football", "baseball", "sunshine", "princess",
+	"master", "shadow", "superman", "trustno1", "michael", "jennifer",
+	"starwars", "whatever", "freedom", "hello123", "secret", "zaq12wsx",
+	"asdfghjkl", "asdf1234", "p@ssw0rd", "p@ssword", "default", "guest",
+}
+
+type PasswordRules struct {
+	MinLength      int
+	MaxLength      int
+	RequireUpper   bool
+	RequireLower   bool
+	RequireDigit   bool
+	RequireSpecial bool
+}
+
+// PasswordPolicy checks the rules that can be decided from the password
+// alone: length, character classes and the blocklist.
+type PasswordPolicy struct {
+	rules     PasswordRules
+	blocklist map[string]bool
+}
+
+func NewPasswordPolicy(rules PasswordRules, blocklist []string) *PasswordPolicy {
+	policy := &PasswordPolicy{
+		rules:     rules,
+		blocklist: make(map[string]bool, len(commonPasswords)+len(blocklist)),
+	}
+	for _, password := range commonPasswords {
+		policy.blocklist[password] = true
+	}
+	for _, password := range blocklist {
+		if password = strings.ToLower(strings.TrimSpace(password)); password != "" {
+			policy.blocklist[password] = true
+		}
+	}
+	return policy
+}
+
+// Validate returns the first rule the password breaks. userInputs are values
+// such as the email or name that must not be used as the password.
+func (p *PasswordPolicy) Validate(password string, userInputs ...string) error {
+	length := len([]rune(password))
+	if length < p.rules.MinLength {
+		return fmt.Errorf("password must be at least %d characters long", p.rules.MinLength)
+	}
+	if p.rules.MaxLength > 0 && len(password) > p.rules.MaxLength {
+		return fmt.Errorf("password cannot exceed %d bytes", p.rules.MaxLength)
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSpecial bool
+	for _, char := range password {
+		switch {
+		case unicode.IsUpper(char):
+			hasUpper = true
+		case unicode.IsLower(char):
+			hasLower = true
+		case unicode.IsDigit(char):
+			hasDigit = true
+		case unicode.IsPunct(char) || unicode.IsSymbol(char) || unicode.IsSpace(char):
+			hasSpecial = true
+		}
+	}
+
+	if p.rules.RequireUpper && !hasUpper {
+		return errors.New("password must contain at least one uppercase letter")
+	}
+	if p.rules.RequireLower && !hasLower {
+		return errors.New("password must contain at least one lowercase letter")
+	}
+	if p.rules.RequireDigit && !hasDigit {
+		return errors.New("password must contain at least one digit")
+	}
+	if p.rules.RequireSpecial && !hasSpecial {
+		return errors.New("password must contain at least one special character")
+	}
+
+	lowered := strings.ToLower(password)
+	if p.blocklist[lowered] {
+		return errors.New("password is too common")
+	}
+	for _, input := range userInputs {
+		input = strings.ToLower(strings.TrimSpace(input))
+		if input == "" {
+			continue
+		}
+		if lowered == input {
+			return errors.New("password must not match your personal details")
+		}
+		if local, _, found := strings.Cut(input, "@"); found && len(local) >= 4 && strings.Contains(lowered, local) {
+			return errors.New("password must not contain your email address")
+		}
+	}
+
+	return nil
+}
+
+// LoadPasswordBlocklist reads one password per line; blank lines and lines
+// starting with # are skipped.
+func LoadPasswordBlocklist(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open password blocklist: %w", err)
+	}
+	defer file.Close()
+
+	var passwords []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		passwords = append(passwords, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read password blocklist: %w", err)
+	}
+
+	return passwords, nil
+}
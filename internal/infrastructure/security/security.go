@@ -5,34 +5,8 @@ import (
 	"crypto/subtle"
 	"encoding/hex"
 	"fmt"
-
-	"golang.org/x/crypto/bcrypt"
 )
 
-type PasswordHasher struct {
-	cost int
-}
-
-func NewPasswordHasher(cost int) *PasswordHasher {
-	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
-		cost = bcrypt.DefaultCost
-	}
-	return &PasswordHasher{cost: cost}
-}
-
-func (h *PasswordHasher) Hash(password string) (string, error) {
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
-	if err != nil {
-		return "", fmt.Errorf("failed to hash password: %w", err)
-	}
-	return string(bytes), nil
-}
-
-func (h *PasswordHasher) Verify(password, hash string) bool {
-	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
-	return err == nil
-}
-
 type TokenGenerator struct{}
 
 func NewTokenGenerator() *TokenGenerator {
@@ -75,45 +49,6 @@ func (s *Sanitizer) SanitizeString(input string) string {
 	return result
 }
 
-func ValidatePassword(password string) error {
-	if len(password) < 8 {
-		return fmt.Errorf("password must be at least 8 characters long")
-	}
-
-	hasUpper := false
-	hasLower := false
-	hasDigit := false
-	hasSpecial := false
-
-	for _, char := range password {
-		switch {
-		case char >= 'A' && char <= 'Z':
-			hasUpper = true
-		case char >= 'a' && char <= 'z':
-			hasLower = true
-		case char >= '0' && char <= '9':
-			hasDigit = true
-		case char >= 32 && char <= 126:
-			hasSpecial = true
-		}
-	}
-
-	if !hasUpper {
-		return fmt.Errorf("password must contain at least one uppercase letter")
-	}
-	if !hasLower {
-		return fmt.Errorf("password must contain at least one lowercase letter")
-	}
-	if !hasDigit {
-		return fmt.Errorf("password must contain at least one digit")
-	}
-	if !hasSpecial {
-		return fmt.Errorf("password must contain at least one special character")
-	}
-
-	return nil
-}
-
 type RateLimiter struct {
 	requests map[string][]int64
 	limit    int
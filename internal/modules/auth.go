@@ -4,16 +4,22 @@ import (
 	"go.uber.org/fx"
 
 	authCommands "github.com/tranvuongduy2003/go-mvc/internal/application/commands/auth"
+	eventHandlers "github.com/tranvuongduy2003/go-mvc/internal/application/event_handlers"
 	authQueries "github.com/tranvuongduy2003/go-mvc/internal/application/queries/auth"
 	appServices "github.com/tranvuongduy2003/go-mvc/internal/application/services"
 	"github.com/tranvuongduy2003/go-mvc/internal/domain/auth"
 	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/job"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/messaging"
 	"github.com/tranvuongduy2003/go-mvc/internal/domain/user"
 	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/cache"
 	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/config"
 	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/external"
+	jobHandlers "github.com/tranvuongduy2003/go-mvc/internal/infrastructure/jobs/handlers"
+	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/jobs/worker"
 	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/logger"
-	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/security"
+	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/metrics"
+	"github.com/tranvuongduy2003/go-mvc/pkg/geoip"
 	"github.com/tranvuongduy2003/go-mvc/pkg/jwt"
 )
 
@@ -21,9 +27,25 @@ var AuthModule = fx.Module("auth",
 	fx.Provide(
 		NewAuthService,
 		NewTokenManagementService,
+		NewSessionManagementService,
+		NewMFAService,
+		NewPasskeyService,
+		NewAccountLockoutService,
+		NewExternalLoginService,
 		NewPasswordManagementService,
 		NewEmailVerificationService,
+		NewEmailChangeService,
+		NewMagicLinkService,
+		NewAPIKeyService,
+		NewClientCredentialsService,
+		NewImpersonationService,
+		NewReauthenticationService,
+		NewLoginHistoryService,
 		NewAuthorizationService,
+		NewAuthorizationCacheMetrics,
+		NewAuthorizationEventHandler,
+		NewPasswordPolicyService,
+		NewTokenIntrospectionService,
 		NewSMTPService,
 
 		NewLoginCommandHandler,
@@ -34,12 +56,45 @@ var AuthModule = fx.Module("auth",
 		NewConfirmPasswordResetCommandHandler,
 		NewVerifyEmailCommandHandler,
 		NewResendVerificationEmailCommandHandler,
+		NewRequestEmailChangeCommandHandler,
+		NewConfirmEmailChangeCommandHandler,
+		NewRevertEmailChangeCommandHandler,
 		NewLogoutCommandHandler,
 		NewLogoutAllDevicesCommandHandler,
+		NewRevokeSessionCommandHandler,
+		NewEnrollMFACommandHandler,
+		NewConfirmMFACommandHandler,
+		NewRegenerateRecoveryCodesCommandHandler,
+		NewVerifyMFACommandHandler,
+		NewResetMFACommandHandler,
+		NewBeginPasskeyRegistrationCommandHandler,
+		NewFinishPasskeyRegistrationCommandHandler,
+		NewBeginPasskeyLoginCommandHandler,
+		NewFinishPasskeyLoginCommandHandler,
+		NewDeletePasskeyCommandHandler,
+		NewUnlockAccountCommandHandler,
+		NewImpersonateUserCommandHandler,
+		NewReauthenticateCommandHandler,
+		NewSecureAccountCommandHandler,
+		NewBeginOIDCLoginCommandHandler,
+		NewFinishOIDCLoginCommandHandler,
+		NewRequestMagicLinkCommandHandler,
+		NewConsumeMagicLinkCommandHandler,
+		NewCreateAPIKeyCommandHandler,
+		NewDeleteAPIKeyCommandHandler,
+		NewIssueClientTokenCommandHandler,
+		NewRevokeTokenCommandHandler,
 
 		NewGetUserProfileQueryHandler,
 		NewGetUserPermissionsQueryHandler,
+		NewListSessionsQueryHandler,
+		NewGetLoginHistoryQueryHandler,
+		NewListPasskeysQueryHandler,
+		NewListAPIKeysQueryHandler,
+		NewIntrospectTokenQueryHandler,
 	),
+	fx.Invoke(RegisterAuthJobHandlers),
+	fx.Invoke(SetupAuthorizationEventSubscriptions),
 )
 
 func NewLoginCommandHandler(authService contracts.AuthService) *authCommands.LoginCommandHandler {
@@ -74,6 +129,18 @@ func NewResendVerificationEmailCommandHandler(emailVerificationService contracts
 	return authCommands.NewResendVerificationEmailCommandHandler(emailVerificationService)
 }
 
+func NewRequestEmailChangeCommandHandler(emailChangeService contracts.EmailChangeService) *authCommands.RequestEmailChangeCommandHandler {
+	return authCommands.NewRequestEmailChangeCommandHandler(emailChangeService)
+}
+
+func NewConfirmEmailChangeCommandHandler(emailChangeService contracts.EmailChangeService) *authCommands.ConfirmEmailChangeCommandHandler {
+	return authCommands.NewConfirmEmailChangeCommandHandler(emailChangeService)
+}
+
+func NewRevertEmailChangeCommandHandler(emailChangeService contracts.EmailChangeService) *authCommands.RevertEmailChangeCommandHandler {
+	return authCommands.NewRevertEmailChangeCommandHandler(emailChangeService)
+}
+
 func NewLogoutCommandHandler(tokenService contracts.TokenManagementService) *authCommands.LogoutCommandHandler {
 	return authCommands.NewLogoutCommandHandler(tokenService)
 }
@@ -82,6 +149,101 @@ func NewLogoutAllDevicesCommandHandler(tokenService contracts.TokenManagementSer
 	return authCommands.NewLogoutAllDevicesCommandHandler(tokenService)
 }
 
+func NewRevokeSessionCommandHandler(sessionService contracts.SessionManagementService) *authCommands.RevokeSessionCommandHandler {
+	return authCommands.NewRevokeSessionCommandHandler(sessionService)
+}
+
+func NewEnrollMFACommandHandler(mfaService contracts.MFAService) *authCommands.EnrollMFACommandHandler {
+	return authCommands.NewEnrollMFACommandHandler(mfaService)
+}
+
+func NewConfirmMFACommandHandler(mfaService contracts.MFAService) *authCommands.ConfirmMFACommandHandler {
+	return authCommands.NewConfirmMFACommandHandler(mfaService)
+}
+
+func NewRegenerateRecoveryCodesCommandHandler(mfaService contracts.MFAService) *authCommands.RegenerateRecoveryCodesCommandHandler {
+	return authCommands.NewRegenerateRecoveryCodesCommandHandler(mfaService)
+}
+
+func NewVerifyMFACommandHandler(mfaService contracts.MFAService) *authCommands.VerifyMFACommandHandler {
+	return authCommands.NewVerifyMFACommandHandler(mfaService)
+}
+
+func NewResetMFACommandHandler(mfaService contracts.MFAService) *authCommands.ResetMFACommandHandler {
+	return authCommands.NewResetMFACommandHandler(mfaService)
+}
+
+func NewBeginPasskeyRegistrationCommandHandler(passkeyService contracts.PasskeyService) *authCommands.BeginPasskeyRegistrationCommandHandler {
+	return authCommands.NewBeginPasskeyRegistrationCommandHandler(passkeyService)
+}
+
+func NewFinishPasskeyRegistrationCommandHandler(passkeyService contracts.PasskeyService) *authCommands.FinishPasskeyRegistrationCommandHandler {
+	return authCommands.NewFinishPasskeyRegistrationCommandHandler(passkeyService)
+}
+
+func NewBeginPasskeyLoginCommandHandler(passkeyService contracts.PasskeyService) *authCommands.BeginPasskeyLoginCommandHandler {
+	return authCommands.NewBeginPasskeyLoginCommandHandler(passkeyService)
+}
+
+func NewFinishPasskeyLoginCommandHandler(passkeyService contracts.PasskeyService) *authCommands.FinishPasskeyLoginCommandHandler {
+	return authCommands.NewFinishPasskeyLoginCommandHandler(passkeyService)
+}
+
+func NewDeletePasskeyCommandHandler(passkeyService contracts.PasskeyService) *authCommands.DeletePasskeyCommandHandler {
+	return authCommands.NewDeletePasskeyCommandHandler(passkeyService)
+}
+
+func NewUnlockAccountCommandHandler(lockoutService contracts.AccountLockoutService) *authCommands.UnlockAccountCommandHandler {
+	return authCommands.NewUnlockAccountCommandHandler(lockoutService)
+}
+
+func NewImpersonateUserCommandHandler(
+	impersonationService contracts.ImpersonationService,
+	authorizationService contracts.AuthorizationService,
+) *authCommands.ImpersonateUserCommandHandler {
+	return authCommands.NewImpersonateUserCommandHandler(impersonationService, authorizationService)
+}
+
+func NewReauthenticateCommandHandler(reauthenticationService contracts.ReauthenticationService) *authCommands.ReauthenticateCommandHandler {
+	return authCommands.NewReauthenticateCommandHandler(reauthenticationService)
+}
+
+func NewSecureAccountCommandHandler(loginHistoryService contracts.LoginHistoryService) *authCommands.SecureAccountCommandHandler {
+	return authCommands.NewSecureAccountCommandHandler(loginHistoryService)
+}
+
+func NewBeginOIDCLoginCommandHandler(externalLoginService contracts.ExternalLoginService) *authCommands.BeginOIDCLoginCommandHandler {
+	return authCommands.NewBeginOIDCLoginCommandHandler(externalLoginService)
+}
+
+func NewFinishOIDCLoginCommandHandler(externalLoginService contracts.ExternalLoginService) *authCommands.FinishOIDCLoginCommandHandler {
+	return authCommands.NewFinishOIDCLoginCommandHandler(externalLoginService)
+}
+
+func NewRequestMagicLinkCommandHandler(magicLinkService contracts.MagicLinkService) *authCommands.RequestMagicLinkCommandHandler {
+	return authCommands.NewRequestMagicLinkCommandHandler(magicLinkService)
+}
+
+func NewConsumeMagicLinkCommandHandler(magicLinkService contracts.MagicLinkService) *authCommands.ConsumeMagicLinkCommandHandler {
+	return authCommands.NewConsumeMagicLinkCommandHandler(magicLinkService)
+}
+
+func NewCreateAPIKeyCommandHandler(apiKeyService contracts.APIKeyService) *authCommands.CreateAPIKeyCommandHandler {
+	return authCommands.NewCreateAPIKeyCommandHandler(apiKeyService)
+}
+
+func NewDeleteAPIKeyCommandHandler(apiKeyService contracts.APIKeyService) *authCommands.DeleteAPIKeyCommandHandler {
+	return authCommands.NewDeleteAPIKeyCommandHandler(apiKeyService)
+}
+
+func NewIssueClientTokenCommandHandler(clientCredentialsService contracts.ClientCredentialsService) *authCommands.IssueClientTokenCommandHandler {
+	return authCommands.NewIssueClientTokenCommandHandler(clientCredentialsService)
+}
+
+func NewRevokeTokenCommandHandler(introspectionService contracts.TokenIntrospectionService) *authCommands.RevokeTokenCommandHandler {
+	return authCommands.NewRevokeTokenCommandHandler(introspectionService)
+}
+
 func NewGetUserProfileQueryHandler(
 	userRepo user.UserRepository,
 	roleRepo auth.RoleRepository,
@@ -94,58 +256,133 @@ func NewGetUserPermissionsQueryHandler(authorizationService contracts.Authorizat
 	return authQueries.NewGetUserPermissionsQueryHandler(authorizationService)
 }
 
+func NewListSessionsQueryHandler(sessionService contracts.SessionManagementService) *authQueries.ListSessionsQueryHandler {
+	return authQueries.NewListSessionsQueryHandler(sessionService)
+}
+
+func NewGetLoginHistoryQueryHandler(loginHistoryService contracts.LoginHistoryService) *authQueries.GetLoginHistoryQueryHandler {
+	return authQueries.NewGetLoginHistoryQueryHandler(loginHistoryService)
+}
+
+func NewListPasskeysQueryHandler(passkeyService contracts.PasskeyService) *authQueries.ListPasskeysQueryHandler {
+	return authQueries.NewListPasskeysQueryHandler(passkeyService)
+}
+
+func NewListAPIKeysQueryHandler(apiKeyService contracts.APIKeyService) *authQueries.ListAPIKeysQueryHandler {
+	return authQueries.NewListAPIKeysQueryHandler(apiKeyService)
+}
+
+func NewIntrospectTokenQueryHandler(introspectionService contracts.TokenIntrospectionService) *authQueries.IntrospectTokenQueryHandler {
+	return authQueries.NewIntrospectTokenQueryHandler(introspectionService)
+}
+
 type AuthServiceParams struct {
 	fx.In
-	UserRepo       user.UserRepository
-	JWTService     jwt.JWTService
-	PasswordHasher *security.PasswordHasher
-	CacheService   *cache.Service
-	SMTPService    *external.SMTPService
-	Logger         *logger.Logger
+	UserRepo         user.UserRepository
+	SessionRepo      auth.SessionRepository
+	CredentialRepo   auth.WebAuthnCredentialRepository
+	IdentityRepo     auth.ExternalIdentityRepository
+	APIKeyRepo       auth.APIKeyRepository
+	AuditLogRepo     auth.AuditLogRepository
+	LoginAttemptRepo auth.LoginAttemptRepository
+	JWTService       jwt.JWTService
+	PasswordHasher   user.PasswordHasher
+	PasswordPolicy   contracts.PasswordPolicyService
+	AuthzService     contracts.AuthorizationService
+	CacheService     *cache.Service
+	SMTPService      *external.SMTPService
+	JobService       job.BackgroundJobService
+	EventBus         messaging.EventBus
+	GeoIP            *geoip.Reader
+	Config           *config.AppConfig
+	Logger           *logger.Logger
 }
 
-func NewAuthService(params AuthServiceParams) contracts.AuthService {
+func newAuthService(params AuthServiceParams) *appServices.AuthService {
 	return appServices.NewAuthService(
 		params.UserRepo,
+		params.SessionRepo,
+		params.CredentialRepo,
+		params.IdentityRepo,
+		params.APIKeyRepo,
+		params.AuditLogRepo,
+		params.LoginAttemptRepo,
 		params.JWTService,
 		params.PasswordHasher,
+		params.PasswordPolicy,
+		params.AuthzService,
 		params.CacheService,
 		params.SMTPService,
+		params.JobService,
+		params.EventBus,
+		params.GeoIP,
+		params.Config.Auth,
 		params.Logger,
 	)
 }
 
+func NewAuthService(params AuthServiceParams) contracts.AuthService {
+	return newAuthService(params)
+}
+
 func NewTokenManagementService(params AuthServiceParams) contracts.TokenManagementService {
-	return appServices.NewAuthService(
-		params.UserRepo,
-		params.JWTService,
-		params.PasswordHasher,
-		params.CacheService,
-		params.SMTPService,
-		params.Logger,
-	)
+	return newAuthService(params)
+}
+
+func NewSessionManagementService(params AuthServiceParams) contracts.SessionManagementService {
+	return newAuthService(params)
+}
+
+func NewMFAService(params AuthServiceParams) contracts.MFAService {
+	return newAuthService(params)
+}
+
+func NewPasskeyService(params AuthServiceParams) contracts.PasskeyService {
+	return newAuthService(params)
+}
+
+func NewAccountLockoutService(params AuthServiceParams) contracts.AccountLockoutService {
+	return newAuthService(params)
+}
+
+func NewExternalLoginService(params AuthServiceParams) contracts.ExternalLoginService {
+	return newAuthService(params)
 }
 
 func NewPasswordManagementService(params AuthServiceParams) contracts.PasswordManagementService {
-	return appServices.NewAuthService(
-		params.UserRepo,
-		params.JWTService,
-		params.PasswordHasher,
-		params.CacheService,
-		params.SMTPService,
-		params.Logger,
-	)
+	return newAuthService(params)
 }
 
 func NewEmailVerificationService(params AuthServiceParams) contracts.EmailVerificationService {
-	return appServices.NewAuthService(
-		params.UserRepo,
-		params.JWTService,
-		params.PasswordHasher,
-		params.CacheService,
-		params.SMTPService,
-		params.Logger,
-	)
+	return newAuthService(params)
+}
+
+func NewEmailChangeService(params AuthServiceParams) contracts.EmailChangeService {
+	return newAuthService(params)
+}
+
+func NewMagicLinkService(params AuthServiceParams) contracts.MagicLinkService {
+	return newAuthService(params)
+}
+
+func NewAPIKeyService(params AuthServiceParams) contracts.APIKeyService {
+	return newAuthService(params)
+}
+
+func NewClientCredentialsService(params AuthServiceParams) contracts.ClientCredentialsService {
+	return newAuthService(params)
+}
+
+func NewImpersonationService(params AuthServiceParams) contracts.ImpersonationService {
+	return newAuthService(params)
+}
+
+func NewReauthenticationService(params AuthServiceParams) contracts.ReauthenticationService {
+	return newAuthService(params)
+}
+
+func NewLoginHistoryService(params AuthServiceParams) contracts.LoginHistoryService {
+	return newAuthService(params)
 }
 
 type AuthorizationServiceParams struct {
@@ -155,16 +392,90 @@ type AuthorizationServiceParams struct {
 	PermissionRepo     auth.PermissionRepository
 	UserRoleRepo       auth.UserRoleRepository
 	RolePermissionRepo auth.RolePermissionRepository
+	CacheService       *cache.Service
+	EventBus           messaging.EventBus
+	CacheMetrics       contracts.AuthorizationCacheMetrics
+	Config             *config.AppConfig
+	Logger             *logger.Logger
 }
 
-func NewAuthorizationService(params AuthorizationServiceParams) contracts.AuthorizationService {
-	return appServices.NewAuthorizationService(
+// NewAuthorizationService also provides the cache in front of the service,
+// which is nil when caching is disabled.
+func NewAuthorizationService(params AuthorizationServiceParams) (contracts.AuthorizationService, contracts.AuthorizationCache) {
+	authzService := appServices.NewAuthorizationService(
 		params.UserRepo,
 		params.RoleRepo,
 		params.PermissionRepo,
 		params.UserRoleRepo,
 		params.RolePermissionRepo,
+		params.CacheService,
+		params.Config.Auth.EmailVerification,
+		params.Config.Auth.RoleHierarchy,
 	)
+
+	if !params.Config.Auth.AuthorizationCache.Enabled {
+		return authzService, nil
+	}
+
+	cachedService := appServices.NewCachedAuthorizationService(
+		authzService,
+		params.RoleRepo,
+		params.UserRoleRepo,
+		params.CacheService,
+		params.EventBus,
+		params.CacheMetrics,
+		params.Config.Auth.AuthorizationCache,
+		params.Logger,
+	)
+	return cachedService, cachedService
+}
+
+func NewAuthorizationCacheMetrics() contracts.AuthorizationCacheMetrics {
+	return metrics.NewAuthorizationCacheCollector()
+}
+
+func NewAuthorizationEventHandler(
+	authzCache contracts.AuthorizationCache,
+	cacheMetrics contracts.AuthorizationCacheMetrics,
+	logger *logger.Logger,
+) *eventHandlers.AuthorizationEventHandler {
+	return eventHandlers.NewAuthorizationEventHandler(authzCache, cacheMetrics, logger.Logger)
+}
+
+// SetupAuthorizationEventSubscriptions has each instance evict the users
+// any instance reports as changed.
+func SetupAuthorizationEventSubscriptions(eventHandler *eventHandlers.AuthorizationEventHandler, eventBus messaging.EventBus, cfg *config.AppConfig) error {
+	if !cfg.Auth.AuthorizationCache.Enabled {
+		return nil
+	}
+	return eventHandler.SetupEventSubscriptions(eventBus)
+}
+
+func RegisterAuthJobHandlers(pool *worker.WorkerPool, smtpService *external.SMTPService, metrics job.JobMetrics) {
+	pool.RegisterHandler(jobHandlers.NewVerificationEmailJobHandler(smtpService, metrics))
+	pool.RegisterHandler(jobHandlers.NewLoginAlertJobHandler(smtpService, metrics))
+	pool.RegisterHandler(jobHandlers.NewPasswordChangedJobHandler(smtpService, metrics))
+	pool.RegisterHandler(jobHandlers.NewUnusualSignInJobHandler(smtpService, metrics))
+	pool.RegisterHandler(jobHandlers.NewMagicLinkJobHandler(smtpService, metrics))
+}
+
+func NewPasswordPolicyService(
+	historyRepo auth.PasswordHistoryRepository,
+	passwordHasher user.PasswordHasher,
+	cfg *config.AppConfig,
+	logger *logger.Logger,
+) contracts.PasswordPolicyService {
+	return appServices.NewPasswordPolicyService(historyRepo, passwordHasher, cfg.Auth.PasswordPolicy, logger)
+}
+
+func NewTokenIntrospectionService(
+	authService contracts.AuthService,
+	tokenService contracts.TokenManagementService,
+	clientService contracts.ClientCredentialsService,
+	authzService contracts.AuthorizationService,
+	jwtService jwt.JWTService,
+) contracts.TokenIntrospectionService {
+	return appServices.NewTokenIntrospectionService(authService, tokenService, clientService, authzService, jwtService)
 }
 
 func NewSMTPService(cfg *config.AppConfig, logger *logger.Logger) *external.SMTPService {
@@ -0,0 +1,35 @@
+package modules
+
+import (
+	"go.uber.org/fx"
+
+	appServices "github.com/tranvuongduy2003/go-mvc/internal/application/services"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/auth"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/user"
+	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/config"
+	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/logger"
+)
+
+// ResourceAttributeLoaderGroup collects the loaders the policy middleware
+// fetches resource attributes with. Other modules add loaders for their
+// resources by providing them into this group.
+const ResourceAttributeLoaderGroup = `group:"resource_attribute_loaders"`
+
+var PolicyModule = fx.Module("policy",
+	fx.Provide(
+		NewPolicyDecisionPoint,
+		fx.Annotate(
+			NewUserAttributeLoader,
+			fx.ResultTags(ResourceAttributeLoaderGroup),
+		),
+	),
+)
+
+func NewPolicyDecisionPoint(policyRepo auth.PolicyRepository, cfg *config.AppConfig, logger *logger.Logger) contracts.PolicyDecisionPoint {
+	return appServices.NewPolicyDecisionPoint(policyRepo, cfg.Auth.Policies, logger)
+}
+
+func NewUserAttributeLoader(userRepo user.UserRepository, authzService contracts.AuthorizationService) contracts.ResourceAttributeLoader {
+	return appServices.NewUserAttributeLoader(userRepo, authzService)
+}
@@ -0,0 +1,129 @@
+package modules
+
+import (
+	"go.uber.org/fx"
+
+	rbacCommands "github.com/tranvuongduy2003/go-mvc/internal/application/commands/rbac"
+	rbacQueries "github.com/tranvuongduy2003/go-mvc/internal/application/queries/rbac"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/auth"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/user"
+)
+
+var RBACModule = fx.Module("rbac",
+	fx.Provide(
+		NewCreateRoleCommandHandler,
+		NewUpdateRoleCommandHandler,
+		NewDeleteRoleCommandHandler,
+		NewSetRoleParentCommandHandler,
+		NewCreatePermissionCommandHandler,
+		NewUpdatePermissionCommandHandler,
+		NewDeletePermissionCommandHandler,
+		NewGrantRolePermissionCommandHandler,
+		NewRevokeRolePermissionCommandHandler,
+		NewAssignUserRoleCommandHandler,
+		NewRevokeUserRoleCommandHandler,
+		NewGetRoleQueryHandler,
+		NewListRolesQueryHandler,
+		NewGetPermissionQueryHandler,
+		NewListPermissionsQueryHandler,
+		NewListRolePermissionsQueryHandler,
+		NewListUserRolesQueryHandler,
+	),
+)
+
+func NewCreateRoleCommandHandler(roleRepo auth.RoleRepository) *rbacCommands.CreateRoleCommandHandler {
+	return rbacCommands.NewCreateRoleCommandHandler(roleRepo)
+}
+
+func NewUpdateRoleCommandHandler(roleRepo auth.RoleRepository, authzService contracts.AuthorizationService) *rbacCommands.UpdateRoleCommandHandler {
+	return rbacCommands.NewUpdateRoleCommandHandler(roleRepo, authzService)
+}
+
+func NewDeleteRoleCommandHandler(
+	roleRepo auth.RoleRepository,
+	userRoleRepo auth.UserRoleRepository,
+	authzService contracts.AuthorizationService,
+) *rbacCommands.DeleteRoleCommandHandler {
+	return rbacCommands.NewDeleteRoleCommandHandler(roleRepo, userRoleRepo, authzService)
+}
+
+func NewSetRoleParentCommandHandler(roleRepo auth.RoleRepository, authzService contracts.AuthorizationService) *rbacCommands.SetRoleParentCommandHandler {
+	return rbacCommands.NewSetRoleParentCommandHandler(roleRepo, authzService)
+}
+
+func NewCreatePermissionCommandHandler(permissionRepo auth.PermissionRepository) *rbacCommands.CreatePermissionCommandHandler {
+	return rbacCommands.NewCreatePermissionCommandHandler(permissionRepo)
+}
+
+func NewUpdatePermissionCommandHandler(
+	permissionRepo auth.PermissionRepository,
+	rolePermissionRepo auth.RolePermissionRepository,
+	authzService contracts.AuthorizationService,
+) *rbacCommands.UpdatePermissionCommandHandler {
+	return rbacCommands.NewUpdatePermissionCommandHandler(permissionRepo, rolePermissionRepo, authzService)
+}
+
+func NewDeletePermissionCommandHandler(
+	permissionRepo auth.PermissionRepository,
+	rolePermissionRepo auth.RolePermissionRepository,
+	authzService contracts.AuthorizationService,
+) *rbacCommands.DeletePermissionCommandHandler {
+	return rbacCommands.NewDeletePermissionCommandHandler(permissionRepo, rolePermissionRepo, authzService)
+}
+
+func NewGrantRolePermissionCommandHandler(
+	roleRepo auth.RoleRepository,
+	permissionRepo auth.PermissionRepository,
+	rolePermissionRepo auth.RolePermissionRepository,
+	authzService contracts.AuthorizationService,
+) *rbacCommands.GrantRolePermissionCommandHandler {
+	return rbacCommands.NewGrantRolePermissionCommandHandler(roleRepo, permissionRepo, rolePermissionRepo, authzService)
+}
+
+func NewRevokeRolePermissionCommandHandler(
+	rolePermissionRepo auth.RolePermissionRepository,
+	authzService contracts.AuthorizationService,
+) *rbacCommands.RevokeRolePermissionCommandHandler {
+	return rbacCommands.NewRevokeRolePermissionCommandHandler(rolePermissionRepo, authzService)
+}
+
+func NewAssignUserRoleCommandHandler(
+	userRepo user.UserRepository,
+	roleRepo auth.RoleRepository,
+	userRoleRepo auth.UserRoleRepository,
+	authzService contracts.AuthorizationService,
+) *rbacCommands.AssignUserRoleCommandHandler {
+	return rbacCommands.NewAssignUserRoleCommandHandler(userRepo, roleRepo, userRoleRepo, authzService)
+}
+
+func NewRevokeUserRoleCommandHandler(
+	userRoleRepo auth.UserRoleRepository,
+	authzService contracts.AuthorizationService,
+) *rbacCommands.RevokeUserRoleCommandHandler {
+	return rbacCommands.NewRevokeUserRoleCommandHandler(userRoleRepo, authzService)
+}
+
+func NewGetRoleQueryHandler(roleRepo auth.RoleRepository) *rbacQueries.GetRoleQueryHandler {
+	return rbacQueries.NewGetRoleQueryHandler(roleRepo)
+}
+
+func NewListRolesQueryHandler(roleRepo auth.RoleRepository) *rbacQueries.ListRolesQueryHandler {
+	return rbacQueries.NewListRolesQueryHandler(roleRepo)
+}
+
+func NewGetPermissionQueryHandler(permissionRepo auth.PermissionRepository) *rbacQueries.GetPermissionQueryHandler {
+	return rbacQueries.NewGetPermissionQueryHandler(permissionRepo)
+}
+
+func NewListPermissionsQueryHandler(permissionRepo auth.PermissionRepository) *rbacQueries.ListPermissionsQueryHandler {
+	return rbacQueries.NewListPermissionsQueryHandler(permissionRepo)
+}
+
+func NewListRolePermissionsQueryHandler(rolePermissionRepo auth.RolePermissionRepository) *rbacQueries.ListRolePermissionsQueryHandler {
+	return rbacQueries.NewListRolePermissionsQueryHandler(rolePermissionRepo)
+}
+
+func NewListUserRolesQueryHandler(userRoleRepo auth.UserRoleRepository) *rbacQueries.ListUserRolesQueryHandler {
+	return rbacQueries.NewListUserRolesQueryHandler(userRoleRepo)
+}
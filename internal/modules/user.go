@@ -8,6 +8,7 @@ import (
 	userQueries "github.com/tranvuongduy2003/go-mvc/internal/application/queries/user"
 	"github.com/tranvuongduy2003/go-mvc/internal/application/services"
 	userValidators "github.com/tranvuongduy2003/go-mvc/internal/application/validators/user"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
 	"github.com/tranvuongduy2003/go-mvc/internal/domain/messaging"
 	"github.com/tranvuongduy2003/go-mvc/internal/domain/user"
 	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/external"
@@ -29,8 +30,8 @@ var UserModule = fx.Module("user",
 	fx.Invoke(SetupUserEventSubscriptions),
 )
 
-func NewCreateUserCommandHandler(userRepo user.UserRepository) *userCommands.CreateUserCommandHandler {
-	return userCommands.NewCreateUserCommandHandler(userRepo)
+func NewCreateUserCommandHandler(userRepo user.UserRepository, passwordHasher user.PasswordHasher, passwordPolicy contracts.PasswordPolicyService, emailVerification contracts.EmailVerificationService) *userCommands.CreateUserCommandHandler {
+	return userCommands.NewCreateUserCommandHandler(userRepo, passwordHasher, passwordPolicy, emailVerification)
 }
 
 func NewUpdateUserCommandHandler(userRepo user.UserRepository) *userCommands.UpdateUserCommandHandler {
@@ -4,18 +4,28 @@ import (
 	"go.uber.org/fx"
 
 	authCommands "github.com/tranvuongduy2003/go-mvc/internal/application/commands/auth"
+	rbacCommands "github.com/tranvuongduy2003/go-mvc/internal/application/commands/rbac"
 	authQueries "github.com/tranvuongduy2003/go-mvc/internal/application/queries/auth"
+	rbacQueries "github.com/tranvuongduy2003/go-mvc/internal/application/queries/rbac"
 	appservices "github.com/tranvuongduy2003/go-mvc/internal/application/services"
 	userValidators "github.com/tranvuongduy2003/go-mvc/internal/application/validators/user"
 	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/logger"
 	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/tracing"
 	v1 "github.com/tranvuongduy2003/go-mvc/internal/presentation/http/handlers/v1"
+	"github.com/tranvuongduy2003/go-mvc/pkg/jwt"
 )
 
 var HandlerModule = fx.Module("handler",
 	fx.Provide(
 		NewUserHandler,
 		NewAuthHandler,
+		NewJWKSHandler,
+		NewWebAuthnHandler,
+		NewOIDCHandler,
+		NewMagicLinkHandler,
+		NewAPIKeyHandler,
+		NewOAuthHandler,
+		NewRBACHandler,
 	),
 )
 
@@ -39,12 +49,88 @@ type AuthHandlerParams struct {
 	LogoutAllDevicesHandler     *authCommands.LogoutAllDevicesCommandHandler
 	GetUserProfileHandler       *authQueries.GetUserProfileQueryHandler
 	GetUserPermissionsHandler   *authQueries.GetUserPermissionsQueryHandler
+	ListSessionsHandler         *authQueries.ListSessionsQueryHandler
+	RevokeSessionHandler        *authCommands.RevokeSessionCommandHandler
+	EnrollMFAHandler            *authCommands.EnrollMFACommandHandler
+	ConfirmMFAHandler           *authCommands.ConfirmMFACommandHandler
+	RegenerateRecoveryHandler   *authCommands.RegenerateRecoveryCodesCommandHandler
+	VerifyMFAHandler            *authCommands.VerifyMFACommandHandler
+	ResetMFAHandler             *authCommands.ResetMFACommandHandler
+	UnlockAccountHandler        *authCommands.UnlockAccountCommandHandler
+	RequestEmailChangeHandler   *authCommands.RequestEmailChangeCommandHandler
+	ConfirmEmailChangeHandler   *authCommands.ConfirmEmailChangeCommandHandler
+	RevertEmailChangeHandler    *authCommands.RevertEmailChangeCommandHandler
+	ImpersonateUserHandler      *authCommands.ImpersonateUserCommandHandler
+	ReauthenticateHandler       *authCommands.ReauthenticateCommandHandler
+	GetLoginHistoryHandler      *authQueries.GetLoginHistoryQueryHandler
+	SecureAccountHandler        *authCommands.SecureAccountCommandHandler
+}
+
+type WebAuthnHandlerParams struct {
+	fx.In
+	BeginRegistrationHandler  *authCommands.BeginPasskeyRegistrationCommandHandler
+	FinishRegistrationHandler *authCommands.FinishPasskeyRegistrationCommandHandler
+	BeginLoginHandler         *authCommands.BeginPasskeyLoginCommandHandler
+	FinishLoginHandler        *authCommands.FinishPasskeyLoginCommandHandler
+	DeletePasskeyHandler      *authCommands.DeletePasskeyCommandHandler
+	ListPasskeysHandler       *authQueries.ListPasskeysQueryHandler
+}
+
+type OIDCHandlerParams struct {
+	fx.In
+	BeginLoginHandler  *authCommands.BeginOIDCLoginCommandHandler
+	FinishLoginHandler *authCommands.FinishOIDCLoginCommandHandler
+}
+
+type MagicLinkHandlerParams struct {
+	fx.In
+	RequestHandler *authCommands.RequestMagicLinkCommandHandler
+	ConsumeHandler *authCommands.ConsumeMagicLinkCommandHandler
+}
+
+type APIKeyHandlerParams struct {
+	fx.In
+	CreateAPIKeyHandler *authCommands.CreateAPIKeyCommandHandler
+	DeleteAPIKeyHandler *authCommands.DeleteAPIKeyCommandHandler
+	ListAPIKeysHandler  *authQueries.ListAPIKeysQueryHandler
+}
+
+type OAuthHandlerParams struct {
+	fx.In
+	IssueClientTokenHandler *authCommands.IssueClientTokenCommandHandler
+	RevokeTokenHandler      *authCommands.RevokeTokenCommandHandler
+	IntrospectTokenHandler  *authQueries.IntrospectTokenQueryHandler
+}
+
+type RBACHandlerParams struct {
+	fx.In
+	CreateRoleHandler           *rbacCommands.CreateRoleCommandHandler
+	UpdateRoleHandler           *rbacCommands.UpdateRoleCommandHandler
+	DeleteRoleHandler           *rbacCommands.DeleteRoleCommandHandler
+	SetRoleParentHandler        *rbacCommands.SetRoleParentCommandHandler
+	CreatePermissionHandler     *rbacCommands.CreatePermissionCommandHandler
+	UpdatePermissionHandler     *rbacCommands.UpdatePermissionCommandHandler
+	DeletePermissionHandler     *rbacCommands.DeletePermissionCommandHandler
+	GrantRolePermissionHandler  *rbacCommands.GrantRolePermissionCommandHandler
+	RevokeRolePermissionHandler *rbacCommands.RevokeRolePermissionCommandHandler
+	AssignUserRoleHandler       *rbacCommands.AssignUserRoleCommandHandler
+	RevokeUserRoleHandler       *rbacCommands.RevokeUserRoleCommandHandler
+	GetRoleHandler              *rbacQueries.GetRoleQueryHandler
+	ListRolesHandler            *rbacQueries.ListRolesQueryHandler
+	GetPermissionHandler        *rbacQueries.GetPermissionQueryHandler
+	ListPermissionsHandler      *rbacQueries.ListPermissionsQueryHandler
+	ListRolePermissionsHandler  *rbacQueries.ListRolePermissionsQueryHandler
+	ListUserRolesHandler        *rbacQueries.ListUserRolesQueryHandler
 }
 
 func NewUserHandler(userService *appservices.UserService, userValidator userValidators.IUserValidator) *v1.UserHandler {
 	return v1.NewUserHandler(userService, userValidator)
 }
 
+func NewJWKSHandler(jwtService jwt.JWTService) *v1.JWKSHandler {
+	return v1.NewJWKSHandler(jwtService)
+}
+
 func NewAuthHandler(params AuthHandlerParams) *v1.AuthHandler {
 	return v1.NewAuthHandler(
 		params.LoginHandler,
@@ -59,5 +145,83 @@ func NewAuthHandler(params AuthHandlerParams) *v1.AuthHandler {
 		params.LogoutAllDevicesHandler,
 		params.GetUserProfileHandler,
 		params.GetUserPermissionsHandler,
+		params.ListSessionsHandler,
+		params.RevokeSessionHandler,
+		params.EnrollMFAHandler,
+		params.ConfirmMFAHandler,
+		params.RegenerateRecoveryHandler,
+		params.VerifyMFAHandler,
+		params.ResetMFAHandler,
+		params.UnlockAccountHandler,
+		params.RequestEmailChangeHandler,
+		params.ConfirmEmailChangeHandler,
+		params.RevertEmailChangeHandler,
+		params.ImpersonateUserHandler,
+		params.ReauthenticateHandler,
+		params.GetLoginHistoryHandler,
+		params.SecureAccountHandler,
+	)
+}
+
+func NewWebAuthnHandler(params WebAuthnHandlerParams) *v1.WebAuthnHandler {
+	return v1.NewWebAuthnHandler(
+		params.BeginRegistrationHandler,
+		params.FinishRegistrationHandler,
+		params.BeginLoginHandler,
+		params.FinishLoginHandler,
+		params.DeletePasskeyHandler,
+		params.ListPasskeysHandler,
+	)
+}
+
+func NewOIDCHandler(params OIDCHandlerParams) *v1.OIDCHandler {
+	return v1.NewOIDCHandler(
+		params.BeginLoginHandler,
+		params.FinishLoginHandler,
+	)
+}
+
+func NewMagicLinkHandler(params MagicLinkHandlerParams) *v1.MagicLinkHandler {
+	return v1.NewMagicLinkHandler(
+		params.RequestHandler,
+		params.ConsumeHandler,
+	)
+}
+
+func NewAPIKeyHandler(params APIKeyHandlerParams) *v1.APIKeyHandler {
+	return v1.NewAPIKeyHandler(
+		params.CreateAPIKeyHandler,
+		params.DeleteAPIKeyHandler,
+		params.ListAPIKeysHandler,
+	)
+}
+
+func NewOAuthHandler(params OAuthHandlerParams) *v1.OAuthHandler {
+	return v1.NewOAuthHandler(
+		params.IssueClientTokenHandler,
+		params.RevokeTokenHandler,
+		params.IntrospectTokenHandler,
+	)
+}
+
+func NewRBACHandler(params RBACHandlerParams) *v1.RBACHandler {
+	return v1.NewRBACHandler(
+		params.CreateRoleHandler,
+		params.UpdateRoleHandler,
+		params.DeleteRoleHandler,
+		params.SetRoleParentHandler,
+		params.CreatePermissionHandler,
+		params.UpdatePermissionHandler,
+		params.DeletePermissionHandler,
+		params.GrantRolePermissionHandler,
+		params.RevokeRolePermissionHandler,
+		params.AssignUserRoleHandler,
+		params.RevokeUserRoleHandler,
+		params.GetRoleHandler,
+		params.ListRolesHandler,
+		params.GetPermissionHandler,
+		params.ListPermissionsHandler,
+		params.ListRolePermissionsHandler,
+		params.ListUserRolesHandler,
 	)
 }
@@ -0,0 +1,93 @@
+package v1
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	authCommands "github.com/tranvuongduy2003/go-mvc/internal/application/commands/auth"
+	dto "github.com/tranvuongduy2003/go-mvc/internal/application/dto/auth"
+	authQueries "github.com/tranvuongduy2003/go-mvc/internal/application/queries/auth"
+	"github.com/tranvuongduy2003/go-mvc/pkg/response"
+)
+
+type APIKeyHandler struct {
+	createAPIKeyHandler *authCommands.CreateAPIKeyCommandHandler
+	deleteAPIKeyHandler *authCommands.DeleteAPIKeyCommandHandler
+	listAPIKeysHandler  *authQueries.ListAPIKeysQueryHandler
+}
+
+func NewAPIKeyHandler(
+	createAPIKeyHandler *authCommands.CreateAPIKeyCommandHandler,
+	deleteAPIKeyHandler *authCommands.DeleteAPIKeyCommandHandler,
+	listAPIKeysHandler *authQueries.ListAPIKeysQueryHandler,
+) *APIKeyHandler {
+	return &APIKeyHandler{
+		createAPIKeyHandler: createAPIKeyHandler,
+		deleteAPIKeyHandler: deleteAPIKeyHandler,
+		listAPIKeysHandler:  listAPIKeysHandler,
+	}
+}
+
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, errors.New("user not authenticated"))
+		return
+	}
+
+	var req dto.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	result, err := h.createAPIKeyHandler.Handle(c.Request.Context(), authCommands.CreateAPIKeyCommand{
+		UserID:    userID.(string),
+		Name:      req.Name,
+		Scopes:    req.Scopes,
+		ExpiresAt: req.ExpiresAt,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "API key created successfully, store it now as it will not be shown again", result)
+}
+
+func (h *APIKeyHandler) ListAPIKeys(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, errors.New("user not authenticated"))
+		return
+	}
+
+	result, err := h.listAPIKeysHandler.Handle(c.Request.Context(), authQueries.ListAPIKeysQuery{
+		UserID: userID.(string),
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "API keys retrieved successfully", result)
+}
+
+func (h *APIKeyHandler) DeleteAPIKey(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, errors.New("user not authenticated"))
+		return
+	}
+
+	result, err := h.deleteAPIKeyHandler.Handle(c.Request.Context(), authCommands.DeleteAPIKeyCommand{
+		UserID:   userID.(string),
+		APIKeyID: c.Param("id"),
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "API key deleted successfully", result)
+}
@@ -3,6 +3,7 @@ package v1
 import (
 	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	authCommands "github.com/tranvuongduy2003/go-mvc/internal/application/commands/auth"
@@ -24,6 +25,21 @@ type AuthHandler struct {
 	logoutAllDevicesHandler     *authCommands.LogoutAllDevicesCommandHandler
 	getUserProfileHandler       *authQueries.GetUserProfileQueryHandler
 	getUserPermissionsHandler   *authQueries.GetUserPermissionsQueryHandler
+	listSessionsHandler         *authQueries.ListSessionsQueryHandler
+	revokeSessionHandler        *authCommands.RevokeSessionCommandHandler
+	enrollMFAHandler            *authCommands.EnrollMFACommandHandler
+	confirmMFAHandler           *authCommands.ConfirmMFACommandHandler
+	regenerateRecoveryHandler   *authCommands.RegenerateRecoveryCodesCommandHandler
+	verifyMFAHandler            *authCommands.VerifyMFACommandHandler
+	resetMFAHandler             *authCommands.ResetMFACommandHandler
+	unlockAccountHandler        *authCommands.UnlockAccountCommandHandler
+	requestEmailChangeHandler   *authCommands.RequestEmailChangeCommandHandler
+	confirmEmailChangeHandler   *authCommands.ConfirmEmailChangeCommandHandler
+	revertEmailChangeHandler    *authCommands.RevertEmailChangeCommandHandler
+	impersonateUserHandler      *authCommands.ImpersonateUserCommandHandler
+	reauthenticateHandler       *authCommands.ReauthenticateCommandHandler
+	getLoginHistoryHandler      *authQueries.GetLoginHistoryQueryHandler
+	secureAccountHandler        *authCommands.SecureAccountCommandHandler
 }
 
 func NewAuthHandler(
@@ -39,6 +55,21 @@ func NewAuthHandler(
 	logoutAllDevicesHandler *authCommands.LogoutAllDevicesCommandHandler,
 	getUserProfileHandler *authQueries.GetUserProfileQueryHandler,
 	getUserPermissionsHandler *authQueries.GetUserPermissionsQueryHandler,
+	listSessionsHandler *authQueries.ListSessionsQueryHandler,
+	revokeSessionHandler *authCommands.RevokeSessionCommandHandler,
+	enrollMFAHandler *authCommands.EnrollMFACommandHandler,
+	confirmMFAHandler *authCommands.ConfirmMFACommandHandler,
+	regenerateRecoveryHandler *authCommands.RegenerateRecoveryCodesCommandHandler,
+	verifyMFAHandler *authCommands.VerifyMFACommandHandler,
+	resetMFAHandler *authCommands.ResetMFACommandHandler,
+	unlockAccountHandler *authCommands.UnlockAccountCommandHandler,
+	requestEmailChangeHandler *authCommands.RequestEmailChangeCommandHandler,
+	confirmEmailChangeHandler *authCommands.ConfirmEmailChangeCommandHandler,
+	revertEmailChangeHandler *authCommands.RevertEmailChangeCommandHandler,
+	impersonateUserHandler *authCommands.ImpersonateUserCommandHandler,
+	reauthenticateHandler *authCommands.ReauthenticateCommandHandler,
+	getLoginHistoryHandler *authQueries.GetLoginHistoryQueryHandler,
+	secureAccountHandler *authCommands.SecureAccountCommandHandler,
 ) *AuthHandler {
 	return &AuthHandler{
 		loginHandler:                loginHandler,
@@ -53,6 +84,21 @@ func NewAuthHandler(
 		logoutAllDevicesHandler:     logoutAllDevicesHandler,
 		getUserProfileHandler:       getUserProfileHandler,
 		getUserPermissionsHandler:   getUserPermissionsHandler,
+		listSessionsHandler:         listSessionsHandler,
+		revokeSessionHandler:        revokeSessionHandler,
+		enrollMFAHandler:            enrollMFAHandler,
+		confirmMFAHandler:           confirmMFAHandler,
+		regenerateRecoveryHandler:   regenerateRecoveryHandler,
+		verifyMFAHandler:            verifyMFAHandler,
+		resetMFAHandler:             resetMFAHandler,
+		unlockAccountHandler:        unlockAccountHandler,
+		requestEmailChangeHandler:   requestEmailChangeHandler,
+		confirmEmailChangeHandler:   confirmEmailChangeHandler,
+		revertEmailChangeHandler:    revertEmailChangeHandler,
+		impersonateUserHandler:      impersonateUserHandler,
+		reauthenticateHandler:       reauthenticateHandler,
+		getLoginHistoryHandler:      getLoginHistoryHandler,
+		secureAccountHandler:        secureAccountHandler,
 	}
 }
 
@@ -64,14 +110,22 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	}
 
 	result, err := h.loginHandler.Handle(c.Request.Context(), authCommands.LoginCommand{
-		Email:    req.Email,
-		Password: req.Password,
+		Email:      req.Email,
+		Password:   req.Password,
+		DeviceName: req.DeviceName,
+		UserAgent:  c.Request.UserAgent(),
+		IPAddress:  c.ClientIP(),
 	})
 	if err != nil {
 		response.Error(c, err)
 		return
 	}
 
+	if result.MFARequired {
+		response.SuccessWithMessage(c, "MFA verification required", result)
+		return
+	}
+
 	response.SuccessWithMessage(c, "Login successful", result)
 }
 
@@ -83,19 +137,27 @@ func (h *AuthHandler) Register(c *gin.Context) {
 	}
 
 	result, err := h.registerHandler.Handle(c.Request.Context(), authCommands.RegisterCommand{
-		Email:    req.Email,
-		Name:     req.Name,
-		Phone:    req.Phone,
-		Password: req.Password,
+		Email:      req.Email,
+		Name:       req.Name,
+		Phone:      req.Phone,
+		Password:   req.Password,
+		DeviceName: req.DeviceName,
+		UserAgent:  c.Request.UserAgent(),
+		IPAddress:  c.ClientIP(),
 	})
 	if err != nil {
 		response.Error(c, err)
 		return
 	}
 
+	message := "Registration successful"
+	if result.VerificationRequired {
+		message = "Registration successful, verify your email address to sign in"
+	}
+
 	c.JSON(http.StatusCreated, gin.H{
 		"success": true,
-		"message": "Registration successful",
+		"message": message,
 		"data":    result,
 	})
 }
@@ -131,8 +193,12 @@ func (h *AuthHandler) ChangePassword(c *gin.Context) {
 		return
 	}
 
+	sessionID, _ := c.Get("session_id")
+	currentSessionID, _ := sessionID.(string)
+
 	result, err := h.changePasswordHandler.Handle(c.Request.Context(), authCommands.ChangePasswordCommand{
 		UserID:      userID.(string),
+		SessionID:   currentSessionID,
 		OldPassword: req.OldPassword,
 		NewPassword: req.NewPassword,
 	})
@@ -144,6 +210,39 @@ func (h *AuthHandler) ChangePassword(c *gin.Context) {
 	response.SuccessWithMessage(c, "Password changed successfully", result)
 }
 
+// Reauthenticate confirms the user's identity for the current session and
+// returns an access token that passes the recent-authentication check.
+func (h *AuthHandler) Reauthenticate(c *gin.Context) {
+	var req dto.ReauthenticateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, errors.New("user not authenticated"))
+		return
+	}
+	sessionID, _ := c.Get("session_id")
+	currentSessionID, _ := sessionID.(string)
+
+	result, err := h.reauthenticateHandler.Handle(c.Request.Context(), authCommands.ReauthenticateCommand{
+		UserID:    userID.(string),
+		SessionID: currentSessionID,
+		Password:  req.Password,
+		Code:      req.Code,
+		UserAgent: c.Request.UserAgent(),
+		IPAddress: c.ClientIP(),
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "Re-authenticated successfully", result)
+}
+
 func (h *AuthHandler) ResetPassword(c *gin.Context) {
 	var req dto.ResetPasswordRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -199,6 +298,68 @@ func (h *AuthHandler) VerifyEmail(c *gin.Context) {
 	response.SuccessWithMessage(c, "Email verified successfully", result)
 }
 
+func (h *AuthHandler) RequestEmailChange(c *gin.Context) {
+	var req dto.ChangeEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, errors.New("user not authenticated"))
+		return
+	}
+
+	result, err := h.requestEmailChangeHandler.Handle(c.Request.Context(), authCommands.RequestEmailChangeCommand{
+		UserID:   userID.(string),
+		NewEmail: req.NewEmail,
+		Password: req.Password,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "Email change requested", result)
+}
+
+func (h *AuthHandler) ConfirmEmailChange(c *gin.Context) {
+	var req dto.EmailChangeTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	result, err := h.confirmEmailChangeHandler.Handle(c.Request.Context(), authCommands.ConfirmEmailChangeCommand{
+		Token: req.Token,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "Email changed successfully", result)
+}
+
+func (h *AuthHandler) RevertEmailChange(c *gin.Context) {
+	var req dto.EmailChangeTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	result, err := h.revertEmailChangeHandler.Handle(c.Request.Context(), authCommands.RevertEmailChangeCommand{
+		Token: req.Token,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "Email change reverted", result)
+}
+
 func (h *AuthHandler) ResendVerificationEmail(c *gin.Context) {
 	var req dto.ResendVerificationRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -224,8 +385,15 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 		return
 	}
 
+	accessToken, exists := c.Get("access_token")
+	if !exists {
+		response.Error(c, errors.New("user not authenticated"))
+		return
+	}
+
 	result, err := h.logoutHandler.Handle(c.Request.Context(), authCommands.LogoutCommand{
-		UserID: userID.(string),
+		UserID:      userID.(string),
+		AccessToken: accessToken.(string),
 	})
 	if err != nil {
 		response.Error(c, err)
@@ -260,8 +428,12 @@ func (h *AuthHandler) GetProfile(c *gin.Context) {
 		return
 	}
 
+	impersonatorID, _ := c.Get("impersonator_id")
+	actorID, _ := impersonatorID.(string)
+
 	result, err := h.getUserProfileHandler.Handle(c.Request.Context(), authQueries.GetUserProfileQuery{
-		UserID: userID.(string),
+		UserID:         userID.(string),
+		ImpersonatorID: actorID,
 	})
 	if err != nil {
 		response.Error(c, err)
@@ -288,3 +460,216 @@ func (h *AuthHandler) GetPermissions(c *gin.Context) {
 
 	response.SuccessWithMessage(c, "Permissions retrieved successfully", result)
 }
+
+func (h *AuthHandler) ListSessions(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, errors.New("user not authenticated"))
+		return
+	}
+
+	sessionID, _ := c.Get("session_id")
+	currentSessionID, _ := sessionID.(string)
+
+	result, err := h.listSessionsHandler.Handle(c.Request.Context(), authQueries.ListSessionsQuery{
+		UserID:           userID.(string),
+		CurrentSessionID: currentSessionID,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "Sessions retrieved successfully", result)
+}
+
+func (h *AuthHandler) GetLoginHistory(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, errors.New("user not authenticated"))
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	result, err := h.getLoginHistoryHandler.Handle(c.Request.Context(), authQueries.GetLoginHistoryQuery{
+		UserID: userID.(string),
+		Limit:  limit,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "Login history retrieved successfully", result)
+}
+
+// SecureAccount handles the "this wasn't me" link of a login alert.
+func (h *AuthHandler) SecureAccount(c *gin.Context) {
+	var req dto.SecureAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	result, err := h.secureAccountHandler.Handle(c.Request.Context(), authCommands.SecureAccountCommand{
+		Token: req.Token,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "Account secured", result)
+}
+
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, errors.New("user not authenticated"))
+		return
+	}
+
+	result, err := h.revokeSessionHandler.Handle(c.Request.Context(), authCommands.RevokeSessionCommand{
+		UserID:    userID.(string),
+		SessionID: c.Param("id"),
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "Session revoked successfully", result)
+}
+
+func (h *AuthHandler) EnrollMFA(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, errors.New("user not authenticated"))
+		return
+	}
+
+	result, err := h.enrollMFAHandler.Handle(c.Request.Context(), authCommands.EnrollMFACommand{
+		UserID: userID.(string),
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "MFA enrollment started", result)
+}
+
+func (h *AuthHandler) ConfirmMFA(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, errors.New("user not authenticated"))
+		return
+	}
+
+	var req dto.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	result, err := h.confirmMFAHandler.Handle(c.Request.Context(), authCommands.ConfirmMFACommand{
+		UserID: userID.(string),
+		Code:   req.Code,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "MFA enabled successfully", result)
+}
+
+func (h *AuthHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, errors.New("user not authenticated"))
+		return
+	}
+
+	var req dto.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	result, err := h.regenerateRecoveryHandler.Handle(c.Request.Context(), authCommands.RegenerateRecoveryCodesCommand{
+		UserID: userID.(string),
+		Code:   req.Code,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "Recovery codes regenerated successfully", result)
+}
+
+func (h *AuthHandler) VerifyMFA(c *gin.Context) {
+	var req dto.VerifyMFARequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	result, err := h.verifyMFAHandler.Handle(c.Request.Context(), authCommands.VerifyMFACommand{
+		ChallengeToken: req.ChallengeToken,
+		Code:           req.Code,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "Login successful", result)
+}
+
+func (h *AuthHandler) ResetUserMFA(c *gin.Context) {
+	result, err := h.resetMFAHandler.Handle(c.Request.Context(), authCommands.ResetMFACommand{
+		UserID: c.Param("id"),
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "MFA reset successfully", result)
+}
+
+func (h *AuthHandler) UnlockUser(c *gin.Context) {
+	result, err := h.unlockAccountHandler.Handle(c.Request.Context(), authCommands.UnlockAccountCommand{
+		UserID: c.Param("id"),
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "Account unlocked successfully", result)
+}
+
+func (h *AuthHandler) ImpersonateUser(c *gin.Context) {
+	adminID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, errors.New("user not authenticated"))
+		return
+	}
+
+	result, err := h.impersonateUserHandler.Handle(c.Request.Context(), authCommands.ImpersonateUserCommand{
+		AdminID:   adminID.(string),
+		UserID:    c.Param("id"),
+		UserAgent: c.Request.UserAgent(),
+		IPAddress: c.ClientIP(),
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "Impersonation started", result)
+}
@@ -0,0 +1,25 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tranvuongduy2003/go-mvc/pkg/jwt"
+)
+
+type JWKSHandler struct {
+	jwtService jwt.JWTService
+}
+
+func NewJWKSHandler(jwtService jwt.JWTService) *JWKSHandler {
+	return &JWKSHandler{
+		jwtService: jwtService,
+	}
+}
+
+// GetJWKS serves the raw key set, since verifiers expect the RFC 7517 document
+// rather than the usual response envelope.
+func (h *JWKSHandler) GetJWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.jwtService.JWKS())
+}
@@ -0,0 +1,89 @@
+package v1
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	authCommands "github.com/tranvuongduy2003/go-mvc/internal/application/commands/auth"
+	dto "github.com/tranvuongduy2003/go-mvc/internal/application/dto/auth"
+	apperrors "github.com/tranvuongduy2003/go-mvc/pkg/errors"
+	"github.com/tranvuongduy2003/go-mvc/pkg/response"
+)
+
+// magicLinkNonceCookie binds a sign-in link to the browser that requested
+// it; the emailed token is useless without it.
+const magicLinkNonceCookie = "magic_link_nonce"
+
+type MagicLinkHandler struct {
+	requestHandler *authCommands.RequestMagicLinkCommandHandler
+	consumeHandler *authCommands.ConsumeMagicLinkCommandHandler
+}
+
+func NewMagicLinkHandler(
+	requestHandler *authCommands.RequestMagicLinkCommandHandler,
+	consumeHandler *authCommands.ConsumeMagicLinkCommandHandler,
+) *MagicLinkHandler {
+	return &MagicLinkHandler{
+		requestHandler: requestHandler,
+		consumeHandler: consumeHandler,
+	}
+}
+
+func (h *MagicLinkHandler) Request(c *gin.Context) {
+	var req dto.MagicLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	result, err := h.requestHandler.Handle(c.Request.Context(), authCommands.RequestMagicLinkCommand{
+		Email: req.Email,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	maxAge := int(time.Until(result.ExpiresAt).Seconds())
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(magicLinkNonceCookie, result.Nonce, maxAge, "/api/v1/auth/magic-link", "", c.Request.TLS != nil, true)
+
+	response.SuccessWithMessage(c, "If the email is registered, a sign-in link has been sent", &dto.StatusResponse{
+		Status:  "success",
+		Message: "Check your email for a sign-in link",
+	})
+}
+
+func (h *MagicLinkHandler) Consume(c *gin.Context) {
+	var req dto.ConsumeMagicLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	nonce, err := c.Cookie(magicLinkNonceCookie)
+	if err != nil || nonce == "" {
+		response.Error(c, apperrors.NewUnauthorizedError("sign-in link must be opened in the browser that requested it"))
+		return
+	}
+
+	result, err := h.consumeHandler.Handle(c.Request.Context(), authCommands.ConsumeMagicLinkCommand{
+		Token:     req.Token,
+		Nonce:     nonce,
+		UserAgent: c.Request.UserAgent(),
+		IPAddress: c.ClientIP(),
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.SetCookie(magicLinkNonceCookie, "", -1, "/api/v1/auth/magic-link", "", c.Request.TLS != nil, true)
+
+	if result.MFARequired {
+		response.SuccessWithMessage(c, "MFA verification required", result)
+		return
+	}
+
+	response.SuccessWithMessage(c, "Login successful", result)
+}
@@ -0,0 +1,141 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+	authCommands "github.com/tranvuongduy2003/go-mvc/internal/application/commands/auth"
+	authQueries "github.com/tranvuongduy2003/go-mvc/internal/application/queries/auth"
+	"github.com/tranvuongduy2003/go-mvc/pkg/oauth"
+	"github.com/tranvuongduy2003/go-mvc/pkg/response"
+)
+
+// OAuthHandler serves the OAuth 2.0 endpoints. They speak the RFC 6749
+// wire format (form encoded requests, bare JSON responses) rather than the
+// API's response envelope so standard client libraries work unchanged.
+type OAuthHandler struct {
+	issueClientTokenHandler *authCommands.IssueClientTokenCommandHandler
+	revokeTokenHandler      *authCommands.RevokeTokenCommandHandler
+	introspectTokenHandler  *authQueries.IntrospectTokenQueryHandler
+}
+
+func NewOAuthHandler(
+	issueClientTokenHandler *authCommands.IssueClientTokenCommandHandler,
+	revokeTokenHandler *authCommands.RevokeTokenCommandHandler,
+	introspectTokenHandler *authQueries.IntrospectTokenQueryHandler,
+) *OAuthHandler {
+	return &OAuthHandler{
+		issueClientTokenHandler: issueClientTokenHandler,
+		revokeTokenHandler:      revokeTokenHandler,
+		introspectTokenHandler:  introspectTokenHandler,
+	}
+}
+
+func (h *OAuthHandler) Token(c *gin.Context) {
+	clientID, clientSecret, err := clientCredentials(c)
+	if err != nil {
+		writeOAuthError(c, err)
+		return
+	}
+
+	result, err := h.issueClientTokenHandler.Handle(c.Request.Context(), authCommands.IssueClientTokenCommand{
+		GrantType:    c.PostForm("grant_type"),
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Scope:        c.PostForm("scope"),
+	})
+	if err != nil {
+		writeOAuthError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Header("Pragma", "no-cache")
+	c.JSON(http.StatusOK, result)
+}
+
+// Introspect implements RFC 7662 token introspection.
+func (h *OAuthHandler) Introspect(c *gin.Context) {
+	clientID, clientSecret, err := clientCredentials(c)
+	if err != nil {
+		writeOAuthError(c, err)
+		return
+	}
+
+	result, err := h.introspectTokenHandler.Handle(c.Request.Context(), authQueries.IntrospectTokenQuery{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Token:        c.PostForm("token"),
+	})
+	if err != nil {
+		writeOAuthError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, result)
+}
+
+// Revoke implements RFC 7009 token revocation. It answers 200 with an empty
+// body whether or not the token was still valid.
+func (h *OAuthHandler) Revoke(c *gin.Context) {
+	clientID, clientSecret, err := clientCredentials(c)
+	if err != nil {
+		writeOAuthError(c, err)
+		return
+	}
+
+	err = h.revokeTokenHandler.Handle(c.Request.Context(), authCommands.RevokeTokenCommand{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Token:        c.PostForm("token"),
+	})
+	if err != nil {
+		writeOAuthError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// clientCredentials reads the client authentication of a request, either
+// client_secret_basic or client_secret_post (RFC 6749 section 2.3.1). Using
+// both at once is rejected.
+func clientCredentials(c *gin.Context) (string, string, error) {
+	formID, formSecret := c.PostForm("client_id"), c.PostForm("client_secret")
+
+	basicID, basicSecret, hasBasic := c.Request.BasicAuth()
+	if hasBasic {
+		if formSecret != "" {
+			return "", "", oauth.NewError(oauth.ErrorInvalidRequest, "multiple client authentication methods used")
+		}
+		clientID, errID := url.QueryUnescape(basicID)
+		clientSecret, errSecret := url.QueryUnescape(basicSecret)
+		if errID != nil || errSecret != nil {
+			return "", "", oauth.NewError(oauth.ErrorInvalidClient, "malformed client credentials")
+		}
+		return clientID, clientSecret, nil
+	}
+
+	if formID == "" || formSecret == "" {
+		return "", "", oauth.NewError(oauth.ErrorInvalidClient, "client authentication is required")
+	}
+	return formID, formSecret, nil
+}
+
+func writeOAuthError(c *gin.Context, err error) {
+	var oauthErr *oauth.Error
+	if !errors.As(err, &oauthErr) {
+		response.Error(c, err)
+		return
+	}
+
+	if oauthErr.Code == oauth.ErrorInvalidClient {
+		c.Header("WWW-Authenticate", `Basic realm="oauth"`)
+	}
+	c.Header("Cache-Control", "no-store")
+	c.Header("Pragma", "no-cache")
+	c.AbortWithStatusJSON(oauthErr.StatusCode(), oauthErr)
+}
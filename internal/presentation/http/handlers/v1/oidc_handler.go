@@ -0,0 +1,84 @@
+package v1
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	authCommands "github.com/tranvuongduy2003/go-mvc/internal/application/commands/auth"
+	apperrors "github.com/tranvuongduy2003/go-mvc/pkg/errors"
+	"github.com/tranvuongduy2003/go-mvc/pkg/response"
+)
+
+// oidcStateCookie binds the authorization request to the browser that
+// started it, so a callback URL cannot be replayed in another session.
+const oidcStateCookie = "oidc_state"
+
+type OIDCHandler struct {
+	beginLoginHandler  *authCommands.BeginOIDCLoginCommandHandler
+	finishLoginHandler *authCommands.FinishOIDCLoginCommandHandler
+}
+
+func NewOIDCHandler(
+	beginLoginHandler *authCommands.BeginOIDCLoginCommandHandler,
+	finishLoginHandler *authCommands.FinishOIDCLoginCommandHandler,
+) *OIDCHandler {
+	return &OIDCHandler{
+		beginLoginHandler:  beginLoginHandler,
+		finishLoginHandler: finishLoginHandler,
+	}
+}
+
+func (h *OIDCHandler) Login(c *gin.Context) {
+	authorization, err := h.beginLoginHandler.Handle(c.Request.Context(), authCommands.BeginOIDCLoginCommand{
+		Provider: c.Param("provider"),
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	maxAge := int(time.Until(authorization.ExpiresAt).Seconds())
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcStateCookie, authorization.State, maxAge, "/api/v1/auth/oidc", "", c.Request.TLS != nil, true)
+
+	c.Redirect(http.StatusFound, authorization.URL)
+}
+
+func (h *OIDCHandler) Callback(c *gin.Context) {
+	if errorCode := c.Query("error"); errorCode != "" {
+		message := c.Query("error_description")
+		if message == "" {
+			message = errorCode
+		}
+		response.Error(c, apperrors.NewUnauthorizedError("identity provider denied the login: "+message))
+		return
+	}
+
+	state := c.Query("state")
+	cookieState, err := c.Cookie(oidcStateCookie)
+	if err != nil || cookieState == "" || cookieState != state {
+		response.Error(c, apperrors.NewUnauthorizedError("login state is invalid or has expired"))
+		return
+	}
+	c.SetCookie(oidcStateCookie, "", -1, "/api/v1/auth/oidc", "", c.Request.TLS != nil, true)
+
+	result, err := h.finishLoginHandler.Handle(c.Request.Context(), authCommands.FinishOIDCLoginCommand{
+		Provider:  c.Param("provider"),
+		State:     state,
+		Code:      c.Query("code"),
+		UserAgent: c.Request.UserAgent(),
+		IPAddress: c.ClientIP(),
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	if result.MFARequired {
+		response.SuccessWithMessage(c, "MFA verification required", result)
+		return
+	}
+
+	response.SuccessWithMessage(c, "Login successful", result)
+}
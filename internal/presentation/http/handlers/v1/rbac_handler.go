@@ -0,0 +1,408 @@
+package v1
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	rbacCommands "github.com/tranvuongduy2003/go-mvc/internal/application/commands/rbac"
+	dto "github.com/tranvuongduy2003/go-mvc/internal/application/dto/rbac"
+	rbacQueries "github.com/tranvuongduy2003/go-mvc/internal/application/queries/rbac"
+	"github.com/tranvuongduy2003/go-mvc/pkg/response"
+)
+
+// RBACHandler serves the admin API for roles, permissions and their
+// assignments.
+type RBACHandler struct {
+	createRoleHandler           *rbacCommands.CreateRoleCommandHandler
+	updateRoleHandler           *rbacCommands.UpdateRoleCommandHandler
+	deleteRoleHandler           *rbacCommands.DeleteRoleCommandHandler
+	setRoleParentHandler        *rbacCommands.SetRoleParentCommandHandler
+	createPermissionHandler     *rbacCommands.CreatePermissionCommandHandler
+	updatePermissionHandler     *rbacCommands.UpdatePermissionCommandHandler
+	deletePermissionHandler     *rbacCommands.DeletePermissionCommandHandler
+	grantRolePermissionHandler  *rbacCommands.GrantRolePermissionCommandHandler
+	revokeRolePermissionHandler *rbacCommands.RevokeRolePermissionCommandHandler
+	assignUserRoleHandler       *rbacCommands.AssignUserRoleCommandHandler
+	revokeUserRoleHandler       *rbacCommands.RevokeUserRoleCommandHandler
+	getRoleHandler              *rbacQueries.GetRoleQueryHandler
+	listRolesHandler            *rbacQueries.ListRolesQueryHandler
+	getPermissionHandler        *rbacQueries.GetPermissionQueryHandler
+	listPermissionsHandler      *rbacQueries.ListPermissionsQueryHandler
+	listRolePermissionsHandler  *rbacQueries.ListRolePermissionsQueryHandler
+	listUserRolesHandler        *rbacQueries.ListUserRolesQueryHandler
+}
+
+func NewRBACHandler(
+	createRoleHandler *rbacCommands.CreateRoleCommandHandler,
+	updateRoleHandler *rbacCommands.UpdateRoleCommandHandler,
+	deleteRoleHandler *rbacCommands.DeleteRoleCommandHandler,
+	setRoleParentHandler *rbacCommands.SetRoleParentCommandHandler,
+	createPermissionHandler *rbacCommands.CreatePermissionCommandHandler,
+	updatePermissionHandler *rbacCommands.UpdatePermissionCommandHandler,
+	deletePermissionHandler *rbacCommands.DeletePermissionCommandHandler,
+	grantRolePermissionHandler *rbacCommands.GrantRolePermissionCommandHandler,
+	revokeRolePermissionHandler *rbacCommands.RevokeRolePermissionCommandHandler,
+	assignUserRoleHandler *rbacCommands.AssignUserRoleCommandHandler,
+	revokeUserRoleHandler *rbacCommands.RevokeUserRoleCommandHandler,
+	getRoleHandler *rbacQueries.GetRoleQueryHandler,
+	listRolesHandler *rbacQueries.ListRolesQueryHandler,
+	getPermissionHandler *rbacQueries.GetPermissionQueryHandler,
+	listPermissionsHandler *rbacQueries.ListPermissionsQueryHandler,
+	listRolePermissionsHandler *rbacQueries.ListRolePermissionsQueryHandler,
+	listUserRolesHandler *rbacQueries.ListUserRolesQueryHandler,
+) *RBACHandler {
+	return &RBACHandler{
+		createRoleHandler:           createRoleHandler,
+		updateRoleHandler:           updateRoleHandler,
+		deleteRoleHandler:           deleteRoleHandler,
+		setRoleParentHandler:        setRoleParentHandler,
+		createPermissionHandler:     createPermissionHandler,
+		updatePermissionHandler:     updatePermissionHandler,
+		deletePermissionHandler:     deletePermissionHandler,
+		grantRolePermissionHandler:  grantRolePermissionHandler,
+		revokeRolePermissionHandler: revokeRolePermissionHandler,
+		assignUserRoleHandler:       assignUserRoleHandler,
+		revokeUserRoleHandler:       revokeUserRoleHandler,
+		getRoleHandler:              getRoleHandler,
+		listRolesHandler:            listRolesHandler,
+		getPermissionHandler:        getPermissionHandler,
+		listPermissionsHandler:      listPermissionsHandler,
+		listRolePermissionsHandler:  listRolePermissionsHandler,
+		listUserRolesHandler:        listUserRolesHandler,
+	}
+}
+
+func (h *RBACHandler) ListRoles(c *gin.Context) {
+	page, limit := pageParams(c)
+
+	roles, pag, err := h.listRolesHandler.Handle(c.Request.Context(), rbacQueries.ListRolesQuery{
+		Page:     page,
+		Limit:    limit,
+		Search:   c.Query("search"),
+		SortBy:   c.Query("sort_by"),
+		SortDir:  c.Query("sort_dir"),
+		IsActive: boolQuery(c, "is_active"),
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithPagination(c, roles, pag)
+}
+
+func (h *RBACHandler) GetRole(c *gin.Context) {
+	role, err := h.getRoleHandler.Handle(c.Request.Context(), rbacQueries.GetRoleQuery{
+		RoleID: c.Param("id"),
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, role)
+}
+
+func (h *RBACHandler) CreateRole(c *gin.Context) {
+	var req dto.CreateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	role, err := h.createRoleHandler.Handle(c.Request.Context(), rbacCommands.CreateRoleCommand{
+		Name:        req.Name,
+		Description: req.Description,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Created(c, role)
+}
+
+func (h *RBACHandler) UpdateRole(c *gin.Context) {
+	var req dto.UpdateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	role, err := h.updateRoleHandler.Handle(c.Request.Context(), rbacCommands.UpdateRoleCommand{
+		RoleID:      c.Param("id"),
+		Description: req.Description,
+		IsActive:    req.IsActive,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "Role updated successfully", role)
+}
+
+func (h *RBACHandler) DeleteRole(c *gin.Context) {
+	err := h.deleteRoleHandler.Handle(c.Request.Context(), rbacCommands.DeleteRoleCommand{
+		RoleID: c.Param("id"),
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "Role deleted successfully", nil)
+}
+
+func (h *RBACHandler) SetRoleParent(c *gin.Context) {
+	var req dto.SetRoleParentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	role, err := h.setRoleParentHandler.Handle(c.Request.Context(), rbacCommands.SetRoleParentCommand{
+		RoleID:   c.Param("id"),
+		ParentID: &req.ParentID,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "Role parent updated successfully", role)
+}
+
+func (h *RBACHandler) RemoveRoleParent(c *gin.Context) {
+	role, err := h.setRoleParentHandler.Handle(c.Request.Context(), rbacCommands.SetRoleParentCommand{
+		RoleID: c.Param("id"),
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "Role parent removed successfully", role)
+}
+
+func (h *RBACHandler) ListRolePermissions(c *gin.Context) {
+	page, limit := pageParams(c)
+
+	grants, pag, err := h.listRolePermissionsHandler.Handle(c.Request.Context(), rbacQueries.ListRolePermissionsQuery{
+		RoleID:   c.Param("id"),
+		Page:     page,
+		Limit:    limit,
+		Resource: c.Query("resource"),
+		Action:   c.Query("action"),
+		IsActive: boolQuery(c, "is_active"),
+		SortBy:   c.Query("sort_by"),
+		SortDir:  c.Query("sort_dir"),
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithPagination(c, grants, pag)
+}
+
+func (h *RBACHandler) GrantRolePermission(c *gin.Context) {
+	var req dto.GrantPermissionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	grant, err := h.grantRolePermissionHandler.Handle(c.Request.Context(), rbacCommands.GrantRolePermissionCommand{
+		RoleID:       c.Param("id"),
+		PermissionID: req.PermissionID,
+		GrantedBy:    actorID(c),
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Created(c, grant)
+}
+
+func (h *RBACHandler) RevokeRolePermission(c *gin.Context) {
+	err := h.revokeRolePermissionHandler.Handle(c.Request.Context(), rbacCommands.RevokeRolePermissionCommand{
+		RoleID:       c.Param("id"),
+		PermissionID: c.Param("permissionId"),
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "Permission revoked from role successfully", nil)
+}
+
+func (h *RBACHandler) ListRoleUsers(c *gin.Context) {
+	h.listUserRoles(c, rbacQueries.ListUserRolesQuery{RoleID: c.Param("id")})
+}
+
+func (h *RBACHandler) ListPermissions(c *gin.Context) {
+	page, limit := pageParams(c)
+
+	permissions, pag, err := h.listPermissionsHandler.Handle(c.Request.Context(), rbacQueries.ListPermissionsQuery{
+		Page:     page,
+		Limit:    limit,
+		Search:   c.Query("search"),
+		SortBy:   c.Query("sort_by"),
+		SortDir:  c.Query("sort_dir"),
+		Resource: c.Query("resource"),
+		Action:   c.Query("action"),
+		IsActive: boolQuery(c, "is_active"),
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithPagination(c, permissions, pag)
+}
+
+func (h *RBACHandler) GetPermission(c *gin.Context) {
+	permission, err := h.getPermissionHandler.Handle(c.Request.Context(), rbacQueries.GetPermissionQuery{
+		PermissionID: c.Param("id"),
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, permission)
+}
+
+func (h *RBACHandler) CreatePermission(c *gin.Context) {
+	var req dto.CreatePermissionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	permission, err := h.createPermissionHandler.Handle(c.Request.Context(), rbacCommands.CreatePermissionCommand{
+		Resource:    req.Resource,
+		Action:      req.Action,
+		Description: req.Description,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Created(c, permission)
+}
+
+func (h *RBACHandler) UpdatePermission(c *gin.Context) {
+	var req dto.UpdatePermissionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	permission, err := h.updatePermissionHandler.Handle(c.Request.Context(), rbacCommands.UpdatePermissionCommand{
+		PermissionID: c.Param("id"),
+		Description:  req.Description,
+		IsActive:     req.IsActive,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "Permission updated successfully", permission)
+}
+
+func (h *RBACHandler) DeletePermission(c *gin.Context) {
+	err := h.deletePermissionHandler.Handle(c.Request.Context(), rbacCommands.DeletePermissionCommand{
+		PermissionID: c.Param("id"),
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "Permission deleted successfully", nil)
+}
+
+func (h *RBACHandler) ListUserRoles(c *gin.Context) {
+	h.listUserRoles(c, rbacQueries.ListUserRolesQuery{UserID: c.Param("id")})
+}
+
+func (h *RBACHandler) AssignUserRole(c *gin.Context) {
+	var req dto.AssignRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	assignment, err := h.assignUserRoleHandler.Handle(c.Request.Context(), rbacCommands.AssignUserRoleCommand{
+		UserID:     c.Param("id"),
+		RoleID:     req.RoleID,
+		AssignedBy: actorID(c),
+		ExpiresAt:  req.ExpiresAt,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "Role assigned successfully", assignment)
+}
+
+func (h *RBACHandler) RevokeUserRole(c *gin.Context) {
+	err := h.revokeUserRoleHandler.Handle(c.Request.Context(), rbacCommands.RevokeUserRoleCommand{
+		UserID: c.Param("id"),
+		RoleID: c.Param("roleId"),
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "Role revoked from user successfully", nil)
+}
+
+func (h *RBACHandler) listUserRoles(c *gin.Context, query rbacQueries.ListUserRolesQuery) {
+	query.Page, query.Limit = pageParams(c)
+	query.IsActive = boolQuery(c, "is_active")
+	query.IsExpired = boolQuery(c, "is_expired")
+	query.SortBy = c.Query("sort_by")
+	query.SortDir = c.Query("sort_dir")
+
+	assignments, pag, err := h.listUserRolesHandler.Handle(c.Request.Context(), query)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithPagination(c, assignments, pag)
+}
+
+func pageParams(c *gin.Context) (int, int) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	return page, limit
+}
+
+// boolQuery returns nil when the filter is absent or not a boolean.
+func boolQuery(c *gin.Context, name string) *bool {
+	value, err := strconv.ParseBool(c.Query(name))
+	if err != nil {
+		return nil
+	}
+	return &value
+}
+
+// actorID is the admin making the change, or nil for client credentials
+// tokens, which act without a user.
+func actorID(c *gin.Context) *string {
+	userID, _ := c.Get("user_id")
+	if id, ok := userID.(string); ok && id != "" {
+		return &id
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package v1
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	authCommands "github.com/tranvuongduy2003/go-mvc/internal/application/commands/auth"
+	dto "github.com/tranvuongduy2003/go-mvc/internal/application/dto/auth"
+	authQueries "github.com/tranvuongduy2003/go-mvc/internal/application/queries/auth"
+	"github.com/tranvuongduy2003/go-mvc/pkg/response"
+)
+
+type WebAuthnHandler struct {
+	beginRegistrationHandler  *authCommands.BeginPasskeyRegistrationCommandHandler
+	finishRegistrationHandler *authCommands.FinishPasskeyRegistrationCommandHandler
+	beginLoginHandler         *authCommands.BeginPasskeyLoginCommandHandler
+	finishLoginHandler        *authCommands.FinishPasskeyLoginCommandHandler
+	deletePasskeyHandler      *authCommands.DeletePasskeyCommandHandler
+	listPasskeysHandler       *authQueries.ListPasskeysQueryHandler
+}
+
+func NewWebAuthnHandler(
+	beginRegistrationHandler *authCommands.BeginPasskeyRegistrationCommandHandler,
+	finishRegistrationHandler *authCommands.FinishPasskeyRegistrationCommandHandler,
+	beginLoginHandler *authCommands.BeginPasskeyLoginCommandHandler,
+	finishLoginHandler *authCommands.FinishPasskeyLoginCommandHandler,
+	deletePasskeyHandler *authCommands.DeletePasskeyCommandHandler,
+	listPasskeysHandler *authQueries.ListPasskeysQueryHandler,
+) *WebAuthnHandler {
+	return &WebAuthnHandler{
+		beginRegistrationHandler:  beginRegistrationHandler,
+		finishRegistrationHandler: finishRegistrationHandler,
+		beginLoginHandler:         beginLoginHandler,
+		finishLoginHandler:        finishLoginHandler,
+		deletePasskeyHandler:      deletePasskeyHandler,
+		listPasskeysHandler:       listPasskeysHandler,
+	}
+}
+
+func (h *WebAuthnHandler) BeginRegistration(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, errors.New("user not authenticated"))
+		return
+	}
+
+	result, err := h.beginRegistrationHandler.Handle(c.Request.Context(), authCommands.BeginPasskeyRegistrationCommand{
+		UserID: userID.(string),
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "Passkey registration started", result)
+}
+
+func (h *WebAuthnHandler) FinishRegistration(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, errors.New("user not authenticated"))
+		return
+	}
+
+	var req dto.FinishPasskeyRegistrationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	result, err := h.finishRegistrationHandler.Handle(c.Request.Context(), authCommands.FinishPasskeyRegistrationCommand{
+		UserID:     userID.(string),
+		Name:       req.Name,
+		Credential: req.Credential,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "Passkey registered successfully", result)
+}
+
+func (h *WebAuthnHandler) BeginLogin(c *gin.Context) {
+	var req dto.BeginPasskeyLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	result, err := h.beginLoginHandler.Handle(c.Request.Context(), authCommands.BeginPasskeyLoginCommand{
+		Email: req.Email,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "Passkey login started", result)
+}
+
+func (h *WebAuthnHandler) FinishLogin(c *gin.Context) {
+	var req dto.FinishPasskeyLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	result, err := h.finishLoginHandler.Handle(c.Request.Context(), authCommands.FinishPasskeyLoginCommand{
+		Credential: req.Credential,
+		DeviceName: req.DeviceName,
+		UserAgent:  c.Request.UserAgent(),
+		IPAddress:  c.ClientIP(),
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "Login successful", result)
+}
+
+func (h *WebAuthnHandler) ListPasskeys(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, errors.New("user not authenticated"))
+		return
+	}
+
+	result, err := h.listPasskeysHandler.Handle(c.Request.Context(), authQueries.ListPasskeysQuery{
+		UserID: userID.(string),
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "Passkeys retrieved successfully", result)
+}
+
+func (h *WebAuthnHandler) DeletePasskey(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, errors.New("user not authenticated"))
+		return
+	}
+
+	result, err := h.deletePasskeyHandler.Handle(c.Request.Context(), authCommands.DeletePasskeyCommand{
+		UserID:    userID.(string),
+		PasskeyID: c.Param("id"),
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "Passkey deleted successfully", result)
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/auth"
 	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
 	apperrors "github.com/tranvuongduy2003/go-mvc/pkg/errors"
 )
@@ -15,9 +16,35 @@ const (
 
 	UserIDContextKey = "user_id"
 
+	AccessTokenContextKey = "access_token"
+
+	SessionIDContextKey = "session_id"
+
+	AuthTypeContextKey = "auth_type"
+
+	APIKeyContextKey = "api_key"
+
+	ClientIDContextKey = "client_id"
+
+	ScopesContextKey = "scopes"
+
+	ImpersonatorIDContextKey = "impersonator_id"
+
+	TokenAuthorizationContextKey = "token_authorization"
+
+	AuthTimeContextKey = "auth_time"
+
+	AuthMethodsContextKey = "auth_methods"
+
+	// ReauthenticationRequiredCode tells clients to call /auth/reauthenticate
+	// and retry with the elevated token.
+	ReauthenticationRequiredCode = "reauthentication_required"
+
 	AuthorizationHeader = "Authorization"
 
 	BearerPrefix = "Bearer "
+
+	APIKeyHeader = "X-API-Key"
 )
 
 type ErrorResponse struct {
@@ -33,15 +60,19 @@ type ErrorInfo struct {
 }
 
 type AuthMiddleware struct {
-	authService contracts.AuthService
+	authService          contracts.AuthService
+	impersonationService contracts.ImpersonationService
 }
 
-func NewAuthMiddleware(authService contracts.AuthService) *AuthMiddleware {
+func NewAuthMiddleware(authService contracts.AuthService, impersonationService contracts.ImpersonationService) *AuthMiddleware {
 	return &AuthMiddleware{
-		authService: authService,
+		authService:          authService,
+		impersonationService: impersonationService,
 	}
 }
 
+// RequireAuth admits user access tokens only. Client credentials tokens
+// have no user behind them; routes that serve machines use FlexibleAuth.
 func (m *AuthMiddleware) RequireAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		token, err := m.extractTokenFromHeader(c)
@@ -50,14 +81,22 @@ func (m *AuthMiddleware) RequireAuth() gin.HandlerFunc {
 			return
 		}
 
-		user, err := m.authService.ValidateToken(c.Request.Context(), token)
+		principal, err := m.authService.Authenticate(c.Request.Context(), token)
 		if err != nil {
 			m.sendErrorResponse(c, http.StatusUnauthorized, "Invalid or expired token", err.Error())
 			return
 		}
 
-		c.Set(UserContextKey, user)
-		c.Set(UserIDContextKey, user.ID())
+		if principal.IsClient() {
+			m.sendErrorResponse(c, http.StatusForbidden, "User account required", "This endpoint cannot be used with a client token.")
+			return
+		}
+
+		setPrincipal(c, principal, token)
+
+		if !m.auditImpersonation(c, principal) {
+			return
+		}
 
 		c.Next()
 	}
@@ -71,14 +110,17 @@ func (m *AuthMiddleware) OptionalAuth() gin.HandlerFunc {
 			return
 		}
 
-		user, err := m.authService.ValidateToken(c.Request.Context(), token)
+		principal, err := m.authService.Authenticate(c.Request.Context(), token)
 		if err != nil {
 			c.Next()
 			return
 		}
 
-		c.Set(UserContextKey, user)
-		c.Set(UserIDContextKey, user.ID())
+		setPrincipal(c, principal, token)
+
+		if !m.auditImpersonation(c, principal) {
+			return
+		}
 
 		c.Next()
 	}
@@ -92,24 +134,127 @@ func (m *AuthMiddleware) RequireActiveUser() gin.HandlerFunc {
 			return
 		}
 
-		user, err := m.authService.ValidateToken(c.Request.Context(), token)
+		principal, err := m.authService.Authenticate(c.Request.Context(), token)
 		if err != nil {
 			m.sendErrorResponse(c, http.StatusUnauthorized, "Invalid or expired token", err.Error())
 			return
 		}
 
-		if !user.IsActive() {
+		if principal.IsClient() {
+			m.sendErrorResponse(c, http.StatusForbidden, "User account required", "This endpoint cannot be used with a client token.")
+			return
+		}
+
+		if !principal.User.IsActive() {
 			m.sendErrorResponse(c, http.StatusForbidden, "Account is inactive", "Your account has been deactivated. Please contact support.")
 			return
 		}
 
-		c.Set(UserContextKey, user)
-		c.Set(UserIDContextKey, user.ID())
+		setPrincipal(c, principal, token)
+
+		if !m.auditImpersonation(c, principal) {
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// DenyImpersonation keeps impersonation tokens away from routes that change
+// how the account is secured or reached: password, second factors, email
+// and credentials that would outlive the impersonation.
+func (m *AuthMiddleware) DenyImpersonation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, impersonated := GetImpersonatorIDFromContext(c); impersonated {
+			m.sendErrorResponse(c, http.StatusForbidden, "Not allowed while impersonating", "This action can only be taken by the account owner.")
+			return
+		}
 
 		c.Next()
 	}
 }
 
+// RequireRecentAuth admits only tokens whose user proved their identity
+// within maxAge. It must run after RequireAuth; API keys and tokens issued
+// before auth_time existed are always refused.
+func (m *AuthMiddleware) RequireRecentAuth(maxAge time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authTime, ok := GetAuthTimeFromContext(c)
+		if !ok || time.Since(authTime) > maxAge {
+			c.JSON(http.StatusForbidden, ErrorResponse{
+				Success: false,
+				Error: &ErrorInfo{
+					Type:    string(apperrors.ErrorTypeForbidden),
+					Message: "Please confirm your identity to continue",
+					Code:    ReauthenticationRequiredCode,
+				},
+				Timestamp: time.Now().UTC(),
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// auditImpersonation records a request made through an impersonation token
+// before it is handled. Requests that cannot be recorded are refused.
+func (m *AuthMiddleware) auditImpersonation(c *gin.Context, principal *contracts.Principal) bool {
+	if !principal.IsImpersonated() {
+		return true
+	}
+
+	if err := m.impersonationService.RecordImpersonatedRequest(c.Request.Context(), &auth.AuditLogEntry{
+		ActorID:   principal.ActorID,
+		UserID:    principal.User.ID(),
+		SessionID: principal.SessionID,
+		Method:    c.Request.Method,
+		Path:      c.Request.URL.Path,
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}); err != nil {
+		m.sendErrorResponse(c, http.StatusInternalServerError, "Request could not be audited", err.Error())
+		return false
+	}
+
+	return true
+}
+
+func setPrincipal(c *gin.Context, principal *contracts.Principal, token string) {
+	if principal.IsClient() {
+		c.Set(ClientIDContextKey, principal.ClientID)
+		c.Set(ScopesContextKey, principal.Scopes)
+		c.Set(AccessTokenContextKey, token)
+		c.Set(AuthTypeContextKey, "client")
+		return
+	}
+
+	c.Set(UserContextKey, principal.User)
+	c.Set(UserIDContextKey, principal.User.ID())
+	c.Set(SessionIDContextKey, principal.SessionID)
+	c.Set(AccessTokenContextKey, token)
+	c.Set(AuthTypeContextKey, "jwt")
+	if principal.IsImpersonated() {
+		c.Set(ImpersonatorIDContextKey, principal.ActorID)
+	}
+	if principal.Authorization != nil {
+		c.Set(TokenAuthorizationContextKey, principal.Authorization)
+	}
+	if !principal.AuthTime.IsZero() {
+		c.Set(AuthTimeContextKey, principal.AuthTime)
+		c.Set(AuthMethodsContextKey, principal.AuthMethods)
+	}
+}
+
+func setAPIKeyPrincipal(c *gin.Context, principal *contracts.Principal) {
+	c.Set(UserContextKey, principal.User)
+	c.Set(UserIDContextKey, principal.User.ID())
+	c.Set(APIKeyContextKey, principal.APIKey)
+	c.Set(ScopesContextKey, principal.APIKey.Scopes)
+	c.Set(AuthTypeContextKey, "api_key")
+}
+
 func (m *AuthMiddleware) TokenRefresh() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		token, err := m.extractTokenFromHeader(c)
@@ -199,19 +344,92 @@ func RequireUserID(c *gin.Context) (string, error) {
 	return userID, nil
 }
 
+// GetClientIDFromContext returns the OAuth client of a request made with a
+// client credentials token.
+func GetClientIDFromContext(c *gin.Context) (string, bool) {
+	clientID, exists := c.Get(ClientIDContextKey)
+	if !exists {
+		return "", false
+	}
+
+	id, ok := clientID.(string)
+	return id, ok
+}
+
+// GetScopesFromContext returns the scopes a request is limited to. It
+// reports false for requests made with a user's access token, which are not
+// scope limited.
+func GetScopesFromContext(c *gin.Context) ([]string, bool) {
+	scopes, exists := c.Get(ScopesContextKey)
+	if !exists {
+		return nil, false
+	}
+
+	values, ok := scopes.([]string)
+	return values, ok
+}
+
+// GetImpersonatorIDFromContext returns the admin acting through an
+// impersonation token, if any.
+func GetImpersonatorIDFromContext(c *gin.Context) (string, bool) {
+	impersonatorID, exists := c.Get(ImpersonatorIDContextKey)
+	if !exists {
+		return "", false
+	}
+
+	id, ok := impersonatorID.(string)
+	return id, ok && id != ""
+}
+
+// GetTokenAuthorizationFromContext returns the role and permission snapshot
+// of the access token, if it carries one.
+func GetTokenAuthorizationFromContext(c *gin.Context) (*contracts.TokenAuthorization, bool) {
+	value, exists := c.Get(TokenAuthorizationContextKey)
+	if !exists {
+		return nil, false
+	}
+
+	authorization, ok := value.(*contracts.TokenAuthorization)
+	return authorization, ok && authorization != nil
+}
+
+// GetAuthTimeFromContext returns when the user behind the access token last
+// authenticated, if the token says.
+func GetAuthTimeFromContext(c *gin.Context) (time.Time, bool) {
+	value, exists := c.Get(AuthTimeContextKey)
+	if !exists {
+		return time.Time{}, false
+	}
+
+	authTime, ok := value.(time.Time)
+	return authTime, ok && !authTime.IsZero()
+}
+
+// GetAPIKeyFromContext returns the key a request was authenticated with,
+// if any.
+func GetAPIKeyFromContext(c *gin.Context) (*auth.APIKey, bool) {
+	value, exists := c.Get(APIKeyContextKey)
+	if !exists {
+		return nil, false
+	}
+
+	apiKey, ok := value.(*auth.APIKey)
+	return apiKey, ok && apiKey != nil
+}
+
 type APIKeyMiddleware struct {
-	validAPIKeys map[string]string // API key -> service name
+	apiKeyService contracts.APIKeyService
 }
 
-func NewAPIKeyMiddleware(apiKeys map[string]string) *APIKeyMiddleware {
+func NewAPIKeyMiddleware(apiKeyService contracts.APIKeyService) *APIKeyMiddleware {
 	return &APIKeyMiddleware{
-		validAPIKeys: apiKeys,
+		apiKeyService: apiKeyService,
 	}
 }
 
 func (m *APIKeyMiddleware) RequireAPIKey() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		apiKey := c.GetHeader("X-API-Key")
+		apiKey := c.GetHeader(APIKeyHeader)
 		if apiKey == "" {
 			c.JSON(http.StatusUnauthorized, ErrorResponse{
 				Success: false,
@@ -225,8 +443,8 @@ func (m *APIKeyMiddleware) RequireAPIKey() gin.HandlerFunc {
 			return
 		}
 
-		serviceName, valid := m.validAPIKeys[apiKey]
-		if !valid {
+		principal, err := m.apiKeyService.AuthenticateAPIKey(c.Request.Context(), apiKey)
+		if err != nil {
 			c.JSON(http.StatusUnauthorized, ErrorResponse{
 				Success: false,
 				Error: &ErrorInfo{
@@ -239,33 +457,35 @@ func (m *APIKeyMiddleware) RequireAPIKey() gin.HandlerFunc {
 			return
 		}
 
-		c.Set("service_name", serviceName)
-		c.Set("auth_type", "api_key")
+		setAPIKeyPrincipal(c, principal)
 
 		c.Next()
 	}
 }
 
+// FlexibleAuth admits user access tokens, client credentials tokens and API
+// keys. It suits routes whose authorization middleware decides by scope as
+// well as by permission.
 func FlexibleAuth(authMiddleware *AuthMiddleware, apiKeyMiddleware *APIKeyMiddleware) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		token, err := authMiddleware.extractTokenFromHeader(c)
 		if err == nil {
-			user, err := authMiddleware.authService.ValidateToken(c.Request.Context(), token)
+			principal, err := authMiddleware.authService.Authenticate(c.Request.Context(), token)
 			if err == nil {
-				c.Set(UserContextKey, user)
-				c.Set(UserIDContextKey, user.ID())
-				c.Set("auth_type", "jwt")
+				setPrincipal(c, principal, token)
+				if !authMiddleware.auditImpersonation(c, principal) {
+					return
+				}
 				c.Next()
 				return
 			}
 		}
 
-		apiKey := c.GetHeader("X-API-Key")
+		apiKey := c.GetHeader(APIKeyHeader)
 		if apiKey != "" {
-			serviceName, valid := apiKeyMiddleware.validAPIKeys[apiKey]
-			if valid {
-				c.Set("service_name", serviceName)
-				c.Set("auth_type", "api_key")
+			principal, err := apiKeyMiddleware.apiKeyService.AuthenticateAPIKey(c.Request.Context(), apiKey)
+			if err == nil {
+				setAPIKeyPrincipal(c, principal)
 				c.Next()
 				return
 			}
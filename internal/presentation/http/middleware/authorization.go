@@ -3,15 +3,19 @@ package middleware
 import (
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/auth"
 	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
 	apperrors "github.com/tranvuongduy2003/go-mvc/pkg/errors"
+	"github.com/tranvuongduy2003/go-mvc/pkg/policy"
 )
 
 type AuthzMiddleware struct {
 	authzService contracts.AuthorizationService
+	conditions   sync.Map // Compiled ConditionalAccess expressions keyed by source
 }
 
 func NewAuthzMiddleware(authzService contracts.AuthorizationService) *AuthzMiddleware {
@@ -22,13 +26,22 @@ func NewAuthzMiddleware(authzService contracts.AuthorizationService) *AuthzMiddl
 
 func (m *AuthzMiddleware) RequirePermission(resource, action string) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if m.authorizeClient(c, resource+":"+action) {
+			return
+		}
+
 		userID, err := RequireUserID(c)
 		if err != nil {
 			m.sendAuthzErrorResponse(c, "User authentication required for authorization")
 			return
 		}
 
-		hasPermission, err := m.authzService.UserHasPermission(c.Request.Context(), userID, resource, action)
+		if !scopeAllows(c, resource+":"+action) {
+			m.sendAuthzErrorResponse(c, "API key is not scoped for this resource")
+			return
+		}
+
+		hasPermission, err := m.hasPermission(c, userID, resource, action)
 		if err != nil {
 			m.sendInternalErrorResponse(c, "Failed to check permission")
 			return
@@ -45,13 +58,22 @@ func (m *AuthzMiddleware) RequirePermission(resource, action string) gin.Handler
 
 func (m *AuthzMiddleware) RequirePermissionByName(permissionName string) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if m.authorizeClient(c, permissionName) {
+			return
+		}
+
 		userID, err := RequireUserID(c)
 		if err != nil {
 			m.sendAuthzErrorResponse(c, "User authentication required for authorization")
 			return
 		}
 
-		hasPermission, err := m.authzService.UserHasPermissionByName(c.Request.Context(), userID, permissionName)
+		if !scopeAllows(c, permissionName) {
+			m.sendAuthzErrorResponse(c, "API key is not scoped for this resource")
+			return
+		}
+
+		hasPermission, err := m.hasPermissionByName(c, userID, permissionName)
 		if err != nil {
 			m.sendInternalErrorResponse(c, "Failed to check permission")
 			return
@@ -74,7 +96,12 @@ func (m *AuthzMiddleware) RequireRole(roleName string) gin.HandlerFunc {
 			return
 		}
 
-		hasRole, err := m.authzService.UserHasRole(c.Request.Context(), userID, roleName)
+		if isAPIKeyRequest(c) {
+			m.sendAuthzErrorResponse(c, "API keys are limited to their scopes and cannot use role based access")
+			return
+		}
+
+		hasRole, err := m.hasAnyRole(c, userID, roleName)
 		if err != nil {
 			m.sendInternalErrorResponse(c, "Failed to check role")
 			return
@@ -97,7 +124,12 @@ func (m *AuthzMiddleware) RequireAnyRole(roleNames ...string) gin.HandlerFunc {
 			return
 		}
 
-		hasRole, err := m.authzService.UserHasAnyRole(c.Request.Context(), userID, roleNames)
+		if isAPIKeyRequest(c) {
+			m.sendAuthzErrorResponse(c, "API keys are limited to their scopes and cannot use role based access")
+			return
+		}
+
+		hasRole, err := m.hasAnyRole(c, userID, roleNames...)
 		if err != nil {
 			m.sendInternalErrorResponse(c, "Failed to check roles")
 			return
@@ -120,7 +152,12 @@ func (m *AuthzMiddleware) RequireAllRoles(roleNames ...string) gin.HandlerFunc {
 			return
 		}
 
-		hasAllRoles, err := m.authzService.UserHasAllRoles(c.Request.Context(), userID, roleNames)
+		if isAPIKeyRequest(c) {
+			m.sendAuthzErrorResponse(c, "API keys are limited to their scopes and cannot use role based access")
+			return
+		}
+
+		hasAllRoles, err := m.hasAllRoles(c, userID, roleNames...)
 		if err != nil {
 			m.sendInternalErrorResponse(c, "Failed to check roles")
 			return
@@ -136,11 +173,11 @@ func (m *AuthzMiddleware) RequireAllRoles(roleNames ...string) gin.HandlerFunc {
 }
 
 func (m *AuthzMiddleware) RequireAdmin() gin.HandlerFunc {
-	return m.RequireRole("admin")
+	return m.RequireRole(auth.RoleNameAdmin)
 }
 
 func (m *AuthzMiddleware) RequireModerator() gin.HandlerFunc {
-	return m.RequireAnyRole("admin", "moderator")
+	return m.RequireAnyRole(auth.RoleNameAdmin, auth.RoleNameModerator)
 }
 
 func (m *AuthzMiddleware) RequireOwnership(paramName string) gin.HandlerFunc {
@@ -158,7 +195,12 @@ func (m *AuthzMiddleware) RequireOwnership(paramName string) gin.HandlerFunc {
 		}
 
 		if userID != resourceOwnerID {
-			isAdmin, err := m.authzService.IsAdmin(c.Request.Context(), userID)
+			if isAPIKeyRequest(c) {
+				m.sendAuthzErrorResponse(c, "You can only access your own resources")
+				return
+			}
+
+			isAdmin, err := m.hasAnyRole(c, userID, auth.RoleNameAdmin)
 			if err != nil {
 				m.sendInternalErrorResponse(c, "Failed to check admin status")
 				return
@@ -193,7 +235,12 @@ func (m *AuthzMiddleware) RequireOwnershipOrRole(paramName, roleName string) gin
 			return
 		}
 
-		hasRole, err := m.authzService.UserHasRole(c.Request.Context(), userID, roleName)
+		if isAPIKeyRequest(c) {
+			m.sendAuthzErrorResponse(c, "API keys are limited to their scopes and cannot use role based access")
+			return
+		}
+
+		hasRole, err := m.hasAnyRole(c, userID, roleName)
 		if err != nil {
 			m.sendInternalErrorResponse(c, "Failed to check role")
 			return
@@ -210,12 +257,6 @@ func (m *AuthzMiddleware) RequireOwnershipOrRole(paramName, roleName string) gin
 
 func (m *AuthzMiddleware) DynamicPermissionCheck() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		userID, err := RequireUserID(c)
-		if err != nil {
-			m.sendAuthzErrorResponse(c, "User authentication required for authorization")
-			return
-		}
-
 		resource := m.extractResourceFromPath(c.Request.URL.Path)
 		if resource == "" {
 			m.sendAuthzErrorResponse(c, "Unable to determine resource from request")
@@ -228,7 +269,22 @@ func (m *AuthzMiddleware) DynamicPermissionCheck() gin.HandlerFunc {
 			return
 		}
 
-		hasPermission, err := m.authzService.UserHasPermission(c.Request.Context(), userID, resource, action)
+		if m.authorizeClient(c, resource+":"+action) {
+			return
+		}
+
+		userID, err := RequireUserID(c)
+		if err != nil {
+			m.sendAuthzErrorResponse(c, "User authentication required for authorization")
+			return
+		}
+
+		if !scopeAllows(c, resource+":"+action) {
+			m.sendAuthzErrorResponse(c, "API key is not scoped for this action on this resource")
+			return
+		}
+
+		hasPermission, err := m.hasPermission(c, userID, resource, action)
 		if err != nil {
 			m.sendInternalErrorResponse(c, "Failed to check permission")
 			return
@@ -291,23 +347,138 @@ func (m *AuthzMiddleware) evaluateCondition(c *gin.Context, condition string) bo
 	case "authenticated":
 		_, exists := GetUserIDFromContext(c)
 		return exists
+	case "client":
+		_, exists := GetClientIDFromContext(c)
+		return exists
 	case "admin":
 		userID, exists := GetUserIDFromContext(c)
-		if !exists {
+		if !exists || isAPIKeyRequest(c) {
 			return false
 		}
-		isAdmin, err := m.authzService.IsAdmin(c.Request.Context(), userID)
+		isAdmin, err := m.hasAnyRole(c, userID, auth.RoleNameAdmin)
 		return err == nil && isAdmin
 	case "moderator":
 		userID, exists := GetUserIDFromContext(c)
-		if !exists {
+		if !exists || isAPIKeyRequest(c) {
 			return false
 		}
-		isModerator, err := m.authzService.IsModerator(c.Request.Context(), userID)
+		isModerator, err := m.hasAnyRole(c, userID, auth.RoleNameAdmin, auth.RoleNameModerator)
 		return err == nil && isModerator
 	default:
+		return m.evaluateExpression(c, condition)
+	}
+}
+
+// evaluateExpression treats any other condition as a policy expression over
+// the subject and environment attributes, such as
+// `"ADMIN" in subject.roles && environment.hour >= 9`. A condition that
+// fails to compile or evaluate does not hold.
+func (m *AuthzMiddleware) evaluateExpression(c *gin.Context, condition string) bool {
+	var expression *policy.Expression
+	if cached, ok := m.conditions.Load(condition); ok {
+		expression = cached.(*policy.Expression)
+	} else {
+		compiled, err := policy.Compile(condition, "subject", "environment")
+		if err != nil {
+			return false
+		}
+		m.conditions.Store(condition, compiled)
+		expression = compiled
+	}
+
+	subject, err := requestSubject(c, m.authzService, nil)
+	if err != nil {
+		return false
+	}
+
+	holds, err := expression.Evaluate(map[string]interface{}{
+		"subject":     subject,
+		"environment": requestEnvironment(c),
+	})
+	return err == nil && holds
+}
+
+// The helpers below answer from the access token's role and permission
+// snapshot when it has one, and from the authorization service otherwise.
+
+func (m *AuthzMiddleware) hasPermission(c *gin.Context, userID, resource, action string) (bool, error) {
+	if authorization, ok := GetTokenAuthorizationFromContext(c); ok {
+		return authorization.HasPermission(resource + ":" + action), nil
+	}
+	return m.authzService.UserHasPermission(c.Request.Context(), userID, resource, action)
+}
+
+func (m *AuthzMiddleware) hasPermissionByName(c *gin.Context, userID, permissionName string) (bool, error) {
+	if authorization, ok := GetTokenAuthorizationFromContext(c); ok {
+		return authorization.HasPermission(permissionName), nil
+	}
+	return m.authzService.UserHasPermissionByName(c.Request.Context(), userID, permissionName)
+}
+
+func (m *AuthzMiddleware) hasAnyRole(c *gin.Context, userID string, roleNames ...string) (bool, error) {
+	if authorization, ok := GetTokenAuthorizationFromContext(c); ok {
+		for _, roleName := range roleNames {
+			if authorization.HasRole(roleName) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+	if len(roleNames) == 1 {
+		return m.authzService.UserHasRole(c.Request.Context(), userID, roleNames[0])
+	}
+	return m.authzService.UserHasAnyRole(c.Request.Context(), userID, roleNames)
+}
+
+func (m *AuthzMiddleware) hasAllRoles(c *gin.Context, userID string, roleNames ...string) (bool, error) {
+	if authorization, ok := GetTokenAuthorizationFromContext(c); ok {
+		for _, roleName := range roleNames {
+			if !authorization.HasRole(roleName) {
+				return false, nil
+			}
+		}
+		return true, nil
+	}
+	return m.authzService.UserHasAllRoles(c.Request.Context(), userID, roleNames)
+}
+
+func isAPIKeyRequest(c *gin.Context) bool {
+	_, ok := GetAPIKeyFromContext(c)
+	return ok
+}
+
+// scopeAllows narrows API key and client requests to their scopes. For API
+// keys the owner's own permissions are still checked afterwards, so a scope
+// never grants more than the owner has. Requests made with a user's access
+// token are not affected.
+func scopeAllows(c *gin.Context, permission string) bool {
+	scopes, ok := GetScopesFromContext(c)
+	if !ok {
+		return true
+	}
+	for _, scope := range scopes {
+		if scope == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// authorizeClient decides requests made with a client credentials token,
+// which have no user and are authorized by scope alone. It reports whether
+// the request was such a request and has been handled.
+func (m *AuthzMiddleware) authorizeClient(c *gin.Context, permission string) bool {
+	if _, ok := GetClientIDFromContext(c); !ok {
 		return false
 	}
+
+	if !scopeAllows(c, permission) {
+		m.sendAuthzErrorResponse(c, "Client is not authorized for this scope")
+		return true
+	}
+
+	c.Next()
+	return true
 }
 
 func (m *AuthzMiddleware) sendAuthzErrorResponse(c *gin.Context, message string) {
@@ -58,12 +58,6 @@ type MiddlewareConfig struct {
 		Duration time.Duration
 	}
 
-	APIAuth struct {
-		Enabled    bool
-		ValidKeys  map[string]string
-		HeaderName string
-	}
-
 	IPWhitelist struct {
 		Enabled    bool
 		AllowedIPs []string
@@ -138,15 +132,6 @@ func DefaultMiddlewareConfig() MiddlewareConfig {
 			Enabled:  true,
 			Duration: 30 * time.Second,
 		},
-		APIAuth: struct {
-			Enabled    bool
-			ValidKeys  map[string]string
-			HeaderName string
-		}{
-			Enabled:    false,
-			ValidKeys:  map[string]string{},
-			HeaderName: "X-API-Key",
-		},
 		IPWhitelist: struct {
 			Enabled    bool
 			AllowedIPs []string
@@ -248,10 +233,6 @@ func (mm *MiddlewareManager) SetupMiddleware(r *gin.Engine) {
 		r.Use(IPWhitelistMiddleware(mm.config.IPWhitelist.AllowedIPs))
 	}
 
-	if mm.config.APIAuth.Enabled && len(mm.config.APIAuth.ValidKeys) > 0 {
-		r.Use(APIKeyAuthMiddleware(mm.config.APIAuth.ValidKeys))
-	}
-
 	r.Use(HealthCheckMiddleware())
 
 	r.NoRoute(NoRouteMiddleware())
@@ -0,0 +1,185 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
+	apperrors "github.com/tranvuongduy2003/go-mvc/pkg/errors"
+)
+
+// subjectResourceType is the loader whose attributes describe the user
+// behind a request.
+const subjectResourceType = "users"
+
+// PolicyMiddleware authorizes requests through the policy decision point.
+// Resource attributes come from the loader registered for the resource
+// type, subject attributes from the users loader completed with what the
+// request's credentials say.
+type PolicyMiddleware struct {
+	pdp          contracts.PolicyDecisionPoint
+	authzService contracts.AuthorizationService
+	loaders      map[string]contracts.ResourceAttributeLoader
+}
+
+func NewPolicyMiddleware(
+	pdp contracts.PolicyDecisionPoint,
+	authzService contracts.AuthorizationService,
+	loaders []contracts.ResourceAttributeLoader,
+) *PolicyMiddleware {
+	m := &PolicyMiddleware{
+		pdp:          pdp,
+		authzService: authzService,
+		loaders:      make(map[string]contracts.ResourceAttributeLoader, len(loaders)),
+	}
+	for _, loader := range loaders {
+		m.loaders[loader.ResourceType()] = loader
+	}
+	return m
+}
+
+// Authorize asks the policies whether the caller may perform action on the
+// resource whose ID is in the idParam path parameter. An empty idParam
+// stands for the resource type as a whole, as when listing or creating.
+func (m *PolicyMiddleware) Authorize(resourceType, action, idParam string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		subject, err := requestSubject(c, m.authzService, m.loaders[subjectResourceType])
+		if err != nil {
+			m.sendErrorResponse(c, http.StatusInternalServerError, apperrors.ErrorTypeInternal, "Failed to load subject attributes")
+			return
+		}
+
+		resource := contracts.Attributes{}
+		if idParam != "" {
+			id := c.Param(idParam)
+			if loader, ok := m.loaders[resourceType]; ok {
+				resource, err = loader.LoadAttributes(c.Request.Context(), id)
+				if err != nil {
+					m.sendErrorResponse(c, http.StatusInternalServerError, apperrors.ErrorTypeInternal, "Failed to load resource attributes")
+					return
+				}
+				if resource == nil {
+					m.sendErrorResponse(c, http.StatusNotFound, apperrors.ErrorTypeNotFound, "Resource not found")
+					return
+				}
+			}
+			resource["id"] = id
+		}
+		resource["type"] = resourceType
+
+		decision, err := m.pdp.Decide(c.Request.Context(), contracts.AccessRequest{
+			Subject:     subject,
+			Resource:    resource,
+			Action:      action,
+			Environment: requestEnvironment(c),
+		})
+		if err != nil {
+			m.sendErrorResponse(c, http.StatusInternalServerError, apperrors.ErrorTypeInternal, "Failed to evaluate policies")
+			return
+		}
+
+		if !decision.Allowed {
+			m.sendErrorResponse(c, http.StatusForbidden, apperrors.ErrorTypeForbidden, "Access denied: "+decision.Reason)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func (m *PolicyMiddleware) sendErrorResponse(c *gin.Context, status int, errorType apperrors.ErrorType, message string) {
+	c.JSON(status, ErrorResponse{
+		Success: false,
+		Error: &ErrorInfo{
+			Type:    string(errorType),
+			Message: message,
+		},
+		Timestamp: time.Now().UTC(),
+	})
+	c.Abort()
+}
+
+// requestSubject describes the caller. Users get their account attributes
+// when a users loader is given, plus their roles and permissions; API key
+// requests carry only the permissions within the key's scopes and no
+// roles, as in the role checks. Clients are described by their scopes and
+// unauthenticated callers only by that fact.
+func requestSubject(c *gin.Context, authzService contracts.AuthorizationService, userLoader contracts.ResourceAttributeLoader) (contracts.Attributes, error) {
+	if clientID, ok := GetClientIDFromContext(c); ok {
+		scopes, _ := GetScopesFromContext(c)
+		return contracts.Attributes{
+			"type":          "client",
+			"id":            clientID,
+			"authenticated": true,
+			"scopes":        scopes,
+		}, nil
+	}
+
+	userID, ok := GetUserIDFromContext(c)
+	if !ok {
+		return contracts.Attributes{"type": "anonymous", "authenticated": false}, nil
+	}
+
+	subject := contracts.Attributes{}
+	if userLoader != nil {
+		loaded, err := userLoader.LoadAttributes(c.Request.Context(), userID)
+		if err != nil {
+			return nil, err
+		}
+		if loaded != nil {
+			subject = loaded
+		}
+	}
+
+	var roles, permissions []string
+	if authorization, ok := GetTokenAuthorizationFromContext(c); ok {
+		roles, permissions = authorization.Roles, authorization.Permissions
+	} else {
+		var err error
+		if roles, err = authzService.GetUserRoles(c.Request.Context(), userID); err != nil {
+			return nil, err
+		}
+		if permissions, err = authzService.GetUserPermissions(c.Request.Context(), userID); err != nil {
+			return nil, err
+		}
+	}
+
+	if isAPIKeyRequest(c) {
+		scoped := make([]string, 0, len(permissions))
+		for _, permission := range permissions {
+			if scopeAllows(c, permission) {
+				scoped = append(scoped, permission)
+			}
+		}
+		roles, permissions = []string{}, scoped
+		subject["api_key"] = true
+	}
+
+	subject["type"] = "user"
+	subject["id"] = userID
+	subject["authenticated"] = true
+	subject["roles"] = roles
+	subject["permissions"] = permissions
+	if impersonatorID, ok := GetImpersonatorIDFromContext(c); ok {
+		subject["impersonator_id"] = impersonatorID
+	}
+
+	return subject, nil
+}
+
+// requestEnvironment describes the circumstances of the request. Times are
+// UTC; time is RFC 3339 so it orders correctly as a string.
+func requestEnvironment(c *gin.Context) contracts.Attributes {
+	now := time.Now().UTC()
+	return contracts.Attributes{
+		"time":    now.Format(time.RFC3339),
+		"hour":    now.Hour(),
+		"weekday": strings.ToLower(now.Weekday().String()),
+		"ip":      c.ClientIP(),
+		"method":  c.Request.Method,
+		"path":    c.Request.URL.Path,
+	}
+}
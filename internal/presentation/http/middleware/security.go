@@ -84,34 +84,6 @@ func DefaultSecurityConfig() SecurityConfig {
 	}
 }
 
-func APIKeyAuthMiddleware(validAPIKeys map[string]string) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		apiKey := c.GetHeader("X-API-Key")
-		if apiKey == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error":   "Missing API key",
-				"message": "X-API-Key header is required",
-				"code":    "MISSING_API_KEY",
-			})
-			c.Abort()
-			return
-		}
-
-		if clientName, exists := validAPIKeys[apiKey]; exists {
-			c.Set("api_key", apiKey)
-			c.Set("client_name", clientName)
-			c.Next()
-		} else {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error":   "Invalid API key",
-				"message": "The provided API key is not valid",
-				"code":    "INVALID_API_KEY",
-			})
-			c.Abort()
-		}
-	}
-}
-
 func BasicAuthMiddleware(users map[string]string) gin.HandlerFunc {
 	return gin.BasicAuth(users)
 }
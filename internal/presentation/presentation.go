@@ -10,6 +10,7 @@ import (
 	"go.uber.org/zap"
 
 	appservices "github.com/tranvuongduy2003/go-mvc/internal/application/services"
+	"github.com/tranvuongduy2003/go-mvc/internal/domain/contracts"
 	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/config"
 	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/logger"
 	v1 "github.com/tranvuongduy2003/go-mvc/internal/presentation/http/handlers/v1"
@@ -39,11 +40,24 @@ type RouterParams struct {
 
 type RouteParams struct {
 	fx.In
-	Router      *gin.Engine
-	UserHandler *v1.UserHandler
-	UserService *appservices.UserService
-	AuthHandler *v1.AuthHandler
-	AuthService appservices.AuthService
+	Router               *gin.Engine
+	UserHandler          *v1.UserHandler
+	UserService          *appservices.UserService
+	AuthHandler          *v1.AuthHandler
+	AuthService          contracts.AuthService
+	ImpersonationService contracts.ImpersonationService
+	APIKeyService        contracts.APIKeyService
+	AuthzService         contracts.AuthorizationService
+	PolicyDecisionPoint  contracts.PolicyDecisionPoint
+	ResourceLoaders      []contracts.ResourceAttributeLoader `group:"resource_attribute_loaders"`
+	JWKSHandler          *v1.JWKSHandler
+	WebAuthnHandler      *v1.WebAuthnHandler
+	OIDCHandler          *v1.OIDCHandler
+	MagicLinkHandler     *v1.MagicLinkHandler
+	APIKeyHandler        *v1.APIKeyHandler
+	OAuthHandler         *v1.OAuthHandler
+	RBACHandler          *v1.RBACHandler
+	Config               *config.AppConfig
 }
 
 type MiddlewareParams struct {
@@ -98,7 +112,24 @@ func SetupMiddleware(params MiddlewareParams) {
 }
 
 func RegisterRoutes(params RouteParams) {
-	authMiddleware := middleware.NewAuthMiddleware(&params.AuthService)
+	authMiddleware := middleware.NewAuthMiddleware(params.AuthService, params.ImpersonationService)
+	apiKeyMiddleware := middleware.NewAPIKeyMiddleware(params.APIKeyService)
+	// Routes authorized by permission also serve API keys and service
+	// clients; the caller's own account routes stay user-only
+	flexibleAuth := middleware.FlexibleAuth(authMiddleware, apiKeyMiddleware)
+	authzMiddleware := middleware.NewAuthzMiddleware(params.AuthzService)
+	policyMiddleware := middleware.NewPolicyMiddleware(params.PolicyDecisionPoint, params.AuthzService, params.ResourceLoaders)
+	denyImpersonation := authMiddleware.DenyImpersonation()
+	recentAuth := authMiddleware.RequireRecentAuth(params.Config.Auth.StepUp.MaxAge)
+
+	params.Router.GET("/.well-known/jwks.json", params.JWKSHandler.GetJWKS)
+
+	oauth := params.Router.Group("/oauth")
+	{
+		oauth.POST("/token", params.OAuthHandler.Token)
+		oauth.POST("/introspect", params.OAuthHandler.Introspect)
+		oauth.POST("/revoke", params.OAuthHandler.Revoke)
+	}
 
 	v1API := params.Router.Group("/api/v1")
 	{
@@ -111,6 +142,16 @@ func RegisterRoutes(params RouteParams) {
 			auth.POST("/reset-password", params.AuthHandler.ResetPassword)
 			auth.POST("/confirm-reset", params.AuthHandler.ConfirmPasswordReset)
 			auth.POST("/resend-verification", params.AuthHandler.ResendVerificationEmail)
+			auth.POST("/email/confirm", params.AuthHandler.ConfirmEmailChange)
+			auth.POST("/email/revert", params.AuthHandler.RevertEmailChange)
+			auth.POST("/secure-account", params.AuthHandler.SecureAccount)
+			auth.POST("/mfa/verify", params.AuthHandler.VerifyMFA)
+			auth.POST("/webauthn/login/begin", params.WebAuthnHandler.BeginLogin)
+			auth.POST("/webauthn/login/finish", params.WebAuthnHandler.FinishLogin)
+			auth.GET("/oidc/:provider/login", params.OIDCHandler.Login)
+			auth.GET("/oidc/:provider/callback", params.OIDCHandler.Callback)
+			auth.POST("/magic-link", params.MagicLinkHandler.Request)
+			auth.POST("/magic-link/consume", params.MagicLinkHandler.Consume)
 		}
 
 		protectedAuth := v1API.Group("/auth")
@@ -120,7 +161,54 @@ func RegisterRoutes(params RouteParams) {
 			protectedAuth.POST("/logout-all", params.AuthHandler.LogoutAllDevices)
 			protectedAuth.GET("/profile", params.AuthHandler.GetProfile)
 			protectedAuth.GET("/permissions", params.AuthHandler.GetPermissions)
-			protectedAuth.PUT("/change-password", params.AuthHandler.ChangePassword)
+			protectedAuth.POST("/reauthenticate", denyImpersonation, params.AuthHandler.Reauthenticate)
+			protectedAuth.PUT("/change-password", denyImpersonation, recentAuth, params.AuthHandler.ChangePassword)
+			protectedAuth.POST("/email/change", denyImpersonation, recentAuth, params.AuthHandler.RequestEmailChange)
+			protectedAuth.GET("/sessions", params.AuthHandler.ListSessions)
+			protectedAuth.DELETE("/sessions/:id", params.AuthHandler.RevokeSession)
+			protectedAuth.GET("/login-history", params.AuthHandler.GetLoginHistory)
+			protectedAuth.POST("/mfa/enroll", denyImpersonation, recentAuth, params.AuthHandler.EnrollMFA)
+			protectedAuth.POST("/mfa/enroll/confirm", denyImpersonation, recentAuth, params.AuthHandler.ConfirmMFA)
+			protectedAuth.POST("/mfa/recovery-codes", denyImpersonation, recentAuth, params.AuthHandler.RegenerateRecoveryCodes)
+			protectedAuth.POST("/webauthn/register/begin", denyImpersonation, recentAuth, params.WebAuthnHandler.BeginRegistration)
+			protectedAuth.POST("/webauthn/register/finish", denyImpersonation, recentAuth, params.WebAuthnHandler.FinishRegistration)
+			protectedAuth.GET("/webauthn/credentials", params.WebAuthnHandler.ListPasskeys)
+			protectedAuth.DELETE("/webauthn/credentials/:id", denyImpersonation, recentAuth, params.WebAuthnHandler.DeletePasskey)
+			protectedAuth.POST("/api-keys", denyImpersonation, recentAuth, params.APIKeyHandler.CreateAPIKey)
+			protectedAuth.GET("/api-keys", params.APIKeyHandler.ListAPIKeys)
+			protectedAuth.DELETE("/api-keys/:id", denyImpersonation, recentAuth, params.APIKeyHandler.DeleteAPIKey)
+		}
+
+		admin := v1API.Group("/admin")
+		admin.Use(flexibleAuth, authzMiddleware.RequireAdmin())
+		{
+			admin.POST("/users/:id/mfa/reset", params.AuthHandler.ResetUserMFA)
+			admin.POST("/users/:id/unlock", params.AuthHandler.UnlockUser)
+			admin.POST("/users/:id/impersonate", policyMiddleware.Authorize("users", "impersonate", "id"), params.AuthHandler.ImpersonateUser)
+		}
+
+		rbac := v1API.Group("/admin")
+		rbac.Use(flexibleAuth)
+		{
+			rbac.GET("/roles", authzMiddleware.RequirePermission("roles", "list"), params.RBACHandler.ListRoles)
+			rbac.POST("/roles", authzMiddleware.RequirePermission("roles", "create"), params.RBACHandler.CreateRole)
+			rbac.GET("/roles/:id", authzMiddleware.RequirePermission("roles", "read"), params.RBACHandler.GetRole)
+			rbac.PATCH("/roles/:id", authzMiddleware.RequirePermission("roles", "update"), params.RBACHandler.UpdateRole)
+			rbac.DELETE("/roles/:id", authzMiddleware.RequirePermission("roles", "delete"), params.RBACHandler.DeleteRole)
+			rbac.PUT("/roles/:id/parent", authzMiddleware.RequirePermission("roles", "update"), params.RBACHandler.SetRoleParent)
+			rbac.DELETE("/roles/:id/parent", authzMiddleware.RequirePermission("roles", "update"), params.RBACHandler.RemoveRoleParent)
+			rbac.GET("/roles/:id/permissions", authzMiddleware.RequirePermission("roles", "read"), params.RBACHandler.ListRolePermissions)
+			rbac.POST("/roles/:id/permissions", authzMiddleware.RequirePermission("roles", "update"), params.RBACHandler.GrantRolePermission)
+			rbac.DELETE("/roles/:id/permissions/:permissionId", authzMiddleware.RequirePermission("roles", "update"), params.RBACHandler.RevokeRolePermission)
+			rbac.GET("/roles/:id/users", authzMiddleware.RequirePermission("user_roles", "list"), params.RBACHandler.ListRoleUsers)
+			rbac.GET("/permissions", authzMiddleware.RequirePermission("permissions", "list"), params.RBACHandler.ListPermissions)
+			rbac.POST("/permissions", authzMiddleware.RequirePermission("permissions", "create"), params.RBACHandler.CreatePermission)
+			rbac.GET("/permissions/:id", authzMiddleware.RequirePermission("permissions", "read"), params.RBACHandler.GetPermission)
+			rbac.PATCH("/permissions/:id", authzMiddleware.RequirePermission("permissions", "update"), params.RBACHandler.UpdatePermission)
+			rbac.DELETE("/permissions/:id", authzMiddleware.RequirePermission("permissions", "delete"), params.RBACHandler.DeletePermission)
+			rbac.GET("/users/:id/roles", authzMiddleware.RequirePermission("user_roles", "list"), params.RBACHandler.ListUserRoles)
+			rbac.POST("/users/:id/roles", authzMiddleware.RequirePermission("user_roles", "create"), params.RBACHandler.AssignUserRole)
+			rbac.DELETE("/users/:id/roles/:roleId", authzMiddleware.RequirePermission("user_roles", "delete"), params.RBACHandler.RevokeUserRole)
 		}
 
 		users := v1API.Group("/users")
@@ -13,6 +13,7 @@ const (
 	ErrorTypeConflict     ErrorType = "CONFLICT"
 	ErrorTypeUnauthorized ErrorType = "UNAUTHORIZED"
 	ErrorTypeForbidden    ErrorType = "FORBIDDEN"
+	ErrorTypeRateLimited  ErrorType = "RATE_LIMIT_EXCEEDED"
 	ErrorTypeInternal     ErrorType = "INTERNAL_ERROR"
 )
 
@@ -80,6 +81,14 @@ func NewForbiddenError(message string) *AppError {
 	}
 }
 
+func NewRateLimitedError(message string) *AppError {
+	return &AppError{
+		Type:    ErrorTypeRateLimited,
+		Message: message,
+		Code:    http.StatusTooManyRequests,
+	}
+}
+
 func NewInternalError(message string, cause error) *AppError {
 	return &AppError{
 		Type:    ErrorTypeInternal,
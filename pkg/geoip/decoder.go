@@ -0,0 +1,228 @@
+package geoip
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// Data section field types from the MaxMind DB format specification.
+const (
+	typeExtended  = 0
+	typePointer   = 1
+	typeString    = 2
+	typeDouble    = 3
+	typeBytes     = 4
+	typeUint16    = 5
+	typeUint32    = 6
+	typeMap       = 7
+	typeInt32     = 8
+	typeUint64    = 9
+	typeUint128   = 10
+	typeArray     = 11
+	typeContainer = 12
+	typeEndMarker = 13
+	typeBool      = 14
+	typeFloat     = 15
+)
+
+// maxDepth guards against pointer loops in a corrupt file.
+const maxDepth = 32
+
+// decoder turns data section fields into Go values: maps become
+// map[string]interface{}, arrays []interface{} and numbers their natural
+// Go type.
+type decoder struct {
+	buffer []byte
+}
+
+// decode reads the field at offset and returns it with the offset of the
+// next field.
+func (d *decoder) decode(offset uint) (interface{}, uint, error) {
+	return d.decodeAt(offset, 0)
+}
+
+func (d *decoder) decodeAt(offset uint, depth int) (interface{}, uint, error) {
+	if depth > maxDepth {
+		return nil, 0, fmt.Errorf("%w: data nested too deeply", ErrInvalidDatabase)
+	}
+
+	fieldType, size, offset, err := d.controlByte(offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if fieldType == typePointer {
+		pointer, next, err := d.pointer(size, offset)
+		if err != nil {
+			return nil, 0, err
+		}
+		value, _, err := d.decodeAt(pointer, depth+1)
+		return value, next, err
+	}
+
+	return d.decodeValue(fieldType, size, offset, depth)
+}
+
+// controlByte reads a field's type and payload size. For pointers, size
+// holds the raw low bits of the control byte instead.
+func (d *decoder) controlByte(offset uint) (int, uint, uint, error) {
+	if offset >= uint(len(d.buffer)) {
+		return 0, 0, 0, ErrInvalidDatabase
+	}
+	control := d.buffer[offset]
+	offset++
+
+	fieldType := int(control >> 5)
+	if fieldType == typeExtended {
+		if offset >= uint(len(d.buffer)) {
+			return 0, 0, 0, ErrInvalidDatabase
+		}
+		fieldType = 7 + int(d.buffer[offset])
+		offset++
+	}
+
+	if fieldType == typePointer {
+		return fieldType, uint(control & 0x1F), offset, nil
+	}
+
+	size := uint(control & 0x1F)
+	if size >= 29 {
+		extra := size - 28
+		if offset+extra > uint(len(d.buffer)) {
+			return 0, 0, 0, ErrInvalidDatabase
+		}
+		value := d.uintFrom(offset, extra)
+		offset += extra
+		switch extra {
+		case 1:
+			size = 29 + value
+		case 2:
+			size = 285 + value
+		default:
+			size = 65821 + value
+		}
+	}
+
+	return fieldType, size, offset, nil
+}
+
+func (d *decoder) pointer(bits, offset uint) (uint, uint, error) {
+	length := (bits >> 3) + 1
+	if offset+length > uint(len(d.buffer)) {
+		return 0, 0, ErrInvalidDatabase
+	}
+
+	value := d.uintFrom(offset, length)
+	var pointer uint
+	switch length {
+	case 1:
+		pointer = (bits&0x7)<<8 | value
+	case 2:
+		pointer = ((bits&0x7)<<16 | value) + 2048
+	case 3:
+		pointer = ((bits&0x7)<<24 | value) + 526336
+	default:
+		pointer = value
+	}
+
+	return pointer, offset + length, nil
+}
+
+func (d *decoder) decodeValue(fieldType int, size, offset uint, depth int) (interface{}, uint, error) {
+	switch fieldType {
+	case typeMap:
+		return d.decodeMap(size, offset, depth)
+	case typeArray:
+		return d.decodeArray(size, offset, depth)
+	case typeBool:
+		return size != 0, offset, nil
+	}
+
+	end := offset + size
+	if end > uint(len(d.buffer)) {
+		return nil, 0, ErrInvalidDatabase
+	}
+	payload := d.buffer[offset:end]
+
+	switch fieldType {
+	case typeString:
+		return string(payload), end, nil
+	case typeDouble:
+		if size != 8 {
+			return nil, 0, ErrInvalidDatabase
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(payload)), end, nil
+	case typeFloat:
+		if size != 4 {
+			return nil, 0, ErrInvalidDatabase
+		}
+		return math.Float32frombits(binary.BigEndian.Uint32(payload)), end, nil
+	case typeBytes:
+		return append([]byte(nil), payload...), end, nil
+	case typeUint16, typeUint32, typeInt32, typeUint64:
+		if size > 8 {
+			return nil, 0, ErrInvalidDatabase
+		}
+		value := d.uintFrom(offset, size)
+		switch fieldType {
+		case typeUint16:
+			return uint16(value), end, nil
+		case typeUint32:
+			return uint32(value), end, nil
+		case typeInt32:
+			return int32(uint32(value)), end, nil
+		default:
+			return uint64(value), end, nil
+		}
+	case typeUint128:
+		return new(big.Int).SetBytes(payload), end, nil
+	default:
+		return nil, 0, fmt.Errorf("%w: unexpected field type %d", ErrInvalidDatabase, fieldType)
+	}
+}
+
+func (d *decoder) decodeMap(size, offset uint, depth int) (interface{}, uint, error) {
+	result := make(map[string]interface{})
+	for i := uint(0); i < size; i++ {
+		key, next, err := d.decodeAt(offset, depth+1)
+		if err != nil {
+			return nil, 0, err
+		}
+		name, ok := key.(string)
+		if !ok {
+			return nil, 0, fmt.Errorf("%w: map key is not a string", ErrInvalidDatabase)
+		}
+
+		value, next, err := d.decodeAt(next, depth+1)
+		if err != nil {
+			return nil, 0, err
+		}
+		result[name] = value
+		offset = next
+	}
+	return result, offset, nil
+}
+
+func (d *decoder) decodeArray(size, offset uint, depth int) (interface{}, uint, error) {
+	var result []interface{}
+	for i := uint(0); i < size; i++ {
+		value, next, err := d.decodeAt(offset, depth+1)
+		if err != nil {
+			return nil, 0, err
+		}
+		result = append(result, value)
+		offset = next
+	}
+	return result, offset, nil
+}
+
+// uintFrom reads a big-endian unsigned integer of up to eight bytes.
+func (d *decoder) uintFrom(offset, length uint) uint {
+	var value uint
+	for _, b := range d.buffer[offset : offset+length] {
+		value = value<<8 | uint(b)
+	}
+	return value
+}
@@ -0,0 +1,314 @@
+package geoip
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// pointerTo encodes as a data section pointer to the given offset.
+type pointerTo uint
+
+// encoder writes values in the MaxMind DB data section format, the inverse
+// of decoder. Only the tests need it.
+type encoder struct {
+	buf []byte
+}
+
+// encode appends value and returns the offset it starts at.
+func (e *encoder) encode(value interface{}) uint {
+	offset := uint(len(e.buf))
+
+	switch v := value.(type) {
+	case pointerTo:
+		e.pointer(uint(v))
+	case string:
+		e.header(typeString, len(v))
+		e.buf = append(e.buf, v...)
+	case []byte:
+		e.header(typeBytes, len(v))
+		e.buf = append(e.buf, v...)
+	case float64:
+		e.header(typeDouble, 8)
+		e.buf = appendUint(e.buf, uint64(math.Float64bits(v)), 8)
+	case float32:
+		e.header(typeFloat, 4)
+		e.buf = appendUint(e.buf, uint64(math.Float32bits(v)), 4)
+	case bool:
+		size := 0
+		if v {
+			size = 1
+		}
+		e.header(typeBool, size)
+	case uint16:
+		e.unsigned(typeUint16, uint64(v))
+	case uint32:
+		e.unsigned(typeUint32, uint64(v))
+	case int32:
+		e.header(typeInt32, 4)
+		e.buf = appendUint(e.buf, uint64(uint32(v)), 4)
+	case uint64:
+		e.unsigned(typeUint64, v)
+	case *big.Int:
+		payload := v.Bytes()
+		e.header(typeUint128, len(payload))
+		e.buf = append(e.buf, payload...)
+	case []interface{}:
+		e.header(typeArray, len(v))
+		for _, item := range v {
+			e.encode(item)
+		}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		e.header(typeMap, len(v))
+		for _, key := range keys {
+			e.encode(key)
+			e.encode(v[key])
+		}
+	default:
+		panic("encoder: unsupported type")
+	}
+
+	return offset
+}
+
+func (e *encoder) header(fieldType, size int) {
+	var sizeBits byte
+	var extra []byte
+	switch {
+	case size < 29:
+		sizeBits = byte(size)
+	case size < 285:
+		sizeBits = 29
+		extra = appendUint(nil, uint64(size-29), 1)
+	case size < 65821:
+		sizeBits = 30
+		extra = appendUint(nil, uint64(size-285), 2)
+	default:
+		sizeBits = 31
+		extra = appendUint(nil, uint64(size-65821), 3)
+	}
+
+	if fieldType <= typeMap {
+		e.buf = append(e.buf, byte(fieldType)<<5|sizeBits)
+	} else {
+		e.buf = append(e.buf, sizeBits, byte(fieldType-7))
+	}
+	e.buf = append(e.buf, extra...)
+}
+
+func (e *encoder) unsigned(fieldType int, value uint64) {
+	size := 0
+	for v := value; v > 0; v >>= 8 {
+		size++
+	}
+	e.header(fieldType, size)
+	e.buf = appendUint(e.buf, value, size)
+}
+
+func (e *encoder) pointer(offset uint) {
+	switch {
+	case offset < 2048:
+		e.buf = append(e.buf, typePointer<<5|byte(offset>>8))
+		e.buf = appendUint(e.buf, uint64(offset), 1)
+	case offset < 526336:
+		value := offset - 2048
+		e.buf = append(e.buf, typePointer<<5|1<<3|byte(value>>16))
+		e.buf = appendUint(e.buf, uint64(value), 2)
+	case offset < 134744064:
+		value := offset - 526336
+		e.buf = append(e.buf, typePointer<<5|2<<3|byte(value>>24))
+		e.buf = appendUint(e.buf, uint64(value), 3)
+	default:
+		e.buf = append(e.buf, typePointer<<5|3<<3)
+		e.buf = appendUint(e.buf, uint64(offset), 4)
+	}
+}
+
+// appendUint appends the low size bytes of value, big-endian.
+func appendUint(buf []byte, value uint64, size int) []byte {
+	for i := size - 1; i >= 0; i-- {
+		buf = append(buf, byte(value>>(8*uint(i))))
+	}
+	return buf
+}
+
+func TestDecodeValues(t *testing.T) {
+	uint128 := new(big.Int).Lsh(big.NewInt(1), 100)
+
+	tests := map[string]interface{}{
+		"empty string":      "",
+		"string":            "London",
+		"29 byte string":    strings.Repeat("a", 29),
+		"300 byte string":   strings.Repeat("b", 300),
+		"70000 byte string": strings.Repeat("c", 70000),
+		"double":            51.5142,
+		"negative double":   -0.0931,
+		"float":             float32(1.5),
+		"bytes":             []byte{0x00, 0xFF},
+		"true":              true,
+		"false":             false,
+		"zero uint16":       uint16(0),
+		"uint16":            uint16(443),
+		"uint32":            uint32(4294967295),
+		"negative int32":    int32(-17),
+		"uint64":            uint64(1) << 63,
+		"uint128":           uint128,
+		"array":             []interface{}{"a", uint16(1), true},
+		"nested map":        map[string]interface{}{"names": map[string]interface{}{"en": "Sweden", "de": "Schweden"}},
+		"array of maps":     []interface{}{map[string]interface{}{"iso_code": "SE"}},
+	}
+
+	for name, want := range tests {
+		t.Run(name, func(t *testing.T) {
+			var e encoder
+			e.encode(want)
+
+			d := decoder{buffer: e.buf}
+			got, next, err := d.decode(0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("decode = %#v, want %#v", got, want)
+			}
+			if next != uint(len(e.buf)) {
+				t.Fatalf("next offset = %d, want %d", next, len(e.buf))
+			}
+		})
+	}
+}
+
+func TestDecodeFollowsPointers(t *testing.T) {
+	tests := map[string]uint{
+		"one byte":    0,
+		"two bytes":   2048,
+		"three bytes": 526336,
+	}
+
+	for name, target := range tests {
+		t.Run(name, func(t *testing.T) {
+			e := encoder{buf: make([]byte, target)}
+			e.encode("United Kingdom")
+			start := e.encode(map[string]interface{}{"country": pointerTo(target), "city": "London"})
+
+			d := decoder{buffer: e.buf}
+			got, next, err := d.decode(start)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := map[string]interface{}{"country": "United Kingdom", "city": "London"}
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("decode = %#v, want %#v", got, want)
+			}
+			// The pointer is followed for the value, but decoding resumes
+			// after the pointer itself
+			if next != uint(len(e.buf)) {
+				t.Fatalf("next offset = %d, want %d", next, len(e.buf))
+			}
+		})
+	}
+}
+
+func TestDecodeRejectsCorruptData(t *testing.T) {
+	tests := map[string]func() []byte{
+		"empty": func() []byte {
+			return nil
+		},
+		"truncated string": func() []byte {
+			var e encoder
+			e.encode("London")
+			return e.buf[:len(e.buf)-2]
+		},
+		"truncated size": func() []byte {
+			var e encoder
+			e.encode(strings.Repeat("a", 300))
+			return e.buf[:1]
+		},
+		"truncated extended type": func() []byte {
+			return []byte{0x00}
+		},
+		"map with missing entries": func() []byte {
+			var e encoder
+			e.header(typeMap, 3)
+			e.encode("key")
+			return e.buf
+		},
+		"map key is not a string": func() []byte {
+			var e encoder
+			e.header(typeMap, 1)
+			e.encode(uint16(1))
+			e.encode("value")
+			return e.buf
+		},
+		"double of wrong size": func() []byte {
+			var e encoder
+			e.header(typeDouble, 4)
+			e.buf = append(e.buf, 0, 0, 0, 0)
+			return e.buf
+		},
+		"float of wrong size": func() []byte {
+			var e encoder
+			e.header(typeFloat, 8)
+			e.buf = append(e.buf, make([]byte, 8)...)
+			return e.buf
+		},
+		"oversized integer": func() []byte {
+			var e encoder
+			e.header(typeUint64, 9)
+			e.buf = append(e.buf, make([]byte, 9)...)
+			return e.buf
+		},
+		"container type": func() []byte {
+			var e encoder
+			e.header(typeContainer, 0)
+			return e.buf
+		},
+		"end marker": func() []byte {
+			var e encoder
+			e.header(typeEndMarker, 0)
+			return e.buf
+		},
+		"truncated pointer": func() []byte {
+			var e encoder
+			e.pointer(4096)
+			return e.buf[:2]
+		},
+		"pointer out of range": func() []byte {
+			var e encoder
+			e.pointer(1000)
+			return e.buf
+		},
+		"pointer loop": func() []byte {
+			var e encoder
+			e.pointer(0)
+			return e.buf
+		},
+		"nested too deeply": func() []byte {
+			var e encoder
+			for i := 0; i <= maxDepth; i++ {
+				e.header(typeArray, 1)
+			}
+			e.encode("bottom")
+			return e.buf
+		},
+	}
+
+	for name, build := range tests {
+		t.Run(name, func(t *testing.T) {
+			d := decoder{buffer: build()}
+			if _, _, err := d.decode(0); !errors.Is(err, ErrInvalidDatabase) {
+				t.Fatalf("decode err = %v, want %v", err, ErrInvalidDatabase)
+			}
+		})
+	}
+}
@@ -0,0 +1,244 @@
+package geoip
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"os"
+)
+
+// metadataMarker precedes the metadata map at the end of a MaxMind DB file.
+var metadataMarker = []byte("\xAB\xCD\xEFMaxMind.com")
+
+// dataSectionSeparator is the run of zero bytes between the search tree and
+// the data section.
+const dataSectionSeparator = 16
+
+var ErrInvalidDatabase = errors.New("invalid GeoIP database")
+
+// Location is the approximate position of an IP address. Coordinates are
+// only meaningful when HasCoordinates is set.
+type Location struct {
+	CountryCode    string
+	Country        string
+	City           string
+	Latitude       float64
+	Longitude      float64
+	HasCoordinates bool
+}
+
+// String renders the location as "City, Country", falling back to whatever
+// part is known.
+func (l *Location) String() string {
+	switch {
+	case l.City != "" && l.Country != "":
+		return l.City + ", " + l.Country
+	case l.Country != "":
+		return l.Country
+	default:
+		return l.City
+	}
+}
+
+// Reader looks up IP addresses in a MaxMind DB file such as GeoLite2-City.
+// The whole file is held in memory; lookups are safe for concurrent use.
+type Reader struct {
+	buffer     []byte
+	decoder    decoder
+	nodeCount  uint
+	recordSize uint
+	ipVersion  uint
+	ipv4Start  uint
+	treeSize   uint
+}
+
+// Open reads the database at path.
+func Open(path string) (*Reader, error) {
+	buffer, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read GeoIP database: %w", err)
+	}
+	return New(buffer)
+}
+
+// New parses a database already loaded into memory.
+func New(buffer []byte) (*Reader, error) {
+	start := bytes.LastIndex(buffer, metadataMarker)
+	if start == -1 {
+		return nil, ErrInvalidDatabase
+	}
+	start += len(metadataMarker)
+
+	metadataDecoder := decoder{buffer: buffer[start:]}
+	value, _, err := metadataDecoder.decode(0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode GeoIP metadata: %w", err)
+	}
+	metadata, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, ErrInvalidDatabase
+	}
+
+	reader := &Reader{
+		nodeCount:  toUint(metadata["node_count"]),
+		recordSize: toUint(metadata["record_size"]),
+		ipVersion:  toUint(metadata["ip_version"]),
+	}
+	if reader.recordSize != 24 && reader.recordSize != 28 && reader.recordSize != 32 {
+		return nil, fmt.Errorf("%w: unsupported record size %d", ErrInvalidDatabase, reader.recordSize)
+	}
+
+	reader.treeSize = reader.nodeCount * reader.recordSize / 4
+	dataStart := reader.treeSize + dataSectionSeparator
+	if dataStart > uint(start-len(metadataMarker)) {
+		return nil, ErrInvalidDatabase
+	}
+	reader.buffer = buffer[:reader.treeSize]
+	reader.decoder = decoder{buffer: buffer[dataStart : start-len(metadataMarker)]}
+
+	// IPv4 addresses live under ::/96 in IPv6 databases
+	if reader.ipVersion == 6 {
+		node := uint(0)
+		for i := 0; i < 96 && node < reader.nodeCount; i++ {
+			if node, err = reader.readNode(node, 0); err != nil {
+				return nil, err
+			}
+		}
+		reader.ipv4Start = node
+	}
+
+	return reader, nil
+}
+
+// Lookup returns the location of ip, or nil when the database has none.
+func (r *Reader) Lookup(ip net.IP) (*Location, error) {
+	record, err := r.lookupRecord(ip)
+	if err != nil || record == nil {
+		return nil, err
+	}
+
+	location := &Location{
+		CountryCode: toString(path(record, "country", "iso_code")),
+		Country:     toString(path(record, "country", "names", "en")),
+		City:        toString(path(record, "city", "names", "en")),
+	}
+	latitude, hasLatitude := path(record, "location", "latitude").(float64)
+	longitude, hasLongitude := path(record, "location", "longitude").(float64)
+	if hasLatitude && hasLongitude {
+		location.Latitude = latitude
+		location.Longitude = longitude
+		location.HasCoordinates = true
+	}
+
+	return location, nil
+}
+
+func (r *Reader) lookupRecord(ip net.IP) (map[string]interface{}, error) {
+	address := ip.To4()
+	node := uint(0)
+	if address != nil && r.ipVersion == 6 {
+		node = r.ipv4Start
+	} else if address == nil {
+		if r.ipVersion != 6 {
+			return nil, nil
+		}
+		address = ip.To16()
+		if address == nil {
+			return nil, nil
+		}
+	}
+
+	bitCount := uint(len(address) * 8)
+	for i := uint(0); i < bitCount && node < r.nodeCount; i++ {
+		bit := uint(address[i>>3]>>(7-(i&7))) & 1
+		next, err := r.readNode(node, bit)
+		if err != nil {
+			return nil, err
+		}
+		node = next
+	}
+
+	if node == r.nodeCount {
+		return nil, nil
+	}
+	if node < r.nodeCount {
+		return nil, ErrInvalidDatabase
+	}
+
+	offset := node - r.nodeCount - dataSectionSeparator
+	value, _, err := r.decoder.decode(offset)
+	if err != nil {
+		return nil, err
+	}
+	record, _ := value.(map[string]interface{})
+	return record, nil
+}
+
+// readNode returns the left (bit 0) or right (bit 1) record of a node.
+func (r *Reader) readNode(node, bit uint) (uint, error) {
+	offset := node * r.recordSize / 4
+	if offset+r.recordSize/4 > uint(len(r.buffer)) {
+		return 0, ErrInvalidDatabase
+	}
+	b := r.buffer[offset:]
+
+	switch r.recordSize {
+	case 24:
+		b = b[bit*3:]
+		return uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2]), nil
+	case 28:
+		if bit == 0 {
+			return uint(b[3]&0xF0)<<20 | uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2]), nil
+		}
+		return uint(b[3]&0x0F)<<24 | uint(b[4])<<16 | uint(b[5])<<8 | uint(b[6]), nil
+	default:
+		b = b[bit*4:]
+		return uint(b[0])<<24 | uint(b[1])<<16 | uint(b[2])<<8 | uint(b[3]), nil
+	}
+}
+
+// Distance returns the great-circle distance between two locations in
+// kilometres.
+func Distance(a, b *Location) float64 {
+	const earthRadius = 6371.0
+
+	lat1 := a.Latitude * math.Pi / 180
+	lat2 := b.Latitude * math.Pi / 180
+	deltaLat := lat2 - lat1
+	deltaLon := (b.Longitude - a.Longitude) * math.Pi / 180
+
+	h := math.Sin(deltaLat/2)*math.Sin(deltaLat/2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Sin(deltaLon/2)*math.Sin(deltaLon/2)
+	return 2 * earthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+func path(value interface{}, keys ...string) interface{} {
+	for _, key := range keys {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = m[key]
+	}
+	return value
+}
+
+func toString(value interface{}) string {
+	s, _ := value.(string)
+	return s
+}
+
+func toUint(value interface{}) uint {
+	switch v := value.(type) {
+	case uint64:
+		return uint(v)
+	case uint32:
+		return uint(v)
+	case uint16:
+		return uint(v)
+	default:
+		return 0
+	}
+}
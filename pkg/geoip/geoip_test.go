@@ -0,0 +1,328 @@
+package geoip
+
+import (
+	"errors"
+	"math"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// testNode is a search tree node of a database under construction. A
+// record holds either a child node or a data section offset plus one.
+type testNode struct {
+	child [2]*testNode
+	data  [2]uint
+}
+
+// testDatabase assembles a MaxMind DB file: search tree, data section and
+// metadata.
+type testDatabase struct {
+	ipVersion  uint16
+	recordSize uint16
+	root       *testNode
+	data       encoder
+}
+
+func newTestDatabase(ipVersion, recordSize uint16) *testDatabase {
+	return &testDatabase{ipVersion: ipVersion, recordSize: recordSize, root: &testNode{}}
+}
+
+// insert maps the network to the data at offset. IPv4 networks go under
+// ::/96 in IPv6 databases.
+func (db *testDatabase) insert(t *testing.T, cidr string, offset uint) {
+	t.Helper()
+
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := []byte(network.IP)
+	prefix, _ := network.Mask.Size()
+	if db.ipVersion == 6 && len(address) == net.IPv4len {
+		address = append(make([]byte, 12), address...)
+		prefix += 96
+	}
+
+	node := db.root
+	for i := 0; i < prefix; i++ {
+		bit := address[i/8] >> (7 - uint(i%8)) & 1
+		if i == prefix-1 {
+			node.data[bit] = offset + 1
+			return
+		}
+		if node.child[bit] == nil {
+			node.child[bit] = &testNode{}
+		}
+		node = node.child[bit]
+	}
+}
+
+func (db *testDatabase) bytes(t *testing.T) []byte {
+	t.Helper()
+
+	nodes := []*testNode{db.root}
+	index := map[*testNode]uint{db.root: 0}
+	for i := 0; i < len(nodes); i++ {
+		for _, child := range nodes[i].child {
+			if child != nil {
+				index[child] = uint(len(nodes))
+				nodes = append(nodes, child)
+			}
+		}
+	}
+	nodeCount := uint(len(nodes))
+
+	var file []byte
+	for _, node := range nodes {
+		var records [2]uint
+		for bit := range records {
+			switch {
+			case node.child[bit] != nil:
+				records[bit] = index[node.child[bit]]
+			case node.data[bit] != 0:
+				records[bit] = nodeCount + dataSectionSeparator + node.data[bit] - 1
+			default:
+				records[bit] = nodeCount
+			}
+		}
+		file = appendNode(file, db.recordSize, records[0], records[1])
+	}
+
+	file = append(file, make([]byte, dataSectionSeparator)...)
+	file = append(file, db.data.buf...)
+	file = append(file, metadataMarker...)
+
+	var metadata encoder
+	metadata.encode(map[string]interface{}{
+		"binary_format_major_version": uint16(2),
+		"binary_format_minor_version": uint16(0),
+		"database_type":               "Test-City",
+		"ip_version":                  db.ipVersion,
+		"languages":                   []interface{}{"en"},
+		"node_count":                  uint32(nodeCount),
+		"record_size":                 db.recordSize,
+	})
+	return append(file, metadata.buf...)
+}
+
+func appendNode(buf []byte, recordSize uint16, left, right uint) []byte {
+	switch recordSize {
+	case 24:
+		buf = appendUint(buf, uint64(left), 3)
+		return appendUint(buf, uint64(right), 3)
+	case 28:
+		buf = appendUint(buf, uint64(left&0xFFFFFF), 3)
+		buf = append(buf, byte(left>>24&0x0F)<<4|byte(right>>24&0x0F))
+		return appendUint(buf, uint64(right&0xFFFFFF), 3)
+	default:
+		buf = appendUint(buf, uint64(left), 4)
+		return appendUint(buf, uint64(right), 4)
+	}
+}
+
+// fixture holds two cities sharing a country through a pointer, a record
+// without coordinates and, in IPv6 databases, an IPv6 network.
+func fixture(t *testing.T, ipVersion, recordSize uint16) []byte {
+	t.Helper()
+
+	db := newTestDatabase(ipVersion, recordSize)
+	country := db.data.encode(map[string]interface{}{
+		"iso_code": "GB",
+		"names":    map[string]interface{}{"en": "United Kingdom", "de": "Vereinigtes Königreich"},
+	})
+	london := db.data.encode(map[string]interface{}{
+		"city":     map[string]interface{}{"names": map[string]interface{}{"en": "London"}},
+		"country":  pointerTo(country),
+		"location": map[string]interface{}{"latitude": 51.5142, "longitude": -0.0931, "accuracy_radius": uint16(10)},
+	})
+	boxford := db.data.encode(map[string]interface{}{
+		"city":     map[string]interface{}{"names": map[string]interface{}{"en": "Boxford"}},
+		"country":  pointerTo(country),
+		"location": map[string]interface{}{"latitude": 51.75, "longitude": -1.25},
+	})
+	sweden := db.data.encode(map[string]interface{}{
+		"country": map[string]interface{}{"iso_code": "SE", "names": map[string]interface{}{"en": "Sweden"}},
+	})
+
+	db.insert(t, "81.2.69.0/24", london)
+	db.insert(t, "2.125.160.216/29", boxford)
+	db.insert(t, "89.160.20.112/28", sweden)
+	if ipVersion == 6 {
+		db.insert(t, "2001:db8::/32", sweden)
+	}
+	return db.bytes(t)
+}
+
+func TestLookup(t *testing.T) {
+	london := &Location{CountryCode: "GB", Country: "United Kingdom", City: "London", Latitude: 51.5142, Longitude: -0.0931, HasCoordinates: true}
+	boxford := &Location{CountryCode: "GB", Country: "United Kingdom", City: "Boxford", Latitude: 51.75, Longitude: -1.25, HasCoordinates: true}
+	sweden := &Location{CountryCode: "SE", Country: "Sweden"}
+
+	tests := map[string]struct {
+		ip   string
+		ipv4 *Location
+		ipv6 *Location
+	}{
+		"city":             {ip: "81.2.69.142", ipv4: london, ipv6: london},
+		"first of network": {ip: "81.2.69.0", ipv4: london, ipv6: london},
+		"shared country":   {ip: "2.125.160.218", ipv4: boxford, ipv6: boxford},
+		"outside network":  {ip: "2.125.160.224"},
+		"country only":     {ip: "89.160.20.120", ipv4: sweden, ipv6: sweden},
+		"unknown":          {ip: "8.8.8.8"},
+		"IPv4-mapped IPv6": {ip: "::ffff:81.2.69.142", ipv4: london, ipv6: london},
+		"IPv6":             {ip: "2001:db8::1", ipv6: sweden},
+		"unknown IPv6":     {ip: "2001:db9::1"},
+		"IPv4-compatible":  {ip: "::81.2.69.142", ipv6: london},
+	}
+
+	for _, ipVersion := range []uint16{4, 6} {
+		for _, recordSize := range []uint16{24, 28, 32} {
+			reader, err := New(fixture(t, ipVersion, recordSize))
+			if err != nil {
+				t.Fatalf("IPv%d, %d bit records: %v", ipVersion, recordSize, err)
+			}
+
+			for name, test := range tests {
+				want := test.ipv4
+				if ipVersion == 6 {
+					want = test.ipv6
+				}
+
+				got, err := reader.Lookup(net.ParseIP(test.ip))
+				if err != nil {
+					t.Errorf("IPv%d, %d bit records, %s: %v", ipVersion, recordSize, name, err)
+					continue
+				}
+				if (got == nil) != (want == nil) || got != nil && *got != *want {
+					t.Errorf("IPv%d, %d bit records, %s: Lookup = %+v, want %+v", ipVersion, recordSize, name, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestOpen(t *testing.T) {
+	dir := t.TempDir()
+
+	valid := filepath.Join(dir, "valid.mmdb")
+	if err := os.WriteFile(valid, fixture(t, 6, 28), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	reader, err := Open(valid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if location, err := reader.Lookup(net.ParseIP("81.2.69.142")); err != nil || location == nil || location.City != "London" {
+		t.Fatalf("Lookup = %+v, %v, want London", location, err)
+	}
+
+	if _, err := Open(filepath.Join(dir, "missing.mmdb")); err == nil {
+		t.Fatal("Open succeeded for a missing file")
+	}
+
+	corrupt := filepath.Join(dir, "corrupt.mmdb")
+	if err := os.WriteFile(corrupt, []byte("not a MaxMind database"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open(corrupt); !errors.Is(err, ErrInvalidDatabase) {
+		t.Fatalf("Open err = %v, want %v", err, ErrInvalidDatabase)
+	}
+}
+
+func TestNewRejectsCorruptDatabase(t *testing.T) {
+	withMetadata := func(metadata interface{}) []byte {
+		var e encoder
+		e.encode(metadata)
+		return append(append(make([]byte, 64), metadataMarker...), e.buf...)
+	}
+
+	valid := fixture(t, 4, 24)
+
+	tests := map[string][]byte{
+		"empty":                 nil,
+		"no metadata":           make([]byte, 128),
+		"truncated metadata":    valid[:len(valid)-10],
+		"metadata is not a map": withMetadata("metadata"),
+		"unsupported record size": withMetadata(map[string]interface{}{
+			"node_count": uint32(1), "record_size": uint16(20), "ip_version": uint16(4),
+		}),
+		"tree larger than file": withMetadata(map[string]interface{}{
+			"node_count": uint32(1000), "record_size": uint16(24), "ip_version": uint16(4),
+		}),
+	}
+
+	for name, buffer := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := New(buffer); !errors.Is(err, ErrInvalidDatabase) {
+				t.Fatalf("New err = %v, want %v", err, ErrInvalidDatabase)
+			}
+		})
+	}
+}
+
+func TestLookupRejectsCorruptRecords(t *testing.T) {
+	tests := map[string]func(db *testDatabase) uint{
+		"data offset past the data section": func(db *testDatabase) uint {
+			db.data.encode("padding")
+			return 4096
+		},
+		"data is a pointer loop": func(db *testDatabase) uint {
+			offset := uint(len(db.data.buf))
+			db.data.encode(pointerTo(offset))
+			return offset
+		},
+	}
+
+	for name, corrupt := range tests {
+		t.Run(name, func(t *testing.T) {
+			db := newTestDatabase(4, 24)
+			db.insert(t, "81.2.69.0/24", corrupt(db))
+
+			reader, err := New(db.bytes(t))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := reader.Lookup(net.ParseIP("81.2.69.142")); !errors.Is(err, ErrInvalidDatabase) {
+				t.Fatalf("Lookup err = %v, want %v", err, ErrInvalidDatabase)
+			}
+		})
+	}
+}
+
+func TestLocationString(t *testing.T) {
+	tests := map[string]struct {
+		location Location
+		want     string
+	}{
+		"city and country": {location: Location{City: "London", Country: "United Kingdom"}, want: "London, United Kingdom"},
+		"country only":     {location: Location{Country: "Sweden"}, want: "Sweden"},
+		"city only":        {location: Location{City: "London"}, want: "London"},
+		"unknown":          {want: ""},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := test.location.String(); got != test.want {
+				t.Fatalf("String = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestDistance(t *testing.T) {
+	london := &Location{Latitude: 51.5142, Longitude: -0.0931}
+	paris := &Location{Latitude: 48.8566, Longitude: 2.3522}
+
+	if got := Distance(london, london); got != 0 {
+		t.Fatalf("Distance to itself = %v, want 0", got)
+	}
+	// About 343 km apart
+	if got := Distance(london, paris); math.Abs(got-343) > 2 {
+		t.Fatalf("Distance = %.1f km, want about 343", got)
+	}
+	if Distance(london, paris) != Distance(paris, london) {
+		t.Fatal("Distance is not symmetric")
+	}
+}
@@ -0,0 +1,64 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+)
+
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	Curve     string `json:"crv,omitempty"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+	X         string `json:"x,omitempty"`
+	Y         string `json:"y,omitempty"`
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+func toJWK(key *signingKey) (JWK, bool) {
+	jwk := JWK{
+		KeyID:     key.id,
+		Use:       "sig",
+		Algorithm: key.method.Alg(),
+	}
+
+	switch publicKey := key.publicKey.(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.N = encodeSegment(publicKey.N.Bytes())
+		jwk.E = encodeSegment(big.NewInt(int64(publicKey.E)).Bytes())
+	case *ecdsa.PublicKey:
+		ecdhKey, err := publicKey.ECDH()
+		if err != nil {
+			return JWK{}, false
+		}
+		// Uncompressed point encoding: 0x04 || X || Y
+		point := ecdhKey.Bytes()
+		size := (len(point) - 1) / 2
+		jwk.KeyType = "EC"
+		jwk.Curve = publicKey.Curve.Params().Name
+		jwk.X = encodeSegment(point[1 : 1+size])
+		jwk.Y = encodeSegment(point[1+size:])
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = encodeSegment(publicKey)
+	default:
+		return JWK{}, false
+	}
+
+	return jwk, true
+}
+
+func encodeSegment(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
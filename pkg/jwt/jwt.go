@@ -2,6 +2,7 @@ package jwt
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -11,16 +12,17 @@ import (
 )
 
 type JWTService interface {
-	GenerateAccessToken(userID uuid.UUID, email string) (string, error)
-	GenerateRefreshToken(userID uuid.UUID, email string) (string, error)
+	GenerateAccessToken(userID uuid.UUID, email string, opts *TokenOptions) (string, error)
+	GenerateRefreshToken(userID uuid.UUID, email string, opts *TokenOptions) (string, error)
+	GenerateClientToken(clientID string, scopes []string, expiry time.Duration) (string, error)
 	ValidateToken(tokenString string) (*Claims, error)
-	RefreshAccessToken(refreshToken string) (string, error)
 	GetAccessTokenExpirationTime() int64
 	GetRefreshTokenExpirationTime() int64
+	JWKS() JWKSet
 }
 
 type Service struct {
-	secret        []byte
+	keys          *keyRing
 	accessExpiry  time.Duration
 	refreshExpiry time.Duration
 	issuer        string
@@ -29,69 +31,208 @@ type Service struct {
 
 var _ JWTService = (*Service)(nil)
 
-func NewService(cfg config.JWT) *Service {
+func NewService(cfg config.JWT) (*Service, error) {
+	// Retired keys must outlive every token they signed by default
+	gracePeriod := cfg.RotationGracePeriod
+	if gracePeriod <= 0 {
+		gracePeriod = cfg.RefreshExpiry
+	}
+
+	keys, err := newKeyRing(cfg, gracePeriod)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Service{
-		secret:        []byte(cfg.Secret),
+		keys:          keys,
 		accessExpiry:  cfg.AccessExpiry,
 		refreshExpiry: cfg.RefreshExpiry,
 		issuer:        cfg.Issuer,
 		audience:      cfg.Audience,
-	}
+	}, nil
 }
 
 type Claims struct {
-	UserID uuid.UUID `json:"user_id"`
-	Email  string    `json:"email"`
-	Type   string    `json:"type"` // "access" or "refresh"
+	UserID       uuid.UUID `json:"user_id"`
+	Email        string    `json:"email"`
+	Type         string    `json:"type"`                // "access" or "refresh"
+	SessionID    string    `json:"sid,omitempty"`       // Session the token was issued to
+	TokenVersion int64     `json:"ver"`                 // User token version at issue time
+	ClientID     string    `json:"client_id,omitempty"` // Set on client credentials tokens, which have no user
+	Scope        string    `json:"scope,omitempty"`     // Space-delimited scopes of a client token
+	Actor        *Actor    `json:"act,omitempty"`       // Set when someone else acts as the user
+	Roles        []string  `json:"roles,omitempty"`     // Role snapshot, present when AuthzVersion is set
+	Permissions  []string  `json:"perms,omitempty"`     // Permission snapshot, present when AuthzVersion is set
+	AuthzVersion int64     `json:"authz_ver,omitempty"` // Authorization version the snapshot was taken at
+	AuthTime     int64     `json:"auth_time,omitempty"` // When the user last proved their identity
+	AMR          []string  `json:"amr,omitempty"`       // How they did it, see the AMR constants
 	jwt.RegisteredClaims
 }
 
-func (s *Service) GenerateAccessToken(userID uuid.UUID, email string) (string, error) {
+// Authentication method references for the "amr" claim. The first four
+// are registered by RFC 8176; the others name the sign-in flows that have
+// no registered value.
+const (
+	AMRPassword    = "pwd"
+	AMROTP         = "otp"
+	AMRMultiFactor = "mfa"
+	AMRHardwareKey = "hwk"
+	AMRFederated   = "fed"
+	AMREmailLink   = "email"
+	AMRRecovery    = "recovery" // An MFA recovery code stood in for the authenticator
+)
+
+// Actor is the RFC 8693 "act" claim: the party actually using a token
+// issued in the name of its subject.
+type Actor struct {
+	Subject string `json:"sub"`
+}
+
+// TokenOptions carries the server-side identifiers embedded into a token.
+// A missing TokenID is generated, so callers only set it when they need to
+// track the jti themselves. ActorID adds an "act" claim and Expiry
+// overrides the configured lifetime. A non-zero AuthzVersion embeds Roles
+// and Permissions into access tokens. A non-zero AuthTime adds the
+// "auth_time" and "amr" claims.
+type TokenOptions struct {
+	TokenID      string
+	SessionID    string
+	TokenVersion int64
+	ActorID      string
+	Expiry       time.Duration
+	Roles        []string
+	Permissions  []string
+	AuthzVersion int64
+	AuthTime     time.Time
+	AMR          []string
+}
+
+func (s *Service) GenerateAccessToken(userID uuid.UUID, email string, opts *TokenOptions) (string, error) {
+	if opts == nil {
+		opts = &TokenOptions{}
+	}
+
+	tokenID := opts.TokenID
+	if tokenID == "" {
+		tokenID = uuid.New().String()
+	}
+
+	expiry := s.accessExpiry
+	if opts.Expiry > 0 {
+		expiry = opts.Expiry
+	}
+
 	now := time.Now()
 	claims := Claims{
-		UserID: userID,
-		Email:  email,
-		Type:   "access",
+		UserID:       userID,
+		Email:        email,
+		Type:         "access",
+		SessionID:    opts.SessionID,
+		TokenVersion: opts.TokenVersion,
 		RegisteredClaims: jwt.RegisteredClaims{
 			Issuer:    s.issuer,
 			Audience:  jwt.ClaimStrings{s.audience},
 			Subject:   userID.String(),
-			ExpiresAt: jwt.NewNumericDate(now.Add(s.accessExpiry)),
+			ID:        tokenID,
+			ExpiresAt: jwt.NewNumericDate(now.Add(expiry)),
 			NotBefore: jwt.NewNumericDate(now),
 			IssuedAt:  jwt.NewNumericDate(now),
 		},
 	}
+	if opts.ActorID != "" {
+		claims.Actor = &Actor{Subject: opts.ActorID}
+	}
+	if opts.AuthzVersion != 0 {
+		claims.Roles = opts.Roles
+		claims.Permissions = opts.Permissions
+		claims.AuthzVersion = opts.AuthzVersion
+	}
+	if !opts.AuthTime.IsZero() {
+		claims.AuthTime = opts.AuthTime.Unix()
+		claims.AMR = opts.AMR
+	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(s.secret)
+	return s.sign(claims)
 }
 
-func (s *Service) GenerateRefreshToken(userID uuid.UUID, email string) (string, error) {
+func (s *Service) GenerateRefreshToken(userID uuid.UUID, email string, opts *TokenOptions) (string, error) {
+	if opts == nil {
+		opts = &TokenOptions{}
+	}
+
+	tokenID := opts.TokenID
+	if tokenID == "" {
+		tokenID = uuid.New().String()
+	}
+
 	now := time.Now()
 	claims := Claims{
-		UserID: userID,
-		Email:  email,
-		Type:   "refresh",
+		UserID:       userID,
+		Email:        email,
+		Type:         "refresh",
+		SessionID:    opts.SessionID,
+		TokenVersion: opts.TokenVersion,
 		RegisteredClaims: jwt.RegisteredClaims{
 			Issuer:    s.issuer,
 			Audience:  jwt.ClaimStrings{s.audience},
 			Subject:   userID.String(),
+			ID:        tokenID,
 			ExpiresAt: jwt.NewNumericDate(now.Add(s.refreshExpiry)),
 			NotBefore: jwt.NewNumericDate(now),
 			IssuedAt:  jwt.NewNumericDate(now),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(s.secret)
+	return s.sign(claims)
+}
+
+// GenerateClientToken issues an access token to an OAuth client acting on
+// its own behalf. The subject is the client ID and no user is attached.
+func (s *Service) GenerateClientToken(clientID string, scopes []string, expiry time.Duration) (string, error) {
+	if expiry <= 0 {
+		expiry = s.accessExpiry
+	}
+
+	now := time.Now()
+	claims := Claims{
+		Type:     "access",
+		ClientID: clientID,
+		Scope:    strings.Join(scopes, " "),
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    s.issuer,
+			Audience:  jwt.ClaimStrings{s.audience},
+			Subject:   clientID,
+			ID:        uuid.New().String(),
+			ExpiresAt: jwt.NewNumericDate(now.Add(expiry)),
+			NotBefore: jwt.NewNumericDate(now),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	}
+
+	return s.sign(claims)
+}
+
+func (s *Service) sign(claims Claims) (string, error) {
+	key := s.keys.current(time.Now())
+
+	token := jwt.NewWithClaims(key.method, claims)
+	if key.id != "" {
+		token.Header["kid"] = key.id
+	}
+	return token.SignedString(key.signKey)
 }
 
 func (s *Service) ValidateToken(tokenString string) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		kid, _ := token.Header["kid"].(string)
+		key := s.keys.lookup(kid, time.Now())
+		if key == nil {
+			return nil, apperrors.NewUnauthorizedError(fmt.Sprintf("unknown signing key: %q", kid))
+		}
+		if token.Method.Alg() != key.method.Alg() {
 			return nil, apperrors.NewUnauthorizedError(fmt.Sprintf("unexpected signing method: %v", token.Header["alg"]))
 		}
-		return s.secret, nil
+		return key.verifyKey, nil
 	})
 
 	if err != nil {
@@ -105,19 +246,6 @@ func (s *Service) ValidateToken(tokenString string) (*Claims, error) {
 	return nil, apperrors.NewUnauthorizedError("invalid token")
 }
 
-func (s *Service) RefreshAccessToken(refreshToken string) (string, error) {
-	claims, err := s.ValidateToken(refreshToken)
-	if err != nil {
-		return "", apperrors.NewUnauthorizedError("invalid refresh token")
-	}
-
-	if claims.Type != "refresh" {
-		return "", apperrors.NewUnauthorizedError("token is not a refresh token")
-	}
-
-	return s.GenerateAccessToken(claims.UserID, claims.Email)
-}
-
 func (s *Service) GetAccessTokenExpirationTime() int64 {
 	return time.Now().Add(s.accessExpiry).Unix()
 }
@@ -126,6 +254,16 @@ func (s *Service) GetRefreshTokenExpirationTime() int64 {
 	return time.Now().Add(s.refreshExpiry).Unix()
 }
 
+func (s *Service) JWKS() JWKSet {
+	set := JWKSet{Keys: []JWK{}}
+	for _, key := range s.keys.published(time.Now()) {
+		if jwk, ok := toJWK(key); ok {
+			set.Keys = append(set.Keys, jwk)
+		}
+	}
+	return set
+}
+
 func ExtractTokenFromHeader(authHeader string) string {
 	if len(authHeader) > 7 && authHeader[:7] == "Bearer " {
 		return authHeader[7:]
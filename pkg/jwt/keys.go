@@ -0,0 +1,238 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/config"
+)
+
+// signingKey is a single entry of the key ring. HMAC keys have no public
+// half and are therefore never published in the JWKS.
+type signingKey struct {
+	id         string
+	method     jwt.SigningMethod
+	signKey    interface{}
+	verifyKey  interface{}
+	publicKey  crypto.PublicKey
+	activeFrom time.Time
+	retiredAt  time.Time // Zero while the key has not been superseded
+}
+
+func (k *signingKey) canSign() bool {
+	return k.signKey != nil
+}
+
+// keyRing holds every configured key ordered by activation time. The key
+// used for signing is the newest one whose activation time has passed;
+// older keys keep verifying tokens for the grace period after they were
+// superseded so that tokens issued just before a rotation stay valid.
+type keyRing struct {
+	keys        []*signingKey
+	gracePeriod time.Duration
+}
+
+func newKeyRing(cfg config.JWT, gracePeriod time.Duration) (*keyRing, error) {
+	if len(cfg.Keys) == 0 {
+		return &keyRing{
+			keys: []*signingKey{{
+				method:    jwt.SigningMethodHS256,
+				signKey:   []byte(cfg.Secret),
+				verifyKey: []byte(cfg.Secret),
+			}},
+			gracePeriod: gracePeriod,
+		}, nil
+	}
+
+	loadedAt := time.Now()
+	keys := make([]*signingKey, 0, len(cfg.Keys))
+	seen := make(map[string]bool, len(cfg.Keys))
+	for _, keyCfg := range cfg.Keys {
+		if keyCfg.ID == "" {
+			return nil, fmt.Errorf("jwt key id is required")
+		}
+		if seen[keyCfg.ID] {
+			return nil, fmt.Errorf("duplicate jwt key id %q", keyCfg.ID)
+		}
+		seen[keyCfg.ID] = true
+
+		key, err := loadKey(keyCfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load jwt key %q: %w", keyCfg.ID, err)
+		}
+
+		// Keys without an explicit schedule become active as soon as they are loaded
+		key.activeFrom = loadedAt
+		if keyCfg.ActiveFrom != "" {
+			activeFrom, err := time.Parse(time.RFC3339, keyCfg.ActiveFrom)
+			if err != nil {
+				return nil, fmt.Errorf("invalid active_from for jwt key %q: %w", keyCfg.ID, err)
+			}
+			key.activeFrom = activeFrom
+		}
+
+		keys = append(keys, key)
+	}
+
+	sort.SliceStable(keys, func(i, j int) bool {
+		return keys[i].activeFrom.Before(keys[j].activeFrom)
+	})
+
+	var previous *signingKey
+	for _, key := range keys {
+		if !key.canSign() {
+			continue
+		}
+		if previous != nil {
+			previous.retiredAt = key.activeFrom
+		}
+		previous = key
+	}
+
+	if previous == nil {
+		return nil, fmt.Errorf("at least one jwt key with a private key is required")
+	}
+
+	return &keyRing{
+		keys:        keys,
+		gracePeriod: gracePeriod,
+	}, nil
+}
+
+func loadKey(cfg config.JWTKey) (*signingKey, error) {
+	if cfg.PrivateKeyPath == "" && cfg.PublicKeyPath == "" {
+		return nil, fmt.Errorf("private_key_path or public_key_path is required")
+	}
+
+	key := &signingKey{id: cfg.ID}
+
+	var privatePEM, publicPEM []byte
+	var err error
+	if cfg.PrivateKeyPath != "" {
+		if privatePEM, err = os.ReadFile(cfg.PrivateKeyPath); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.PublicKeyPath != "" {
+		if publicPEM, err = os.ReadFile(cfg.PublicKeyPath); err != nil {
+			return nil, err
+		}
+	}
+
+	switch cfg.Algorithm {
+	case "RS256":
+		key.method = jwt.SigningMethodRS256
+		if privatePEM != nil {
+			privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(privatePEM)
+			if err != nil {
+				return nil, err
+			}
+			key.signKey = privateKey
+			key.publicKey = &privateKey.PublicKey
+		} else {
+			if key.publicKey, err = jwt.ParseRSAPublicKeyFromPEM(publicPEM); err != nil {
+				return nil, err
+			}
+		}
+	case "ES256":
+		key.method = jwt.SigningMethodES256
+		var publicKey *ecdsa.PublicKey
+		if privatePEM != nil {
+			privateKey, err := jwt.ParseECPrivateKeyFromPEM(privatePEM)
+			if err != nil {
+				return nil, err
+			}
+			key.signKey = privateKey
+			publicKey = &privateKey.PublicKey
+		} else {
+			if publicKey, err = jwt.ParseECPublicKeyFromPEM(publicPEM); err != nil {
+				return nil, err
+			}
+		}
+		if publicKey.Curve != elliptic.P256() {
+			return nil, fmt.Errorf("ES256 requires a P-256 key")
+		}
+		key.publicKey = publicKey
+	case "EdDSA":
+		key.method = jwt.SigningMethodEdDSA
+		if privatePEM != nil {
+			privateKey, err := jwt.ParseEdPrivateKeyFromPEM(privatePEM)
+			if err != nil {
+				return nil, err
+			}
+			edKey, ok := privateKey.(ed25519.PrivateKey)
+			if !ok {
+				return nil, fmt.Errorf("EdDSA requires an Ed25519 key")
+			}
+			key.signKey = edKey
+			key.publicKey = edKey.Public()
+		} else {
+			if key.publicKey, err = jwt.ParseEdPublicKeyFromPEM(publicPEM); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unsupported algorithm %q", cfg.Algorithm)
+	}
+
+	key.verifyKey = key.publicKey
+	return key, nil
+}
+
+// current returns the key new tokens are signed with at the given time.
+func (r *keyRing) current(now time.Time) *signingKey {
+	var current *signingKey
+	for _, key := range r.keys {
+		if !key.canSign() || key.activeFrom.After(now) {
+			continue
+		}
+		current = key
+	}
+	if current == nil {
+		// Every key is scheduled for the future, fall back to the earliest one
+		for _, key := range r.keys {
+			if key.canSign() {
+				return key
+			}
+		}
+	}
+	return current
+}
+
+// lookup returns the key matching kid if it is still allowed to verify tokens.
+func (r *keyRing) lookup(kid string, now time.Time) *signingKey {
+	for _, key := range r.keys {
+		if key.id != kid {
+			continue
+		}
+		if r.expired(key, now) {
+			return nil
+		}
+		return key
+	}
+	return nil
+}
+
+// published returns the public keys that should currently be exposed,
+// including keys scheduled for activation so verifiers can cache them early.
+func (r *keyRing) published(now time.Time) []*signingKey {
+	keys := make([]*signingKey, 0, len(r.keys))
+	for _, key := range r.keys {
+		if key.publicKey == nil || r.expired(key, now) {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func (r *keyRing) expired(key *signingKey, now time.Time) bool {
+	return !key.retiredAt.IsZero() && now.After(key.retiredAt.Add(r.gracePeriod))
+}
@@ -0,0 +1,323 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/tranvuongduy2003/go-mvc/internal/infrastructure/config"
+)
+
+const testGracePeriod = time.Hour
+
+// writeKey stores the PEM encoding of a private key, or of its public half
+// when publicOnly is set, and returns the file path.
+func writeKey(t *testing.T, key crypto.Signer, publicOnly bool) string {
+	t.Helper()
+
+	var block *pem.Block
+	if publicOnly {
+		der, err := x509.MarshalPKIXPublicKey(key.Public())
+		if err != nil {
+			t.Fatal(err)
+		}
+		block = &pem.Block{Type: "PUBLIC KEY", Bytes: der}
+	} else {
+		der, err := x509.MarshalPKCS8PrivateKey(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		block = &pem.Block{Type: "PRIVATE KEY", Bytes: der}
+	}
+
+	path := filepath.Join(t.TempDir(), "key.pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(block), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func newECKey(t *testing.T, curve elliptic.Curve) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(curve, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func newEd25519Key(t *testing.T) ed25519.PrivateKey {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func signingKeyConfig(t *testing.T, id string, activeFrom time.Time) config.JWTKey {
+	t.Helper()
+	return config.JWTKey{
+		ID:             id,
+		Algorithm:      "ES256",
+		PrivateKeyPath: writeKey(t, newECKey(t, elliptic.P256()), false),
+		ActiveFrom:     activeFrom.Format(time.RFC3339),
+	}
+}
+
+func keyIDs(keys []*signingKey) []string {
+	ids := make([]string, 0, len(keys))
+	for _, key := range keys {
+		ids = append(ids, key.id)
+	}
+	return ids
+}
+
+func equalIDs(got, want []string) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestKeyRingFallsBackToSecret(t *testing.T) {
+	ring, err := newKeyRing(config.JWT{Secret: "secret"}, testGracePeriod)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	current := ring.current(now)
+	if current == nil || current.method != jwt.SigningMethodHS256 {
+		t.Fatalf("current = %+v, want the HS256 secret", current)
+	}
+	if ring.lookup("", now) != current {
+		t.Fatal("lookup without kid did not return the secret")
+	}
+	if published := ring.published(now); len(published) != 0 {
+		t.Fatalf("published %v, want no keys for an HMAC secret", keyIDs(published))
+	}
+}
+
+func TestKeyRingRotation(t *testing.T) {
+	rotation := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+	ring, err := newKeyRing(config.JWT{Keys: []config.JWTKey{
+		// Configured out of order on purpose: the ring sorts by activation
+		signingKeyConfig(t, "new", rotation),
+		signingKeyConfig(t, "old", rotation.Add(-48*time.Hour)),
+		{
+			ID:            "verify-only",
+			Algorithm:     "EdDSA",
+			PublicKeyPath: writeKey(t, newEd25519Key(t), true),
+			ActiveFrom:    rotation.Add(-72 * time.Hour).Format(time.RFC3339),
+		},
+	}}, testGracePeriod)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		now       time.Time
+		current   string
+		lookup    map[string]bool
+		published []string
+	}{
+		"before rotation": {
+			now:       rotation.Add(-time.Minute),
+			current:   "old",
+			lookup:    map[string]bool{"old": true, "new": true, "verify-only": true},
+			published: []string{"verify-only", "old", "new"},
+		},
+		"at rotation": {
+			now:       rotation,
+			current:   "new",
+			lookup:    map[string]bool{"old": true, "new": true},
+			published: []string{"verify-only", "old", "new"},
+		},
+		"within grace period": {
+			now:       rotation.Add(testGracePeriod),
+			current:   "new",
+			lookup:    map[string]bool{"old": true, "new": true},
+			published: []string{"verify-only", "old", "new"},
+		},
+		"after grace period": {
+			now:       rotation.Add(testGracePeriod + time.Second),
+			current:   "new",
+			lookup:    map[string]bool{"old": false, "new": true, "verify-only": true, "unknown": false},
+			published: []string{"verify-only", "new"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if current := ring.current(test.now); current == nil || current.id != test.current {
+				t.Fatalf("current = %v, want %s", current, test.current)
+			}
+			for kid, want := range test.lookup {
+				if got := ring.lookup(kid, test.now) != nil; got != want {
+					t.Errorf("lookup(%q) found = %v, want %v", kid, got, want)
+				}
+			}
+			if got := keyIDs(ring.published(test.now)); !equalIDs(got, test.published) {
+				t.Errorf("published = %v, want %v", got, test.published)
+			}
+		})
+	}
+}
+
+func TestKeyRingUsesEarliestKeyWhenAllAreScheduled(t *testing.T) {
+	now := time.Now()
+	ring, err := newKeyRing(config.JWT{Keys: []config.JWTKey{
+		signingKeyConfig(t, "later", now.Add(48*time.Hour)),
+		signingKeyConfig(t, "sooner", now.Add(24*time.Hour)),
+	}}, testGracePeriod)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if current := ring.current(now); current == nil || current.id != "sooner" {
+		t.Fatalf("current = %v, want sooner", current)
+	}
+}
+
+func TestNewKeyRingRejectsInvalidConfig(t *testing.T) {
+	valid := signingKeyConfig(t, "valid", time.Now())
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string][]config.JWTKey{
+		"missing id":           {{Algorithm: "ES256", PrivateKeyPath: valid.PrivateKeyPath}},
+		"duplicate id":         {valid, valid},
+		"no key file":          {{ID: "none", Algorithm: "ES256"}},
+		"missing file":         {{ID: "missing", Algorithm: "ES256", PrivateKeyPath: filepath.Join(t.TempDir(), "missing.pem")}},
+		"unsupported":          {{ID: "hs", Algorithm: "HS512", PrivateKeyPath: valid.PrivateKeyPath}},
+		"algorithm mismatch":   {{ID: "rsa", Algorithm: "ES256", PrivateKeyPath: writeKey(t, rsaKey, false)}},
+		"wrong curve":          {{ID: "p384", Algorithm: "ES256", PrivateKeyPath: writeKey(t, newECKey(t, elliptic.P384()), false)}},
+		"invalid active_from":  {{ID: "bad", Algorithm: "ES256", PrivateKeyPath: valid.PrivateKeyPath, ActiveFrom: "tomorrow"}},
+		"no private key":       {{ID: "public", Algorithm: "RS256", PublicKeyPath: writeKey(t, rsaKey, true)}},
+		"ed25519 for RS256":    {{ID: "ed", Algorithm: "RS256", PrivateKeyPath: writeKey(t, newEd25519Key(t), false)}},
+		"P-256 key for EdDSA":  {{ID: "ec", Algorithm: "EdDSA", PrivateKeyPath: valid.PrivateKeyPath}},
+		"public key as signer": {{ID: "swap", Algorithm: "ES256", PrivateKeyPath: writeKey(t, newECKey(t, elliptic.P256()), true)}},
+	}
+
+	for name, keys := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := newKeyRing(config.JWT{Keys: keys}, testGracePeriod); err == nil {
+				t.Fatal("newKeyRing succeeded, want an error")
+			}
+		})
+	}
+}
+
+func TestServiceVerifiesRetiredKeysDuringGracePeriod(t *testing.T) {
+	rotation := time.Now().Add(-2 * time.Minute).Truncate(time.Second)
+	keys := []config.JWTKey{
+		signingKeyConfig(t, "old", rotation.Add(-48*time.Hour)),
+		signingKeyConfig(t, "new", rotation),
+	}
+
+	signWith := func(t *testing.T, kid string, method jwt.SigningMethod, signKey interface{}) string {
+		t.Helper()
+		token := jwt.NewWithClaims(method, Claims{
+			Type: "access",
+			RegisteredClaims: jwt.RegisteredClaims{
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+			},
+		})
+		token.Header["kid"] = kid
+		signed, err := token.SignedString(signKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed
+	}
+
+	tests := map[string]struct {
+		gracePeriod time.Duration
+		kid         string
+		hmac        bool
+		valid       bool
+	}{
+		"current key":              {gracePeriod: time.Hour, kid: "new", valid: true},
+		"retired key within grace": {gracePeriod: time.Hour, kid: "old", valid: true},
+		"retired key after grace":  {gracePeriod: time.Minute, kid: "old", valid: false},
+		"HMAC with public key":     {gracePeriod: time.Hour, kid: "new", hmac: true, valid: false},
+		"unknown key":              {gracePeriod: time.Hour, kid: "missing", valid: false},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			service, err := NewService(config.JWT{Keys: keys, RotationGracePeriod: test.gracePeriod, AccessExpiry: time.Minute})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var token string
+			switch key := service.keys.lookup(test.kid, rotation.Add(-time.Hour)); {
+			case key == nil:
+				token = signWith(t, test.kid, jwt.SigningMethodES256, newECKey(t, elliptic.P256()))
+			case test.hmac:
+				publicDER, err := x509.MarshalPKIXPublicKey(key.publicKey)
+				if err != nil {
+					t.Fatal(err)
+				}
+				token = signWith(t, test.kid, jwt.SigningMethodHS256, publicDER)
+			default:
+				token = signWith(t, test.kid, key.method, key.signKey)
+			}
+
+			if _, err := service.ValidateToken(token); (err == nil) != test.valid {
+				t.Fatalf("ValidateToken err = %v, want valid = %v", err, test.valid)
+			}
+		})
+	}
+}
+
+func TestServiceSignsWithCurrentKey(t *testing.T) {
+	rotation := time.Now().Add(-time.Minute).Truncate(time.Second)
+	service, err := NewService(config.JWT{
+		Keys: []config.JWTKey{
+			signingKeyConfig(t, "old", rotation.Add(-time.Hour)),
+			signingKeyConfig(t, "new", rotation),
+			signingKeyConfig(t, "next", rotation.Add(time.Hour)),
+		},
+		AccessExpiry:  time.Minute,
+		RefreshExpiry: time.Hour, // Default grace period keeps "old" published
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := service.GenerateClientToken("client", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, &Claims{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kid := parsed.Header["kid"]; kid != "new" {
+		t.Fatalf("kid = %v, want new", kid)
+	}
+
+	if got := len(service.JWKS().Keys); got != 3 {
+		t.Fatalf("JWKS has %d keys, want 3 including the scheduled one", got)
+	}
+}
@@ -0,0 +1,39 @@
+package oauth
+
+import "net/http"
+
+// Error codes from RFC 6749 section 5.2.
+const (
+	ErrorInvalidRequest       = "invalid_request"
+	ErrorInvalidClient        = "invalid_client"
+	ErrorInvalidGrant         = "invalid_grant"
+	ErrorUnauthorizedClient   = "unauthorized_client"
+	ErrorUnsupportedGrantType = "unsupported_grant_type"
+	ErrorInvalidScope         = "invalid_scope"
+)
+
+// Error is the error response of an OAuth 2.0 endpoint.
+type Error struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description,omitempty"`
+}
+
+func NewError(code, description string) *Error {
+	return &Error{Code: code, Description: description}
+}
+
+func (e *Error) Error() string {
+	if e.Description != "" {
+		return e.Code + ": " + e.Description
+	}
+	return e.Code
+}
+
+// StatusCode follows RFC 6749: failed client authentication is a 401, every
+// other error a 400.
+func (e *Error) StatusCode() int {
+	if e.Code == ErrorInvalidClient {
+		return http.StatusUnauthorized
+	}
+	return http.StatusBadRequest
+}
@@ -0,0 +1,46 @@
+package oauth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+)
+
+const (
+	GrantTypeClientCredentials = "client_credentials"
+
+	TokenTypeBearer = "Bearer"
+
+	// ScopeTokenIntrospect lets a client introspect any token (RFC 7662
+	// section 4). ScopeTokenRevoke lets it revoke tokens that were not
+	// issued to it (RFC 7009 section 2.1).
+	ScopeTokenIntrospect = "tokens:introspect"
+	ScopeTokenRevoke     = "tokens:revoke"
+)
+
+// ParseScope splits a space-delimited scope parameter (RFC 6749 section 3.3).
+func ParseScope(scope string) []string {
+	return strings.Fields(scope)
+}
+
+func FormatScope(scopes []string) string {
+	return strings.Join(scopes, " ")
+}
+
+// GenerateClientSecret returns a random secret for a confidential client.
+func GenerateClientSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashClientSecret is how client secrets are stored in the registry. Secrets
+// are generated with full entropy, so a plain SHA-256 is sufficient.
+func HashClientSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,116 @@
+package oidc
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+)
+
+type jsonWebKey struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	Curve     string `json:"crv"`
+	N         string `json:"n"`
+	E         string `json:"e"`
+	X         string `json:"x"`
+	Y         string `json:"y"`
+}
+
+type jsonWebKeySet struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+type verificationKey struct {
+	id        string
+	algorithm string
+	key       interface{}
+}
+
+type keySet struct {
+	keys []verificationKey
+}
+
+// newKeySet keeps the RSA and P-256 signature keys of a JWKS document and
+// silently skips anything else.
+func newKeySet(document jsonWebKeySet) *keySet {
+	set := &keySet{}
+	for _, jwk := range document.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		switch jwk.KeyType {
+		case "RSA":
+			if key, ok := parseRSAKey(jwk); ok {
+				set.keys = append(set.keys, verificationKey{id: jwk.KeyID, algorithm: "RS256", key: key})
+			}
+		case "EC":
+			if key, ok := parseECKey(jwk); ok {
+				set.keys = append(set.keys, verificationKey{id: jwk.KeyID, algorithm: "ES256", key: key})
+			}
+		}
+	}
+	return set
+}
+
+// lookup matches by key ID. Tokens without a kid are accepted only when the
+// provider publishes a single key for the algorithm.
+func (s *keySet) lookup(kid, algorithm string) (interface{}, bool) {
+	var candidates []verificationKey
+	for _, key := range s.keys {
+		if key.algorithm != algorithm {
+			continue
+		}
+		if kid != "" && key.id == kid {
+			return key.key, true
+		}
+		candidates = append(candidates, key)
+	}
+	if kid == "" && len(candidates) == 1 {
+		return candidates[0].key, true
+	}
+	return nil, false
+}
+
+func parseRSAKey(jwk jsonWebKey) (*rsa.PublicKey, bool) {
+	n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+	if err != nil || len(n) < 256 {
+		return nil, false
+	}
+	e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+	if err != nil || len(e) == 0 || len(e) > 4 {
+		return nil, false
+	}
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(new(big.Int).SetBytes(e).Int64()),
+	}, true
+}
+
+func parseECKey(jwk jsonWebKey) (*ecdsa.PublicKey, bool) {
+	if jwk.Curve != "P-256" {
+		return nil, false
+	}
+	x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+	if err != nil || len(x) != 32 {
+		return nil, false
+	}
+	y, err := base64.RawURLEncoding.DecodeString(jwk.Y)
+	if err != nil || len(y) != 32 {
+		return nil, false
+	}
+	// Rejects points that are not on the curve
+	point := append([]byte{0x04}, append(append([]byte(nil), x...), y...)...)
+	if _, err := ecdh.P256().NewPublicKey(point); err != nil {
+		return nil, false
+	}
+	return &ecdsa.PublicKey{
+		Curve: elliptic.P256(),
+		X:     new(big.Int).SetBytes(x),
+		Y:     new(big.Int).SetBytes(y),
+	}, true
+}
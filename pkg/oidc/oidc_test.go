@@ -0,0 +1,406 @@
+package oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	testClientID     = "client"
+	testClientSecret = "secret"
+	testRedirectURL  = "https://app.example.com/callback"
+)
+
+type issuerKey struct {
+	id  string
+	key *ecdsa.PrivateKey
+}
+
+// pendingAuthorization is what the mock issuer remembers between the
+// authorization request and the token request.
+type pendingAuthorization struct {
+	challenge string
+	nonce     string
+}
+
+// testIssuer is a minimal OpenID provider serving discovery, the JWKS and
+// the token endpoint of the authorization code flow with PKCE.
+type testIssuer struct {
+	t      *testing.T
+	server *httptest.Server
+
+	mu          sync.Mutex
+	keys        []issuerKey
+	codes       map[string]pendingAuthorization
+	jwksFetches int
+}
+
+func newTestIssuer(t *testing.T) *testIssuer {
+	t.Helper()
+
+	issuer := &testIssuer{t: t, codes: make(map[string]pendingAuthorization)}
+	issuer.rotate("key-1")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", issuer.serveDiscovery)
+	mux.HandleFunc("/jwks", issuer.serveJWKS)
+	mux.HandleFunc("/token", issuer.serveToken)
+	issuer.server = httptest.NewServer(mux)
+	t.Cleanup(issuer.server.Close)
+
+	return issuer
+}
+
+func (i *testIssuer) provider() *Provider {
+	return NewProvider(Config{
+		Issuer:       i.server.URL,
+		ClientID:     testClientID,
+		ClientSecret: testClientSecret,
+		RedirectURL:  testRedirectURL,
+	}, i.server.Client())
+}
+
+// rotate makes a new key the signing key while still publishing the old ones.
+func (i *testIssuer) rotate(kid string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		i.t.Fatal(err)
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.keys = append(i.keys, issuerKey{id: kid, key: key})
+}
+
+func (i *testIssuer) fetches() int {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.jwksFetches
+}
+
+// authorize plays the user approving the authorization request and returns
+// the code the provider would redirect back with.
+func (i *testIssuer) authorize(authURL string) string {
+	i.t.Helper()
+
+	parsed, err := url.Parse(authURL)
+	if err != nil {
+		i.t.Fatal(err)
+	}
+	query := parsed.Query()
+	if method := query.Get("code_challenge_method"); method != "S256" {
+		i.t.Fatalf("code_challenge_method = %q, want S256", method)
+	}
+
+	code, err := GenerateVerifier()
+	if err != nil {
+		i.t.Fatal(err)
+	}
+	i.mu.Lock()
+	i.codes[code] = pendingAuthorization{challenge: query.Get("code_challenge"), nonce: query.Get("nonce")}
+	i.mu.Unlock()
+	return code
+}
+
+// sign issues an ID token with the newest key, leaving out the kid header
+// unless withKid is set.
+func (i *testIssuer) sign(claims IDTokenClaims, withKid bool) string {
+	i.t.Helper()
+
+	i.mu.Lock()
+	current := i.keys[len(i.keys)-1]
+	i.mu.Unlock()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+	if withKid {
+		token.Header["kid"] = current.id
+	}
+	signed, err := token.SignedString(current.key)
+	if err != nil {
+		i.t.Fatal(err)
+	}
+	return signed
+}
+
+func (i *testIssuer) claims(nonce string) IDTokenClaims {
+	now := time.Now()
+	return IDTokenClaims{
+		Email:         "user@example.com",
+		EmailVerified: true,
+		Nonce:         nonce,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    i.server.URL,
+			Subject:   "subject",
+			Audience:  jwt.ClaimStrings{testClientID},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+		},
+	}
+}
+
+func (i *testIssuer) serveDiscovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, Metadata{
+		Issuer:                i.server.URL,
+		AuthorizationEndpoint: i.server.URL + "/authorize",
+		TokenEndpoint:         i.server.URL + "/token",
+		JWKSURI:               i.server.URL + "/jwks",
+	})
+}
+
+func (i *testIssuer) serveJWKS(w http.ResponseWriter, r *http.Request) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.jwksFetches++
+	document := jsonWebKeySet{}
+	for _, key := range i.keys {
+		document.Keys = append(document.Keys, jsonWebKey{
+			KeyType:   "EC",
+			KeyID:     key.id,
+			Use:       "sig",
+			Algorithm: "ES256",
+			Curve:     "P-256",
+			X:         base64.RawURLEncoding.EncodeToString(key.key.X.FillBytes(make([]byte, 32))),
+			Y:         base64.RawURLEncoding.EncodeToString(key.key.Y.FillBytes(make([]byte, 32))),
+		})
+	}
+	writeJSON(w, document)
+}
+
+func (i *testIssuer) serveToken(w http.ResponseWriter, r *http.Request) {
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok || clientID != testClientID || clientSecret != testClientSecret {
+		http.Error(w, `{"error":"invalid_client"}`, http.StatusUnauthorized)
+		return
+	}
+	if err := r.ParseForm(); err != nil || r.PostForm.Get("grant_type") != "authorization_code" ||
+		r.PostForm.Get("redirect_uri") != testRedirectURL {
+		http.Error(w, `{"error":"invalid_request"}`, http.StatusBadRequest)
+		return
+	}
+
+	i.mu.Lock()
+	pending, ok := i.codes[r.PostForm.Get("code")]
+	delete(i.codes, r.PostForm.Get("code"))
+	i.mu.Unlock()
+
+	if !ok || CodeChallenge(r.PostForm.Get("code_verifier")) != pending.challenge {
+		http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+		return
+	}
+
+	writeJSON(w, TokenResponse{
+		AccessToken: "access-token",
+		TokenType:   "Bearer",
+		IDToken:     i.sign(i.claims(pending.nonce), true),
+		ExpiresIn:   3600,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+func TestAuthorizationCodeFlowWithPKCE(t *testing.T) {
+	issuer := newTestIssuer(t)
+	provider := issuer.provider()
+	ctx := context.Background()
+
+	verifier, err := GenerateVerifier()
+	if err != nil {
+		t.Fatal(err)
+	}
+	authURL, err := provider.AuthCodeURL(ctx, "state", "nonce", verifier)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("wrong verifier", func(t *testing.T) {
+		code := issuer.authorize(authURL)
+		if _, err := provider.Exchange(ctx, code, verifier+"x"); err == nil {
+			t.Fatal("Exchange succeeded with the wrong code verifier")
+		}
+	})
+
+	t.Run("matching verifier", func(t *testing.T) {
+		code := issuer.authorize(authURL)
+		token, err := provider.Exchange(ctx, code, verifier)
+		if err != nil {
+			t.Fatal(err)
+		}
+		claims, err := provider.VerifyIDToken(ctx, token.IDToken, "nonce")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if claims.Subject != "subject" || !claims.EmailVerified {
+			t.Fatalf("claims = %+v", claims)
+		}
+
+		// Codes are single use
+		if _, err := provider.Exchange(ctx, code, verifier); err == nil {
+			t.Fatal("Exchange redeemed the same code twice")
+		}
+	})
+}
+
+func TestVerifyIDToken(t *testing.T) {
+	issuer := newTestIssuer(t)
+	provider := issuer.provider()
+
+	tests := map[string]struct {
+		modify  func(claims *IDTokenClaims)
+		withKid bool
+		wantErr error
+	}{
+		"valid": {
+			withKid: true,
+		},
+		"valid without kid": {},
+		"nonce mismatch": {
+			modify:  func(claims *IDTokenClaims) { claims.Nonce = "other" },
+			withKid: true,
+			wantErr: ErrNonceMismatch,
+		},
+		"wrong audience": {
+			modify:  func(claims *IDTokenClaims) { claims.Audience = jwt.ClaimStrings{"other-client"} },
+			withKid: true,
+			wantErr: ErrInvalidIDToken,
+		},
+		"wrong issuer": {
+			modify:  func(claims *IDTokenClaims) { claims.Issuer = "https://attacker.example.com" },
+			withKid: true,
+			wantErr: ErrInvalidIDToken,
+		},
+		"expired": {
+			modify: func(claims *IDTokenClaims) {
+				claims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-2 * time.Minute))
+			},
+			withKid: true,
+			wantErr: ErrInvalidIDToken,
+		},
+		"missing subject": {
+			modify:  func(claims *IDTokenClaims) { claims.Subject = "" },
+			withKid: true,
+			wantErr: ErrInvalidIDToken,
+		},
+		"multiple audiences without azp": {
+			modify:  func(claims *IDTokenClaims) { claims.Audience = jwt.ClaimStrings{testClientID, "other-client"} },
+			withKid: true,
+			wantErr: ErrInvalidIDToken,
+		},
+		"multiple audiences with foreign azp": {
+			modify: func(claims *IDTokenClaims) {
+				claims.Audience = jwt.ClaimStrings{testClientID, "other-client"}
+				claims.AuthorizedBy = "other-client"
+			},
+			withKid: true,
+			wantErr: ErrInvalidIDToken,
+		},
+		"multiple audiences with our azp": {
+			modify: func(claims *IDTokenClaims) {
+				claims.Audience = jwt.ClaimStrings{testClientID, "other-client"}
+				claims.AuthorizedBy = testClientID
+			},
+			withKid: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			claims := issuer.claims("nonce")
+			if test.modify != nil {
+				test.modify(&claims)
+			}
+
+			_, err := provider.VerifyIDToken(context.Background(), issuer.sign(claims, test.withKid), "nonce")
+			if test.wantErr == nil {
+				if err != nil {
+					t.Fatalf("VerifyIDToken err = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("VerifyIDToken err = %v, want %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifyIDTokenRejectsForeignSignature(t *testing.T) {
+	issuer := newTestIssuer(t)
+	provider := issuer.provider()
+
+	foreign, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, issuer.claims("nonce"))
+	token.Header["kid"] = "key-1"
+	signed, err := token.SignedString(foreign)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := provider.VerifyIDToken(context.Background(), signed, "nonce"); !errors.Is(err, ErrInvalidIDToken) {
+		t.Fatalf("VerifyIDToken err = %v, want %v", err, ErrInvalidIDToken)
+	}
+}
+
+func TestVerifyIDTokenRefetchesKeysAfterRotation(t *testing.T) {
+	issuer := newTestIssuer(t)
+	provider := issuer.provider()
+	ctx := context.Background()
+
+	if _, err := provider.VerifyIDToken(ctx, issuer.sign(issuer.claims("nonce"), true), "nonce"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := provider.VerifyIDToken(ctx, issuer.sign(issuer.claims("nonce"), true), "nonce"); err != nil {
+		t.Fatal(err)
+	}
+	if got := issuer.fetches(); got != 1 {
+		t.Fatalf("JWKS fetched %d times, want 1 while the key is cached", got)
+	}
+
+	issuer.rotate("key-2")
+	if _, err := provider.VerifyIDToken(ctx, issuer.sign(issuer.claims("nonce"), true), "nonce"); err != nil {
+		t.Fatalf("token signed with the rotated key: %v", err)
+	}
+	if got := issuer.fetches(); got != 2 {
+		t.Fatalf("JWKS fetched %d times, want a refetch for the new kid", got)
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, issuer.claims("nonce"))
+	token.Header["kid"] = "key-unknown"
+	signed, err := token.SignedString(issuer.keys[0].key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := provider.VerifyIDToken(ctx, signed, "nonce"); !errors.Is(err, ErrInvalidIDToken) {
+		t.Fatalf("VerifyIDToken err = %v, want %v for an unknown kid", err, ErrInvalidIDToken)
+	}
+}
+
+func TestDiscoveryRejectsIssuerMismatch(t *testing.T) {
+	issuer := newTestIssuer(t)
+	provider := NewProvider(Config{
+		Issuer:   issuer.server.URL + "/",
+		ClientID: testClientID,
+	}, issuer.server.Client())
+
+	if _, err := provider.AuthCodeURL(context.Background(), "state", "nonce", "verifier"); err == nil {
+		t.Fatal("AuthCodeURL succeeded with a discovery document for another issuer")
+	}
+}
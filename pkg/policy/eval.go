@@ -0,0 +1,304 @@
+package policy
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+func (n *literalNode) eval(map[string]interface{}) (interface{}, error) {
+	return n.value, nil
+}
+
+func (n *nullNode) eval(map[string]interface{}) (interface{}, error) {
+	return nil, nil
+}
+
+// eval walks the path through nested maps. A missing segment yields nil
+// rather than an error, as attributes are often optional.
+func (n *pathNode) eval(vars map[string]interface{}) (interface{}, error) {
+	var current interface{} = vars
+	for _, segment := range n.path {
+		current = lookup(current, segment)
+		if current == nil {
+			return nil, nil
+		}
+	}
+	return normalize(current), nil
+}
+
+func (n *listNode) eval(vars map[string]interface{}) (interface{}, error) {
+	items := make([]interface{}, 0, len(n.items))
+	for _, item := range n.items {
+		value, err := item.eval(vars)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, value)
+	}
+	return items, nil
+}
+
+func (n *notNode) eval(vars map[string]interface{}) (interface{}, error) {
+	value, err := n.operand.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	b, ok := value.(bool)
+	if !ok {
+		return nil, fmt.Errorf("operand of \"!\" is %s, not a boolean", describe(value))
+	}
+	return !b, nil
+}
+
+func (n *logicalNode) eval(vars map[string]interface{}) (interface{}, error) {
+	left, err := evalBool(n.left, vars, n.operator)
+	if err != nil {
+		return nil, err
+	}
+	if n.operator == "&&" && !left || n.operator == "||" && left {
+		return left, nil
+	}
+	return evalBool(n.right, vars, n.operator)
+}
+
+func evalBool(operand node, vars map[string]interface{}, operator string) (bool, error) {
+	value, err := operand.eval(vars)
+	if err != nil {
+		return false, err
+	}
+	b, ok := value.(bool)
+	if !ok {
+		return false, fmt.Errorf("operand of %q is %s, not a boolean", operator, describe(value))
+	}
+	return b, nil
+}
+
+func (n *comparisonNode) eval(vars map[string]interface{}) (interface{}, error) {
+	left, err := n.left.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+
+	_, leftNull := n.left.(*nullNode)
+	_, rightNull := n.right.(*nullNode)
+	if leftNull || rightNull {
+		switch n.operator {
+		case "==":
+			return left == nil && right == nil, nil
+		case "!=":
+			return left != nil || right != nil, nil
+		default:
+			return nil, fmt.Errorf("null cannot be used with %q", n.operator)
+		}
+	}
+
+	// A missing attribute never satisfies a comparison, so two attributes
+	// that are both absent do not count as equal
+	if left == nil || right == nil {
+		return false, nil
+	}
+
+	switch n.operator {
+	case "==":
+		return equal(left, right), nil
+	case "!=":
+		return !equal(left, right), nil
+	case "in":
+		return contains(right, left)
+	default:
+		return order(n.operator, left, right)
+	}
+}
+
+func lookup(value interface{}, key string) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		return typed[key]
+	case map[string]string:
+		if v, ok := typed[key]; ok {
+			return v
+		}
+		return nil
+	}
+
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Map && rv.Type().Key().Kind() == reflect.String {
+		v := rv.MapIndex(reflect.ValueOf(key).Convert(rv.Type().Key()))
+		if v.IsValid() {
+			return v.Interface()
+		}
+	}
+	return nil
+}
+
+// normalize converts attribute values to the few types expressions work
+// with: bool, float64, string, []interface{} and maps.
+func normalize(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case bool, float64, string, []interface{}, map[string]interface{}:
+		return typed
+	case int:
+		return float64(typed)
+	case int8:
+		return float64(typed)
+	case int16:
+		return float64(typed)
+	case int32:
+		return float64(typed)
+	case int64:
+		return float64(typed)
+	case uint:
+		return float64(typed)
+	case uint8:
+		return float64(typed)
+	case uint16:
+		return float64(typed)
+	case uint32:
+		return float64(typed)
+	case uint64:
+		return float64(typed)
+	case float32:
+		return float64(typed)
+	case time.Time:
+		return typed.UTC().Format(time.RFC3339)
+	case fmt.Stringer:
+		return typed.String()
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		items := make([]interface{}, rv.Len())
+		for i := range items {
+			items[i] = normalize(rv.Index(i).Interface())
+		}
+		return items
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return value
+		}
+		items := make(map[string]interface{}, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			items[iter.Key().String()] = iter.Value().Interface()
+		}
+		return items
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return nil
+		}
+		return normalize(rv.Elem().Interface())
+	}
+
+	return value
+}
+
+func equal(left, right interface{}) bool {
+	switch l := left.(type) {
+	case bool, float64, string:
+		return left == right
+	case []interface{}:
+		r, ok := right.([]interface{})
+		if !ok || len(l) != len(r) {
+			return false
+		}
+		for i := range l {
+			if !equal(normalize(l[i]), normalize(r[i])) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(left, right)
+}
+
+// contains implements "in": membership for lists, substring for strings
+// and key presence for maps.
+func contains(collection, item interface{}) (bool, error) {
+	switch typed := collection.(type) {
+	case []interface{}:
+		for _, element := range typed {
+			if element := normalize(element); element != nil && equal(item, element) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case string:
+		s, ok := item.(string)
+		if !ok {
+			return false, fmt.Errorf("cannot look for %s in a string", describe(item))
+		}
+		return strings.Contains(typed, s), nil
+	case map[string]interface{}:
+		key, ok := item.(string)
+		if !ok {
+			return false, fmt.Errorf("cannot look for %s in a map", describe(item))
+		}
+		_, exists := typed[key]
+		return exists, nil
+	}
+	return false, fmt.Errorf("right side of \"in\" is %s, not a list, string or map", describe(collection))
+}
+
+// order compares numbers numerically and strings lexically, which also
+// orders RFC 3339 timestamps in the same zone correctly.
+func order(operator string, left, right interface{}) (bool, error) {
+	var cmp int
+	switch l := left.(type) {
+	case float64:
+		r, ok := right.(float64)
+		if !ok {
+			return false, fmt.Errorf("cannot compare a number with %s", describe(right))
+		}
+		switch {
+		case l < r:
+			cmp = -1
+		case l > r:
+			cmp = 1
+		}
+	case string:
+		r, ok := right.(string)
+		if !ok {
+			return false, fmt.Errorf("cannot compare a string with %s", describe(right))
+		}
+		cmp = strings.Compare(l, r)
+	default:
+		return false, fmt.Errorf("%q cannot compare %s", operator, describe(left))
+	}
+
+	switch operator {
+	case "<":
+		return cmp < 0, nil
+	case "<=":
+		return cmp <= 0, nil
+	case ">":
+		return cmp > 0, nil
+	case ">=":
+		return cmp >= 0, nil
+	}
+	return false, fmt.Errorf("unknown operator %q", operator)
+}
+
+func describe(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "a boolean"
+	case float64:
+		return "a number"
+	case string:
+		return "a string"
+	case []interface{}:
+		return "a list"
+	case map[string]interface{}:
+		return "a map"
+	}
+	return fmt.Sprintf("a %T", value)
+}
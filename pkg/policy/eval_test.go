@@ -0,0 +1,209 @@
+package policy
+
+import (
+	"testing"
+	"time"
+)
+
+type stringer struct{}
+
+func (stringer) String() string { return "stringer" }
+
+func testVars() map[string]interface{} {
+	level := 3
+	return map[string]interface{}{
+		"subject": map[string]interface{}{
+			"id":         "u1",
+			"roles":      []string{"USER", "EDITOR"},
+			"level":      &level,
+			"active":     true,
+			"score":      int64(42),
+			"ratio":      float32(0.5),
+			"department": "sales",
+			"labels":     map[string]string{"team": "blue"},
+			"joined_at":  time.Date(2024, 5, 1, 12, 0, 0, 0, time.FixedZone("CEST", 2*60*60)),
+			"tags":       []interface{}{"a", nil, 2},
+			"name":       stringer{},
+			"nothing":    nil,
+			"empty":      []string{},
+		},
+		"resource": map[string]interface{}{
+			"owner_id":   "u1",
+			"roles":      []string{"ADMIN"},
+			"department": "sales",
+			"counts":     map[string]int{"views": 10},
+		},
+		"action": "update",
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	tests := map[string]bool{
+		// Comparisons
+		"subject.id == resource.owner_id":            true,
+		"subject.id != resource.owner_id":            false,
+		"subject.department == 'sales'":              true,
+		"action == 'update'":                         true,
+		"subject.score == 42":                        true,
+		"subject.score > 41 && subject.score < 43":   true,
+		"subject.score >= 42 && subject.score <= 42": true,
+		"subject.ratio == 0.5":                       true,
+		"subject.level == 3":                         true,
+		"subject.active == true":                     true,
+		"subject.active != false":                    true,
+		"'b' > 'a'":                                  true,
+		"subject.roles == ['USER', 'EDITOR']":        true,
+		"subject.roles == ['EDITOR', 'USER']":        false,
+		"subject.tags == ['a', null, 2]":             true,
+		"subject.id == 1":                            false,
+		"subject.id != 1":                            true,
+		"resource.counts.views == 10":                true,
+		"subject.labels.team == 'blue'":              true,
+		"subject.name == 'stringer'":                 true,
+
+		// Timestamps normalise to UTC RFC 3339, which orders lexically
+		"subject.joined_at == '2024-05-01T10:00:00Z'": true,
+		"subject.joined_at < '2024-05-02T00:00:00Z'":  true,
+
+		// in and not in
+		"'ADMIN' in resource.roles":                  true,
+		"'ADMIN' not in subject.roles":               true,
+		"'EDITOR' in subject.roles":                  true,
+		"2 in subject.tags":                          true,
+		"subject.department in ['sales', 'support']": true,
+		"'ale' in subject.department":                true,
+		"'team' in subject.labels":                   true,
+		"'color' in subject.labels":                  false,
+		"'x' in subject.empty":                       false,
+		"'x' in []":                                  false,
+
+		// Logic and short circuits: the right side would fail to evaluate
+		"true || subject.id > 1":   true,
+		"false && subject.id > 1":  false,
+		"!(subject.id == 'u2')":    true,
+		"!subject.active || true":  true,
+		"(true || false) && false": false,
+	}
+
+	vars := testVars()
+	for source, want := range tests {
+		t.Run(source, func(t *testing.T) {
+			expression, err := Compile(source)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := expression.Evaluate(vars)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != want {
+				t.Fatalf("Evaluate = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestEvaluateMissingAttributes(t *testing.T) {
+	tests := map[string]bool{
+		// A missing attribute satisfies no comparison, not even "!="
+		"subject.missing == 'x'":              false,
+		"subject.missing != 'x'":              false,
+		"subject.missing < 1":                 false,
+		"subject.missing in ['x']":            false,
+		"'x' in subject.missing":              false,
+		"subject.id.deeper == 'u1'":           false,
+		"unknown.root.path == 1":              false,
+		"subject.missing == resource.missing": false,
+		"subject.missing != resource.missing": false,
+		"subject.nothing == subject.nothing":  false,
+		"subject.id == subject.missing":       false,
+		"subject.roles.length > 0":            false,
+		"'x' not in subject.missing":          true,
+		"!(subject.missing == 'x')":           true,
+
+		// Only null matches a missing attribute
+		"subject.missing == null":                        true,
+		"null == subject.missing":                        true,
+		"subject.missing != null":                        false,
+		"subject.nothing == null":                        true,
+		"subject.id == null":                             false,
+		"subject.id != null":                             true,
+		"null == null":                                   true,
+		"null != null":                                   false,
+		"subject.missing == null || subject.missing > 1": true,
+
+		// A list keeps missing items as nulls, which "in" skips
+		"subject.missing in [subject.other]":    false,
+		"'u1' in [subject.missing, subject.id]": true,
+	}
+
+	vars := testVars()
+	for source, want := range tests {
+		t.Run(source, func(t *testing.T) {
+			expression, err := Compile(source)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := expression.Evaluate(vars)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != want {
+				t.Fatalf("Evaluate = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestEvaluateRejectsTypeErrors(t *testing.T) {
+	tests := map[string]string{
+		"not a boolean":             "subject.id",
+		"missing attribute alone":   "subject.missing",
+		"number result":             "1",
+		"list result":               "[true]",
+		"negated string":            "!subject.id",
+		"negated missing attribute": "!subject.missing",
+		"string in logic":           "subject.active && subject.id",
+		"missing in logic":          "subject.missing || true",
+		"null ordered":              "subject.score < null",
+		"null in list":              "null in ['a']",
+		"number with string":        "subject.score < 'a'",
+		"string with number":        "subject.id > 1",
+		"ordered booleans":          "subject.active > false",
+		"ordered lists":             "subject.roles < ['A']",
+		"in a number":               "1 in subject.score",
+		"number in a string":        "1 in subject.department",
+		"number in a map":           "1 in subject.labels",
+		"null literal alone":        "null",
+	}
+
+	vars := testVars()
+	for name, source := range tests {
+		t.Run(name, func(t *testing.T) {
+			expression, err := Compile(source)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, err := expression.Evaluate(vars); err == nil {
+				t.Fatalf("Evaluate = %v, want an error", got)
+			}
+		})
+	}
+}
+
+func TestEvaluateWithoutVars(t *testing.T) {
+	expression, err := Compile("resource.owner_id == subject.id || subject.id == null")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, vars := range []map[string]interface{}{nil, {}, {"subject": nil}, {"subject": "not a map"}} {
+		got, err := expression.Evaluate(vars)
+		if err != nil {
+			t.Fatalf("Evaluate(%v): %v", vars, err)
+		}
+		if !got {
+			t.Fatalf("Evaluate(%v) = false, want the null check to hold", vars)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package policy
+
+import (
+	"fmt"
+)
+
+// Expression is a compiled policy condition such as
+//
+//	resource.owner_id == subject.id || subject.department == resource.department
+//
+// Conditions combine attribute paths, string, number, boolean and list
+// literals with ==, !=, <, <=, >, >=, in, not in, &&, || and !. A
+// comparison involving a missing attribute is false unless it is compared
+// with null, so a condition never holds because two attributes are both
+// absent.
+type Expression struct {
+	source string
+	root   node
+}
+
+// Compile parses source. When roots are given, every attribute path must
+// start with one of them, which catches misspelt attribute names at load
+// time instead of leaving a condition that silently never matches.
+func Compile(source string, roots ...string) (*Expression, error) {
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	if len(roots) > 0 {
+		p.roots = make(map[string]bool, len(roots))
+		for _, root := range roots {
+			p.roots[root] = true
+		}
+	}
+
+	root, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Expression{source: source, root: root}, nil
+}
+
+// Evaluate runs the expression against vars, whose keys are the attribute
+// path roots. The expression must produce a boolean.
+func (e *Expression) Evaluate(vars map[string]interface{}) (bool, error) {
+	value, err := e.root.eval(vars)
+	if err != nil {
+		return false, err
+	}
+
+	result, ok := value.(bool)
+	if !ok {
+		return false, fmt.Errorf("expression produced %s, not a boolean", describe(value))
+	}
+	return result, nil
+}
+
+func (e *Expression) String() string {
+	return e.source
+}
@@ -0,0 +1,145 @@
+package policy
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenOperator
+	tokenDot
+	tokenComma
+	tokenLParen
+	tokenRParen
+	tokenLBracket
+	tokenRBracket
+)
+
+type token struct {
+	kind  tokenKind
+	text  string
+	value interface{} // Decoded strings and numbers
+	pos   int
+}
+
+// operators is checked in order, so two-character operators come before
+// their one-character prefixes.
+var operators = []string{"==", "!=", "<=", ">=", "&&", "||", "<", ">", "!"}
+
+func tokenize(source string) ([]token, error) {
+	var tokens []token
+
+	for pos := 0; pos < len(source); {
+		ch := source[pos]
+
+		switch {
+		case ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r':
+			pos++
+
+		case ch == '.':
+			tokens = append(tokens, token{kind: tokenDot, text: ".", pos: pos})
+			pos++
+		case ch == ',':
+			tokens = append(tokens, token{kind: tokenComma, text: ",", pos: pos})
+			pos++
+		case ch == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "(", pos: pos})
+			pos++
+		case ch == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")", pos: pos})
+			pos++
+		case ch == '[':
+			tokens = append(tokens, token{kind: tokenLBracket, text: "[", pos: pos})
+			pos++
+		case ch == ']':
+			tokens = append(tokens, token{kind: tokenRBracket, text: "]", pos: pos})
+			pos++
+
+		case ch == '"' || ch == '\'':
+			end, value, err := scanString(source, pos)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenString, text: source[pos:end], value: value, pos: pos})
+			pos = end
+
+		case ch >= '0' && ch <= '9' || ch == '-' && pos+1 < len(source) && source[pos+1] >= '0' && source[pos+1] <= '9':
+			end := pos + 1
+			for end < len(source) && (source[end] >= '0' && source[end] <= '9' || source[end] == '.') {
+				end++
+			}
+			number, err := strconv.ParseFloat(source[pos:end], 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number %q at position %d", source[pos:end], pos)
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: source[pos:end], value: number, pos: pos})
+			pos = end
+
+		case isIdentStart(source[pos]):
+			end := pos + 1
+			for end < len(source) && (isIdentStart(source[end]) || source[end] >= '0' && source[end] <= '9') {
+				end++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: source[pos:end], pos: pos})
+			pos = end
+
+		default:
+			operator := ""
+			for _, candidate := range operators {
+				if strings.HasPrefix(source[pos:], candidate) {
+					operator = candidate
+					break
+				}
+			}
+			if operator == "" {
+				return nil, fmt.Errorf("unexpected character %q at position %d", ch, pos)
+			}
+			tokens = append(tokens, token{kind: tokenOperator, text: operator, pos: pos})
+			pos += len(operator)
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, pos: len(source)}), nil
+}
+
+func isIdentStart(ch byte) bool {
+	return ch == '_' || ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z'
+}
+
+// scanString reads the quoted string starting at pos and returns the
+// offset just past the closing quote with the unescaped value.
+func scanString(source string, pos int) (int, string, error) {
+	quote := source[pos]
+	var value strings.Builder
+
+	for i := pos + 1; i < len(source); i++ {
+		switch source[i] {
+		case quote:
+			return i + 1, value.String(), nil
+		case '\\':
+			if i+1 == len(source) {
+				return 0, "", fmt.Errorf("unterminated string at position %d", pos)
+			}
+			i++
+			switch source[i] {
+			case 'n':
+				value.WriteByte('\n')
+			case 't':
+				value.WriteByte('\t')
+			default:
+				value.WriteByte(source[i])
+			}
+		default:
+			value.WriteByte(source[i])
+		}
+	}
+
+	return 0, "", fmt.Errorf("unterminated string at position %d", pos)
+}
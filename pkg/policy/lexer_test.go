@@ -0,0 +1,141 @@
+package policy
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	type tok struct {
+		kind  tokenKind
+		text  string
+		value interface{}
+	}
+
+	tests := map[string]struct {
+		source string
+		want   []tok
+	}{
+		"path comparison": {
+			source: "resource.owner_id == subject.id",
+			want: []tok{
+				{kind: tokenIdent, text: "resource"},
+				{kind: tokenDot, text: "."},
+				{kind: tokenIdent, text: "owner_id"},
+				{kind: tokenOperator, text: "=="},
+				{kind: tokenIdent, text: "subject"},
+				{kind: tokenDot, text: "."},
+				{kind: tokenIdent, text: "id"},
+			},
+		},
+		"two-character operators before their prefixes": {
+			source: "a<=b>=c!=d<e>f !g&&h||i",
+			want: []tok{
+				{kind: tokenIdent, text: "a"}, {kind: tokenOperator, text: "<="},
+				{kind: tokenIdent, text: "b"}, {kind: tokenOperator, text: ">="},
+				{kind: tokenIdent, text: "c"}, {kind: tokenOperator, text: "!="},
+				{kind: tokenIdent, text: "d"}, {kind: tokenOperator, text: "<"},
+				{kind: tokenIdent, text: "e"}, {kind: tokenOperator, text: ">"},
+				{kind: tokenIdent, text: "f"}, {kind: tokenOperator, text: "!"},
+				{kind: tokenIdent, text: "g"}, {kind: tokenOperator, text: "&&"},
+				{kind: tokenIdent, text: "h"}, {kind: tokenOperator, text: "||"},
+				{kind: tokenIdent, text: "i"},
+			},
+		},
+		"numbers": {
+			source: "1 2.5 -3 a-4",
+			want: []tok{
+				{kind: tokenNumber, text: "1", value: 1.0},
+				{kind: tokenNumber, text: "2.5", value: 2.5},
+				{kind: tokenNumber, text: "-3", value: -3.0},
+				{kind: tokenIdent, text: "a"},
+				{kind: tokenNumber, text: "-4", value: -4.0},
+			},
+		},
+		"strings with escapes": {
+			source: `"say \"hi\"" 'it\'s' "a\tb\n"`,
+			want: []tok{
+				{kind: tokenString, text: `"say \"hi\""`, value: `say "hi"`},
+				{kind: tokenString, text: `'it\'s'`, value: "it's"},
+				{kind: tokenString, text: `"a\tb\n"`, value: "a\tb\n"},
+			},
+		},
+		"list": {
+			source: "['a', 1]",
+			want: []tok{
+				{kind: tokenLBracket, text: "["},
+				{kind: tokenString, text: "'a'", value: "a"},
+				{kind: tokenComma, text: ","},
+				{kind: tokenNumber, text: "1", value: 1.0},
+				{kind: tokenRBracket, text: "]"},
+			},
+		},
+		"identifiers with digits and underscores": {
+			source: "(_x1)",
+			want: []tok{
+				{kind: tokenLParen, text: "("},
+				{kind: tokenIdent, text: "_x1"},
+				{kind: tokenRParen, text: ")"},
+			},
+		},
+		"whitespace only": {
+			source: " \t\r\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tokens, err := tokenize(test.source)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if last := tokens[len(tokens)-1]; last.kind != tokenEOF || last.pos != len(test.source) {
+				t.Fatalf("last token = %+v, want EOF at %d", last, len(test.source))
+			}
+
+			got := make([]tok, 0, len(tokens)-1)
+			for _, token := range tokens[:len(tokens)-1] {
+				got = append(got, tok{kind: token.kind, text: token.text, value: token.value})
+			}
+			if len(got) == 0 && len(test.want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("tokens = %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestTokenizeRecordsPositions(t *testing.T) {
+	tokens, err := tokenize(`a  == "b"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []int{0, 3, 6, 9}
+	for i, token := range tokens {
+		if token.pos != want[i] {
+			t.Errorf("token %d (%q) at %d, want %d", i, token.text, token.pos, want[i])
+		}
+	}
+}
+
+func TestTokenizeRejectsInvalidInput(t *testing.T) {
+	tests := map[string]string{
+		"unterminated string":          `"abc`,
+		"escape at end of string":      `"abc\`,
+		"unknown character":            "a # b",
+		"single ampersand":             "a & b",
+		"single equals":                "a = b",
+		"number with two decimal dots": "1.2.3",
+	}
+
+	for name, source := range tests {
+		t.Run(name, func(t *testing.T) {
+			if tokens, err := tokenize(source); err == nil {
+				t.Fatalf("tokenize succeeded with %d tokens, want an error", len(tokens))
+			}
+		})
+	}
+}
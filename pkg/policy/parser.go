@@ -0,0 +1,244 @@
+package policy
+
+import (
+	"fmt"
+	"strings"
+)
+
+// node is an element of a parsed expression.
+type node interface {
+	eval(vars map[string]interface{}) (interface{}, error)
+}
+
+type literalNode struct {
+	value interface{}
+}
+
+// nullNode is the null literal. It is the only thing a missing attribute
+// compares equal to.
+type nullNode struct{}
+
+type pathNode struct {
+	path []string
+}
+
+type listNode struct {
+	items []node
+}
+
+type notNode struct {
+	operand node
+}
+
+type logicalNode struct {
+	operator    string // && or ||
+	left, right node
+}
+
+type comparisonNode struct {
+	operator    string // ==, !=, <, <=, >, >= or in
+	left, right node
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+	roots  map[string]bool
+}
+
+// The grammar, loosest binding first:
+//
+//	or         = and { "||" and }
+//	and        = unary { "&&" unary }
+//	unary      = "!" unary | comparison
+//	comparison = operand [ ( "==" | "!=" | "<" | "<=" | ">" | ">=" | "in" | "not" "in" ) operand ]
+//	operand    = literal | path | list | "(" or ")"
+//	path       = ident { "." ident }
+//	list       = "[" [ operand { "," operand } ] "]"
+func (p *parser) parse() (node, error) {
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos)
+	}
+	return expr, nil
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptOperator("||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalNode{operator: "||", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptOperator("&&") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalNode{operator: "&&", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.acceptOperator("!") {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{operand: operand}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	tok := p.peek()
+	switch {
+	case tok.kind == tokenOperator && tok.text != "&&" && tok.text != "||" && tok.text != "!":
+		p.pos++
+		right, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		return &comparisonNode{operator: tok.text, left: left, right: right}, nil
+
+	case tok.kind == tokenIdent && tok.text == "in":
+		p.pos++
+		right, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		return &comparisonNode{operator: "in", left: left, right: right}, nil
+
+	case tok.kind == tokenIdent && tok.text == "not":
+		p.pos++
+		if next := p.next(); next.kind != tokenIdent || next.text != "in" {
+			return nil, fmt.Errorf("expected \"in\" after \"not\" at position %d", next.pos)
+		}
+		right, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{operand: &comparisonNode{operator: "in", left: left, right: right}}, nil
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseOperand() (node, error) {
+	tok := p.next()
+
+	switch tok.kind {
+	case tokenString, tokenNumber:
+		return &literalNode{value: tok.value}, nil
+
+	case tokenLParen:
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, fmt.Errorf("expected \")\" at position %d", closing.pos)
+		}
+		return expr, nil
+
+	case tokenLBracket:
+		list := &listNode{}
+		if p.peek().kind == tokenRBracket {
+			p.pos++
+			return list, nil
+		}
+		for {
+			item, err := p.parseOperand()
+			if err != nil {
+				return nil, err
+			}
+			list.items = append(list.items, item)
+
+			separator := p.next()
+			if separator.kind == tokenRBracket {
+				return list, nil
+			}
+			if separator.kind != tokenComma {
+				return nil, fmt.Errorf("expected \",\" or \"]\" at position %d", separator.pos)
+			}
+		}
+
+	case tokenIdent:
+		switch tok.text {
+		case "true":
+			return &literalNode{value: true}, nil
+		case "false":
+			return &literalNode{value: false}, nil
+		case "null":
+			return &nullNode{}, nil
+		case "in", "not":
+			return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos)
+		}
+
+		if len(p.roots) > 0 && !p.roots[tok.text] {
+			return nil, fmt.Errorf("unknown attribute %q at position %d", tok.text, tok.pos)
+		}
+
+		path := []string{tok.text}
+		for p.peek().kind == tokenDot {
+			p.pos++
+			segment := p.next()
+			if segment.kind != tokenIdent {
+				return nil, fmt.Errorf("expected attribute name at position %d", segment.pos)
+			}
+			path = append(path, segment.text)
+		}
+		return &pathNode{path: path}, nil
+
+	case tokenEOF:
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+
+	return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos)
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) acceptOperator(operator string) bool {
+	if tok := p.peek(); tok.kind == tokenOperator && tok.text == operator {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (n *pathNode) String() string {
+	return strings.Join(n.path, ".")
+}
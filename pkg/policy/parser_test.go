@@ -0,0 +1,123 @@
+package policy
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// dump renders a parsed expression fully parenthesised, which makes the
+// tree the parser built visible.
+func dump(n node) string {
+	switch typed := n.(type) {
+	case *literalNode:
+		return fmt.Sprintf("%#v", typed.value)
+	case *nullNode:
+		return "null"
+	case *pathNode:
+		return typed.String()
+	case *listNode:
+		items := make([]string, 0, len(typed.items))
+		for _, item := range typed.items {
+			items = append(items, dump(item))
+		}
+		return "[" + strings.Join(items, " ") + "]"
+	case *notNode:
+		return "(! " + dump(typed.operand) + ")"
+	case *logicalNode:
+		return "(" + typed.operator + " " + dump(typed.left) + " " + dump(typed.right) + ")"
+	case *comparisonNode:
+		return "(" + typed.operator + " " + dump(typed.left) + " " + dump(typed.right) + ")"
+	}
+	return fmt.Sprintf("%T", n)
+}
+
+func TestCompileBuildsTree(t *testing.T) {
+	tests := map[string]string{
+		"a == b":                    "(== a b)",
+		"a.b.c != 'x'":              `(!= a.b.c "x")`,
+		"a || b && c":               "(|| a (&& b c))",
+		"a && b || c":               "(|| (&& a b) c)",
+		"a || b || c":               "(|| (|| a b) c)",
+		"(a || b) && c":             "(&& (|| a b) c)",
+		"!a && b":                   "(&& (! a) b)",
+		"!!a":                       "(! (! a))",
+		"!(a == 1)":                 "(! (== a 1))",
+		"a in [1, 'x', true, null]": `(in a [1 "x" true null])`,
+		"a not in []":               "(! (in a []))",
+		"a in [[1], b.c]":           "(in a [[1] b.c])",
+		"a.n >= -1.5":               "(>= a.n -1.5)",
+		"a == null || a < 3":        "(|| (== a null) (< a 3))",
+		"true":                      "true",
+		"'ADMIN' in resource.roles": `(in "ADMIN" resource.roles)`,
+	}
+
+	for source, want := range tests {
+		t.Run(source, func(t *testing.T) {
+			expression, err := Compile(source)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := dump(expression.root); got != want {
+				t.Fatalf("tree = %s, want %s", got, want)
+			}
+			if expression.String() != source {
+				t.Fatalf("String = %q, want the source", expression.String())
+			}
+		})
+	}
+}
+
+func TestCompileRejectsInvalidExpressions(t *testing.T) {
+	tests := map[string]string{
+		"empty":                   "",
+		"dangling operator":       "a ==",
+		"missing left operand":    "== a",
+		"chained comparison":      "a == b == c",
+		"unclosed parenthesis":    "(a == b",
+		"extra closing":           "a == b)",
+		"unclosed list":           "a in [1, 2",
+		"list without commas":     "a in [1 2]",
+		"trailing comma":          "a in [1,]",
+		"not without in":          "a not b",
+		"in as operand":           "in == a",
+		"path ending in a dot":    "a. == b",
+		"path segment is literal": "a.1 == b",
+		"adjacent operands":       "a b",
+		"lexer error":             `a == "b`,
+	}
+
+	for name, source := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Compile(source); err == nil {
+				t.Fatalf("Compile(%q) succeeded, want an error", source)
+			}
+		})
+	}
+}
+
+func TestCompileChecksAttributeRoots(t *testing.T) {
+	roots := []string{"subject", "resource"}
+
+	tests := map[string]bool{
+		"subject.id == resource.owner_id": true,
+		"'ADMIN' in subject.roles":        true,
+		"subjects.id == resource.id":      false,
+		"subject.id == owner_id":          false,
+		"subject.id in [resource.a, b]":   false,
+		"true && null == null":            true,
+	}
+
+	for source, valid := range tests {
+		t.Run(source, func(t *testing.T) {
+			_, err := Compile(source, roots...)
+			if (err == nil) != valid {
+				t.Fatalf("Compile err = %v, want valid = %v", err, valid)
+			}
+		})
+	}
+
+	if _, err := Compile("anything.goes == 1"); err != nil {
+		t.Fatalf("Compile without roots: %v", err)
+	}
+}
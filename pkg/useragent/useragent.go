@@ -0,0 +1,134 @@
+package useragent
+
+import (
+	"strings"
+)
+
+const (
+	DeviceDesktop = "desktop"
+	DeviceMobile  = "mobile"
+	DeviceTablet  = "tablet"
+	DeviceBot     = "bot"
+	DeviceUnknown = "unknown"
+)
+
+// Info is the coarse description of a client shown in login history.
+// Versions are left out on purpose: they change with every update and
+// would make a known device look new.
+type Info struct {
+	Browser string
+	OS      string
+	Device  string
+}
+
+// String renders the client as "Browser on OS".
+func (i Info) String() string {
+	switch {
+	case i.Browser != "" && i.OS != "":
+		return i.Browser + " on " + i.OS
+	case i.Browser != "":
+		return i.Browser
+	case i.OS != "":
+		return i.OS
+	default:
+		return "Unknown device"
+	}
+}
+
+// browsers is checked in order: many browsers also claim to be Chrome or
+// Safari, so the more specific tokens come first.
+var browsers = []struct {
+	token string
+	name  string
+}{
+	{"edg/", "Edge"},
+	{"edga/", "Edge"},
+	{"edgios/", "Edge"},
+	{"opr/", "Opera"},
+	{"opera", "Opera"},
+	{"samsungbrowser/", "Samsung Internet"},
+	{"yabrowser/", "Yandex Browser"},
+	{"vivaldi/", "Vivaldi"},
+	{"coc_coc_browser/", "Coc Coc"},
+	{"firefox/", "Firefox"},
+	{"fxios/", "Firefox"},
+	{"crios/", "Chrome"},
+	{"chrome/", "Chrome"},
+	{"chromium/", "Chromium"},
+	{"safari/", "Safari"},
+	{"msie ", "Internet Explorer"},
+	{"trident/", "Internet Explorer"},
+	{"curl/", "curl"},
+	{"postmanruntime/", "Postman"},
+	{"okhttp/", "OkHttp"},
+	{"go-http-client/", "Go HTTP client"},
+	{"python-requests/", "Python Requests"},
+}
+
+var operatingSystems = []struct {
+	token string
+	name  string
+}{
+	{"windows phone", "Windows Phone"},
+	{"windows", "Windows"},
+	{"iphone", "iOS"},
+	{"ipad", "iPadOS"},
+	{"ipod", "iOS"},
+	{"android", "Android"},
+	{"cros", "ChromeOS"},
+	{"mac os x", "macOS"},
+	{"macintosh", "macOS"},
+	{"ubuntu", "Ubuntu"},
+	{"linux", "Linux"},
+}
+
+var botTokens = []string{"bot", "crawler", "spider", "slurp", "headless"}
+
+// Parse extracts browser, operating system and device type from a
+// User-Agent header. It knows the common clients and falls back to empty
+// names rather than guessing.
+func Parse(userAgent string) Info {
+	ua := strings.ToLower(userAgent)
+	if ua == "" {
+		return Info{Device: DeviceUnknown}
+	}
+
+	info := Info{}
+	for _, browser := range browsers {
+		if strings.Contains(ua, browser.token) {
+			info.Browser = browser.name
+			break
+		}
+	}
+	for _, system := range operatingSystems {
+		if strings.Contains(ua, system.token) {
+			info.OS = system.name
+			break
+		}
+	}
+
+	switch {
+	case containsAny(ua, botTokens):
+		info.Device = DeviceBot
+	case strings.Contains(ua, "ipad") || strings.Contains(ua, "tablet") ||
+		(strings.Contains(ua, "android") && !strings.Contains(ua, "mobile")):
+		info.Device = DeviceTablet
+	case strings.Contains(ua, "mobi") || strings.Contains(ua, "iphone") || strings.Contains(ua, "ipod"):
+		info.Device = DeviceMobile
+	case info.OS != "":
+		info.Device = DeviceDesktop
+	default:
+		info.Device = DeviceUnknown
+	}
+
+	return info
+}
+
+func containsAny(s string, tokens []string) bool {
+	for _, token := range tokens {
+		if strings.Contains(s, token) {
+			return true
+		}
+	}
+	return false
+}
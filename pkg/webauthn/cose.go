@@ -0,0 +1,110 @@
+package webauthn
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// COSE algorithm identifiers (RFC 9053) supported for credentials.
+const (
+	AlgES256 int64 = -7
+	AlgEdDSA int64 = -8
+	AlgRS256 int64 = -257
+)
+
+const (
+	coseKeyTypeOKP int64 = 1
+	coseKeyTypeEC2 int64 = 2
+	coseKeyTypeRSA int64 = 3
+
+	coseCurveP256    int64 = 1
+	coseCurveEd25519 int64 = 6
+)
+
+type publicKey struct {
+	algorithm int64
+	key       crypto.PublicKey
+}
+
+// parsePublicKey decodes a COSE_Key as stored on the credential.
+func parsePublicKey(data []byte) (*publicKey, error) {
+	decoded, _, err := decodeCBOR(data)
+	if err != nil {
+		return nil, err
+	}
+	coseKey, ok := decoded.(map[interface{}]interface{})
+	if !ok {
+		return nil, errors.New("cose: key is not a map")
+	}
+
+	keyType, _ := coseKey[int64(1)].(int64)
+	algorithm, _ := coseKey[int64(3)].(int64)
+
+	switch algorithm {
+	case AlgES256:
+		curve, _ := coseKey[int64(-1)].(int64)
+		x, _ := coseKey[int64(-2)].([]byte)
+		y, _ := coseKey[int64(-3)].([]byte)
+		if keyType != coseKeyTypeEC2 || curve != coseCurveP256 || len(x) != 32 || len(y) != 32 {
+			return nil, errors.New("cose: invalid ES256 key")
+		}
+		// Rejects points that are not on the curve
+		point := append([]byte{0x04}, append(append([]byte(nil), x...), y...)...)
+		if _, err := ecdh.P256().NewPublicKey(point); err != nil {
+			return nil, fmt.Errorf("cose: invalid ES256 key: %w", err)
+		}
+		return &publicKey{
+			algorithm: algorithm,
+			key: &ecdsa.PublicKey{
+				Curve: elliptic.P256(),
+				X:     new(big.Int).SetBytes(x),
+				Y:     new(big.Int).SetBytes(y),
+			},
+		}, nil
+	case AlgEdDSA:
+		curve, _ := coseKey[int64(-1)].(int64)
+		x, _ := coseKey[int64(-2)].([]byte)
+		if keyType != coseKeyTypeOKP || curve != coseCurveEd25519 || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("cose: invalid EdDSA key")
+		}
+		return &publicKey{algorithm: algorithm, key: ed25519.PublicKey(x)}, nil
+	case AlgRS256:
+		n, _ := coseKey[int64(-1)].([]byte)
+		e, _ := coseKey[int64(-2)].([]byte)
+		if keyType != coseKeyTypeRSA || len(n) < 256 || len(e) == 0 || len(e) > 4 {
+			return nil, errors.New("cose: invalid RS256 key")
+		}
+		exponent := new(big.Int).SetBytes(e)
+		return &publicKey{
+			algorithm: algorithm,
+			key: &rsa.PublicKey{
+				N: new(big.Int).SetBytes(n),
+				E: int(exponent.Int64()),
+			},
+		}, nil
+	}
+
+	return nil, fmt.Errorf("cose: unsupported algorithm %d", algorithm)
+}
+
+func (k *publicKey) verify(data, signature []byte) bool {
+	switch key := k.key.(type) {
+	case *ecdsa.PublicKey:
+		digest := sha256.Sum256(data)
+		return ecdsa.VerifyASN1(key, digest[:], signature)
+	case ed25519.PublicKey:
+		return ed25519.Verify(key, data, signature)
+	case *rsa.PublicKey:
+		digest := sha256.Sum256(data)
+		return rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature) == nil
+	}
+	return false
+}
@@ -0,0 +1,416 @@
+package webauthn
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	flagUserPresent        byte = 0x01
+	flagUserVerified       byte = 0x04
+	flagAttestedCredential byte = 0x40
+
+	challengeSize = 32
+)
+
+var (
+	ErrInvalidClientData   = errors.New("webauthn: invalid client data")
+	ErrInvalidAuthData     = errors.New("webauthn: invalid authenticator data")
+	ErrInvalidSignature    = errors.New("webauthn: invalid signature")
+	ErrClonedAuthenticator = errors.New("webauthn: signature counter did not increase")
+)
+
+// Base64URL is a byte slice that travels as unpadded base64url in JSON, the
+// encoding used by PublicKeyCredential.toJSON().
+type Base64URL []byte
+
+func (b Base64URL) MarshalJSON() ([]byte, error) {
+	return json.Marshal(base64.RawURLEncoding.EncodeToString(b))
+}
+
+func (b *Base64URL) UnmarshalJSON(data []byte) error {
+	var encoded string
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return err
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(encoded, "="))
+	if err != nil {
+		return fmt.Errorf("webauthn: invalid base64url value: %w", err)
+	}
+	*b = decoded
+	return nil
+}
+
+type Config struct {
+	RPID             string
+	RPName           string
+	Origins          []string
+	Timeout          time.Duration
+	UserVerification string // "required", "preferred" or "discouraged"
+}
+
+type RelyingPartyEntity struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type UserEntity struct {
+	ID          Base64URL `json:"id"`
+	Name        string    `json:"name"`
+	DisplayName string    `json:"displayName"`
+}
+
+type CredentialParameter struct {
+	Type      string `json:"type"`
+	Algorithm int64  `json:"alg"`
+}
+
+type CredentialDescriptor struct {
+	Type       string    `json:"type"`
+	ID         Base64URL `json:"id"`
+	Transports []string  `json:"transports,omitempty"`
+}
+
+type AuthenticatorSelection struct {
+	ResidentKey      string `json:"residentKey"`
+	UserVerification string `json:"userVerification"`
+}
+
+// CreationOptions is passed to navigator.credentials.create().
+type CreationOptions struct {
+	Challenge              Base64URL              `json:"challenge"`
+	RP                     RelyingPartyEntity     `json:"rp"`
+	User                   UserEntity             `json:"user"`
+	PubKeyCredParams       []CredentialParameter  `json:"pubKeyCredParams"`
+	Timeout                int64                  `json:"timeout"`
+	ExcludeCredentials     []CredentialDescriptor `json:"excludeCredentials,omitempty"`
+	AuthenticatorSelection AuthenticatorSelection `json:"authenticatorSelection"`
+	Attestation            string                 `json:"attestation"`
+}
+
+// RequestOptions is passed to navigator.credentials.get().
+type RequestOptions struct {
+	Challenge        Base64URL              `json:"challenge"`
+	Timeout          int64                  `json:"timeout"`
+	RPID             string                 `json:"rpId"`
+	AllowCredentials []CredentialDescriptor `json:"allowCredentials,omitempty"`
+	UserVerification string                 `json:"userVerification"`
+}
+
+type AttestationResponse struct {
+	ID       string                           `json:"id"`
+	RawID    Base64URL                        `json:"rawId"`
+	Type     string                           `json:"type"`
+	Response AuthenticatorAttestationResponse `json:"response"`
+}
+
+type AuthenticatorAttestationResponse struct {
+	ClientDataJSON    Base64URL `json:"clientDataJSON"`
+	AttestationObject Base64URL `json:"attestationObject"`
+	Transports        []string  `json:"transports,omitempty"`
+}
+
+type AssertionResponse struct {
+	ID       string                         `json:"id"`
+	RawID    Base64URL                      `json:"rawId"`
+	Type     string                         `json:"type"`
+	Response AuthenticatorAssertionResponse `json:"response"`
+}
+
+type AuthenticatorAssertionResponse struct {
+	ClientDataJSON    Base64URL `json:"clientDataJSON"`
+	AuthenticatorData Base64URL `json:"authenticatorData"`
+	Signature         Base64URL `json:"signature"`
+	UserHandle        Base64URL `json:"userHandle,omitempty"`
+}
+
+// Credential is the result of a successful registration ceremony.
+type Credential struct {
+	ID         []byte
+	PublicKey  []byte // COSE_Key
+	Algorithm  int64
+	SignCount  uint32
+	AAGUID     []byte
+	Transports []string
+}
+
+// Assertion is the outcome of a verified login.
+type Assertion struct {
+	SignCount    uint32 // The new signature counter to persist
+	UserVerified bool   // The authenticator verified the user, by PIN or biometrics, not just their presence
+}
+
+type clientData struct {
+	Type      string `json:"type"`
+	Challenge string `json:"challenge"`
+	Origin    string `json:"origin"`
+}
+
+type authenticatorData struct {
+	rpIDHash     []byte
+	flags        byte
+	signCount    uint32
+	aaguid       []byte
+	credentialID []byte
+	publicKey    []byte
+}
+
+type RelyingParty struct {
+	config Config
+}
+
+func New(config Config) *RelyingParty {
+	if config.UserVerification == "" {
+		config.UserVerification = "preferred"
+	}
+	return &RelyingParty{config: config}
+}
+
+func (rp *RelyingParty) Timeout() time.Duration {
+	return rp.config.Timeout
+}
+
+func (rp *RelyingParty) BeginRegistration(user UserEntity, exclude []CredentialDescriptor) (*CreationOptions, error) {
+	challenge, err := newChallenge()
+	if err != nil {
+		return nil, err
+	}
+
+	return &CreationOptions{
+		Challenge: challenge,
+		RP: RelyingPartyEntity{
+			ID:   rp.config.RPID,
+			Name: rp.config.RPName,
+		},
+		User: user,
+		PubKeyCredParams: []CredentialParameter{
+			{Type: "public-key", Algorithm: AlgES256},
+			{Type: "public-key", Algorithm: AlgEdDSA},
+			{Type: "public-key", Algorithm: AlgRS256},
+		},
+		Timeout:            rp.config.Timeout.Milliseconds(),
+		ExcludeCredentials: exclude,
+		AuthenticatorSelection: AuthenticatorSelection{
+			ResidentKey:      "preferred",
+			UserVerification: rp.config.UserVerification,
+		},
+		Attestation: "none",
+	}, nil
+}
+
+// BeginLogin starts an assertion. An empty allow list lets the authenticator
+// offer any discoverable credential for this relying party.
+func (rp *RelyingParty) BeginLogin(allow []CredentialDescriptor) (*RequestOptions, error) {
+	challenge, err := newChallenge()
+	if err != nil {
+		return nil, err
+	}
+
+	return &RequestOptions{
+		Challenge:        challenge,
+		Timeout:          rp.config.Timeout.Milliseconds(),
+		RPID:             rp.config.RPID,
+		AllowCredentials: allow,
+		UserVerification: rp.config.UserVerification,
+	}, nil
+}
+
+// FinishRegistration verifies an attestation response against the issued
+// challenge. Only "none" attestation is requested, so attestation statements
+// are not evaluated; the credential is trusted on first use.
+func (rp *RelyingParty) FinishRegistration(challenge []byte, response *AttestationResponse) (*Credential, error) {
+	if response.Type != "public-key" {
+		return nil, errors.New("webauthn: unexpected credential type")
+	}
+
+	if err := rp.verifyClientData(response.Response.ClientDataJSON, "webauthn.create", challenge); err != nil {
+		return nil, err
+	}
+
+	decoded, _, err := decodeCBOR(response.Response.AttestationObject)
+	if err != nil {
+		return nil, fmt.Errorf("webauthn: invalid attestation object: %w", err)
+	}
+	attestation, ok := decoded.(map[interface{}]interface{})
+	if !ok {
+		return nil, errors.New("webauthn: invalid attestation object")
+	}
+	rawAuthData, ok := attestation["authData"].([]byte)
+	if !ok {
+		return nil, errors.New("webauthn: attestation object has no authData")
+	}
+
+	authData, err := parseAuthenticatorData(rawAuthData)
+	if err != nil {
+		return nil, err
+	}
+	if err := rp.verifyAuthenticatorData(authData); err != nil {
+		return nil, err
+	}
+	if authData.flags&flagAttestedCredential == 0 {
+		return nil, errors.New("webauthn: no attested credential data")
+	}
+	if len(response.RawID) > 0 && !bytes.Equal(response.RawID, authData.credentialID) {
+		return nil, errors.New("webauthn: credential ID mismatch")
+	}
+
+	key, err := parsePublicKey(authData.publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Credential{
+		ID:         authData.credentialID,
+		PublicKey:  authData.publicKey,
+		Algorithm:  key.algorithm,
+		SignCount:  authData.signCount,
+		AAGUID:     authData.aaguid,
+		Transports: response.Response.Transports,
+	}, nil
+}
+
+// FinishLogin verifies an assertion made with the stored credential. Unless
+// user verification is required, the result tells whether it took place.
+func (rp *RelyingParty) FinishLogin(challenge, storedPublicKey []byte, storedSignCount uint32, response *AssertionResponse) (*Assertion, error) {
+	if response.Type != "public-key" {
+		return nil, errors.New("webauthn: unexpected credential type")
+	}
+
+	if err := rp.verifyClientData(response.Response.ClientDataJSON, "webauthn.get", challenge); err != nil {
+		return nil, err
+	}
+
+	authData, err := parseAuthenticatorData(response.Response.AuthenticatorData)
+	if err != nil {
+		return nil, err
+	}
+	if err := rp.verifyAuthenticatorData(authData); err != nil {
+		return nil, err
+	}
+
+	key, err := parsePublicKey(storedPublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	clientDataHash := sha256.Sum256(response.Response.ClientDataJSON)
+	signed := append(append([]byte(nil), response.Response.AuthenticatorData...), clientDataHash[:]...)
+	if !key.verify(signed, response.Response.Signature) {
+		return nil, ErrInvalidSignature
+	}
+
+	// Authenticators that do not implement a counter always report zero
+	if (authData.signCount != 0 || storedSignCount != 0) && authData.signCount <= storedSignCount {
+		return nil, ErrClonedAuthenticator
+	}
+
+	return &Assertion{
+		SignCount:    authData.signCount,
+		UserVerified: authData.flags&flagUserVerified != 0,
+	}, nil
+}
+
+// ChallengeFromClientData extracts the challenge a response claims to answer,
+// so the server-side ceremony state can be looked up before verification.
+func ChallengeFromClientData(clientDataJSON []byte) ([]byte, error) {
+	var data clientData
+	if err := json.Unmarshal(clientDataJSON, &data); err != nil {
+		return nil, ErrInvalidClientData
+	}
+	challenge, err := base64.RawURLEncoding.DecodeString(data.Challenge)
+	if err != nil || len(challenge) == 0 {
+		return nil, ErrInvalidClientData
+	}
+	return challenge, nil
+}
+
+func (rp *RelyingParty) verifyClientData(raw []byte, ceremony string, challenge []byte) error {
+	var data clientData
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return ErrInvalidClientData
+	}
+
+	if data.Type != ceremony {
+		return fmt.Errorf("%w: unexpected type %q", ErrInvalidClientData, data.Type)
+	}
+
+	received, err := base64.RawURLEncoding.DecodeString(data.Challenge)
+	if err != nil || subtle.ConstantTimeCompare(received, challenge) != 1 {
+		return fmt.Errorf("%w: challenge mismatch", ErrInvalidClientData)
+	}
+
+	for _, origin := range rp.config.Origins {
+		if data.Origin == origin {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: unexpected origin %q", ErrInvalidClientData, data.Origin)
+}
+
+func (rp *RelyingParty) verifyAuthenticatorData(authData *authenticatorData) error {
+	rpIDHash := sha256.Sum256([]byte(rp.config.RPID))
+	if !bytes.Equal(authData.rpIDHash, rpIDHash[:]) {
+		return fmt.Errorf("%w: relying party ID mismatch", ErrInvalidAuthData)
+	}
+	if authData.flags&flagUserPresent == 0 {
+		return fmt.Errorf("%w: user not present", ErrInvalidAuthData)
+	}
+	if rp.config.UserVerification == "required" && authData.flags&flagUserVerified == 0 {
+		return fmt.Errorf("%w: user not verified", ErrInvalidAuthData)
+	}
+	return nil
+}
+
+func parseAuthenticatorData(data []byte) (*authenticatorData, error) {
+	if len(data) < 37 {
+		return nil, fmt.Errorf("%w: too short", ErrInvalidAuthData)
+	}
+
+	authData := &authenticatorData{
+		rpIDHash:  data[:32],
+		flags:     data[32],
+		signCount: binary.BigEndian.Uint32(data[33:37]),
+	}
+
+	if authData.flags&flagAttestedCredential == 0 {
+		return authData, nil
+	}
+
+	rest := data[37:]
+	if len(rest) < 18 {
+		return nil, fmt.Errorf("%w: truncated attested credential data", ErrInvalidAuthData)
+	}
+	authData.aaguid = rest[:16]
+	idLength := int(binary.BigEndian.Uint16(rest[16:18]))
+	rest = rest[18:]
+	if idLength == 0 || idLength > 1023 || len(rest) < idLength {
+		return nil, fmt.Errorf("%w: invalid credential ID", ErrInvalidAuthData)
+	}
+	authData.credentialID = rest[:idLength]
+	rest = rest[idLength:]
+
+	_, consumed, err := decodeCBOR(rest)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid credential public key", ErrInvalidAuthData)
+	}
+	authData.publicKey = rest[:consumed]
+
+	return authData, nil
+}
+
+func newChallenge() (Base64URL, error) {
+	challenge := make([]byte, challengeSize)
+	if _, err := rand.Read(challenge); err != nil {
+		return nil, fmt.Errorf("webauthn: failed to generate challenge: %w", err)
+	}
+	return challenge, nil
+}
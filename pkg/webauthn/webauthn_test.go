@@ -0,0 +1,584 @@
+package webauthn
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"sort"
+	"testing"
+)
+
+const (
+	testRPID   = "example.com"
+	testOrigin = "https://example.com"
+)
+
+// encodeCBOR encodes the values the tests need: int64, int, []byte, string,
+// []interface{} and map[interface{}]interface{} with int64 or string keys.
+// Map keys are sorted so encodings are deterministic.
+func encodeCBOR(t *testing.T, value interface{}) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	var encode func(value interface{})
+	header := func(major byte, arg uint64) {
+		switch {
+		case arg < 24:
+			buf.WriteByte(major<<5 | byte(arg))
+		case arg <= 0xff:
+			buf.WriteByte(major<<5 | 24)
+			buf.WriteByte(byte(arg))
+		case arg <= 0xffff:
+			buf.WriteByte(major<<5 | 25)
+			buf.Write(binary.BigEndian.AppendUint16(nil, uint16(arg)))
+		case arg <= 0xffffffff:
+			buf.WriteByte(major<<5 | 26)
+			buf.Write(binary.BigEndian.AppendUint32(nil, uint32(arg)))
+		default:
+			buf.WriteByte(major<<5 | 27)
+			buf.Write(binary.BigEndian.AppendUint64(nil, arg))
+		}
+	}
+	encode = func(value interface{}) {
+		switch v := value.(type) {
+		case int:
+			encode(int64(v))
+		case int64:
+			if v >= 0 {
+				header(0, uint64(v))
+			} else {
+				header(1, uint64(-1-v))
+			}
+		case []byte:
+			header(2, uint64(len(v)))
+			buf.Write(v)
+		case string:
+			header(3, uint64(len(v)))
+			buf.WriteString(v)
+		case []interface{}:
+			header(4, uint64(len(v)))
+			for _, item := range v {
+				encode(item)
+			}
+		case map[interface{}]interface{}:
+			keys := make([]interface{}, 0, len(v))
+			for key := range v {
+				keys = append(keys, key)
+			}
+			sort.Slice(keys, func(i, j int) bool {
+				return encodeKey(t, keys[i]) < encodeKey(t, keys[j])
+			})
+			header(5, uint64(len(v)))
+			for _, key := range keys {
+				encode(key)
+				encode(v[key])
+			}
+		default:
+			t.Fatalf("encodeCBOR: unsupported type %T", value)
+		}
+	}
+	encode(value)
+	return buf.Bytes()
+}
+
+func encodeKey(t *testing.T, key interface{}) string {
+	return string(encodeCBOR(t, key))
+}
+
+// softwareAuthenticator is an in-memory authenticator holding one
+// credential, enough to drive registration and login ceremonies.
+type softwareAuthenticator struct {
+	t            *testing.T
+	credentialID []byte
+	signer       crypto.Signer
+	coseKey      []byte
+	signCount    uint32
+	userVerified bool
+}
+
+func newSoftwareAuthenticator(t *testing.T, algorithm int64) *softwareAuthenticator {
+	t.Helper()
+
+	a := &softwareAuthenticator{t: t, credentialID: randomBytes(t, 16)}
+
+	switch algorithm {
+	case AlgES256:
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		a.signer = key
+		a.coseKey = encodeCBOR(t, map[interface{}]interface{}{
+			int64(1):  coseKeyTypeEC2,
+			int64(3):  AlgES256,
+			int64(-1): coseCurveP256,
+			int64(-2): key.X.FillBytes(make([]byte, 32)),
+			int64(-3): key.Y.FillBytes(make([]byte, 32)),
+		})
+	case AlgEdDSA:
+		public, private, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		a.signer = private
+		a.coseKey = encodeCBOR(t, map[interface{}]interface{}{
+			int64(1):  coseKeyTypeOKP,
+			int64(3):  AlgEdDSA,
+			int64(-1): coseCurveEd25519,
+			int64(-2): []byte(public),
+		})
+	case AlgRS256:
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			t.Fatal(err)
+		}
+		a.signer = key
+		a.coseKey = encodeCBOR(t, map[interface{}]interface{}{
+			int64(1):  coseKeyTypeRSA,
+			int64(3):  AlgRS256,
+			int64(-1): key.N.Bytes(),
+			int64(-2): big2bytes(key.E),
+		})
+	default:
+		t.Fatalf("unsupported algorithm %d", algorithm)
+	}
+
+	return a
+}
+
+func big2bytes(e int) []byte {
+	b := binary.BigEndian.AppendUint32(nil, uint32(e))
+	for len(b) > 1 && b[0] == 0 {
+		b = b[1:]
+	}
+	return b
+}
+
+func (a *softwareAuthenticator) authenticatorData(rpID string, attested bool) []byte {
+	rpIDHash := sha256.Sum256([]byte(rpID))
+	flags := flagUserPresent
+	if a.userVerified {
+		flags |= flagUserVerified
+	}
+	if attested {
+		flags |= flagAttestedCredential
+	}
+
+	data := append([]byte(nil), rpIDHash[:]...)
+	data = append(data, flags)
+	data = binary.BigEndian.AppendUint32(data, a.signCount)
+	if attested {
+		data = append(data, make([]byte, 16)...) // AAGUID
+		data = binary.BigEndian.AppendUint16(data, uint16(len(a.credentialID)))
+		data = append(data, a.credentialID...)
+		data = append(data, a.coseKey...)
+	}
+	return data
+}
+
+func (a *softwareAuthenticator) create(challenge []byte) *AttestationResponse {
+	attestationObject := encodeCBOR(a.t, map[interface{}]interface{}{
+		"fmt":      "none",
+		"attStmt":  map[interface{}]interface{}{},
+		"authData": a.authenticatorData(testRPID, true),
+	})
+
+	return &AttestationResponse{
+		ID:    base64.RawURLEncoding.EncodeToString(a.credentialID),
+		RawID: a.credentialID,
+		Type:  "public-key",
+		Response: AuthenticatorAttestationResponse{
+			ClientDataJSON:    clientDataJSON(a.t, "webauthn.create", challenge, testOrigin),
+			AttestationObject: attestationObject,
+		},
+	}
+}
+
+func (a *softwareAuthenticator) get(challenge []byte) *AssertionResponse {
+	a.signCount++
+	authData := a.authenticatorData(testRPID, false)
+	clientData := clientDataJSON(a.t, "webauthn.get", challenge, testOrigin)
+
+	return &AssertionResponse{
+		ID:    base64.RawURLEncoding.EncodeToString(a.credentialID),
+		RawID: a.credentialID,
+		Type:  "public-key",
+		Response: AuthenticatorAssertionResponse{
+			ClientDataJSON:    clientData,
+			AuthenticatorData: authData,
+			Signature:         a.sign(authData, clientData),
+		},
+	}
+}
+
+func (a *softwareAuthenticator) sign(authData, clientData []byte) []byte {
+	clientDataHash := sha256.Sum256(clientData)
+	signed := append(append([]byte(nil), authData...), clientDataHash[:]...)
+
+	var (
+		signature []byte
+		err       error
+	)
+	if _, ok := a.signer.(ed25519.PrivateKey); ok {
+		signature, err = a.signer.Sign(rand.Reader, signed, crypto.Hash(0))
+	} else {
+		digest := sha256.Sum256(signed)
+		signature, err = a.signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+	}
+	if err != nil {
+		a.t.Fatal(err)
+	}
+	return signature
+}
+
+func clientDataJSON(t *testing.T, ceremony string, challenge []byte, origin string) []byte {
+	t.Helper()
+	data, err := json.Marshal(clientData{
+		Type:      ceremony,
+		Challenge: base64.RawURLEncoding.EncodeToString(challenge),
+		Origin:    origin,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func randomBytes(t *testing.T, n int) []byte {
+	t.Helper()
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func newTestRelyingParty(userVerification string) *RelyingParty {
+	return New(Config{
+		RPID:             testRPID,
+		RPName:           "Example",
+		Origins:          []string{testOrigin},
+		UserVerification: userVerification,
+	})
+}
+
+func register(t *testing.T, rp *RelyingParty, authenticator *softwareAuthenticator) *Credential {
+	t.Helper()
+
+	options, err := rp.BeginRegistration(UserEntity{ID: []byte("user"), Name: "user@example.com"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	credential, err := rp.FinishRegistration(options.Challenge, authenticator.create(options.Challenge))
+	if err != nil {
+		t.Fatalf("FinishRegistration: %v", err)
+	}
+	return credential
+}
+
+func TestRegistrationAndLogin(t *testing.T) {
+	for name, algorithm := range map[string]int64{"ES256": AlgES256, "EdDSA": AlgEdDSA, "RS256": AlgRS256} {
+		t.Run(name, func(t *testing.T) {
+			rp := newTestRelyingParty("")
+			authenticator := newSoftwareAuthenticator(t, algorithm)
+
+			credential := register(t, rp, authenticator)
+			if !bytes.Equal(credential.ID, authenticator.credentialID) {
+				t.Fatalf("credential ID = %x, want %x", credential.ID, authenticator.credentialID)
+			}
+			if credential.Algorithm != algorithm {
+				t.Fatalf("algorithm = %d, want %d", credential.Algorithm, algorithm)
+			}
+
+			options, err := rp.BeginLogin(nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertion, err := rp.FinishLogin(options.Challenge, credential.PublicKey, credential.SignCount, authenticator.get(options.Challenge))
+			if err != nil {
+				t.Fatalf("FinishLogin: %v", err)
+			}
+			if assertion.SignCount != authenticator.signCount {
+				t.Fatalf("sign count = %d, want %d", assertion.SignCount, authenticator.signCount)
+			}
+		})
+	}
+}
+
+func TestFinishLoginReportsUserVerification(t *testing.T) {
+	rp := newTestRelyingParty("preferred")
+	authenticator := newSoftwareAuthenticator(t, AlgES256)
+	credential := register(t, rp, authenticator)
+
+	for _, verified := range []bool{false, true} {
+		authenticator.userVerified = verified
+		storedSignCount := authenticator.signCount
+		options, _ := rp.BeginLogin(nil)
+		assertion, err := rp.FinishLogin(options.Challenge, credential.PublicKey, storedSignCount, authenticator.get(options.Challenge))
+		if err != nil {
+			t.Fatalf("FinishLogin: %v", err)
+		}
+		if assertion.UserVerified != verified {
+			t.Fatalf("UserVerified = %v, want %v", assertion.UserVerified, verified)
+		}
+	}
+}
+
+func TestFinishLoginRequiresUserVerificationWhenConfigured(t *testing.T) {
+	rp := newTestRelyingParty("required")
+	authenticator := newSoftwareAuthenticator(t, AlgES256)
+	authenticator.userVerified = true
+	credential := register(t, rp, authenticator)
+
+	authenticator.userVerified = false
+	options, _ := rp.BeginLogin(nil)
+	_, err := rp.FinishLogin(options.Challenge, credential.PublicKey, credential.SignCount, authenticator.get(options.Challenge))
+	if !errors.Is(err, ErrInvalidAuthData) {
+		t.Fatalf("err = %v, want ErrInvalidAuthData", err)
+	}
+}
+
+func TestFinishLoginRejectsTamperedAssertions(t *testing.T) {
+	rp := newTestRelyingParty("")
+	authenticator := newSoftwareAuthenticator(t, AlgES256)
+	credential := register(t, rp, authenticator)
+
+	tests := map[string]struct {
+		tamper func(challenge []byte) (*AssertionResponse, []byte)
+		want   error
+	}{
+		"wrong challenge": {
+			tamper: func(challenge []byte) (*AssertionResponse, []byte) {
+				return authenticator.get(randomBytes(t, challengeSize)), challenge
+			},
+			want: ErrInvalidClientData,
+		},
+		"wrong origin": {
+			tamper: func(challenge []byte) (*AssertionResponse, []byte) {
+				response := authenticator.get(challenge)
+				response.Response.ClientDataJSON = clientDataJSON(t, "webauthn.get", challenge, "https://evil.example")
+				response.Response.Signature = authenticator.sign(response.Response.AuthenticatorData, response.Response.ClientDataJSON)
+				return response, challenge
+			},
+			want: ErrInvalidClientData,
+		},
+		"registration client data": {
+			tamper: func(challenge []byte) (*AssertionResponse, []byte) {
+				response := authenticator.get(challenge)
+				response.Response.ClientDataJSON = clientDataJSON(t, "webauthn.create", challenge, testOrigin)
+				return response, challenge
+			},
+			want: ErrInvalidClientData,
+		},
+		"wrong relying party": {
+			tamper: func(challenge []byte) (*AssertionResponse, []byte) {
+				response := authenticator.get(challenge)
+				response.Response.AuthenticatorData = authenticator.authenticatorData("evil.example", false)
+				response.Response.Signature = authenticator.sign(response.Response.AuthenticatorData, response.Response.ClientDataJSON)
+				return response, challenge
+			},
+			want: ErrInvalidAuthData,
+		},
+		"bad signature": {
+			tamper: func(challenge []byte) (*AssertionResponse, []byte) {
+				response := authenticator.get(challenge)
+				response.Response.Signature[len(response.Response.Signature)-1] ^= 0xff
+				return response, challenge
+			},
+			want: ErrInvalidSignature,
+		},
+		"signature by another key": {
+			tamper: func(challenge []byte) (*AssertionResponse, []byte) {
+				other := newSoftwareAuthenticator(t, AlgES256)
+				other.credentialID = authenticator.credentialID
+				return other.get(challenge), challenge
+			},
+			want: ErrInvalidSignature,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			options, _ := rp.BeginLogin(nil)
+			response, challenge := test.tamper(options.Challenge)
+			_, err := rp.FinishLogin(challenge, credential.PublicKey, 0, response)
+			if !errors.Is(err, test.want) {
+				t.Fatalf("err = %v, want %v", err, test.want)
+			}
+		})
+	}
+}
+
+func TestFinishLoginDetectsClonedAuthenticator(t *testing.T) {
+	rp := newTestRelyingParty("")
+	authenticator := newSoftwareAuthenticator(t, AlgEdDSA)
+	credential := register(t, rp, authenticator)
+
+	authenticator.signCount = 10
+	options, _ := rp.BeginLogin(nil)
+	_, err := rp.FinishLogin(options.Challenge, credential.PublicKey, 11, authenticator.get(options.Challenge))
+	if !errors.Is(err, ErrClonedAuthenticator) {
+		t.Fatalf("err = %v, want ErrClonedAuthenticator", err)
+	}
+}
+
+func TestFinishRegistrationRejectsInvalidAttestations(t *testing.T) {
+	rp := newTestRelyingParty("")
+	authenticator := newSoftwareAuthenticator(t, AlgES256)
+
+	tests := map[string]func(response *AttestationResponse){
+		"not a map": func(response *AttestationResponse) {
+			response.Response.AttestationObject = encodeCBOR(t, []interface{}{"authData"})
+		},
+		"missing authData": func(response *AttestationResponse) {
+			response.Response.AttestationObject = encodeCBOR(t, map[interface{}]interface{}{"fmt": "none"})
+		},
+		"no attested credential": func(response *AttestationResponse) {
+			response.Response.AttestationObject = encodeCBOR(t, map[interface{}]interface{}{
+				"fmt":      "none",
+				"attStmt":  map[interface{}]interface{}{},
+				"authData": authenticator.authenticatorData(testRPID, false),
+			})
+		},
+		"credential ID mismatch": func(response *AttestationResponse) {
+			response.RawID = randomBytes(t, 16)
+		},
+		"truncated": func(response *AttestationResponse) {
+			object := response.Response.AttestationObject
+			response.Response.AttestationObject = object[:len(object)-10]
+		},
+	}
+
+	for name, tamper := range tests {
+		t.Run(name, func(t *testing.T) {
+			options, _ := rp.BeginRegistration(UserEntity{ID: []byte("user")}, nil)
+			response := authenticator.create(options.Challenge)
+			tamper(response)
+			if _, err := rp.FinishRegistration(options.Challenge, response); err == nil {
+				t.Fatal("FinishRegistration succeeded, want an error")
+			}
+		})
+	}
+}
+
+func TestDecodeCBOR(t *testing.T) {
+	value, consumed, err := decodeCBOR(append(encodeCBOR(t, map[interface{}]interface{}{
+		int64(-7): []byte{1, 2, 3},
+		"list":    []interface{}{int64(0), int64(23), int64(24), int64(65536), int64(-1000), "text"},
+	}), 0xff))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries, ok := value.(map[interface{}]interface{})
+	if !ok {
+		t.Fatalf("decoded %T, want a map", value)
+	}
+	if !bytes.Equal(entries[int64(-7)].([]byte), []byte{1, 2, 3}) {
+		t.Fatalf("entry -7 = %v", entries[int64(-7)])
+	}
+	list := entries["list"].([]interface{})
+	want := []interface{}{int64(0), int64(23), int64(24), int64(65536), int64(-1000), "text"}
+	for i := range want {
+		if list[i] != want[i] {
+			t.Fatalf("list[%d] = %v, want %v", i, list[i], want[i])
+		}
+	}
+	// The trailing byte is not part of the item
+	if consumed != len(encodeCBOR(t, entries)) {
+		t.Fatalf("consumed %d bytes", consumed)
+	}
+
+	simple, _, err := decodeCBOR([]byte{0xf5})
+	if err != nil || simple != true {
+		t.Fatalf("true decoded as %v, %v", simple, err)
+	}
+}
+
+func TestDecodeCBORRejectsMalformedInput(t *testing.T) {
+	deep := bytes.Repeat([]byte{0x81}, maxCBORDepth+2)
+	deep = append(deep, 0x00)
+
+	tests := map[string][]byte{
+		"empty":                 {},
+		"truncated byte string": {0x44, 1, 2},
+		"truncated argument":    {0x19, 0x01},
+		"truncated map":         {0xa2, 0x01, 0x02},
+		"huge array length":     {0x9b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+		"indefinite length":     {0x5f, 0x41, 0x00, 0xff},
+		"array map key":         {0xa1, 0x80, 0x00},
+		"integer overflow":      {0x1b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+		"nesting too deep":      deep,
+		"unsupported simple":    {0xf8, 0x20},
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, _, err := decodeCBOR(data); err == nil {
+				t.Fatal("decodeCBOR succeeded, want an error")
+			}
+		})
+	}
+}
+
+func TestParsePublicKeyRejectsInvalidKeys(t *testing.T) {
+	x := make([]byte, 32)
+	x[31] = 1
+
+	tests := map[string]map[interface{}]interface{}{
+		"point not on curve": {
+			int64(1): coseKeyTypeEC2, int64(3): AlgES256, int64(-1): coseCurveP256,
+			int64(-2): x, int64(-3): x,
+		},
+		"wrong curve": {
+			int64(1): coseKeyTypeEC2, int64(3): AlgES256, int64(-1): int64(2),
+			int64(-2): x, int64(-3): x,
+		},
+		"key type mismatch": {
+			int64(1): coseKeyTypeEC2, int64(3): AlgEdDSA, int64(-1): coseCurveEd25519,
+			int64(-2): make([]byte, ed25519.PublicKeySize),
+		},
+		"short RSA modulus": {
+			int64(1): coseKeyTypeRSA, int64(3): AlgRS256,
+			int64(-1): make([]byte, 128), int64(-2): []byte{1, 0, 1},
+		},
+		"unsupported algorithm": {
+			int64(1): coseKeyTypeEC2, int64(3): int64(-35),
+		},
+	}
+
+	for name, key := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := parsePublicKey(encodeCBOR(t, key)); err == nil {
+				t.Fatal("parsePublicKey succeeded, want an error")
+			}
+		})
+	}
+}
+
+func TestBase64URLAcceptsPadding(t *testing.T) {
+	var decoded Base64URL
+	if err := json.Unmarshal([]byte(`"AQI="`), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(decoded, []byte{1, 2}) {
+		t.Fatalf("decoded %v", decoded)
+	}
+
+	encoded, err := json.Marshal(decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(encoded) != `"AQI"` {
+		t.Fatalf("encoded %s", encoded)
+	}
+}